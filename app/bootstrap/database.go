@@ -18,5 +18,8 @@ func InitDatabase(cfg *config.AppConfig) *db.DB {
 	}
 
 	database := db.NewDB(rocksdb, families, cfg)
+	if err := database.LoadFamilySettings(); err != nil {
+		log.Fatalf("Error loading column family settings: %v", err)
+	}
 	return database
 }
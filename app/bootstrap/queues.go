@@ -0,0 +1,20 @@
+package bootstrap
+
+import (
+	"log"
+
+	"mithrildb/config"
+	"mithrildb/db"
+)
+
+// InitQueueManager enables durable job queues.
+func InitQueueManager(database *db.DB, cfg config.AppConfig) *db.QueueManager {
+	queueCfg, err := db.BuildQueueManagerConfig(cfg.Queues)
+	if err != nil {
+		log.Fatalf("invalid queues config: %v", err)
+	}
+
+	manager := db.NewQueueManager(database, queueCfg)
+	database.Queues = manager
+	return manager
+}
@@ -0,0 +1,21 @@
+package bootstrap
+
+import (
+	"log"
+
+	"mithrildb/config"
+	"mithrildb/db"
+)
+
+// InitSnapshotManager enables pinned snapshots and starts the lease reaper.
+func InitSnapshotManager(database *db.DB, cfg config.AppConfig) *db.SnapshotManager {
+	snapCfg, err := db.BuildSnapshotManagerConfig(cfg.Snapshots)
+	if err != nil {
+		log.Fatalf("invalid snapshots config: %v", err)
+	}
+
+	manager := db.NewSnapshotManager(database, snapCfg)
+	manager.Start()
+	database.Snapshots = manager
+	return manager
+}
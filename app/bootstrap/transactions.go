@@ -0,0 +1,21 @@
+package bootstrap
+
+import (
+	"log"
+
+	"mithrildb/config"
+	"mithrildb/db"
+)
+
+// InitTransactionManager enables interactive transactions and starts the idle reaper.
+func InitTransactionManager(database *db.DB, cfg config.AppConfig) *db.TransactionManager {
+	txnCfg, err := db.BuildTransactionManagerConfig(cfg.Transactions)
+	if err != nil {
+		log.Fatalf("invalid transactions config: %v", err)
+	}
+
+	manager := db.NewTransactionManager(database, txnCfg)
+	manager.Start()
+	database.Transactions = manager
+	return manager
+}
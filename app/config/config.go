@@ -14,6 +14,8 @@ import (
 type ServerConfig struct {
 	// Port where the HTTP server listens
 	Port int `json:"port"`
+	// Largest request body accepted, in bytes (0 = no limit)
+	MaxBodySize int64 `json:"max_body_size"`
 }
 
 // RocksDBConfig holds configuration parameters for the RocksDB database.
@@ -28,6 +30,11 @@ type RocksDBConfig struct {
 	MaxOpenFiles      int    `json:"max_open_files"`       // Maximum number of open files
 	EnableCompression bool   `json:"enable_compression"`   // Whether compression is enabled
 	CompressionType   string `json:"compression_type"`     // Compression type: snappy, zstd, lz4, none
+	EnableBlobFiles   bool   `json:"enable_blob_files"`    // Store large values in separate blob files (BlobDB)
+	MinBlobSize       int    `json:"min_blob_size"`        // Values of at least this size in bytes go to blob files
+	BlobFileSize      int    `json:"blob_file_size"`       // Size limit for blob files in bytes
+	EnableBlobGC      bool   `json:"enable_blob_gc"`       // Garbage collect blob files during compaction
+	DocumentFormat    string `json:"document_format"`      // Encoding of stored documents: json or msgpack
 }
 
 // WriteOptionsConfig represents configurable write options for RocksDB.
@@ -53,6 +60,9 @@ type AppConfig struct {
 	WriteDefaults WriteOptionsConfig `json:"write_defaults"`
 	ReadDefaults  ReadOptionsConfig  `json:"read_defaults"`
 	Expiration    ExpirationConfig   `json:"expiration"`
+	Transactions  TransactionsConfig `json:"transactions"`
+	Snapshots     SnapshotsConfig    `json:"snapshots"`
+	Queues        QueuesConfig       `json:"queues"`
 }
 
 // UpdateResult represents the result of a configuration update.
@@ -77,10 +87,61 @@ type ExpirationConfig struct {
 	ScaleUpFactor      float64 `json:"scale_up_factor"`      // Scale up if duration < TickInterval * factor
 }
 
+// TransactionsConfig holds limits for interactive server-side transactions.
+//
+// @Description Interactive transaction configuration.
+type TransactionsConfig struct {
+	MaxOpen     int    `json:"max_open"`     // Maximum number of concurrently open transactions
+	IdleTimeout string `json:"idle_timeout"` // Transactions idle for longer are rolled back (e.g. "30s")
+	LockTimeout string `json:"lock_timeout"` // How long a transaction waits for a locked key (e.g. "1s")
+}
+
+// SnapshotsConfig holds limits for snapshots pinned for consistent reads across requests.
+//
+// @Description Pinned snapshot configuration.
+type SnapshotsConfig struct {
+	MaxOpen      int    `json:"max_open"`      // Maximum number of snapshots pinned at the same time
+	DefaultLease string `json:"default_lease"` // Lease of snapshots created without one (e.g. "1m")
+	MaxLease     string `json:"max_lease"`     // Longest lease a client may request (e.g. "10m")
+}
+
+// QueuesConfig holds defaults and limits for job queues.
+//
+// @Description Job queue configuration.
+type QueuesConfig struct {
+	DefaultVisibilityTimeout string `json:"default_visibility_timeout"` // How long received messages stay hidden by default (e.g. "30s")
+	MaxVisibilityTimeout     string `json:"max_visibility_timeout"`     // Longest visibility timeout a client may request (e.g. "12h")
+	MaxDelay                 string `json:"max_delay"`                  // Longest delay a message may be enqueued with (e.g. "24h")
+	DefaultMaxAttempts       int    `json:"default_max_attempts"`       // Deliveries before a message is dead-lettered (0 = no limit)
+	RetryBackoff             string `json:"retry_backoff"`              // Default delay before retrying a nacked message (e.g. "1s")
+	MaxRetryBackoff          string `json:"max_retry_backoff"`          // Default longest delay between retries (e.g. "5m")
+	MaxReceive               int    `json:"max_receive"`                // Maximum messages returned by one receive
+}
+
 func LoadConfig() AppConfig {
 	cfg := AppConfig{
 		Server: ServerConfig{
-			Port: 5126,
+			Port:        5126,
+			MaxBodySize: 64 * 1024 * 1024, // 64MB
+		},
+		Transactions: TransactionsConfig{
+			MaxOpen:     1000,
+			IdleTimeout: "30s",
+			LockTimeout: "1s",
+		},
+		Snapshots: SnapshotsConfig{
+			MaxOpen:      100,
+			DefaultLease: "1m",
+			MaxLease:     "10m",
+		},
+		Queues: QueuesConfig{
+			DefaultVisibilityTimeout: "30s",
+			MaxVisibilityTimeout:     "12h",
+			MaxDelay:                 "24h",
+			DefaultMaxAttempts:       5,
+			RetryBackoff:             "1s",
+			MaxRetryBackoff:          "5m",
+			MaxReceive:               100,
 		},
 	}
 	path := os.Getenv("CONFIG_PATH")
@@ -95,6 +156,7 @@ func LoadConfig() AppConfig {
 		// [Server]
 		s := file.Section("Server")
 		cfg.Server.Port = s.Key("Port").MustInt(cfg.Server.Port)
+		cfg.Server.MaxBodySize = s.Key("MaxBodySize").MustInt64(cfg.Server.MaxBodySize)
 
 		// [Database.RocksDB]
 		if file.HasSection("Database.RocksDB") {
@@ -109,6 +171,11 @@ func LoadConfig() AppConfig {
 				MaxOpenFiles:      r.Key("MaxOpenFiles").MustInt(500),
 				EnableCompression: r.Key("EnableCompression").MustBool(false),
 				CompressionType:   r.Key("CompressionType").MustString("snappy"),
+				EnableBlobFiles:   r.Key("EnableBlobFiles").MustBool(false),
+				MinBlobSize:       r.Key("MinBlobSize").MustInt(64 * 1024),          // 64KB
+				BlobFileSize:      r.Key("BlobFileSize").MustInt(256 * 1024 * 1024), // 256MB
+				EnableBlobGC:      r.Key("EnableBlobGC").MustBool(true),
+				DocumentFormat:    r.Key("DocumentFormat").In("json", []string{"json", "msgpack"}),
 			}
 		} else {
 			log.Println("⚠️  No se encontró [Database.RocksDB] en config.ini — se usarán valores por defecto.")
@@ -138,6 +205,33 @@ func LoadConfig() AppConfig {
 			ScaleDownThreshold: exp.Key("ScaleDownThreshold").MustFloat64(0.25),
 			ScaleUpFactor:      exp.Key("ScaleUpFactor").MustFloat64(0.5),
 		}
+		// [Transactions]
+		txn := file.Section("Transactions")
+		cfg.Transactions = TransactionsConfig{
+			MaxOpen:     txn.Key("MaxOpen").MustInt(cfg.Transactions.MaxOpen),
+			IdleTimeout: txn.Key("IdleTimeout").MustString(cfg.Transactions.IdleTimeout),
+			LockTimeout: txn.Key("LockTimeout").MustString(cfg.Transactions.LockTimeout),
+		}
+
+		// [Snapshots]
+		snap := file.Section("Snapshots")
+		cfg.Snapshots = SnapshotsConfig{
+			MaxOpen:      snap.Key("MaxOpen").MustInt(cfg.Snapshots.MaxOpen),
+			DefaultLease: snap.Key("DefaultLease").MustString(cfg.Snapshots.DefaultLease),
+			MaxLease:     snap.Key("MaxLease").MustString(cfg.Snapshots.MaxLease),
+		}
+
+		// [Queues]
+		q := file.Section("Queues")
+		cfg.Queues = QueuesConfig{
+			DefaultVisibilityTimeout: q.Key("DefaultVisibilityTimeout").MustString(cfg.Queues.DefaultVisibilityTimeout),
+			MaxVisibilityTimeout:     q.Key("MaxVisibilityTimeout").MustString(cfg.Queues.MaxVisibilityTimeout),
+			MaxDelay:                 q.Key("MaxDelay").MustString(cfg.Queues.MaxDelay),
+			DefaultMaxAttempts:       q.Key("DefaultMaxAttempts").MustInt(cfg.Queues.DefaultMaxAttempts),
+			RetryBackoff:             q.Key("RetryBackoff").MustString(cfg.Queues.RetryBackoff),
+			MaxRetryBackoff:          q.Key("MaxRetryBackoff").MustString(cfg.Queues.MaxRetryBackoff),
+			MaxReceive:               q.Key("MaxReceive").MustInt(cfg.Queues.MaxReceive),
+		}
 	}
 
 	// if file not loaded them defaults
@@ -152,6 +246,11 @@ func LoadConfig() AppConfig {
 			MaxOpenFiles:      500,
 			EnableCompression: false,
 			CompressionType:   "snappy",
+			EnableBlobFiles:   false,
+			MinBlobSize:       64 * 1024,
+			BlobFileSize:      256 * 1024 * 1024,
+			EnableBlobGC:      true,
+			DocumentFormat:    "json",
 		}
 	}
 
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"fmt"
+
+	"mithrildb/model"
+)
+
+// Operations supported by ExecuteBatch.
+const (
+	BatchOpPut          = "put"
+	BatchOpInsert       = "insert"
+	BatchOpReplace      = "replace"
+	BatchOpDelete       = "delete"
+	BatchOpTouch        = "touch"
+	BatchOpCounterDelta = "counter_delta"
+	BatchOpListPush     = "list_push"
+	BatchOpListUnshift  = "list_unshift"
+	BatchOpListPop      = "list_pop"
+	BatchOpListShift    = "list_shift"
+	BatchOpSetAdd       = "set_add"
+	BatchOpSetRemove    = "set_remove"
+)
+
+// BatchResult reports the outcome of a single batch operation.
+type BatchResult struct {
+	Op       string          `json:"op"`
+	CF       string          `json:"cf"`
+	Key      string          `json:"key"`
+	Document *model.Document `json:"document,omitempty"` // Resulting document for put, insert, replace and touch
+	Old      interface{}     `json:"old,omitempty"`      // Previous counter value for counter_delta
+	New      interface{}     `json:"new,omitempty"`      // New counter value for counter_delta
+	Element  interface{}     `json:"element,omitempty"`  // Removed element for list_pop and list_shift
+}
+
+// BatchError identifies the operation that aborted a batch.
+type BatchError struct {
+	Index int
+	Op    string
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch operation %d (%s) failed: %v", e.Index, e.Op, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// ExecuteBatch runs an ordered list of operations across user column families in a single
+// transaction. Either all operations are applied or none is; the error identifies the failing
+// operation as a *BatchError.
+func (db *DB) ExecuteBatch(opts BatchOptions) ([]BatchResult, error) {
+	if len(opts.Operations) == 0 {
+		return nil, fmt.Errorf("%w: no operations given", ErrInvalidBatchOperation)
+	}
+
+	results := make([]BatchResult, len(opts.Operations))
+	err := db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		for i, op := range opts.Operations {
+			result, err := tc.executeOperation(op)
+			if err != nil {
+				return &BatchError{Index: i, Op: op.Op, Err: err}
+			}
+			results[i] = result
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// executeOperation applies one batch operation inside the transaction.
+func (tc *txnContext) executeOperation(op BatchOperation) (BatchResult, error) {
+	result := BatchResult{Op: op.Op, CF: op.ColumnFamily, Key: op.Key}
+
+	if !IsValidUserCF(op.ColumnFamily) {
+		return result, ErrInvalidUserColumnFamily
+	}
+
+	writeOpts := DocumentWriteOptions{
+		ColumnFamily: op.ColumnFamily,
+		Key:          op.Key,
+		Value:        op.Value,
+		Cas:          op.Cas,
+		Type:         op.Type,
+		Expiration:   op.Expiration,
+	}
+	listOpts := ListOpOptions{
+		ColumnFamily: op.ColumnFamily,
+		Key:          op.Key,
+		Cas:          op.Cas,
+		Expiration:   op.Expiration,
+	}
+
+	var err error
+	switch op.Op {
+	case BatchOpPut:
+		result.Document, err = tc.putDocument(writeOpts)
+	case BatchOpInsert:
+		result.Document, err = tc.insertDocument(writeOpts)
+	case BatchOpReplace:
+		result.Document, err = tc.replaceDocument(writeOpts)
+	case BatchOpTouch:
+		result.Document, err = tc.touchDocument(writeOpts)
+	case BatchOpDelete:
+		err = tc.deleteDocument(DocumentDeleteOptions{
+			ColumnFamily: op.ColumnFamily,
+			Key:          op.Key,
+			Cas:          op.Cas,
+		})
+	case BatchOpCounterDelta:
+		if op.Delta == 0 {
+			return result, fmt.Errorf("%w: delta must be a non-zero integer", ErrInvalidBatchOperation)
+		}
+		var counter *CounterResult
+		counter, err = tc.incrementCounter(CounterIncrementOptions{
+			ColumnFamily: op.ColumnFamily,
+			Key:          op.Key,
+			Delta:        op.Delta,
+			Cas:          op.Cas,
+			Expiration:   op.Expiration,
+		})
+		if err == nil {
+			result.Old, result.New = counter.Old, counter.New
+		}
+	case BatchOpListPush, BatchOpListUnshift, BatchOpSetAdd, BatchOpSetRemove:
+		if op.Element == nil {
+			return result, fmt.Errorf("%w: element is required", ErrInvalidBatchOperation)
+		}
+		element, nerr := model.NormalizeValue(op.Element)
+		if nerr != nil {
+			return result, nerr
+		}
+		switch op.Op {
+		case BatchOpListPush:
+			_, err = tc.modifyList(listOpts, appendElement(element))
+		case BatchOpListUnshift:
+			_, err = tc.modifyList(listOpts, prependElement(element))
+		case BatchOpSetAdd:
+			_, err = tc.modifySet(listOpts, addMember(element))
+		case BatchOpSetRemove:
+			_, err = tc.modifySet(listOpts, removeMember(element))
+		}
+	case BatchOpListPop, BatchOpListShift:
+		if err = tc.db.checkListTurn(listOpts.ColumnFamily, listOpts.Key); err != nil {
+			return result, err
+		}
+		if op.Op == BatchOpListPop {
+			result.Element, err = tc.modifyList(listOpts, removeLast)
+		} else {
+			result.Element, err = tc.modifyList(listOpts, removeFirst)
+		}
+	default:
+		return result, fmt.Errorf("%w: unsupported operation %q", ErrInvalidBatchOperation, op.Op)
+	}
+	return result, err
+}
@@ -0,0 +1,31 @@
+package db
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"mithrildb/model"
+)
+
+// DefaultBlobContentType is recorded for blobs stored without an explicit media type.
+const DefaultBlobContentType = "application/octet-stream"
+
+// applyContentMetadata records content type, checksum and size for blob documents and clears
+// them for every other document type.
+func applyContentMetadata(meta *model.Metadata, value interface{}, contentType string) {
+	data, ok := value.([]byte)
+	if meta.Type != model.DocTypeBlob || !ok {
+		meta.ContentType = ""
+		meta.Checksum = ""
+		meta.Size = 0
+		return
+	}
+
+	if contentType == "" {
+		contentType = DefaultBlobContentType
+	}
+	sum := sha256.Sum256(data)
+	meta.ContentType = contentType
+	meta.Checksum = hex.EncodeToString(sum[:])
+	meta.Size = int64(len(data))
+}
@@ -0,0 +1,205 @@
+package db
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"mithrildb/model"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Stored document formats, chosen with the DocumentFormat setting.
+const (
+	DocumentFormatJSON    = "json"
+	DocumentFormatMsgPack = "msgpack"
+)
+
+// encodeDocument serializes a document for storage in the configured format.
+//
+// Documents are persisted as JSON unless DocumentFormat is "msgpack". JSON keeps them readable
+// by every version but turns binary values and dates into strings; MessagePack keeps both, so
+// documents holding binary data are always written as MessagePack. Either way 64-bit integers
+// are exact.
+func (db *DB) encodeDocument(doc *model.Document) ([]byte, error) {
+	if db.rocksConfig.DocumentFormat == DocumentFormatMsgPack || holdsBinary(doc.Value) {
+		return encodeRecord(doc)
+	}
+	if f, ok := doc.Value.(float64); ok && doc.Meta.Type == model.DocTypeCounter {
+		// Whole floats would be written as integers and read back as an integer counter.
+		counter := *doc
+		counter.Value = floatNumber(f)
+		return json.Marshal(&counter)
+	}
+	return json.Marshal(doc)
+}
+
+// floatNumber formats a float so it reads back as a float, keeping a fraction on whole values.
+func floatNumber(f float64) json.Number {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return json.Number(s)
+}
+
+// holdsBinary reports whether a normalized value is or contains a []byte.
+func holdsBinary(value interface{}) bool {
+	switch v := value.(type) {
+	case []byte:
+		return true
+	case []interface{}:
+		for _, item := range v {
+			if holdsBinary(item) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			if holdsBinary(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// encodeRecord serializes a record of a system column family as MessagePack. Struct fields
+// reuse their JSON tags to keep the same field names on disk.
+func encodeRecord(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	enc.SetSortMapKeys(true)
+	enc.UseCompactInts(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeRecord parses a record written with encodeRecord.
+func decodeRecord(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+// decodeQueueMessage parses a message stored in a job queue.
+func decodeQueueMessage(data []byte, msg *model.QueueMessage) error {
+	if err := decodeRecord(data, msg); err != nil {
+		return err
+	}
+	body, err := model.NormalizeValue(msg.Body)
+	if err != nil {
+		return err
+	}
+	msg.Body = body
+	return nil
+}
+
+// decodeTrashedDocument parses a document stored in a trash column family.
+func decodeTrashedDocument(data []byte, entry *model.TrashedDocument) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	if err := dec.Decode(entry); err != nil {
+		return err
+	}
+
+	value, err := model.NormalizeValue(entry.Document.Value)
+	if err != nil {
+		return err
+	}
+	entry.Document.Value = value
+	return nil
+}
+
+// decodeElement parses a list or set element stored under its own key.
+func decodeElement(data []byte) (interface{}, error) {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	return model.NormalizeValue(value)
+}
+
+// decodeDocument parses a stored document in either format, so changing DocumentFormat keeps
+// existing documents readable.
+//
+// A document is always an object: JSON records start with '{' and MessagePack records with a
+// map header, which never overlap. JSON numbers are kept exact. Blobs written as JSON by earlier
+// versions hold base64 text and are read back as binary.
+func decodeDocument(data []byte, doc *model.Document) error {
+	if isMsgPackMap(data) {
+		dec := msgpack.NewDecoder(bytes.NewReader(data))
+		dec.SetCustomStructTag("json")
+		if err := dec.Decode(doc); err != nil {
+			return err
+		}
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(doc); err != nil {
+			return err
+		}
+	}
+
+	value, err := model.NormalizeValue(doc.Value)
+	if err != nil {
+		return err
+	}
+	if text, ok := value.(string); ok && doc.Meta.Type == model.DocTypeBlob {
+		if value, err = base64.StdEncoding.DecodeString(text); err != nil {
+			return err
+		}
+	}
+	doc.Value = value
+	return nil
+}
+
+// isMsgPackMap reports whether data starts with a MessagePack map header: a fixmap, map 16 or
+// map 32.
+func isMsgPackMap(data []byte) bool {
+	if len(data) == 0 {
+		return false
+	}
+	b := data[0]
+	return b&0xf0 == 0x80 || b == 0xde || b == 0xdf
+}
+
+// prepareDocumentValue validates a value for the given document type and converts it to the
+// canonical representation stored on disk.
+func prepareDocumentValue(value interface{}, docType string) (interface{}, error) {
+	if value == nil {
+		return nil, ErrNilValue
+	}
+	normalized, err := model.NormalizeValue(value)
+	if err != nil {
+		return nil, err
+	}
+	if err := model.ValidateValue(normalized, docType); err != nil {
+		return nil, fmt.Errorf("invalid value for type %s: %w", docType, err)
+	}
+	// Sorted sets are stored in score order.
+	if docType == model.DocTypeZSet {
+		z, _ := model.ParseZSet(normalized)
+		return z.Value(), nil
+	}
+	// Stream entry IDs are stored in "<ms>-<seq>" form.
+	if docType == model.DocTypeStream {
+		entries, _ := model.ParseStreamEntries(normalized)
+		return model.StreamValue(entries), nil
+	}
+	// Time series samples are stored in timestamp order.
+	if docType == model.DocTypeTimeSeries {
+		samples, _ := model.ParseTimeSeriesSamples(normalized)
+		return model.TimeSeriesValue(samples), nil
+	}
+	return normalized, nil
+}
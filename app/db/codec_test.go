@@ -0,0 +1,51 @@
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"mithrildb/config"
+	"mithrildb/model"
+)
+
+func TestBinaryValuesSurviveDefaultFormat(t *testing.T) {
+	database := &DB{rocksConfig: config.RocksDBConfig{DocumentFormat: DocumentFormatJSON}}
+	blob := []byte{0x00, 0xff, 0x10, 'a'}
+
+	tests := []*model.Document{
+		{Key: "b", Value: blob, Meta: model.Metadata{Type: model.DocTypeBlob}},
+		{Key: "j", Value: map[string]interface{}{"data": blob, "n": int64(1)}, Meta: model.Metadata{Type: model.DocTypeJSON}},
+	}
+	for _, doc := range tests {
+		data, err := database.encodeDocument(doc)
+		if err != nil {
+			t.Fatalf("%s: encodeDocument: %v", doc.Key, err)
+		}
+		var got model.Document
+		if err := decodeDocument(data, &got); err != nil {
+			t.Fatalf("%s: decodeDocument: %v", doc.Key, err)
+		}
+		value := got.Value
+		if m, ok := value.(map[string]interface{}); ok {
+			value = m["data"]
+		}
+		if b, ok := value.([]byte); !ok || !bytes.Equal(b, blob) {
+			t.Errorf("%s: read back %#v, want %v", doc.Key, got.Value, blob)
+		}
+	}
+}
+
+func TestLegacyJSONBlobReadsAsBinary(t *testing.T) {
+	data, err := json.Marshal(&model.Document{Key: "b", Value: []byte("hello"), Meta: model.Metadata{Type: model.DocTypeBlob}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got model.Document
+	if err := decodeDocument(data, &got); err != nil {
+		t.Fatalf("decodeDocument: %v", err)
+	}
+	if b, ok := got.Value.([]byte); !ok || string(b) != "hello" {
+		t.Fatalf("read back %#v, want []byte(\"hello\")", got.Value)
+	}
+}
@@ -1,114 +1,302 @@
 package db
 
 import (
-	"encoding/json"
 	"fmt"
+	"math"
 	"time"
 
 	"mithrildb/events"
 	"mithrildb/model"
 
-	"github.com/google/uuid"
 	"github.com/linxGnu/grocksdb"
 )
 
-// IncrementCounter atomically increments a counter document and returns both the old and new values.
-func (db *DB) IncrementCounter(opts CounterIncrementOptions) (oldVal, newVal int64, err error) {
-	handle, ok := db.Families[opts.ColumnFamily]
-	if !ok {
-		return 0, 0, ErrInvalidColumnFamily
-	}
+// CounterResult is the outcome of an operation on a counter document. Values are an int64, or
+// a float64 for float counters.
+type CounterResult struct {
+	Key     string      `json:"key"`
+	Old     interface{} `json:"old"`               // Previous value, or nil when the counter was created
+	New     interface{} `json:"new"`               // Value written
+	Created bool        `json:"created,omitempty"` // The counter did not exist and was created
+	Clamped bool        `json:"clamped,omitempty"` // The value was clamped to the bounds
+}
 
-	if err := model.ValidateDocumentKey(opts.Key); err != nil {
-		return 0, 0, err
+// IncrementCounter atomically increments a counter document and returns both the old and new
+// values. With an initial value, a missing counter is created with that value instead. The
+// value written must lie within the bounds, or is clamped to them.
+func (db *DB) IncrementCounter(opts CounterIncrementOptions) (*CounterResult, error) {
+	var result *CounterResult
+	err := db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		var err error
+		result, err = tc.incrementCounter(opts)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return result, nil
+}
 
-	txnOpts := grocksdb.NewDefaultTransactionOptions()
-	txnOpts.SetSetSnapshot(true)
-	defer txnOpts.Destroy()
-
-	txn := db.TransactionDB.TransactionBegin(opts.WriteOptions, txnOpts, nil)
-	defer txn.Destroy()
+// IncrementCounters atomically increments several counter documents of a column family, in
+// order, and returns the result of each. Either every counter is incremented or none is.
+func (db *DB) IncrementCounters(opts CounterMultiIncrementOptions) ([]CounterResult, error) {
+	if len(opts.Entries) == 0 {
+		return nil, fmt.Errorf("%w: no counters given", ErrInvalidCounterOperation)
+	}
 
-	readOpts := grocksdb.NewDefaultReadOptions()
-	readOpts.SetSnapshot(txn.GetSnapshot())
-	readOpts.SetFillCache(false)
-	defer readOpts.Destroy()
+	results := make([]CounterResult, len(opts.Entries))
+	err := db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		for i, e := range opts.Entries {
+			result, err := tc.incrementCounter(CounterIncrementOptions{
+				ColumnFamily:  opts.ColumnFamily,
+				Key:           e.Key,
+				Delta:         e.Delta,
+				Initial:       e.Initial,
+				CounterBounds: e.CounterBounds,
+				Cas:           e.Cas,
+				Expiration:    opts.Expiration,
+			})
+			if err != nil {
+				return fmt.Errorf("counter %q: %w", e.Key, err)
+			}
+			results[i] = *result
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
 
-	val, err := txn.GetWithCF(readOpts, handle, []byte(opts.Key))
+// incrementCounter applies a counter delta inside a transaction.
+func (tc *txnContext) incrementCounter(opts CounterIncrementOptions) (*CounterResult, error) {
+	delta, err := parseCounterOperand("delta", opts.Delta)
+	if err != nil {
+		return nil, err
+	}
+	if compareCounter(delta, int64(0)) == 0 {
+		return nil, fmt.Errorf("%w: delta must be a non-zero number", ErrInvalidCounterOperation)
+	}
+	bounds, err := parseCounterBounds(opts.CounterBounds)
+	if err != nil {
+		return nil, err
+	}
+	handle, err := tc.family(opts.ColumnFamily, opts.Key)
 	if err != nil {
-		txn.Rollback()
-		return 0, 0, err
+		return nil, err
 	}
-	defer val.Free()
 
-	var doc model.Document
+	doc, err := tc.getForWrite(handle, opts.ColumnFamily, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+	result := &CounterResult{Key: opts.Key}
 	var prevMeta *model.Metadata
-	if val.Exists() {
-		if err := json.Unmarshal(val.Data(), &doc); err != nil {
-			txn.Rollback()
-			return 0, 0, fmt.Errorf("failed to decode existing counter: %w", err)
+	if doc == nil {
+		if opts.Initial == nil {
+			return nil, ErrKeyNotFound
+		}
+		if opts.Cas != "" {
+			return nil, ErrRevisionMismatch
+		}
+		if result.New, err = parseCounterOperand("initial", opts.Initial); err != nil {
+			return nil, err
 		}
+		result.Created = true
+		doc = &model.Document{Key: opts.Key, Meta: model.Metadata{Type: model.DocTypeCounter}}
+	} else {
 		if doc.Meta.Type != model.DocTypeCounter {
-			txn.Rollback()
-			return 0, 0, model.ErrInvalidCounterType
+			return nil, model.ErrInvalidCounterType
+		}
+		if opts.Cas != "" && doc.Meta.Rev != opts.Cas {
+			return nil, ErrRevisionMismatch
 		}
-		if model.IsExpired(doc.Meta) {
-			txn.Rollback()
-			return 0, 0, ErrKeyNotFound
+		if result.Old, err = model.ParseCounterNumber(doc.Value); err != nil {
+			return nil, err
 		}
-		oldVal, err = model.ParseCounterValue(doc.Value)
-		if err != nil {
-			txn.Rollback()
-			return 0, 0, err
+		if result.New, err = addCounter(result.Old, delta); err != nil {
+			return nil, err
 		}
 		metaCopy := doc.Meta
 		prevMeta = &metaCopy
-	} else {
-		return 0, 0, ErrKeyNotFound
 	}
 
-	newVal = oldVal + opts.Delta
-	doc.Value = newVal
-	doc.Meta.Rev = uuid.NewString()
+	if result.New, result.Clamped, err = bounds.apply(result.New); err != nil {
+		return nil, err
+	}
+	if err := tc.writeCounter(handle, opts.ColumnFamily, doc, result.New, prevMeta, opts.Expiration); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// writeCounter stores a new value of a counter document, created when prevMeta is nil.
+func (tc *txnContext) writeCounter(handle *grocksdb.ColumnFamilyHandle, cf string, doc *model.Document, value interface{}, prevMeta *model.Metadata, expiration *int64) error {
+	doc.Value = value
 	doc.Meta.UpdatedAt = time.Now()
 
-	if opts.Expiration != nil {
-		if err := model.ValidateExpiration(*opts.Expiration); err != nil {
-			txn.Rollback()
-			return 0, 0, err
+	if expiration != nil {
+		if err := model.ValidateExpiration(*expiration); err != nil {
+			return err
+		}
+		doc.Meta.Expiration = *expiration
+	}
+
+	operation := events.OpMutate
+	if prevMeta == nil {
+		operation = events.OpPut
+	}
+	return tc.writeDocument(handle, cf, doc, events.ChangeEventOptions{
+		Operation:          operation,
+		PreviousMeta:       prevMeta,
+		ExplicitExpiration: expiration,
+	})
+}
+
+// counterBounds are the parsed bounds of a counter operation.
+type counterBounds struct {
+	min, max interface{}
+	clamp    bool
+}
+
+// parseCounterBounds validates the bounds of a counter operation.
+func parseCounterBounds(b CounterBounds) (counterBounds, error) {
+	bounds := counterBounds{clamp: b.Clamp}
+	var err error
+	if b.Min != nil {
+		if bounds.min, err = parseCounterOperand("min", b.Min); err != nil {
+			return bounds, err
 		}
-		doc.Meta.Expiration = *opts.Expiration
 	}
+	if b.Max != nil {
+		if bounds.max, err = parseCounterOperand("max", b.Max); err != nil {
+			return bounds, err
+		}
+	}
+	if bounds.min != nil && bounds.max != nil && compareCounter(bounds.min, bounds.max) > 0 {
+		return bounds, fmt.Errorf("%w: min cannot be greater than max", ErrInvalidCounterOperation)
+	}
+	return bounds, nil
+}
 
-	data, err := json.Marshal(doc)
+// apply checks a counter value against the bounds, clamping it to them when clamp is set.
+func (b counterBounds) apply(value interface{}) (interface{}, bool, error) {
+	var limit interface{}
+	switch {
+	case b.min != nil && compareCounter(value, b.min) < 0:
+		limit = b.min
+	case b.max != nil && compareCounter(value, b.max) > 0:
+		limit = b.max
+	default:
+		return value, false, nil
+	}
+	if !b.clamp {
+		return nil, false, fmt.Errorf("%w: %v is outside [%v, %v]", ErrCounterOutOfRange, value, boundString(b.min), boundString(b.max))
+	}
+	clamped, err := clampCounter(value, limit)
 	if err != nil {
-		txn.Rollback()
-		return 0, 0, fmt.Errorf("failed to serialize new counter value: %w", err)
+		return nil, false, err
 	}
+	return clamped, true, nil
+}
 
-	if err := txn.PutCF(handle, []byte(opts.Key), data); err != nil {
-		txn.Rollback()
-		return 0, 0, fmt.Errorf("failed to update counter: %w", err)
+// clampCounter converts the bound a value is clamped to into the type of the value, so
+// clamping never changes the type of a counter. Integer counters are clamped to the nearest
+// integer within a float bound.
+func clampCounter(value, limit interface{}) (interface{}, error) {
+	if _, ok := value.(float64); ok {
+		return counterFloat(limit), nil
 	}
+	f, ok := limit.(float64)
+	if !ok {
+		return limit, nil
+	}
+	if compareCounter(value, f) < 0 {
+		f = math.Ceil(f)
+	} else {
+		f = math.Floor(f)
+	}
+	if f < math.MinInt64 || f >= math.MaxInt64 {
+		return nil, fmt.Errorf("%w: bound %v does not fit an integer counter", ErrCounterOutOfRange, limit)
+	}
+	return int64(f), nil
+}
 
-	if err := events.PublishChangeEvent(events.ChangeEventOptions{
-		Txn:                txn,
-		CFName:             opts.ColumnFamily,
-		Key:                opts.Key,
-		Document:           &doc,
-		Operation:          events.OpMutate,
-		PreviousMeta:       prevMeta,
-		ExplicitExpiration: opts.Expiration,
-	}); err != nil {
-		txn.Rollback()
-		return 0, 0, fmt.Errorf("failed to enqueue change event: %w", err)
+// boundString formats a counter bound for error messages.
+func boundString(bound interface{}) string {
+	if bound == nil {
+		return "unbounded"
+	}
+	return fmt.Sprint(bound)
+}
+
+// parseCounterOperand converts a delta, initial value or bound given to a counter operation.
+func parseCounterOperand(name string, value interface{}) (interface{}, error) {
+	n, err := model.NormalizeValue(value)
+	if err == nil {
+		n, err = model.ParseCounterNumber(n)
 	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s must be a finite number", ErrInvalidCounterOperation, name)
+	}
+	return n, nil
+}
 
-	if err := txn.Commit(); err != nil {
-		txn.Rollback()
-		return 0, 0, fmt.Errorf("failed to commit transaction: %w", err)
+// addCounter adds two counter values. Integers stay integers while the sum fits an int64, and
+// sums involving a float are floats, even when they are whole. Integers beyond ±2^53 are not
+// added to floats, as the sum would silently lose precision.
+func addCounter(a, b interface{}) (interface{}, error) {
+	x, xInt := a.(int64)
+	y, yInt := b.(int64)
+	if xInt && yInt {
+		if (y > 0 && x > math.MaxInt64-y) || (y < 0 && x < math.MinInt64-y) {
+			return nil, ErrCounterOverflow
+		}
+		return x + y, nil
+	}
+	if (xInt && !exactFloat(x)) || (yInt && !exactFloat(y)) {
+		return nil, fmt.Errorf("%w: integer counters beyond ±2^53 only accept integer deltas", ErrInvalidCounterOperation)
 	}
+	sum := counterFloat(a) + counterFloat(b)
+	if math.IsInf(sum, 0) {
+		return nil, ErrCounterOverflow
+	}
+	return sum, nil
+}
 
-	return oldVal, newVal, nil
+// exactFloat reports whether an integer converts to a float64 without losing precision.
+func exactFloat(n int64) bool {
+	return n <= model.MaxSafeFloatInteger && n >= -model.MaxSafeFloatInteger
+}
+
+// compareCounter compares two counter values, returning -1, 0 or 1.
+func compareCounter(a, b interface{}) int {
+	x, xInt := a.(int64)
+	y, yInt := b.(int64)
+	if xInt && yInt {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	fx, fy := counterFloat(a), counterFloat(b)
+	switch {
+	case fx < fy:
+		return -1
+	case fx > fy:
+		return 1
+	}
+	return 0
+}
+
+// counterFloat converts a counter value to a float64.
+func counterFloat(v interface{}) float64 {
+	if n, ok := v.(int64); ok {
+		return float64(n)
+	}
+	return v.(float64)
 }
@@ -0,0 +1,51 @@
+package db
+
+import "mithrildb/model"
+
+// SetCounter atomically replaces the value of a counter document, creating it if needed, and
+// returns the previous value along with the new one. Setting 0 resets the counter.
+func (db *DB) SetCounter(opts CounterSetOptions) (*CounterResult, error) {
+	value, err := parseCounterOperand("value", opts.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *CounterResult
+	err = db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		handle, err := tc.family(opts.ColumnFamily, opts.Key)
+		if err != nil {
+			return err
+		}
+		doc, err := tc.getForWrite(handle, opts.ColumnFamily, opts.Key)
+		if err != nil {
+			return err
+		}
+
+		result = &CounterResult{Key: opts.Key, New: value}
+		var prevMeta *model.Metadata
+		if doc == nil {
+			if opts.Cas != "" {
+				return ErrRevisionMismatch
+			}
+			result.Created = true
+			doc = &model.Document{Key: opts.Key, Meta: model.Metadata{Type: model.DocTypeCounter}}
+		} else {
+			if doc.Meta.Type != model.DocTypeCounter {
+				return model.ErrInvalidCounterType
+			}
+			if opts.Cas != "" && doc.Meta.Rev != opts.Cas {
+				return ErrRevisionMismatch
+			}
+			if result.Old, err = model.ParseCounterNumber(doc.Value); err != nil {
+				return err
+			}
+			metaCopy := doc.Meta
+			prevMeta = &metaCopy
+		}
+		return tc.writeCounter(handle, opts.ColumnFamily, doc, value, prevMeta, opts.Expiration)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
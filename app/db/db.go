@@ -3,6 +3,7 @@ package db
 import (
 	"fmt"
 	"mithrildb/config"
+	"mithrildb/model"
 	"os"
 	"strings"
 	"sync"
@@ -16,8 +17,16 @@ type DB struct {
 	TransactionDB       *grocksdb.TransactionDB
 	DefaultReadOptions  *grocksdb.ReadOptions
 	DefaultWriteOptions *grocksdb.WriteOptions
-	Families            map[string]*grocksdb.ColumnFamilyHandle
-	mu                  sync.Mutex
+	Families            map[string]*grocksdb.ColumnFamilyHandle // Read through Family
+	Transactions        *TransactionManager
+	Snapshots           *SnapshotManager
+	Queues              *QueueManager
+	clock               model.HLCClock
+	rocksConfig         config.RocksDBConfig
+	settings            map[string]model.FamilySettings
+	settingsMu          sync.RWMutex
+	listWaiters         listWaiters
+	mu                  sync.RWMutex // Guards Families
 }
 
 // NewDB creates a DB instance with default read and write options based on the application config.
@@ -35,16 +44,27 @@ func NewDB(rocks *grocksdb.TransactionDB, families map[string]*grocksdb.ColumnFa
 	writeOpts.DisableWAL(cfg.WriteDefaults.DisableWAL)
 	writeOpts.SetNoSlowdown(cfg.WriteDefaults.NoSlowdown)
 
-	return &DB{
+	database := &DB{
 		TransactionDB:       rocks,
 		Families:            families,
 		DefaultReadOptions:  readOpts,
 		DefaultWriteOptions: writeOpts,
+		settings:            make(map[string]model.FamilySettings),
 	}
+	if cfg.RocksDB != nil {
+		database.rocksConfig = *cfg.RocksDB
+	}
+	return database
 }
 
 // Close releases resources associated with the database.
 func (db *DB) Close() {
+	if db.Transactions != nil {
+		db.Transactions.Close()
+	}
+	if db.Snapshots != nil {
+		db.Snapshots.Close()
+	}
 	db.DefaultReadOptions.Destroy()
 	db.DefaultWriteOptions.Destroy()
 	db.TransactionDB.Close()
@@ -70,8 +90,7 @@ func OpenRocksDBWithConfig(cfg config.RocksDBConfig) (*grocksdb.TransactionDB, m
 	bbto.SetBlockCache(cache)
 	opts.SetBlockBasedTableFactory(bbto)
 
-	// Compression
-	opts.SetCompression(parseCompressionType(cfg.CompressionType, cfg.EnableCompression))
+	applyColumnFamilyOptions(opts, cfg)
 
 	txnOpts := grocksdb.NewDefaultTransactionDBOptions()
 	dbPath := cfg.DBPath
@@ -105,6 +124,24 @@ func OpenRocksDBWithConfig(cfg config.RocksDBConfig) (*grocksdb.TransactionDB, m
 	return db, handles, nil
 }
 
+// applyColumnFamilyOptions sets the per column family storage options derived from the config.
+func applyColumnFamilyOptions(opts *grocksdb.Options, cfg config.RocksDBConfig) {
+	// Compression
+	opts.SetCompression(parseCompressionType(cfg.CompressionType, cfg.EnableCompression))
+
+	// BlobDB: keep large values out of the LSM tree
+	opts.EnableBlobFiles(cfg.EnableBlobFiles)
+	if cfg.EnableBlobFiles {
+		if cfg.MinBlobSize > 0 {
+			opts.SetMinBlobSize(uint64(cfg.MinBlobSize))
+		}
+		if cfg.BlobFileSize > 0 {
+			opts.SetBlobFileSize(uint64(cfg.BlobFileSize))
+		}
+		opts.EnableBlobGC(cfg.EnableBlobGC)
+	}
+}
+
 // parseCompressionType converts a string compression type to the corresponding RocksDB constant.
 func parseCompressionType(name string, enabled bool) grocksdb.CompressionType {
 	if !enabled {
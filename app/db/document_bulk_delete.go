@@ -0,0 +1,107 @@
+package db
+
+import (
+	"fmt"
+
+	"mithrildb/events"
+
+	"github.com/linxGnu/grocksdb"
+)
+
+// BulkDeleteDocuments removes multiple documents in a single transaction. Either every entry
+// is deleted or none is; the error identifies the failing entry as a *BatchError.
+func (db *DB) BulkDeleteDocuments(opts BulkKeyOptions) error {
+	if len(opts.Entries) == 0 {
+		return fmt.Errorf("%w: no keys given", ErrInvalidBatchOperation)
+	}
+
+	return db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		for i, entry := range opts.Entries {
+			err := tc.deleteDocument(DocumentDeleteOptions{
+				ColumnFamily: opts.ColumnFamily,
+				Key:          entry.Key,
+				Cas:          entry.Cas,
+				Actor:        opts.Actor,
+			})
+			if err != nil {
+				return &BatchError{Index: i, Op: BatchOpDelete, Err: err}
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteDocumentRange purges every document whose key falls in a prefix or key range using a
+// RocksDB range tombstone, without reading the documents first.
+//
+// The range delete bypasses transaction locks, so it should not race with writers of the same
+// keys. It also skips the trash and history of the column family: purged documents cannot be
+// restored, and no revision records their deletion. A single OpDeleteRange change event is
+// published afterwards, from which the expiration listener drops the TTL entries of the range.
+func (db *DB) DeleteDocumentRange(opts RangeDeleteOptions) (start, end string, err error) {
+	handle, ok := db.Family(opts.ColumnFamily)
+	if !ok {
+		return "", "", ErrInvalidColumnFamily
+	}
+
+	start, end = opts.Start, opts.End
+	if opts.Prefix != "" {
+		if start != "" || end != "" {
+			return "", "", fmt.Errorf("%w: use either prefix or start/end", ErrInvalidKeyRange)
+		}
+		start = opts.Prefix
+		end = prefixEnd(opts.Prefix)
+		if end == "" {
+			return "", "", fmt.Errorf("%w: prefix has no upper bound", ErrInvalidKeyRange)
+		}
+	}
+	if start == "" || end == "" || start >= end {
+		return "", "", fmt.Errorf("%w: start must be lower than end", ErrInvalidKeyRange)
+	}
+
+	writeOpts := opts.WriteOptions
+	if writeOpts == nil {
+		writeOpts = db.DefaultWriteOptions
+	}
+
+	base := db.TransactionDB.GetBaseDB()
+	defer grocksdb.CloseBaseDBOfTransactionDB(base)
+
+	if err := base.DeleteRangeCF(writeOpts, handle, []byte(start), []byte(end)); err != nil {
+		return "", "", fmt.Errorf("failed to delete range: %w", err)
+	}
+	// Element keys start with "<cf>\x00<key>\x00", so the same key range covers the elements
+	// of the deleted lists and sets.
+	if elements, ok := db.Family(CFSystemElements); ok {
+		prefix := opts.ColumnFamily + "\x00"
+		if err := base.DeleteRangeCF(writeOpts, elements, []byte(prefix+start), []byte(prefix+end)); err != nil {
+			return "", "", fmt.Errorf("failed to delete element range: %w", err)
+		}
+	}
+
+	err = db.runInTransaction("", writeOpts, func(tc *txnContext) error {
+		return tc.publish(events.ChangeEventOptions{
+			CFName:    opts.ColumnFamily,
+			Key:       start,
+			RangeEnd:  end,
+			Operation: events.OpDeleteRange,
+		})
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to enqueue delete range event: %w", err)
+	}
+	return start, end, nil
+}
+
+// prefixEnd returns the smallest key greater than every key starting with prefix, or "" when
+// no such key exists.
+func prefixEnd(prefix string) string {
+	b := []byte(prefix)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < 0xff {
+			b[i]++
+			return string(b[:i+1])
+		}
+	}
+	return ""
+}
@@ -1,7 +1,6 @@
 package db
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"mithrildb/model"
@@ -9,7 +8,7 @@ import (
 
 // BulkGetDocuments retrieves multiple documents by key from the given column family.
 func (db *DB) BulkGetDocuments(opts BulkReadOptions) (map[string]*model.Document, error) {
-	handle, ok := db.Families[opts.ColumnFamily]
+	handle, ok := db.Family(opts.ColumnFamily)
 	if !ok {
 		return nil, ErrInvalidColumnFamily
 	}
@@ -44,14 +43,17 @@ func (db *DB) BulkGetDocuments(opts BulkReadOptions) (map[string]*model.Document
 			result[opts.Keys[i]] = nil
 		} else {
 			var doc model.Document
-			if err := json.Unmarshal(val.Data(), &doc); err != nil {
+			if err := decodeDocument(val.Data(), &doc); err != nil {
 				return nil, fmt.Errorf("failed to decode document for key '%s': %w", opts.Keys[i], err)
 			}
 			if model.IsExpired(doc.Meta) {
 				result[opts.Keys[i]] = nil
-			} else {
-				result[opts.Keys[i]] = &doc
+				continue
 			}
+			if err := db.loadElements(opts.ReadOptions, opts.ColumnFamily, &doc); err != nil {
+				return nil, err
+			}
+			result[opts.Keys[i]] = &doc
 		}
 	}
 
@@ -1,96 +1,75 @@
 package db
 
 import (
-	"encoding/json"
 	"fmt"
-	"time"
 
-	"mithrildb/events"
 	"mithrildb/model"
-
-	"github.com/google/uuid"
-	"github.com/linxGnu/grocksdb"
 )
 
-// BulkPutDocuments writes multiple documents in a single atomic transaction.
-// All documents must be valid and optionally share a common expiration.
-func (db *DB) BulkPutDocuments(opts BulkWriteOptions) error {
-	handle, ok := db.Families[opts.ColumnFamily]
-	if !ok {
-		return ErrInvalidColumnFamily
-	}
+// BulkPutResult reports the outcome of one bulk put entry.
+type BulkPutResult struct {
+	Key      string
+	Document *model.Document // Stored document, when the entry succeeded
+	Err      error           // Failure of the entry in partial mode
+}
 
-	if opts.Expiration != nil {
-		if err := model.ValidateExpiration(*opts.Expiration); err != nil {
-			return err
-		}
+// BulkPutDocuments writes multiple documents in a single transaction, in entry order.
+//
+// By default either every entry is written or none is, and the error identifies the failing
+// entry as a *BatchError. In partial mode a failing entry is undone on its own, reported in its
+// result, and the remaining entries are still committed.
+func (db *DB) BulkPutDocuments(opts BulkWriteOptions) ([]BulkPutResult, error) {
+	if len(opts.Entries) == 0 {
+		return nil, fmt.Errorf("%w: no documents given", ErrInvalidBatchOperation)
 	}
 
-	txnOpts := grocksdb.NewDefaultTransactionOptions()
-	txnOpts.SetSetSnapshot(true)
-	defer txnOpts.Destroy()
-
-	txn := db.TransactionDB.TransactionBegin(opts.WriteOptions, txnOpts, nil)
-	defer txn.Destroy()
-
-	now := time.Now()
-
-	for k, rawValue := range opts.Documents {
-		if err := model.ValidateDocumentKey(k); err != nil {
-			txn.Rollback()
-			return fmt.Errorf("invalid key '%s': %w", k, err)
-		}
-		if err := model.ValidateValue(rawValue, model.DocTypeJSON); err != nil {
-			txn.Rollback()
-			return fmt.Errorf("invalid value for key '%s': %w", k, err)
-		}
-
-		exp := int64(0)
-		if opts.Expiration != nil {
-			exp = *opts.Expiration
-		}
-
-		doc := model.Document{
-			Key:   k,
-			Value: rawValue,
-			Meta: model.Metadata{
-				Rev:        uuid.NewString(),
-				Type:       model.DocTypeJSON,
-				UpdatedAt:  now,
-				Expiration: exp,
-			},
-		}
-
-		data, err := json.Marshal(doc)
-		if err != nil {
-			txn.Rollback()
-			return fmt.Errorf("failed to encode document for key '%s': %w", k, err)
-		}
-
-		if err := txn.PutCF(handle, []byte(k), data); err != nil {
-			txn.Rollback()
-			return fmt.Errorf("failed to write document for key '%s': %w", k, err)
-		}
-
-		// Enqueue event for each document written
-		if err := events.PublishChangeEvent(events.ChangeEventOptions{
-			Txn:                txn,
-			CFName:             opts.ColumnFamily,
-			Key:                k,
-			Document:           &doc,
-			Operation:          events.OpPut,
-			PreviousMeta:       nil,
-			ExplicitExpiration: opts.Expiration,
-		}); err != nil {
-			txn.Rollback()
-			return fmt.Errorf("failed to enqueue change event for key '%s': %w", k, err)
+	results := make([]BulkPutResult, len(opts.Entries))
+	err := db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		for i, entry := range opts.Entries {
+			results[i] = BulkPutResult{Key: entry.Key}
+
+			var sp txnSavePoint
+			if opts.Partial {
+				sp = tc.setSavePoint()
+			}
+
+			doc, err := tc.putDocument(bulkEntryOptions(opts, entry))
+			if err == nil {
+				results[i].Document = doc
+				continue
+			}
+			if !opts.Partial {
+				return &BatchError{Index: i, Op: BatchOpPut, Err: err}
+			}
+			if rbErr := tc.rollbackToSavePoint(sp); rbErr != nil {
+				return rbErr
+			}
+			results[i].Err = err
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return results, nil
+}
 
-	if err := txn.Commit(); err != nil {
-		txn.Rollback()
-		return fmt.Errorf("failed to commit BulkPutDocuments: %w", err)
+// bulkEntryOptions applies the bulk defaults to an entry.
+func bulkEntryOptions(opts BulkWriteOptions, entry BulkPutEntry) DocumentWriteOptions {
+	docType := entry.Type
+	if docType == "" {
+		docType = model.DocTypeJSON
+	}
+	expiration := entry.Expiration
+	if expiration == nil {
+		expiration = opts.Expiration
+	}
+	return DocumentWriteOptions{
+		ColumnFamily: opts.ColumnFamily,
+		Key:          entry.Key,
+		Value:        entry.Value,
+		Type:         docType,
+		Cas:          entry.Cas,
+		Expiration:   expiration,
 	}
-
-	return nil
 }
@@ -0,0 +1,42 @@
+package db
+
+import (
+	"fmt"
+
+	"mithrildb/model"
+)
+
+// BulkTouchDocuments updates the expiration of multiple documents in a single transaction.
+// Each entry uses its own expiration when set and the shared one otherwise.
+// Either every document is touched or none is; the error identifies the failing entry as a
+// *BatchError.
+func (db *DB) BulkTouchDocuments(opts BulkKeyOptions) ([]*model.Document, error) {
+	if len(opts.Entries) == 0 {
+		return nil, fmt.Errorf("%w: no keys given", ErrInvalidBatchOperation)
+	}
+
+	docs := make([]*model.Document, len(opts.Entries))
+	err := db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		for i, entry := range opts.Entries {
+			expiration := entry.Expiration
+			if expiration == nil {
+				expiration = opts.Expiration
+			}
+			doc, err := tc.touchDocument(DocumentWriteOptions{
+				ColumnFamily: opts.ColumnFamily,
+				Key:          entry.Key,
+				Cas:          entry.Cas,
+				Expiration:   expiration,
+			})
+			if err != nil {
+				return &BatchError{Index: i, Op: BatchOpTouch, Err: err}
+			}
+			docs[i] = doc
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
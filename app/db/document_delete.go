@@ -3,48 +3,69 @@ package db
 import (
 	"fmt"
 	"mithrildb/events"
-	"mithrildb/model"
-
-	"github.com/linxGnu/grocksdb"
 )
 
 // DeleteDocument removes a document and its TTL index (if any) in an atomic transaction.
 func (db *DB) DeleteDocument(opts DocumentDeleteOptions) error {
-	handle, ok := db.Families[opts.ColumnFamily]
-	if !ok {
-		return ErrInvalidColumnFamily
-	}
-	if err := model.ValidateDocumentKey(opts.Key); err != nil {
+	return db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		return tc.deleteDocument(opts)
+	})
+}
+
+// deleteDocument removes a document inside a transaction. When a CAS is given, or MustExist
+// is set, the document must exist, with that revision, or the delete fails with
+// ErrRevisionMismatch like any other failed precondition. Unless the delete is permanent,
+// column families with a trash keep the document there. The delete event carries the revision
+// the deletion would have had, so consumers can order it after the document's last write.
+func (tc *txnContext) deleteDocument(opts DocumentDeleteOptions) error {
+	handle, err := tc.family(opts.ColumnFamily, opts.Key)
+	if err != nil {
 		return err
 	}
 
-	txnOpts := grocksdb.NewDefaultTransactionOptions()
-	txnOpts.SetSetSnapshot(true)
-	defer txnOpts.Destroy()
-
-	txn := db.TransactionDB.TransactionBegin(opts.WriteOptions, txnOpts, nil)
-	defer txn.Destroy()
-
-	if err := txn.DeleteCF(handle, []byte(opts.Key)); err != nil {
-		txn.Rollback()
-		return fmt.Errorf("failed to delete document: %w", err)
+	existing, err := tc.getForWrite(handle, opts.ColumnFamily, opts.Key)
+	if err != nil {
+		return err
+	}
+	if existing == nil && (opts.Cas != "" || opts.MustExist) {
+		return ErrRevisionMismatch
+	}
+	if opts.Cas != "" && existing.Meta.Rev != opts.Cas {
+		return ErrRevisionMismatch
 	}
 
-	// Publicar evento de eliminación sin documento ni expiración
-	if err := events.PublishChangeEvent(events.ChangeEventOptions{
-		Txn:       txn,
+	event := events.ChangeEventOptions{
 		CFName:    opts.ColumnFamily,
 		Key:       opts.Key,
 		Operation: events.OpDelete,
-		Document:  nil,
-	}); err != nil {
-		txn.Rollback()
-		return fmt.Errorf("failed to enqueue delete event: %w", err)
+	}
+	if existing != nil {
+		event.PreviousMeta = &existing.Meta
+		event.Seq, event.HLC = tc.nextRevision(existing.Meta)
+	}
+
+	if err := tc.txn.DeleteCF(handle, []byte(opts.Key)); err != nil {
+		return fmt.Errorf("failed to delete document: %w", conflictError(err))
+	}
+	if existing != nil {
+		if !opts.Permanent {
+			if err := tc.moveToTrash(opts.ColumnFamily, existing, opts.Actor); err != nil {
+				return err
+			}
+		}
+		if err := tc.recordHistory(opts.ColumnFamily, opts.Key, event.Seq, event.HLC, nil); err != nil {
+			return err
+		}
+		if existing.Meta.Elements != nil {
+			if err := tc.clearElements(opts.ColumnFamily, opts.Key, existing.Meta.Elements); err != nil {
+				return err
+			}
+		}
 	}
 
-	if err := txn.Commit(); err != nil {
-		txn.Rollback()
-		return fmt.Errorf("failed to commit delete: %w", err)
+	// Publicar evento de eliminación sin documento ni expiración
+	if err := tc.publish(event); err != nil {
+		return fmt.Errorf("failed to enqueue delete event: %w", err)
 	}
 
 	return nil
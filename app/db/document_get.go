@@ -1,15 +1,38 @@
 package db
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"mithrildb/model"
 )
 
 // GetDocument retrieves a full document by key from a specific column family.
+//
+// Inside an interactive transaction the document is read and locked with GetForUpdate, so it
+// reflects the transaction's own writes and cannot change until the transaction ends.
 func (db *DB) GetDocument(opts DocumentReadOptions) (*model.Document, error) {
-	handle, ok := db.Families[opts.ColumnFamily]
+	if opts.TxnID != "" {
+		var doc *model.Document
+		err := db.runInTransaction(opts.TxnID, nil, func(tc *txnContext) error {
+			handle, err := tc.family(opts.ColumnFamily, opts.Key)
+			if err != nil {
+				return err
+			}
+			if doc, err = tc.getForUpdate(handle, opts.Key); err != nil {
+				return err
+			}
+			if doc == nil {
+				return ErrKeyNotFound
+			}
+			return tc.loadElements(opts.ColumnFamily, doc)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return doc, nil
+	}
+
+	handle, ok := db.Family(opts.ColumnFamily)
 	if !ok {
 		return nil, ErrInvalidColumnFamily
 	}
@@ -29,13 +52,16 @@ func (db *DB) GetDocument(opts DocumentReadOptions) (*model.Document, error) {
 	}
 
 	var doc model.Document
-	if err := json.Unmarshal(value.Data(), &doc); err != nil {
+	if err := decodeDocument(value.Data(), &doc); err != nil {
 		return nil, fmt.Errorf("failed to decode stored document: %w", err)
 	}
 
 	if model.IsExpired(doc.Meta) {
 		return nil, ErrKeyNotFound
 	}
+	if err := db.loadElements(opts.ReadOptions, opts.ColumnFamily, &doc); err != nil {
+		return nil, err
+	}
 
 	return &doc, nil
 }
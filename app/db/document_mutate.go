@@ -0,0 +1,74 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"mithrildb/events"
+	"mithrildb/model"
+)
+
+// MutateDocument applies a list of sub-document operations to a JSON document in a single transaction.
+//
+// Either every operation is applied or none is; a single OpMutate change event is emitted.
+func (db *DB) MutateDocument(opts DocumentMutateOptions) (*model.Document, error) {
+	var doc *model.Document
+	err := db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		var err error
+		doc, err = tc.mutateDocument(opts)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// mutateDocument applies sub-document operations inside a transaction.
+func (tc *txnContext) mutateDocument(opts DocumentMutateOptions) (*model.Document, error) {
+	if len(opts.Operations) == 0 {
+		return nil, fmt.Errorf("%w: no operations given", model.ErrInvalidMutation)
+	}
+
+	ops := make([]model.MutationOp, len(opts.Operations))
+	for i, op := range opts.Operations {
+		value, err := model.NormalizeValue(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		op.Value = value
+		ops[i] = op
+	}
+
+	if opts.Expiration != nil {
+		if err := model.ValidateExpiration(*opts.Expiration); err != nil {
+			return nil, err
+		}
+	}
+
+	return tc.updateIfExists(DocumentWriteOptions{
+		ColumnFamily: opts.ColumnFamily,
+		Key:          opts.Key,
+		Cas:          opts.Cas,
+		Expiration:   opts.Expiration,
+	}, events.ChangeEventOptions{Operation: events.OpMutate}, func(doc *model.Document) error {
+		if doc.Meta.Type != model.DocTypeJSON {
+			return fmt.Errorf("%w: document type %s does not support path operations", model.ErrInvalidMutation, doc.Meta.Type)
+		}
+
+		value, err := model.ApplyMutations(doc.Value, ops)
+		if err != nil {
+			return err
+		}
+		if err := model.ValidateValue(value, model.DocTypeJSON); err != nil {
+			return fmt.Errorf("%w: %v", model.ErrInvalidMutation, err)
+		}
+
+		doc.Value = value
+		if opts.Expiration != nil {
+			doc.Meta.Expiration = *opts.Expiration
+		}
+		doc.Meta.UpdatedAt = time.Now().UTC()
+		return nil
+	})
+}
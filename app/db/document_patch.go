@@ -0,0 +1,117 @@
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"mithrildb/events"
+	"mithrildb/model"
+)
+
+// PatchDocument applies an RFC 7396 merge patch or an RFC 6902 JSON patch to an existing JSON document.
+//
+// The patch is applied inside a transaction and recorded in the OpPatch change event.
+func (db *DB) PatchDocument(opts DocumentPatchOptions) (*model.Document, error) {
+	var doc *model.Document
+	err := db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		var err error
+		doc, err = tc.patchDocument(opts)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// patchDocument applies a patch to an existing document inside a transaction.
+func (tc *txnContext) patchDocument(opts DocumentPatchOptions) (*model.Document, error) {
+	apply, err := parsePatch(opts.PatchType, opts.Patch)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Expiration != nil {
+		if err := model.ValidateExpiration(*opts.Expiration); err != nil {
+			return nil, err
+		}
+	}
+
+	return tc.updateIfExists(DocumentWriteOptions{
+		ColumnFamily: opts.ColumnFamily,
+		Key:          opts.Key,
+		Cas:          opts.Cas,
+		Expiration:   opts.Expiration,
+	}, events.ChangeEventOptions{
+		Operation: events.OpPatch,
+		PatchType: opts.PatchType,
+		Patch:     json.RawMessage(opts.Patch),
+	}, func(doc *model.Document) error {
+		if doc.Meta.Type != model.DocTypeJSON {
+			return fmt.Errorf("%w: document type %s cannot be patched", model.ErrInvalidPatch, doc.Meta.Type)
+		}
+
+		value, err := apply(doc.Value)
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			return ErrNilValue
+		}
+		if err := model.ValidateValue(value, model.DocTypeJSON); err != nil {
+			return fmt.Errorf("%w: %v", model.ErrInvalidPatch, err)
+		}
+
+		doc.Value = value
+		if opts.Expiration != nil {
+			doc.Meta.Expiration = *opts.Expiration
+		}
+		doc.Meta.UpdatedAt = time.Now().UTC()
+		return nil
+	})
+}
+
+// parsePatch decodes a patch document and returns a function that applies it to a value.
+func parsePatch(patchType string, raw []byte) (func(interface{}) (interface{}, error), error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	switch patchType {
+	case model.PatchTypeMerge:
+		var patch interface{}
+		if err := dec.Decode(&patch); err != nil {
+			return nil, fmt.Errorf("%w: %v", model.ErrInvalidPatch, err)
+		}
+		patch, err := model.NormalizeValue(patch)
+		if err != nil {
+			return nil, err
+		}
+		return func(value interface{}) (interface{}, error) {
+			return model.ApplyMergePatch(value, patch), nil
+		}, nil
+
+	case model.PatchTypeJSON:
+		var ops []model.PatchOp
+		if err := dec.Decode(&ops); err != nil {
+			return nil, fmt.Errorf("%w: %v", model.ErrInvalidPatch, err)
+		}
+		if len(ops) == 0 {
+			return nil, fmt.Errorf("%w: no operations given", model.ErrInvalidPatch)
+		}
+		for i := range ops {
+			value, err := model.NormalizeValue(ops[i].Value)
+			if err != nil {
+				return nil, err
+			}
+			ops[i].Value = value
+		}
+		return func(value interface{}) (interface{}, error) {
+			return model.ApplyJSONPatch(value, ops)
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: unsupported patch type %q", model.ErrInvalidPatch, patchType)
+	}
+}
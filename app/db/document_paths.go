@@ -0,0 +1,49 @@
+package db
+
+import (
+	"mithrildb/model"
+)
+
+// GetDocumentPaths retrieves only the requested paths from a document value.
+//
+// Each path is reported with an existence flag, so a missing field is not an error.
+func (db *DB) GetDocumentPaths(opts DocumentPathReadOptions) (*model.DocumentPaths, error) {
+	if len(opts.Paths) == 0 {
+		return nil, model.ErrInvalidPath
+	}
+
+	parsed := make([]model.Path, len(opts.Paths))
+	for i, raw := range opts.Paths {
+		path, err := model.ParsePath(raw)
+		if err != nil {
+			return nil, err
+		}
+		parsed[i] = path
+	}
+
+	doc, err := db.GetDocument(DocumentReadOptions{
+		ColumnFamily: opts.ColumnFamily,
+		Key:          opts.Key,
+		ReadOptions:  opts.ReadOptions,
+		TxnID:        opts.TxnID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.DocumentPaths{
+		Key:   doc.Key,
+		Paths: make([]model.PathResult, len(parsed)),
+		Meta:  doc.Meta,
+	}
+	for i, path := range parsed {
+		value, exists := path.Lookup(doc.Value)
+		result.Paths[i] = model.PathResult{
+			Path:   opts.Paths[i],
+			Exists: exists,
+			Value:  value,
+		}
+	}
+
+	return result, nil
+}
@@ -1,32 +1,35 @@
 package db
 
 import (
-	"encoding/json"
-	"fmt"
 	"time"
 
 	"mithrildb/events"
 	"mithrildb/model"
-
-	"github.com/google/uuid"
-	"github.com/linxGnu/grocksdb"
 )
 
 // PutDocument stores or updates a document with optional CAS and expiration.
 func (db *DB) PutDocument(opts DocumentWriteOptions) (*model.Document, error) {
-	handle, ok := db.Families[opts.ColumnFamily]
-	if !ok {
-		return nil, ErrInvalidColumnFamily
+	var doc *model.Document
+	err := db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		var err error
+		doc, err = tc.putDocument(opts)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return doc, nil
+}
 
-	if err := model.ValidateDocumentKey(opts.Key); err != nil {
+// putDocument stores or updates a document inside a transaction.
+func (tc *txnContext) putDocument(opts DocumentWriteOptions) (*model.Document, error) {
+	handle, err := tc.family(opts.ColumnFamily, opts.Key)
+	if err != nil {
 		return nil, err
 	}
-	if opts.Value == nil {
-		return nil, ErrNilValue
-	}
-	if err := model.ValidateValue(opts.Value, opts.Type); err != nil {
-		return nil, fmt.Errorf("invalid value for type %s: %w", opts.Type, err)
+	value, err := prepareDocumentValue(opts.Value, opts.Type)
+	if err != nil {
+		return nil, err
 	}
 	if opts.Expiration != nil {
 		if err := model.ValidateExpiration(*opts.Expiration); err != nil {
@@ -34,87 +37,42 @@ func (db *DB) PutDocument(opts DocumentWriteOptions) (*model.Document, error) {
 		}
 	}
 
-	txnOpts := grocksdb.NewDefaultTransactionOptions()
-	txnOpts.SetSetSnapshot(true)
-	txnOpts.SetLockTimeout(1000)
-	defer txnOpts.Destroy()
-
-	txn := db.TransactionDB.TransactionBegin(opts.WriteOptions, txnOpts, nil)
-	defer txn.Destroy()
-
+	// The existing document is locked even without a CAS so its revision sequence continues.
+	existing, err := tc.getForWrite(handle, opts.ColumnFamily, opts.Key)
+	if err != nil {
+		return nil, err
+	}
 	var prevMeta *model.Metadata
-	if opts.Cas != "" {
-		readOpts := grocksdb.NewDefaultReadOptions()
-		readOpts.SetFillCache(false)
-		defer readOpts.Destroy()
-
-		val, err := txn.GetWithCF(readOpts, handle, []byte(opts.Key))
-		if err != nil {
-			txn.Rollback()
-			return nil, err
-		}
-		defer val.Free()
-
-		if val.Size() > 0 {
-			var existing model.Document
-			if err := json.Unmarshal(val.Data(), &existing); err != nil {
-				txn.Rollback()
-				return nil, fmt.Errorf("failed to unmarshal existing document: %w", err)
-			}
-			if existing.Meta.Rev != opts.Cas {
-				txn.Rollback()
-				return nil, ErrRevisionMismatch
-			}
-			metaCopy := existing.Meta
-			prevMeta = &metaCopy
+	if existing != nil {
+		if opts.Cas != "" && existing.Meta.Rev != opts.Cas {
+			return nil, ErrRevisionMismatch
 		}
+		metaCopy := existing.Meta
+		prevMeta = &metaCopy
 	}
 
-	now := time.Now()
 	exp := int64(0)
 	if opts.Expiration != nil {
 		exp = *opts.Expiration
 	}
 
-	doc := model.Document{
+	doc := &model.Document{
 		Key:   opts.Key,
-		Value: opts.Value,
+		Value: value,
 		Meta: model.Metadata{
-			Rev:        uuid.NewString(),
 			Type:       opts.Type,
-			UpdatedAt:  now,
+			UpdatedAt:  time.Now(),
 			Expiration: exp,
 		},
 	}
+	applyContentMetadata(&doc.Meta, doc.Value, opts.ContentType)
 
-	data, err := json.Marshal(doc)
-	if err != nil {
-		txn.Rollback()
-		return nil, fmt.Errorf("error marshaling document: %w", err)
-	}
-
-	if err := txn.PutCF(handle, []byte(opts.Key), data); err != nil {
-		txn.Rollback()
-		return nil, fmt.Errorf("failed to put document: %w", err)
-	}
-
-	if err := events.PublishChangeEvent(events.ChangeEventOptions{
-		Txn:                txn,
-		CFName:             opts.ColumnFamily,
-		Key:                opts.Key,
-		Document:           &doc,
+	if err := tc.writeDocument(handle, opts.ColumnFamily, doc, events.ChangeEventOptions{
 		Operation:          events.OpPut,
 		PreviousMeta:       prevMeta,
 		ExplicitExpiration: opts.Expiration,
 	}); err != nil {
-		txn.Rollback()
-		return nil, fmt.Errorf("failed to enqueue change event: %w", err)
-	}
-
-	if err := txn.Commit(); err != nil {
-		txn.Rollback()
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, err
 	}
-
-	return &doc, nil
+	return doc, nil
 }
@@ -1,15 +1,10 @@
 package db
 
 import (
-	"encoding/json"
-	"fmt"
 	"time"
 
 	"mithrildb/events"
 	"mithrildb/model"
-
-	"github.com/google/uuid"
-	"github.com/linxGnu/grocksdb"
 )
 
 // updateIfExists executes a transactional update on an existing document.
@@ -18,97 +13,73 @@ func (db *DB) updateIfExists(
 	operation string,
 	modify func(doc *model.Document) error,
 ) (*model.Document, error) {
-	handle, ok := db.Families[opts.ColumnFamily]
-	if !ok {
-		return nil, ErrInvalidColumnFamily
+	var doc *model.Document
+	err := db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		var err error
+		doc, err = tc.updateIfExists(opts, events.ChangeEventOptions{Operation: operation}, modify)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return doc, nil
+}
 
-	if err := model.ValidateDocumentKey(opts.Key); err != nil {
+// updateIfExists locks an existing document, applies modify and writes it back inside the transaction.
+//
+// The event carries the operation and any extra fields such as the applied patch; key, document
+// and metadata fields are filled in here.
+func (tc *txnContext) updateIfExists(
+	opts DocumentWriteOptions,
+	event events.ChangeEventOptions,
+	modify func(doc *model.Document) error,
+) (*model.Document, error) {
+	handle, err := tc.family(opts.ColumnFamily, opts.Key)
+	if err != nil {
 		return nil, err
 	}
 
-	txnOpts := grocksdb.NewDefaultTransactionOptions()
-	txnOpts.SetSetSnapshot(true)
-	defer txnOpts.Destroy()
-
-	txn := db.TransactionDB.TransactionBegin(opts.WriteOptions, txnOpts, nil)
-	defer txn.Destroy()
-
-	readOpts := grocksdb.NewDefaultReadOptions()
-	readOpts.SetSnapshot(txn.GetSnapshot())
-	readOpts.SetFillCache(false)
-	defer readOpts.Destroy()
-
-	val, err := txn.GetWithCF(readOpts, handle, []byte(opts.Key))
+	existing, err := tc.getForWrite(handle, opts.ColumnFamily, opts.Key)
 	if err != nil {
-		txn.Rollback()
 		return nil, err
 	}
-	defer val.Free()
-
-	if !val.Exists() {
-		txn.Rollback()
+	if existing == nil {
 		return nil, ErrKeyNotFound
 	}
 
-	var existing model.Document
-	if err := json.Unmarshal(val.Data(), &existing); err != nil {
-		txn.Rollback()
-		return nil, fmt.Errorf("failed to parse document: %w", err)
-	}
-
-	if model.IsExpired(existing.Meta) {
-		txn.Rollback()
-		return nil, ErrKeyNotFound
+	if opts.Cas != "" && existing.Meta.Rev != opts.Cas {
+		return nil, ErrRevisionMismatch
 	}
 
 	metaCopy := existing.Meta
 
-	if err := modify(&existing); err != nil {
-		txn.Rollback()
+	if err := modify(existing); err != nil {
 		return nil, err
 	}
 
-	data, err := json.Marshal(existing)
-	if err != nil {
-		txn.Rollback()
-		return nil, fmt.Errorf("failed to serialize document: %w", err)
-	}
-
-	if err := txn.PutCF(handle, []byte(opts.Key), data); err != nil {
-		txn.Rollback()
-		return nil, fmt.Errorf("failed to write document: %w", err)
-	}
-
-	if err := events.PublishChangeEvent(events.ChangeEventOptions{
-		Txn:                txn,
-		CFName:             opts.ColumnFamily,
-		Key:                opts.Key,
-		Document:           &existing,
-		Operation:          operation,
-		PreviousMeta:       &metaCopy,
-		ExplicitExpiration: opts.Expiration,
-	}); err != nil {
-		txn.Rollback()
-		return nil, fmt.Errorf("failed to enqueue change event: %w", err)
-	}
-
-	if err := txn.Commit(); err != nil {
-		txn.Rollback()
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	event.PreviousMeta = &metaCopy
+	event.ExplicitExpiration = opts.Expiration
+	if err := tc.writeDocument(handle, opts.ColumnFamily, existing, event); err != nil {
+		return nil, err
 	}
-
-	return &existing, nil
+	return existing, nil
 }
 
 // ReplaceDocument overwrites a document only if the key already exists.
 func (db *DB) ReplaceDocument(opts DocumentWriteOptions) (*model.Document, error) {
-	return db.updateIfExists(opts, events.OpReplace, func(doc *model.Document) error {
-		if opts.Value == nil {
-			return ErrNilValue
-		}
-		if err := model.ValidateValue(opts.Value, opts.Type); err != nil {
-			return fmt.Errorf("invalid value for type %s: %w", opts.Type, err)
+	return db.updateIfExists(opts, events.OpReplace, replaceModifier(opts))
+}
+
+// replaceDocument overwrites an existing document inside a transaction.
+func (tc *txnContext) replaceDocument(opts DocumentWriteOptions) (*model.Document, error) {
+	return tc.updateIfExists(opts, events.ChangeEventOptions{Operation: events.OpReplace}, replaceModifier(opts))
+}
+
+func replaceModifier(opts DocumentWriteOptions) func(doc *model.Document) error {
+	return func(doc *model.Document) error {
+		value, err := prepareDocumentValue(opts.Value, opts.Type)
+		if err != nil {
+			return err
 		}
 		if opts.Expiration != nil {
 			if err := model.ValidateExpiration(*opts.Expiration); err != nil {
@@ -116,17 +87,26 @@ func (db *DB) ReplaceDocument(opts DocumentWriteOptions) (*model.Document, error
 			}
 			doc.Meta.Expiration = *opts.Expiration
 		}
-		doc.Value = opts.Value
+		doc.Value = value
 		doc.Meta.Type = opts.Type
+		applyContentMetadata(&doc.Meta, value, opts.ContentType)
 		doc.Meta.UpdatedAt = time.Now().UTC()
-		doc.Meta.Rev = uuid.NewString()
 		return nil
-	})
+	}
 }
 
 // TouchDocument updates only the expiration timestamp of an existing document.
 func (db *DB) TouchDocument(opts DocumentWriteOptions) (*model.Document, error) {
-	return db.updateIfExists(opts, events.OpTouch, func(doc *model.Document) error {
+	return db.updateIfExists(opts, events.OpTouch, touchModifier(opts))
+}
+
+// touchDocument updates the expiration of an existing document inside a transaction.
+func (tc *txnContext) touchDocument(opts DocumentWriteOptions) (*model.Document, error) {
+	return tc.updateIfExists(opts, events.ChangeEventOptions{Operation: events.OpTouch}, touchModifier(opts))
+}
+
+func touchModifier(opts DocumentWriteOptions) func(doc *model.Document) error {
+	return func(doc *model.Document) error {
 		if opts.Expiration == nil {
 			return model.ErrInvalidExpiration
 		}
@@ -135,7 +115,6 @@ func (db *DB) TouchDocument(opts DocumentWriteOptions) (*model.Document, error)
 		}
 		doc.Meta.Expiration = *opts.Expiration
 		doc.Meta.UpdatedAt = time.Now().UTC()
-		doc.Meta.Rev = uuid.NewString()
 		return nil
-	})
+	}
 }
@@ -0,0 +1,639 @@
+package db
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"mithrildb/model"
+
+	"github.com/linxGnu/grocksdb"
+)
+
+// CFSystemElements stores the elements of list, set, sorted set, stream and time series
+// documents, one key per element.
+//
+// Keys are "<cf>\x00<key>\x00" followed by the big-endian generation of the document's element
+// storage and, for lists, the element index with its sign bit flipped so indices sort in
+// order, or, for sets, the encoded member so membership is a single lookup. Values are the
+// encoded elements. Sorted sets lay out their members as described in zset.go, streams their
+// entries and consumer groups as described in streams.go, and time series their samples and
+// settings as described in timeseries.go.
+//
+// The document itself keeps its metadata and an ElementsHeader with the generation, list
+// bounds and length, so pushes, pops and set updates only write the header and the elements
+// they touch. Documents stored inline are converted by their first list, set, sorted set,
+// stream or time series operation.
+// A new generation is used on every conversion, so elements left behind by an expired
+// document can never show up in the document that replaces it.
+const CFSystemElements = "system.elements"
+
+// elementsPrefix returns the key prefix shared by every element of one generation of a
+// document.
+func elementsPrefix(cf, key string, gen uint64) []byte {
+	prefix := make([]byte, 0, len(cf)+len(key)+10)
+	prefix = append(prefix, cf...)
+	prefix = append(prefix, 0)
+	prefix = append(prefix, key...)
+	prefix = append(prefix, 0)
+	var g [8]byte
+	binary.BigEndian.PutUint64(g[:], gen)
+	return append(prefix, g[:]...)
+}
+
+// listElementKey returns the key of the list element at index.
+func listElementKey(cf, key string, gen uint64, index int64) []byte {
+	var idx [8]byte
+	binary.BigEndian.PutUint64(idx[:], uint64(index)^(1<<63))
+	return append(elementsPrefix(cf, key, gen), idx[:]...)
+}
+
+// setMemberKey returns the key of an encoded set member.
+func setMemberKey(cf, key string, gen uint64, member []byte) []byte {
+	return append(elementsPrefix(cf, key, gen), member...)
+}
+
+// elementList is a list document stored as one key per element, modified inside a transaction.
+type elementList struct {
+	tc     *txnContext
+	handle *grocksdb.ColumnFamilyHandle
+	cf     string
+	key    string
+	header *model.ElementsHeader
+}
+
+// push appends an element at the tail.
+func (l *elementList) push(element interface{}) error {
+	if err := l.put(l.header.Tail, element); err != nil {
+		return err
+	}
+	l.header.Tail++
+	l.header.Len++
+	return nil
+}
+
+// unshift inserts an element at the head.
+func (l *elementList) unshift(element interface{}) error {
+	if err := l.put(l.header.Head-1, element); err != nil {
+		return err
+	}
+	l.header.Head--
+	l.header.Len++
+	return nil
+}
+
+// pop removes and returns the element at the tail.
+func (l *elementList) pop() (interface{}, error) {
+	if l.header.Len == 0 {
+		return nil, ErrEmptyList
+	}
+	element, err := l.remove(l.header.Tail - 1)
+	if err != nil {
+		return nil, err
+	}
+	l.header.Tail--
+	l.header.Len--
+	return element, nil
+}
+
+// shift removes and returns the element at the head.
+func (l *elementList) shift() (interface{}, error) {
+	if l.header.Len == 0 {
+		return nil, ErrEmptyList
+	}
+	element, err := l.remove(l.header.Head)
+	if err != nil {
+		return nil, err
+	}
+	l.header.Head++
+	l.header.Len--
+	return element, nil
+}
+
+// put stores the element at index.
+func (l *elementList) put(index int64, element interface{}) error {
+	data, err := encodeRecord(element)
+	if err != nil {
+		return fmt.Errorf("failed to serialize list element: %w", err)
+	}
+	if err := l.tc.txn.PutCF(l.handle, listElementKey(l.cf, l.key, l.header.Gen, index), data); err != nil {
+		return fmt.Errorf("failed to write list element: %w", conflictError(err))
+	}
+	return nil
+}
+
+// remove deletes and returns the element at index.
+func (l *elementList) remove(index int64) (interface{}, error) {
+	k := listElementKey(l.cf, l.key, l.header.Gen, index)
+	val, err := l.tc.txn.GetWithCF(l.tc.readOpts, l.handle, k)
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	if !val.Exists() {
+		return nil, fmt.Errorf("%w: missing element %d", ErrInvalidListType, index)
+	}
+	element, err := decodeElement(val.Data())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode list element: %w", err)
+	}
+	if err := l.tc.txn.DeleteCF(l.handle, k); err != nil {
+		return nil, fmt.Errorf("failed to delete list element: %w", conflictError(err))
+	}
+	return element, nil
+}
+
+// position converts a list index, negative from the end, into an element index.
+func (l *elementList) position(index int) (int64, error) {
+	i := int64(index)
+	if i < 0 {
+		i += l.header.Len
+	}
+	if i < 0 || i >= l.header.Len {
+		return 0, ErrListIndexOutOfRange
+	}
+	return l.header.Head + i, nil
+}
+
+// get returns the element at a list index.
+func (l *elementList) get(index int) (interface{}, error) {
+	pos, err := l.position(index)
+	if err != nil {
+		return nil, err
+	}
+	val, err := l.tc.txn.GetWithCF(l.tc.readOpts, l.handle, listElementKey(l.cf, l.key, l.header.Gen, pos))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	if !val.Exists() {
+		return nil, fmt.Errorf("%w: missing element %d", ErrInvalidListType, pos)
+	}
+	return decodeElement(val.Data())
+}
+
+// set replaces the element at a list index.
+func (l *elementList) set(index int, element interface{}) error {
+	pos, err := l.position(index)
+	if err != nil {
+		return err
+	}
+	return l.put(pos, element)
+}
+
+// all returns every element of the list in order.
+func (l *elementList) all() ([]interface{}, error) {
+	iter := l.tc.txn.NewIteratorCF(l.tc.readOpts, l.handle)
+	defer iter.Close()
+
+	doc := &model.Document{Key: l.key, Meta: model.Metadata{Elements: l.header}}
+	if err := fillElements(iter, l.cf, doc); err != nil {
+		return nil, err
+	}
+	return doc.Value.([]interface{}), nil
+}
+
+// rewrite replaces every element of the list.
+func (l *elementList) rewrite(items []interface{}) error {
+	if err := l.tc.clearElements(l.cf, l.key, l.header); err != nil {
+		return err
+	}
+	l.header.Head, l.header.Tail, l.header.Len = 0, 0, 0
+	for _, item := range items {
+		if err := l.push(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trim keeps only the elements between two indices, inclusive. Negative indices count from
+// the end; a range outside the list empties it.
+func (l *elementList) trim(start, stop int) error {
+	n := l.header.Len
+	from, to := int64(start), int64(stop)
+	if from < 0 {
+		from += n
+	}
+	if to < 0 {
+		to += n
+	}
+	if from < 0 {
+		from = 0
+	}
+	if to >= n {
+		to = n - 1
+	}
+	if from > to {
+		from, to = n, n-1
+	}
+
+	for i := int64(0); i < from; i++ {
+		if _, err := l.shift(); err != nil {
+			return err
+		}
+	}
+	for i := n - 1; i > to; i-- {
+		if _, err := l.pop(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// elementSet is a set document stored as one key per member, modified inside a transaction.
+type elementSet struct {
+	tc     *txnContext
+	handle *grocksdb.ColumnFamilyHandle
+	cf     string
+	key    string
+	header *model.ElementsHeader
+}
+
+// add stores a member and reports whether it was new.
+func (s *elementSet) add(member interface{}) (bool, error) {
+	data, err := encodeRecord(member)
+	if err != nil {
+		return false, fmt.Errorf("failed to serialize set member: %w", err)
+	}
+	k := setMemberKey(s.cf, s.key, s.header.Gen, data)
+	exists, err := s.exists(k)
+	if err != nil || exists {
+		return false, err
+	}
+	if err := s.tc.txn.PutCF(s.handle, k, data); err != nil {
+		return false, fmt.Errorf("failed to write set member: %w", conflictError(err))
+	}
+	s.header.Len++
+	return true, nil
+}
+
+// remove deletes a member and reports whether it was present.
+func (s *elementSet) remove(member interface{}) (bool, error) {
+	data, err := encodeRecord(member)
+	if err != nil {
+		return false, fmt.Errorf("failed to serialize set member: %w", err)
+	}
+	k := setMemberKey(s.cf, s.key, s.header.Gen, data)
+	exists, err := s.exists(k)
+	if err != nil || !exists {
+		return false, err
+	}
+	if err := s.tc.txn.DeleteCF(s.handle, k); err != nil {
+		return false, fmt.Errorf("failed to delete set member: %w", conflictError(err))
+	}
+	s.header.Len--
+	return true, nil
+}
+
+// exists reports whether a member key is stored.
+func (s *elementSet) exists(k []byte) (bool, error) {
+	val, err := s.tc.txn.GetWithCF(s.tc.readOpts, s.handle, k)
+	if err != nil {
+		return false, err
+	}
+	defer val.Free()
+	return val.Exists(), nil
+}
+
+// removeRandom removes up to count members chosen at random and returns them.
+func (s *elementSet) removeRandom(count int) ([]interface{}, error) {
+	doc := &model.Document{Key: s.key, Meta: model.Metadata{Elements: s.header}}
+	picked, err := sampleMembers(func(fn func(setMember) bool) error {
+		return s.tc.scanSet(s.cf, doc, nil, fn)
+	}, count)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := make([]interface{}, 0, len(picked))
+	for _, m := range picked {
+		if err := s.tc.txn.DeleteCF(s.handle, setMemberKey(s.cf, s.key, s.header.Gen, m.data)); err != nil {
+			return nil, fmt.Errorf("failed to delete set member: %w", conflictError(err))
+		}
+		s.header.Len--
+		removed = append(removed, m.value)
+	}
+	return removed, nil
+}
+
+// setMember is a set member together with its encoding, which orders members and keys them
+// in storage.
+type setMember struct {
+	data  []byte
+	value interface{}
+}
+
+// scanSetElements calls fn with the members of a set stored as elements, in member order,
+// starting after the encoded member after. It stops early when fn returns false.
+func scanSetElements(iter *grocksdb.Iterator, cf, key string, header *model.ElementsHeader, after []byte, fn func(setMember) bool) error {
+	prefix := elementsPrefix(cf, key, header.Gen)
+	for iter.Seek(append(prefix[:len(prefix):len(prefix)], after...)); iter.ValidForPrefix(prefix); iter.Next() {
+		k := iter.Key()
+		data := append([]byte(nil), k.Data()[len(prefix):]...)
+		k.Free()
+		if len(after) > 0 && bytes.Equal(data, after) {
+			continue
+		}
+		v := iter.Value()
+		member, err := decodeElement(v.Data())
+		v.Free()
+		if err != nil {
+			return fmt.Errorf("failed to decode set member: %w", err)
+		}
+		if !fn(setMember{data: data, value: member}) {
+			return nil
+		}
+	}
+	return iter.Err()
+}
+
+// scanInlineSet is scanSetElements for a set still stored inline, which may hold duplicates.
+func scanInlineSet(items []interface{}, after []byte, fn func(setMember) bool) error {
+	members := make([]setMember, 0, len(items))
+	for _, item := range items {
+		data, err := encodeRecord(item)
+		if err != nil {
+			return fmt.Errorf("failed to serialize set member: %w", err)
+		}
+		members = append(members, setMember{data: data, value: item})
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return bytes.Compare(members[i].data, members[j].data) < 0
+	})
+
+	for i, m := range members {
+		if i > 0 && bytes.Equal(m.data, members[i-1].data) {
+			continue
+		}
+		if len(after) > 0 && bytes.Compare(m.data, after) <= 0 {
+			continue
+		}
+		if !fn(m) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// scanSet calls fn with the members of a set document, as read by the transaction, in member
+// order, starting after the encoded member after.
+func (tc *txnContext) scanSet(cf string, doc *model.Document, after []byte, fn func(setMember) bool) error {
+	header := doc.Meta.Elements
+	if header == nil {
+		items, _ := doc.Value.([]interface{})
+		return scanInlineSet(items, after, fn)
+	}
+	handle, ok := tc.db.Family(CFSystemElements)
+	if !ok {
+		return fmt.Errorf("column family %q not available", CFSystemElements)
+	}
+	iter := tc.txn.NewIteratorCF(tc.readOpts, handle)
+	defer iter.Close()
+	return scanSetElements(iter, cf, doc.Key, header, after, fn)
+}
+
+// scanSet calls fn with the members of a set document in member order, starting after the
+// encoded member after.
+func (db *DB) scanSet(readOpts *grocksdb.ReadOptions, cf string, doc *model.Document, after []byte, fn func(setMember) bool) error {
+	header := doc.Meta.Elements
+	if header == nil {
+		items, _ := doc.Value.([]interface{})
+		return scanInlineSet(items, after, fn)
+	}
+	handle, ok := db.Family(CFSystemElements)
+	if !ok {
+		return fmt.Errorf("column family %q not available", CFSystemElements)
+	}
+	if readOpts == nil {
+		readOpts = db.DefaultReadOptions
+	}
+	iter := db.TransactionDB.NewIteratorCF(readOpts, handle)
+	defer iter.Close()
+	return scanSetElements(iter, cf, doc.Key, header, after, fn)
+}
+
+// memberRand picks random set members; the global math/rand source is not seeded on every
+// supported Go version.
+var (
+	memberRandMu sync.Mutex
+	memberRand   = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// sampleMembers picks up to count distinct members of a set at random, reading it once.
+func sampleMembers(scan func(func(setMember) bool) error, count int) ([]setMember, error) {
+	sample := make([]setMember, 0, count)
+	seen := 0
+	err := scan(func(m setMember) bool {
+		if len(sample) < count {
+			sample = append(sample, m)
+		} else if j := randomIntn(seen + 1); j < count {
+			sample[j] = m
+		}
+		seen++
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := len(sample) - 1; i > 0; i-- {
+		j := randomIntn(i + 1)
+		sample[i], sample[j] = sample[j], sample[i]
+	}
+	return sample, nil
+}
+
+// randomIntn returns a random number in [0, n).
+func randomIntn(n int) int {
+	memberRandMu.Lock()
+	defer memberRandMu.Unlock()
+	return memberRand.Intn(n)
+}
+
+// convertToElements moves the inline list or set value of a document to element keys and
+// gives the document a header of the given kind. Documents already stored as elements are
+// left untouched. It fails with ok=false when the value is not an array.
+func (tc *txnContext) convertToElements(cf string, doc *model.Document, kind string) (ok bool, err error) {
+	if doc.Meta.Elements != nil {
+		return true, nil
+	}
+	items, isArray := doc.Value.([]interface{})
+	if !isArray {
+		return false, nil
+	}
+	handle, err := tc.db.EnsureSystemColumnFamily(CFSystemElements)
+	if err != nil {
+		return false, err
+	}
+
+	header := &model.ElementsHeader{Kind: kind, Gen: uint64(tc.db.clock.Now())}
+	switch kind {
+	case model.ElementsList:
+		l := &elementList{tc: tc, handle: handle, cf: cf, key: doc.Key, header: header}
+		for _, item := range items {
+			if err := l.push(item); err != nil {
+				return false, err
+			}
+		}
+	case model.ElementsSet:
+		s := &elementSet{tc: tc, handle: handle, cf: cf, key: doc.Key, header: header}
+		for _, item := range items {
+			if _, err := s.add(item); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	doc.Value = nil
+	doc.Meta.Elements = header
+	return true, nil
+}
+
+// clearElements deletes the element keys of one generation of a document.
+func (tc *txnContext) clearElements(cf, key string, header *model.ElementsHeader) error {
+	handle, ok := tc.db.Family(CFSystemElements)
+	if !ok {
+		return nil
+	}
+	prefix := elementsPrefix(cf, key, header.Gen)
+
+	iter := tc.txn.NewIteratorCF(tc.readOpts, handle)
+	defer iter.Close()
+	for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+		k := iter.Key()
+		err := tc.txn.DeleteCF(handle, append([]byte(nil), k.Data()...))
+		k.Free()
+		if err != nil {
+			return fmt.Errorf("failed to delete elements: %w", conflictError(err))
+		}
+	}
+	return iter.Err()
+}
+
+// loadElements fills in the value of a document stored as elements, as read by the
+// transaction, and drops its header.
+func (tc *txnContext) loadElements(cf string, doc *model.Document) error {
+	if doc.Meta.Elements == nil {
+		return nil
+	}
+	handle, ok := tc.db.Family(CFSystemElements)
+	if !ok {
+		return fmt.Errorf("column family %q not available", CFSystemElements)
+	}
+	iter := tc.txn.NewIteratorCF(tc.readOpts, handle)
+	defer iter.Close()
+	return fillElements(iter, cf, doc)
+}
+
+// loadElements fills in the value of a document stored as elements and drops its header.
+//
+// Unless the read options pin a snapshot, elements changed while they are read may or may
+// not be included.
+func (db *DB) loadElements(readOpts *grocksdb.ReadOptions, cf string, doc *model.Document) error {
+	if doc.Meta.Elements == nil {
+		return nil
+	}
+	handle, ok := db.Family(CFSystemElements)
+	if !ok {
+		return fmt.Errorf("column family %q not available", CFSystemElements)
+	}
+	iter := db.TransactionDB.NewIteratorCF(readOpts, handle)
+	defer iter.Close()
+	return fillElements(iter, cf, doc)
+}
+
+// fillElements reads every element of a document, in list order or member order, into its
+// value. Sorted sets get their members as {member, score} objects in score order, streams
+// their entries as {id, value} objects, without their consumer groups, and time series their
+// samples, without their settings.
+func fillElements(iter *grocksdb.Iterator, cf string, doc *model.Document) error {
+	header := doc.Meta.Elements
+	switch header.Kind {
+	case model.ElementsZSet:
+		return fillZSetEntries(iter, cf, doc)
+	case model.ElementsStream:
+		return fillStreamEntries(iter, cf, doc)
+	case model.ElementsTimeSeries:
+		return fillTimeSeriesSamples(iter, cf, doc)
+	}
+	prefix := elementsPrefix(cf, doc.Key, header.Gen)
+
+	items := make([]interface{}, 0, header.Len)
+	for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+		v := iter.Value()
+		item, err := decodeElement(v.Data())
+		v.Free()
+		if err != nil {
+			return fmt.Errorf("failed to decode element of %q: %w", doc.Key, err)
+		}
+		items = append(items, item)
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	doc.Value = items
+	doc.Meta.Elements = nil
+	return nil
+}
+
+// listRange reads the list elements between two positions, inclusive, of a document stored
+// as elements.
+func (db *DB) listRange(readOpts *grocksdb.ReadOptions, cf, key string, header *model.ElementsHeader, start, end int) ([]interface{}, error) {
+	handle, ok := db.Family(CFSystemElements)
+	if !ok {
+		return nil, fmt.Errorf("column family %q not available", CFSystemElements)
+	}
+	iter := db.TransactionDB.NewIteratorCF(readOpts, handle)
+	defer iter.Close()
+
+	prefix := elementsPrefix(cf, key, header.Gen)
+	last := listElementKey(cf, key, header.Gen, header.Head+int64(end))
+
+	items := make([]interface{}, 0, end-start+1)
+	iter.Seek(listElementKey(cf, key, header.Gen, header.Head+int64(start)))
+	for ; iter.ValidForPrefix(prefix); iter.Next() {
+		k := iter.Key()
+		past := string(k.Data()) > string(last)
+		k.Free()
+		if past {
+			break
+		}
+		v := iter.Value()
+		item, err := decodeElement(v.Data())
+		v.Free()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode list element: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// setContains reports whether an element is a member of a set document stored as elements.
+func (db *DB) setContains(readOpts *grocksdb.ReadOptions, cf, key string, header *model.ElementsHeader, element interface{}) (bool, error) {
+	handle, ok := db.Family(CFSystemElements)
+	if !ok {
+		return false, fmt.Errorf("column family %q not available", CFSystemElements)
+	}
+	data, err := encodeRecord(element)
+	if err != nil {
+		return false, err
+	}
+	if readOpts == nil {
+		readOpts = db.DefaultReadOptions
+	}
+	val, err := db.TransactionDB.GetCF(readOpts, handle, setMemberKey(cf, key, header.Gen, data))
+	if err != nil {
+		return false, err
+	}
+	defer val.Free()
+	return val.Exists(), nil
+}
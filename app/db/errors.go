@@ -3,15 +3,53 @@ package db
 import "errors"
 
 var (
-	ErrKeyAlreadyExists          = errors.New("key already exists")
-	ErrKeyNotFound               = errors.New("key does not exist")
-	ErrRevisionMismatch          = errors.New("revision mismatch")
-	ErrInvalidColumnFamily       = errors.New("invalid column family")
-	ErrInvalidUserColumnFamily   = errors.New("invalid user column family")
-	ErrInvalidSystemColumnFamily = errors.New("invalid system column family")
-	ErrNilValue                  = errors.New("value cannot be nil")
-	ErrFamilyExists              = errors.New("column family already exists")
-	ErrInvalidListType           = errors.New("document is not a valid list")
-	ErrEmptyList                 = errors.New("list is empty")
-	ErrInvalidSetType            = errors.New("document is not a valid set")
+	ErrKeyAlreadyExists           = errors.New("key already exists")
+	ErrKeyNotFound                = errors.New("key does not exist")
+	ErrRevisionMismatch           = errors.New("revision mismatch")
+	ErrInvalidColumnFamily        = errors.New("invalid column family")
+	ErrInvalidUserColumnFamily    = errors.New("invalid user column family")
+	ErrInvalidSystemColumnFamily  = errors.New("invalid system column family")
+	ErrNilValue                   = errors.New("value cannot be nil")
+	ErrFamilyExists               = errors.New("column family already exists")
+	ErrInvalidListType            = errors.New("document is not a valid list")
+	ErrEmptyList                  = errors.New("list is empty")
+	ErrInvalidListOperation       = errors.New("invalid list operation")
+	ErrListIndexOutOfRange        = errors.New("list index out of range")
+	ErrInvalidSetType             = errors.New("document is not a valid set")
+	ErrInvalidSetOperation        = errors.New("invalid set operation")
+	ErrInvalidZSetType            = errors.New("document is not a valid sorted set")
+	ErrInvalidZSetOperation       = errors.New("invalid sorted set operation")
+	ErrMemberNotFound             = errors.New("member does not exist")
+	ErrInvalidHashType            = errors.New("document is not a valid hash")
+	ErrInvalidHashOperation       = errors.New("invalid hash operation")
+	ErrFieldNotFound              = errors.New("field does not exist")
+	ErrInvalidStreamType          = errors.New("document is not a valid stream")
+	ErrInvalidStreamOperation     = errors.New("invalid stream operation")
+	ErrStreamGroupNotFound        = errors.New("consumer group does not exist")
+	ErrStreamGroupExists          = errors.New("consumer group already exists")
+	ErrInvalidTimeSeriesType      = errors.New("document is not a valid time series")
+	ErrInvalidTimeSeriesOperation = errors.New("invalid time series operation")
+	ErrInvalidBlobType            = errors.New("document is not a valid blob")
+	ErrInvalidBatchOperation      = errors.New("invalid batch operation")
+	ErrCounterOutOfRange          = errors.New("counter value out of range")
+	ErrInvalidCounterOperation    = errors.New("invalid counter operation")
+	ErrCounterOverflow            = errors.New("counter overflow")
+	ErrTransactionNotFound        = errors.New("transaction not found")
+	ErrTooManyTransactions        = errors.New("too many open transactions")
+	ErrTransactionConflict        = errors.New("transaction conflict")
+	ErrInvalidKeyRange            = errors.New("invalid key range")
+	ErrInvalidFamilySettings      = errors.New("invalid column family settings")
+	ErrHistoryDisabled            = errors.New("history is not enabled for column family")
+	ErrRevisionNotFound           = errors.New("revision not found")
+	ErrRevisionValueNotKept       = errors.New("revision value was not kept")
+	ErrSnapshotNotFound           = errors.New("snapshot not found")
+	ErrTooManySnapshots           = errors.New("too many open snapshots")
+	ErrInvalidSnapshotLease       = errors.New("invalid snapshot lease")
+	ErrQueuesDisabled             = errors.New("job queues are disabled")
+	ErrQueueNotFound              = errors.New("queue not found")
+	ErrInvalidQueueName           = errors.New("invalid queue name")
+	ErrInvalidQueueSettings       = errors.New("invalid queue settings")
+	ErrInvalidQueueOperation      = errors.New("invalid queue operation")
+	ErrQueueMessageNotFound       = errors.New("queue message not found")
+	ErrQueueReceiptMismatch       = errors.New("queue message receipt does not match its current delivery")
 )
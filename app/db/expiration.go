@@ -2,61 +2,142 @@ package db
 
 import (
 	"bytes"
-	"errors"
+	"encoding/binary"
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 
 	"github.com/linxGnu/grocksdb"
 )
 
 const CFSystemExpiration = "system.expiration"
 
+// CFSystemExpirationKeys indexes the entries of CFSystemExpiration by column family and key, so
+// the entries of one key or of a key range are found with a seek instead of a scan.
+//
+// Keys are "<cf>\x00<key>\x00" followed by the big-endian expiration; values are empty.
+const CFSystemExpirationKeys = "system.expiration.keys"
+
+// ttlKey returns the expiration index key of a document, ordered by expiration time.
+func ttlKey(cfName, key string, expiration int64) []byte {
+	return []byte(fmt.Sprintf("%d:%s:%s", expiration, cfName, key))
+}
+
+// ttlKeyPrefix returns the prefix shared by the key index entries of a document.
+func ttlKeyPrefix(cfName, key string) []byte {
+	prefix := make([]byte, 0, len(cfName)+len(key)+2)
+	prefix = append(prefix, cfName...)
+	prefix = append(prefix, 0)
+	prefix = append(prefix, key...)
+	return append(prefix, 0)
+}
+
+// ttlByKey returns the key index entry of a document expiring at the given time.
+func ttlByKey(cfName, key string, expiration int64) []byte {
+	var suffix [8]byte
+	binary.BigEndian.PutUint64(suffix[:], uint64(expiration))
+	return append(ttlKeyPrefix(cfName, key), suffix[:]...)
+}
+
+// parseTTLByKey extracts the document key and expiration from a key index entry of a column
+// family.
+func parseTTLByKey(cfName string, k []byte) (string, int64) {
+	n := len(k)
+	return string(k[len(cfName)+1 : n-9]), int64(binary.BigEndian.Uint64(k[n-8:]))
+}
+
 func (db *DB) WriteTTL(cfName, key string, expiration int64) error {
 	if expiration <= 0 {
 		return nil // no TTL to write
 	}
+	return db.runInTransaction("", db.DefaultWriteOptions, func(tc *txnContext) error {
+		return tc.scheduleExpiration(cfName, key, expiration)
+	})
+}
 
-	handle, err := db.EnsureSystemColumnFamily(CFSystemExpiration)
+// scheduleExpiration adds an expiration index entry inside a transaction, so the expiration
+// job processes the key once the timestamp has passed.
+func (tc *txnContext) scheduleExpiration(cfName, key string, expiration int64) error {
+	handle, err := tc.db.EnsureSystemColumnFamily(CFSystemExpiration)
+	if err != nil {
+		return err
+	}
+	byKey, err := tc.db.EnsureSystemColumnFamily(CFSystemExpirationKeys)
 	if err != nil {
 		return err
 	}
 
-	ttlKey := fmt.Sprintf("%d:%s:%s", expiration, cfName, key)
 	expStr := strconv.FormatInt(expiration, 10)
 
-	return db.TransactionDB.PutCF(db.DefaultWriteOptions, handle, []byte(ttlKey), []byte(expStr))
+	if err := tc.txn.PutCF(handle, ttlKey(cfName, key, expiration), []byte(expStr)); err != nil {
+		return fmt.Errorf("failed to write TTL entry: %w", conflictError(err))
+	}
+	if err := tc.txn.PutCF(byKey, ttlByKey(cfName, key, expiration), nil); err != nil {
+		return fmt.Errorf("failed to write TTL entry: %w", conflictError(err))
+	}
+	return nil
 }
 
+// ClearAllTTL removes every expiration index entry of a document.
 func (db *DB) ClearAllTTL(cfName, key string) error {
-	handle, err := db.EnsureSystemColumnFamily(CFSystemExpiration)
-	if err != nil {
-		return err
+	prefix := cfName + "\x00" + key
+	return db.clearTTLEntries(cfName, []byte(prefix+"\x00"), []byte(prefix+"\x01"))
+}
+
+// ClearTTLRange removes the expiration index entries of every key of a column family from
+// start (inclusive) to end (exclusive), left behind by a range delete.
+func (db *DB) ClearTTLRange(cfName, start, end string) error {
+	prefix := cfName + "\x00"
+	return db.clearTTLEntries(cfName, []byte(prefix+start), []byte(prefix+end))
+}
+
+// clearTTLEntries removes the expiration index entries of a column family whose key index
+// entries sort from lower (inclusive) to upper (exclusive). Keys never contain a NUL byte, so
+// "<cf>\x00<key>" bounds order entries like their keys.
+func (db *DB) clearTTLEntries(cfName string, lower, upper []byte) error {
+	handle, ok := db.Family(CFSystemExpiration)
+	byKey, indexed := db.Family(CFSystemExpirationKeys)
+	if !ok || !indexed {
+		return nil
 	}
 
 	readOpts := grocksdb.NewDefaultReadOptions()
 	defer readOpts.Destroy()
-	readOpts.SetPrefixSameAsStart(true)
 	readOpts.SetFillCache(false)
 
-	iter := db.TransactionDB.NewIteratorCF(readOpts, handle)
+	iter := db.TransactionDB.NewIteratorCF(readOpts, byKey)
 	defer iter.Close()
 
-	prefix := fmt.Sprintf("0:%s:%s", cfName, key)
-
-	for iter.Seek([]byte(prefix)); iter.Valid(); iter.Next() {
+	var stale [][]byte
+	for iter.Seek(lower); iter.Valid(); iter.Next() {
 		k := iter.Key()
-		if !bytes.Contains(k.Data(), []byte(fmt.Sprintf(":%s:%s", cfName, key))) {
-			k.Free()
+		entry := append([]byte(nil), k.Data()...)
+		k.Free()
+		if bytes.Compare(entry, upper) >= 0 {
 			break
 		}
-		if err := db.TransactionDB.DeleteCF(db.DefaultWriteOptions, handle, k.Data()); err != nil {
-			k.Free()
-			return fmt.Errorf("failed to delete TTL entry for key=%q: %w", key, err)
-		}
-		k.Free()
+		stale = append(stale, entry)
 	}
-	return nil
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	return db.runInTransaction("", db.DefaultWriteOptions, func(tc *txnContext) error {
+		for _, entry := range stale {
+			key, expiration := parseTTLByKey(cfName, entry)
+			if err := tc.txn.DeleteCF(handle, ttlKey(cfName, key, expiration)); err != nil {
+				return fmt.Errorf("failed to delete TTL entry for key=%q: %w", key, conflictError(err))
+			}
+			if err := tc.txn.DeleteCF(byKey, entry); err != nil {
+				return fmt.Errorf("failed to delete TTL entry for key=%q: %w", key, conflictError(err))
+			}
+		}
+		return nil
+	})
 }
 
 func (db *DB) ReplaceTTL(cfName, key string, expiration int64) error {
@@ -67,7 +148,7 @@ func (db *DB) ReplaceTTL(cfName, key string, expiration int64) error {
 }
 
 func (db *DB) ProcessExpiredBatch(now int64, limit int) (int, error) {
-	handle, ok := db.Families[CFSystemExpiration]
+	handle, ok := db.Family(CFSystemExpiration)
 	if !ok {
 		return 0, fmt.Errorf("expiration index not found")
 	}
@@ -105,29 +186,28 @@ func (db *DB) ProcessExpiredBatch(now int64, limit int) (int, error) {
 		cfName := string(parts[1])
 		docKey := string(parts[2])
 
-		doc, err := db.GetDocument(DocumentReadOptions{
-			ColumnFamily: cfName,
-			Key:          docKey,
-			ReadOptions:  readOpts,
-		})
+		var deleted bool
+		if strings.HasPrefix(cfName, CFTrashPrefix) {
+			deleted, err = db.purgeTrashed(cfName, docKey, ts)
+		} else {
+			deleted, err = db.deleteExpired(cfName, docKey, ts)
+		}
 		if err != nil {
-			if !errors.Is(err, ErrKeyNotFound) {
-				log.Printf("[expiration] failed to read %s:%s: %v", cfName, docKey, err)
-			}
+			log.Printf("[expiration] failed to delete expired %s:%s: %v", cfName, docKey, err)
 			continue
 		}
 
-		if doc.Meta.Expiration != ts {
-			continue
+		// The entry is dropped either way: when nothing was deleted it is an orphan whose
+		// document is gone (deleted, purged by a range delete) or now expires at another time.
+		if err := db.TransactionDB.DeleteCF(db.DefaultWriteOptions, handle, ttlKey); err != nil {
+			log.Printf("[expiration] failed to drop TTL entry %s: %v", ttlKey, err)
 		}
-
-		err = db.DeleteDocument(DocumentDeleteOptions{
-			ColumnFamily: cfName,
-			Key:          docKey,
-			WriteOptions: db.DefaultWriteOptions,
-		})
-		if err != nil {
-			log.Printf("[expiration] failed to delete expired %s:%s: %v", cfName, docKey, err)
+		if byKey, ok := db.Family(CFSystemExpirationKeys); ok {
+			if err := db.TransactionDB.DeleteCF(db.DefaultWriteOptions, byKey, ttlByKey(cfName, docKey, ts)); err != nil {
+				log.Printf("[expiration] failed to drop TTL entry %s: %v", ttlKey, err)
+			}
+		}
+		if !deleted {
 			continue
 		}
 
@@ -136,3 +216,33 @@ func (db *DB) ProcessExpiredBatch(now int64, limit int) (int, error) {
 
 	return count, nil
 }
+
+// deleteExpired removes a document if it still expires at the given time. It reports false
+// when the document or its column family no longer exists or its expiration changed.
+func (db *DB) deleteExpired(cfName, key string, expiration int64) (bool, error) {
+	handle, ok := db.Family(cfName)
+	if !ok {
+		return false, nil
+	}
+
+	deleted := false
+	err := db.runInTransaction("", db.DefaultWriteOptions, func(tc *txnContext) error {
+		doc, err := tc.getStoredForUpdate(handle, key)
+		if err != nil || doc == nil || doc.Meta.Expiration != expiration {
+			return err
+		}
+		// The expired document is no longer visible to the delete, so its elements are
+		// dropped here.
+		if doc.Meta.Elements != nil {
+			if err := tc.clearElements(cfName, key, doc.Meta.Elements); err != nil {
+				return err
+			}
+		}
+		if err := tc.deleteDocument(DocumentDeleteOptions{ColumnFamily: cfName, Key: key, Permanent: true}); err != nil {
+			return err
+		}
+		deleted = true
+		return nil
+	})
+	return deleted, err
+}
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTTLByKeyRoundTrip(t *testing.T) {
+	entry := ttlByKey("logs", "user:1", 1718035200)
+	if !bytes.HasPrefix(entry, ttlKeyPrefix("logs", "user:1")) {
+		t.Fatalf("entry %q lacks the key prefix", entry)
+	}
+	key, expiration := parseTTLByKey("logs", entry)
+	if key != "user:1" || expiration != 1718035200 {
+		t.Fatalf("parseTTLByKey = %q, %d", key, expiration)
+	}
+}
+
+func TestTTLByKeyOrdersLikeKeys(t *testing.T) {
+	// Entries of a key sort between the bounds ClearAllTTL uses, and apart from the entries
+	// of keys that extend it.
+	a := ttlByKey("logs", "a", 1<<40)
+	ab := ttlByKey("logs", "ab", 1)
+	if bytes.Compare(a, []byte("logs\x00a\x00")) < 0 || bytes.Compare(a, []byte("logs\x00a\x01")) >= 0 {
+		t.Fatalf("entry %q is outside the bounds of its key", a)
+	}
+	if bytes.Compare(ab, []byte("logs\x00a\x01")) < 0 {
+		t.Fatalf("entry %q sorts inside the bounds of key a", ab)
+	}
+	// A range delete of [a, b) covers both.
+	for _, entry := range [][]byte{a, ab} {
+		if bytes.Compare(entry, []byte("logs\x00a")) < 0 || bytes.Compare(entry, []byte("logs\x00b")) >= 0 {
+			t.Errorf("entry %q is outside the range [a, b)", entry)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package db
 
 import (
-	"errors"
 	"fmt"
 	"regexp"
 	"sort"
@@ -12,51 +11,70 @@ import (
 
 // ListColumnFamilies returns the loaded column family names, split into user/system categories.
 func (db *DB) ListColumnFamilies() (userCFs, systemCFs []string) {
+	db.mu.RLock()
 	names := make([]string, 0, len(db.Families))
 	for name := range db.Families {
 		names = append(names, name)
 	}
+	db.mu.RUnlock()
 	sort.Strings(names)
 	return splitCFNamesByType(names)
 }
 
-// CreateColumnFamily creates a new column family with default RocksDB options.
+// Family returns the handle of a loaded column family. Column families are created while
+// requests are served, so Families must only be read through this method.
+func (db *DB) Family(name string) (*grocksdb.ColumnFamilyHandle, bool) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	handle, ok := db.Families[name]
+	return handle, ok
+}
+
+// CreateColumnFamily creates a new column family with the configured RocksDB options.
 func (db *DB) CreateColumnFamily(name string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	_, err := db.createColumnFamily(name)
+	return err
+}
+
+// createColumnFamily creates a column family. The caller must hold db.mu.
+func (db *DB) createColumnFamily(name string) (*grocksdb.ColumnFamilyHandle, error) {
 	if _, exists := db.Families[name]; exists {
-		return ErrFamilyExists
+		return nil, ErrFamilyExists
 	}
 
 	opts := grocksdb.NewDefaultOptions()
 	defer opts.Destroy()
+	applyColumnFamilyOptions(opts, db.rocksConfig)
 
 	handle, err := db.TransactionDB.CreateColumnFamily(opts, name)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	db.Families[name] = handle
-	return nil
+	return handle, nil
 }
 
 // EnsureSystemColumnFamily ensures a system-level column family exists, creating it if necessary.
 func (db *DB) EnsureSystemColumnFamily(name string) (*grocksdb.ColumnFamilyHandle, error) {
-	if handle, ok := db.Families[name]; ok {
+	if handle, ok := db.Family(name); ok {
 		return handle, nil
 	}
 
 	db.mu.Lock()
 	defer db.mu.Unlock()
 
-	if err := db.CreateColumnFamily(name); err != nil {
-		if !errors.Is(err, ErrFamilyExists) {
-			return nil, err
-		}
-	}
-
+	// Another request may have created it while we waited for the lock.
 	if handle, ok := db.Families[name]; ok {
 		return handle, nil
 	}
-	return nil, fmt.Errorf("column family %q not available after creation", name)
+	handle, err := db.createColumnFamily(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create column family %q: %w", name, err)
+	}
+	return handle, nil
 }
 
 var userCFRegex = regexp.MustCompile(`^[a-z0-9_-]{1,64}$`)
@@ -0,0 +1,125 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"mithrildb/model"
+
+	"github.com/linxGnu/grocksdb"
+)
+
+// CFSystemFamilies stores the settings of user column families, keyed by family name.
+const CFSystemFamilies = "system.families"
+
+// LoadFamilySettings reads the stored column family settings into memory. It is called once at
+// startup, before requests are served.
+func (db *DB) LoadFamilySettings() error {
+	handle, ok := db.Family(CFSystemFamilies)
+	if !ok {
+		return nil
+	}
+
+	readOpts := grocksdb.NewDefaultReadOptions()
+	readOpts.SetFillCache(false)
+	defer readOpts.Destroy()
+
+	iter := db.TransactionDB.NewIteratorCF(readOpts, handle)
+	defer iter.Close()
+
+	db.settingsMu.Lock()
+	defer db.settingsMu.Unlock()
+
+	for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+		k := iter.Key()
+		v := iter.Value()
+		name := string(k.Data())
+		var settings model.FamilySettings
+		err := json.Unmarshal(v.Data(), &settings)
+		k.Free()
+		v.Free()
+		if err != nil {
+			return fmt.Errorf("failed to decode settings of column family %q: %w", name, err)
+		}
+		db.settings[name] = settings
+	}
+	return iter.Err()
+}
+
+// GetFamilySettings returns the settings of a user column family. Families that were never
+// configured report the defaults.
+func (db *DB) GetFamilySettings(cf string) (model.FamilySettings, error) {
+	if _, ok := db.Family(cf); !ok || !IsValidUserCF(cf) {
+		return model.FamilySettings{}, ErrInvalidColumnFamily
+	}
+	return db.familySettings(cf), nil
+}
+
+// SetFamilySettings validates and stores the settings of a user column family. They apply to
+// writes made after the call; stored history is kept when history is disabled.
+func (db *DB) SetFamilySettings(cf string, settings model.FamilySettings) error {
+	if _, ok := db.Family(cf); !ok || !IsValidUserCF(cf) {
+		return ErrInvalidColumnFamily
+	}
+	if err := ValidateFamilySettings(settings); err != nil {
+		return err
+	}
+
+	if settings.History.Enabled {
+		if _, err := db.EnsureSystemColumnFamily(CFSystemHistory); err != nil {
+			return err
+		}
+	}
+	if settings.Trash.Enabled {
+		if _, err := db.EnsureSystemColumnFamily(TrashFamily(cf)); err != nil {
+			return err
+		}
+	}
+
+	handle, err := db.EnsureSystemColumnFamily(CFSystemFamilies)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to serialize settings: %w", err)
+	}
+
+	db.settingsMu.Lock()
+	defer db.settingsMu.Unlock()
+
+	if err := db.TransactionDB.PutCF(db.DefaultWriteOptions, handle, []byte(cf), data); err != nil {
+		return fmt.Errorf("failed to store settings: %w", err)
+	}
+	db.settings[cf] = settings
+	return nil
+}
+
+// familySettings returns the cached settings of a column family.
+func (db *DB) familySettings(cf string) model.FamilySettings {
+	db.settingsMu.RLock()
+	defer db.settingsMu.RUnlock()
+	return db.settings[cf]
+}
+
+// ValidateFamilySettings checks column family settings before they are stored.
+func ValidateFamilySettings(settings model.FamilySettings) error {
+	h := settings.History
+	if h.MaxRevisions < 0 {
+		return fmt.Errorf("%w: max_revisions cannot be negative", ErrInvalidFamilySettings)
+	}
+	if h.Retention != "" {
+		d, err := time.ParseDuration(h.Retention)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("%w: retention must be a positive duration such as \"720h\"", ErrInvalidFamilySettings)
+		}
+	}
+	if t := settings.Trash; t.Retention != "" {
+		d, err := time.ParseDuration(t.Retention)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("%w: trash retention must be a positive duration such as \"720h\"", ErrInvalidFamilySettings)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+
+	"mithrildb/model"
+)
+
+// HGet returns the value of a hash field.
+func (db *DB) HGet(opts HashReadOptions, field string) (interface{}, error) {
+	h, err := db.readHash(opts)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := h[field]
+	if !ok {
+		return nil, ErrFieldNotFound
+	}
+	return value, nil
+}
+
+// HMGet returns the values of several hash fields in the order requested, with nil for the
+// fields that do not exist.
+func (db *DB) HMGet(opts HashReadOptions, fields []string) ([]interface{}, error) {
+	h, err := db.readHash(opts)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, len(fields))
+	for i, field := range fields {
+		values[i] = h[field]
+	}
+	return values, nil
+}
+
+// HExists reports whether a hash field exists.
+func (db *DB) HExists(opts HashReadOptions, field string) (bool, error) {
+	h, err := db.readHash(opts)
+	if err != nil {
+		return false, err
+	}
+	_, ok := h[field]
+	return ok, nil
+}
+
+// HKeys returns the field names of a hash in sorted order.
+func (db *DB) HKeys(opts HashReadOptions) ([]string, error) {
+	h, err := db.readHash(opts)
+	if err != nil {
+		return nil, err
+	}
+	return sortedFields(h), nil
+}
+
+// HLen returns the number of fields of a hash.
+func (db *DB) HLen(opts HashReadOptions) (int, error) {
+	h, err := db.readHash(opts)
+	if err != nil {
+		return 0, err
+	}
+	return len(h), nil
+}
+
+// HGetAll returns a page of hash fields in field order. next is the last field returned when
+// more fields follow, and can be passed as StartAfter to read the next page.
+func (db *DB) HGetAll(opts HGetAllOptions) (fields map[string]interface{}, next string, err error) {
+	h, err := db.readHash(opts.HashReadOptions)
+	if err != nil {
+		return nil, "", err
+	}
+
+	names := sortedFields(h)
+	start := sort.SearchStrings(names, opts.StartAfter)
+	if start < len(names) && names[start] == opts.StartAfter && opts.StartAfter != "" {
+		start++
+	}
+	names = names[start:]
+	if opts.Limit > 0 && len(names) > opts.Limit {
+		names = names[:opts.Limit]
+		next = names[len(names)-1]
+	}
+
+	fields = make(map[string]interface{}, len(names))
+	for _, name := range names {
+		fields[name] = h[name]
+	}
+	return fields, next, nil
+}
+
+// readHash loads the fields of a hash document.
+func (db *DB) readHash(opts HashReadOptions) (map[string]interface{}, error) {
+	handle, ok := db.Family(opts.ColumnFamily)
+	if !ok {
+		return nil, ErrInvalidColumnFamily
+	}
+	if opts.ReadOptions == nil {
+		opts.ReadOptions = db.DefaultReadOptions
+	}
+	if err := model.ValidateDocumentKey(opts.Key); err != nil {
+		return nil, err
+	}
+
+	val, err := db.TransactionDB.GetCF(opts.ReadOptions, handle, []byte(opts.Key))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+
+	if !val.Exists() || val.Size() == 0 {
+		return nil, ErrKeyNotFound
+	}
+
+	var doc model.Document
+	if err := decodeDocument(val.Data(), &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	if model.IsExpired(doc.Meta) {
+		return nil, ErrKeyNotFound
+	}
+	if doc.Meta.Type != model.DocTypeHash {
+		return nil, ErrInvalidHashType
+	}
+
+	h, ok := doc.Value.(map[string]interface{})
+	if !ok {
+		return nil, ErrInvalidHashType
+	}
+	return h, nil
+}
+
+// sortedFields returns the field names of a hash in sorted order.
+func sortedFields(h map[string]interface{}) []string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
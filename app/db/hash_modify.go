@@ -0,0 +1,164 @@
+package db
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"mithrildb/events"
+	"mithrildb/model"
+)
+
+// HSet sets fields of a hash document and returns how many of them were new.
+func (db *DB) HSet(opts HSetOptions) (int, error) {
+	if len(opts.Fields) == 0 {
+		return 0, fmt.Errorf("%w: no fields given", ErrInvalidHashOperation)
+	}
+	fields := make(map[string]interface{}, len(opts.Fields))
+	for field, value := range opts.Fields {
+		if field == "" {
+			return 0, fmt.Errorf("%w: field names cannot be empty", ErrInvalidHashOperation)
+		}
+		normalized, err := model.NormalizeValue(value)
+		if err != nil {
+			return 0, err
+		}
+		fields[field] = normalized
+	}
+
+	result, err := db.withHashTransaction(opts.HashOpOptions, func(h map[string]interface{}) (interface{}, error) {
+		added := 0
+		for field, value := range fields {
+			if _, exists := h[field]; !exists {
+				added++
+			}
+			h[field] = value
+		}
+		return added, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
+
+// HDel removes fields from a hash document and returns how many were present.
+func (db *DB) HDel(opts HDelOptions) (int, error) {
+	result, err := db.withHashTransaction(opts.HashOpOptions, func(h map[string]interface{}) (interface{}, error) {
+		removed := 0
+		for _, field := range opts.Fields {
+			if _, exists := h[field]; exists {
+				delete(h, field)
+				removed++
+			}
+		}
+		return removed, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
+
+// HIncrBy adds a delta to an integer hash field, treating a missing field as 0, and returns
+// the new value.
+func (db *DB) HIncrBy(opts HIncrByOptions) (int64, error) {
+	if opts.Field == "" {
+		return 0, fmt.Errorf("%w: field names cannot be empty", ErrInvalidHashOperation)
+	}
+	result, err := db.withHashTransaction(opts.HashOpOptions, func(h map[string]interface{}) (interface{}, error) {
+		var current int64
+		if value, exists := h[opts.Field]; exists {
+			n, err := model.ParseCounterValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: field %q is not an integer", ErrInvalidHashOperation, opts.Field)
+			}
+			current = n
+		}
+		if (opts.Delta > 0 && current > math.MaxInt64-opts.Delta) ||
+			(opts.Delta < 0 && current < math.MinInt64-opts.Delta) {
+			return nil, ErrCounterOverflow
+		}
+		h[opts.Field] = current + opts.Delta
+		return current + opts.Delta, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int64), nil
+}
+
+// withHashTransaction applies a transactional update to a hash document.
+func (db *DB) withHashTransaction(
+	opts HashOpOptions,
+	modifier func(h map[string]interface{}) (interface{}, error),
+) (interface{}, error) {
+	var result interface{}
+	err := db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		var err error
+		result, err = tc.modifyHash(opts, modifier)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// modifyHash applies an update to a hash document inside a transaction. The modifier
+// changes the fields in place.
+func (tc *txnContext) modifyHash(
+	opts HashOpOptions,
+	modifier func(h map[string]interface{}) (interface{}, error),
+) (interface{}, error) {
+	handle, err := tc.family(opts.ColumnFamily, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := tc.getForWrite(handle, opts.ColumnFamily, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return nil, ErrKeyNotFound
+	}
+	if opts.Cas != "" && doc.Meta.Rev != opts.Cas {
+		return nil, ErrRevisionMismatch
+	}
+	if doc.Meta.Type != model.DocTypeHash {
+		return nil, ErrInvalidHashType
+	}
+
+	metaCopy := doc.Meta
+
+	h, ok := doc.Value.(map[string]interface{})
+	if !ok {
+		return nil, ErrInvalidHashType
+	}
+
+	result, err := modifier(h)
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Value = h
+	doc.Meta.UpdatedAt = time.Now()
+
+	if opts.Expiration != nil {
+		if err := model.ValidateExpiration(*opts.Expiration); err != nil {
+			return nil, err
+		}
+		doc.Meta.Expiration = *opts.Expiration
+	}
+
+	if err := tc.writeDocument(handle, opts.ColumnFamily, doc, events.ChangeEventOptions{
+		Operation:          events.OpMutate,
+		PreviousMeta:       &metaCopy,
+		ExplicitExpiration: opts.Expiration,
+	}); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,442 @@
+package db
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"mithrildb/events"
+	"mithrildb/model"
+
+	"github.com/linxGnu/grocksdb"
+)
+
+// CFSystemHistory stores past revisions of documents in column families with history enabled.
+//
+// Keys are "<cf>\x00<key>\x00" followed by the big-endian sequence number and HLC of the
+// revision, so the revisions of a document are contiguous and sorted oldest first. The value is
+// the stored document, or empty for a deletion. Documents stored as elements are recorded as
+// stored, with their element header and no value, since their elements are not copied.
+const CFSystemHistory = "system.history"
+
+// historyPrefix returns the key prefix shared by every revision of a document.
+func historyPrefix(cf, key string) []byte {
+	prefix := make([]byte, 0, len(cf)+len(key)+2)
+	prefix = append(prefix, cf...)
+	prefix = append(prefix, 0)
+	prefix = append(prefix, key...)
+	return append(prefix, 0)
+}
+
+// historyKey returns the key of one revision of a document.
+func historyKey(cf, key string, seq uint64, hlc model.HLC) []byte {
+	var suffix [16]byte
+	binary.BigEndian.PutUint64(suffix[:8], seq)
+	binary.BigEndian.PutUint64(suffix[8:], uint64(hlc))
+	return append(historyPrefix(cf, key), suffix[:]...)
+}
+
+// parseHistoryKey extracts the sequence number and HLC from a revision key.
+func parseHistoryKey(k []byte) (uint64, model.HLC) {
+	n := len(k)
+	return binary.BigEndian.Uint64(k[n-16 : n-8]), model.HLC(binary.BigEndian.Uint64(k[n-8:]))
+}
+
+// historyEntry is a revision read from the history column family.
+type historyEntry struct {
+	key  []byte
+	seq  uint64
+	hlc  model.HLC
+	data []byte // Empty for a deletion
+}
+
+// scanHistory returns the stored revisions of a document, oldest first.
+func scanHistory(iter *grocksdb.Iterator, cf, key string, withValues bool) ([]historyEntry, error) {
+	prefix := historyPrefix(cf, key)
+
+	var entries []historyEntry
+	for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+		k := iter.Key()
+		entry := historyEntry{key: append([]byte(nil), k.Data()...)}
+		k.Free()
+		entry.seq, entry.hlc = parseHistoryKey(entry.key)
+		if withValues {
+			v := iter.Value()
+			entry.data = append([]byte(nil), v.Data()...)
+			v.Free()
+		}
+		entries = append(entries, entry)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// revision converts a history entry into its public description.
+func (e historyEntry) revision(key string, withValue bool) (model.DocumentRevision, error) {
+	rev := model.DocumentRevision{
+		Rev:       model.FormatRevision(e.seq, e.hlc),
+		Seq:       e.seq,
+		HLC:       e.hlc,
+		Timestamp: e.hlc.Time(),
+		Deleted:   len(e.data) == 0 && withValue,
+	}
+	if withValue && len(e.data) > 0 {
+		var doc model.Document
+		if err := decodeDocument(e.data, &doc); err != nil {
+			return rev, fmt.Errorf("failed to decode revision %s of %q: %w", rev.Rev, key, err)
+		}
+		rev.Document = &doc
+	}
+	return rev, nil
+}
+
+// previousRevision returns the metadata the next write of a document continues from. When the
+// document does not exist, a column family with history continues after the newest revision
+// stored, so a recreated document does not reuse the sequence numbers of its previous life.
+func (tc *txnContext) previousRevision(cf, key string, prev model.Metadata) (model.Metadata, error) {
+	if prev.Seq > 0 || !tc.db.familySettings(cf).History.Enabled {
+		return prev, nil
+	}
+	handle, ok := tc.db.Family(CFSystemHistory)
+	if !ok {
+		return prev, nil
+	}
+
+	iter := tc.txn.NewIteratorCF(tc.readOpts, handle)
+	defer iter.Close()
+
+	// The newest revision sorts last, just before the largest possible suffix.
+	prefix := historyPrefix(cf, key)
+	last := append(prefix[:len(prefix):len(prefix)], bytes.Repeat([]byte{0xff}, 16)...)
+	iter.SeekForPrev(last)
+	if iter.ValidForPrefix(prefix) {
+		k := iter.Key()
+		prev.Seq, prev.HLC = parseHistoryKey(k.Data())
+		k.Free()
+	}
+	if err := iter.Err(); err != nil {
+		return prev, fmt.Errorf("failed to read history: %w", err)
+	}
+	return prev, nil
+}
+
+// recordHistory stores a revision of a document when its column family keeps history, then
+// prunes the revisions the settings no longer retain. Empty data records a deletion.
+func (tc *txnContext) recordHistory(cf, key string, seq uint64, hlc model.HLC, data []byte) error {
+	settings := tc.db.familySettings(cf).History
+	if !settings.Enabled {
+		return nil
+	}
+	handle, err := tc.db.EnsureSystemColumnFamily(CFSystemHistory)
+	if err != nil {
+		return err
+	}
+
+	newest := historyKey(cf, key, seq, hlc)
+	if err := tc.txn.PutCF(handle, newest, data); err != nil {
+		return fmt.Errorf("failed to write history: %w", conflictError(err))
+	}
+	return tc.pruneHistory(handle, cf, key, newest, settings)
+}
+
+// pruneHistory deletes the revisions of a document beyond MaxRevisions or older than Retention.
+// It only walks the revisions it deletes, the ones it keeps under MaxRevisions and the first one
+// kept under Retention, from either end of the history, so its cost does not grow with the
+// number of revisions kept. The newest revision is always kept so the document's sequence can
+// continue from it.
+func (tc *txnContext) pruneHistory(handle *grocksdb.ColumnFamilyHandle, cf, key string, newest []byte, settings model.HistorySettings) error {
+	retention, err := time.ParseDuration(settings.Retention)
+	hasRetention := err == nil && settings.Retention != ""
+	if settings.MaxRevisions == 0 && !hasRetention {
+		return nil
+	}
+	prefix := historyPrefix(cf, key)
+
+	var stale [][]byte
+	iter := tc.txn.NewIteratorCF(tc.readOpts, handle)
+	if hasRetention {
+		// Oldest first, up to the first revision inside the retention period.
+		cutoff := time.Now().Add(-retention)
+		for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+			k := iter.Key()
+			entry := append([]byte(nil), k.Data()...)
+			k.Free()
+			if _, hlc := parseHistoryKey(entry); bytes.Equal(entry, newest) || !hlc.Time().Before(cutoff) {
+				break
+			}
+			stale = append(stale, entry)
+		}
+	}
+	if settings.MaxRevisions > 0 {
+		// Newest first, past the revisions kept, down to those already dropped by retention.
+		kept := 0
+		for iter.SeekForPrev(newest); iter.ValidForPrefix(prefix); iter.Prev() {
+			k := iter.Key()
+			entry := append([]byte(nil), k.Data()...)
+			k.Free()
+			if len(stale) > 0 && bytes.Compare(entry, stale[len(stale)-1]) <= 0 {
+				break
+			}
+			if kept < settings.MaxRevisions {
+				kept++
+				continue
+			}
+			stale = append(stale, entry)
+		}
+	}
+	err = iter.Err()
+	iter.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+
+	for _, k := range stale {
+		if err := tc.txn.DeleteCF(handle, k); err != nil {
+			return fmt.Errorf("failed to prune history: %w", conflictError(err))
+		}
+	}
+	return nil
+}
+
+// GetDocumentHistory lists the stored revisions of a document, newest first.
+func (db *DB) GetDocumentHistory(opts DocumentHistoryOptions) ([]model.DocumentRevision, error) {
+	if err := db.checkHistory(opts.ColumnFamily, opts.Key); err != nil {
+		return nil, err
+	}
+	handle, ok := db.Family(CFSystemHistory)
+	if !ok {
+		return []model.DocumentRevision{}, nil
+	}
+
+	iter := db.TransactionDB.NewIteratorCF(opts.ReadOptions, handle)
+	defer iter.Close()
+
+	// Values are needed to tell deletions apart even when they are not returned.
+	entries, err := scanHistory(iter, opts.ColumnFamily, opts.Key, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	revisions := make([]model.DocumentRevision, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		if opts.Limit > 0 && len(revisions) >= opts.Limit {
+			break
+		}
+		rev, err := entries[i].revision(opts.Key, true)
+		if err != nil {
+			return nil, err
+		}
+		if !opts.IncludeValues {
+			rev.Document = nil
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, nil
+}
+
+// GetDocumentRevision returns a past version of a document: the revision named by Rev, or the
+// one that was current at AsOf. The current document answers when it matches, so these reads
+// work for documents written before history was enabled. Past revisions of documents stored as
+// elements have no value and fail with ErrRevisionValueNotKept.
+func (db *DB) GetDocumentRevision(opts DocumentRevisionReadOptions) (*model.Document, error) {
+	current, err := db.GetDocument(DocumentReadOptions{
+		ColumnFamily: opts.ColumnFamily,
+		Key:          opts.Key,
+		ReadOptions:  opts.ReadOptions,
+	})
+	if err != nil && !errors.Is(err, ErrKeyNotFound) {
+		return nil, err
+	}
+
+	if opts.Rev != "" {
+		if current != nil && current.Meta.Rev == opts.Rev {
+			return current, nil
+		}
+		if err := db.checkHistory(opts.ColumnFamily, opts.Key); err != nil {
+			return nil, err
+		}
+		return db.getRevision(opts.ColumnFamily, opts.Key, opts.Rev, opts.ReadOptions)
+	}
+
+	asOfMs := opts.AsOf.UnixMilli()
+	if current != nil && current.Meta.HLC.Physical() <= asOfMs {
+		return current, nil
+	}
+	if err := db.checkHistory(opts.ColumnFamily, opts.Key); err != nil {
+		return nil, err
+	}
+	handle, ok := db.Family(CFSystemHistory)
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+
+	iter := db.TransactionDB.NewIteratorCF(opts.ReadOptions, handle)
+	defer iter.Close()
+
+	entries, err := scanHistory(iter, opts.ColumnFamily, opts.Key, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	var found *historyEntry
+	for i := range entries {
+		if entries[i].hlc.Physical() > asOfMs {
+			break
+		}
+		found = &entries[i]
+	}
+	if found == nil || len(found.data) == 0 {
+		return nil, ErrKeyNotFound
+	}
+
+	rev, err := found.revision(opts.Key, true)
+	if err != nil {
+		return nil, err
+	}
+	if exp := rev.Document.Meta.Expiration; exp > 0 && exp <= opts.AsOf.Unix() {
+		return nil, ErrKeyNotFound
+	}
+	if rev.Document.Meta.Elements != nil {
+		return nil, fmt.Errorf("%w: revision %s is stored as elements", ErrRevisionValueNotKept, rev.Rev)
+	}
+	return rev.Document, nil
+}
+
+// getRevision reads one stored revision of a document.
+func (db *DB) getRevision(cf, key, rev string, readOpts *grocksdb.ReadOptions) (*model.Document, error) {
+	seq, hlc, ok := model.ParseRevision(rev)
+	handle, exists := db.Family(CFSystemHistory)
+	if !ok || !exists {
+		return nil, ErrRevisionNotFound
+	}
+
+	val, err := db.TransactionDB.GetCF(readOpts, handle, historyKey(cf, key, seq, hlc))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+
+	if !val.Exists() {
+		return nil, ErrRevisionNotFound
+	}
+	if val.Size() == 0 {
+		return nil, fmt.Errorf("%w: the document was deleted in revision %s", ErrKeyNotFound, rev)
+	}
+
+	var doc model.Document
+	if err := decodeDocument(val.Data(), &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode revision: %w", err)
+	}
+	if doc.Meta.Elements != nil {
+		return nil, fmt.Errorf("%w: revision %s is stored as elements", ErrRevisionValueNotKept, rev)
+	}
+	return &doc, nil
+}
+
+// checkHistory validates a history read against a column family and document key.
+func (db *DB) checkHistory(cf, key string) error {
+	if _, ok := db.Family(cf); !ok {
+		return ErrInvalidColumnFamily
+	}
+	if err := model.ValidateDocumentKey(key); err != nil {
+		return err
+	}
+	if !db.familySettings(cf).History.Enabled {
+		return ErrHistoryDisabled
+	}
+	return nil
+}
+
+// RevertDocument writes the value of a past revision as the document's newest revision.
+func (db *DB) RevertDocument(opts DocumentRevertOptions) (*model.Document, error) {
+	var doc *model.Document
+	err := db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		var err error
+		doc, err = tc.revertDocument(opts)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// revertDocument restores a past revision inside a transaction. The restored document takes
+// the given expiration, or none, like a put.
+func (tc *txnContext) revertDocument(opts DocumentRevertOptions) (*model.Document, error) {
+	handle, err := tc.family(opts.ColumnFamily, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+	if !tc.db.familySettings(opts.ColumnFamily).History.Enabled {
+		return nil, ErrHistoryDisabled
+	}
+	if opts.Expiration != nil {
+		if err := model.ValidateExpiration(*opts.Expiration); err != nil {
+			return nil, err
+		}
+	}
+
+	seq, hlc, ok := model.ParseRevision(opts.Rev)
+	histHandle, exists := tc.db.Family(CFSystemHistory)
+	if !ok || !exists {
+		return nil, ErrRevisionNotFound
+	}
+	val, err := tc.txn.GetWithCF(tc.readOpts, histHandle, historyKey(opts.ColumnFamily, opts.Key, seq, hlc))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	if !val.Exists() {
+		return nil, ErrRevisionNotFound
+	}
+	if val.Size() == 0 {
+		return nil, fmt.Errorf("%w: revision %s is a deletion", ErrRevisionNotFound, opts.Rev)
+	}
+	var past model.Document
+	if err := decodeDocument(val.Data(), &past); err != nil {
+		return nil, fmt.Errorf("failed to decode revision: %w", err)
+	}
+	if past.Meta.Elements != nil {
+		return nil, fmt.Errorf("%w: revision %s is stored as elements", ErrRevisionValueNotKept, opts.Rev)
+	}
+
+	existing, err := tc.getForWrite(handle, opts.ColumnFamily, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+	var prevMeta *model.Metadata
+	if existing != nil {
+		if opts.Cas != "" && existing.Meta.Rev != opts.Cas {
+			return nil, ErrRevisionMismatch
+		}
+		metaCopy := existing.Meta
+		prevMeta = &metaCopy
+	} else if opts.Cas != "" {
+		return nil, ErrKeyNotFound
+	}
+
+	doc := &model.Document{
+		Key:   opts.Key,
+		Value: past.Value,
+		Meta:  past.Meta,
+	}
+	doc.Meta.Rev, doc.Meta.Seq, doc.Meta.HLC = "", 0, 0
+	doc.Meta.UpdatedAt = time.Now()
+	doc.Meta.Expiration = 0
+	if opts.Expiration != nil {
+		doc.Meta.Expiration = *opts.Expiration
+	}
+
+	if err := tc.writeDocument(handle, opts.ColumnFamily, doc, events.ChangeEventOptions{
+		Operation:          events.OpRevert,
+		PreviousMeta:       prevMeta,
+		ExplicitExpiration: opts.Expiration,
+	}); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
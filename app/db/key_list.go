@@ -2,7 +2,7 @@ package db
 
 // ListDocumentKeys returns keys from a column family that match the prefix and pagination options.
 func (db *DB) ListDocumentKeys(opts KeyListOptions) ([]string, error) {
-	handle, ok := db.Families[opts.ColumnFamily]
+	handle, ok := db.Family(opts.ColumnFamily)
 	if !ok {
 		return nil, ErrInvalidColumnFamily
 	}
@@ -1,153 +1,368 @@
 package db
 
 import (
-	"encoding/json"
 	"fmt"
 	"mithrildb/events"
 	"mithrildb/model"
+	"reflect"
 	"time"
-
-	"github.com/google/uuid"
-	"github.com/linxGnu/grocksdb"
 )
 
-// PushToList appends an element to the end of a list document.
+// PushToList appends an element, or several in order, to the end of a list document and
+// returns the new length.
 func (db *DB) PushToList(opts ListPushOptions) (interface{}, error) {
-	return db.withListTransaction(opts.ListOpOptions, func(list []interface{}) ([]interface{}, interface{}, error) {
-		return append(list, opts.Element), nil, nil
+	elements, err := pushedElements(opts)
+	if err != nil {
+		return nil, err
+	}
+	return db.withListTransaction(opts.ListOpOptions, func(l *elementList) (interface{}, error) {
+		for _, element := range elements {
+			if err := l.push(element); err != nil {
+				return nil, err
+			}
+		}
+		return l.header.Len, nil
 	})
 }
 
-// UnshiftToList inserts an element at the beginning of a list document.
+// UnshiftToList inserts an element, or several one after the other, at the beginning of a
+// list document and returns the new length. Several elements end up in reverse order.
 func (db *DB) UnshiftToList(opts ListPushOptions) (interface{}, error) {
-	return db.withListTransaction(opts.ListOpOptions, func(list []interface{}) ([]interface{}, interface{}, error) {
-		return append([]interface{}{opts.Element}, list...), nil, nil
+	elements, err := pushedElements(opts)
+	if err != nil {
+		return nil, err
+	}
+	return db.withListTransaction(opts.ListOpOptions, func(l *elementList) (interface{}, error) {
+		for _, element := range elements {
+			if err := l.unshift(element); err != nil {
+				return nil, err
+			}
+		}
+		return l.header.Len, nil
 	})
 }
 
-// PopFromList removes the last element from a list document and returns it.
-func (db *DB) PopFromList(opts ListOpOptions) (interface{}, error) {
-	return db.withListTransaction(opts, func(list []interface{}) ([]interface{}, interface{}, error) {
-		if len(list) == 0 {
-			return nil, nil, ErrEmptyList
+// pushedElements returns the normalized elements of a push or unshift.
+func pushedElements(opts ListPushOptions) ([]interface{}, error) {
+	raw := opts.Elements
+	if len(raw) == 0 {
+		raw = []interface{}{opts.Element}
+	}
+	elements := make([]interface{}, len(raw))
+	for i, e := range raw {
+		element, err := model.NormalizeValue(e)
+		if err != nil {
+			return nil, err
 		}
-		last := list[len(list)-1]
-		return list[:len(list)-1], last, nil
+		elements[i] = element
+	}
+	return elements, nil
+}
+
+// SetListElement replaces the element at an index of a list document.
+func (db *DB) SetListElement(opts ListSetOptions) error {
+	element, err := model.NormalizeValue(opts.Element)
+	if err != nil {
+		return err
+	}
+	_, err = db.withListTransaction(opts.ListOpOptions, func(l *elementList) (interface{}, error) {
+		return nil, l.set(opts.Index, element)
 	})
+	return err
 }
 
-// ShiftFromList removes the first element from a list document and returns it.
-func (db *DB) ShiftFromList(opts ListOpOptions) (interface{}, error) {
-	return db.withListTransaction(opts, func(list []interface{}) ([]interface{}, interface{}, error) {
-		if len(list) == 0 {
-			return nil, nil, ErrEmptyList
+// InsertIntoList inserts an element before or after the first element equal to a pivot and
+// returns the new length, or -1 when the pivot is not in the list.
+func (db *DB) InsertIntoList(opts ListInsertOptions) (int64, error) {
+	pivot, err := model.NormalizeValue(opts.Pivot)
+	if err != nil {
+		return 0, err
+	}
+	element, err := model.NormalizeValue(opts.Element)
+	if err != nil {
+		return 0, err
+	}
+	result, err := db.withListTransaction(opts.ListOpOptions, func(l *elementList) (interface{}, error) {
+		items, err := l.all()
+		if err != nil {
+			return nil, err
+		}
+		at := -1
+		for i, item := range items {
+			if reflect.DeepEqual(item, pivot) {
+				at = i
+				break
+			}
+		}
+		if at < 0 {
+			return int64(-1), nil
+		}
+		if opts.After {
+			at++
+		}
+		items = append(items, nil)
+		copy(items[at+1:], items[at:])
+		items[at] = element
+		if err := l.rewrite(items); err != nil {
+			return nil, err
+		}
+		return l.header.Len, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int64), nil
+}
+
+// RemoveFromList removes elements equal to a value and returns how many were removed. A
+// positive count removes up to count matches from the head, a negative one from the tail,
+// and zero removes every match.
+func (db *DB) RemoveFromList(opts ListRemoveOptions) (int, error) {
+	element, err := model.NormalizeValue(opts.Element)
+	if err != nil {
+		return 0, err
+	}
+	result, err := db.withListTransaction(opts.ListOpOptions, func(l *elementList) (interface{}, error) {
+		items, err := l.all()
+		if err != nil {
+			return nil, err
+		}
+		limit := opts.Count
+		if limit < 0 {
+			limit = -limit
 		}
-		first := list[0]
-		return list[1:], first, nil
+		drop := make([]bool, len(items))
+		removed := 0
+		for n := 0; n < len(items) && (limit == 0 || removed < limit); n++ {
+			i := n
+			if opts.Count < 0 {
+				i = len(items) - 1 - n
+			}
+			if reflect.DeepEqual(items[i], element) {
+				drop[i] = true
+				removed++
+			}
+		}
+		if removed == 0 {
+			return 0, nil
+		}
+		kept := make([]interface{}, 0, len(items)-removed)
+		for i, item := range items {
+			if !drop[i] {
+				kept = append(kept, item)
+			}
+		}
+		if err := l.rewrite(kept); err != nil {
+			return nil, err
+		}
+		return removed, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
+
+// TrimList keeps only the elements of a list document between two indices, inclusive.
+func (db *DB) TrimList(opts ListTrimOptions) error {
+	_, err := db.withListTransaction(opts.ListOpOptions, func(l *elementList) (interface{}, error) {
+		return nil, l.trim(opts.Start, opts.Stop)
 	})
+	return err
 }
 
-// withListTransaction applies a list-modifying function transactionally to a list document.
-func (db *DB) withListTransaction(
-	opts ListOpOptions,
-	modifier func([]interface{}) ([]interface{}, interface{}, error),
-) (interface{}, error) {
-	handle, ok := db.Families[opts.ColumnFamily]
-	if !ok {
-		return nil, ErrInvalidColumnFamily
+// MoveListElement atomically pops an element from one end of a source list and pushes it to
+// one end of a destination list, which may be the same list or live in another column
+// family. Both lists must exist. It returns the moved element.
+func (db *DB) MoveListElement(opts ListMoveOptions) (interface{}, error) {
+	fromLeft, err := parseListEnd(opts.From)
+	if err != nil {
+		return nil, err
 	}
-	if err := model.ValidateDocumentKey(opts.Key); err != nil {
+	toLeft, err := parseListEnd(opts.To)
+	if err != nil {
 		return nil, err
 	}
 
-	txnOpts := grocksdb.NewDefaultTransactionOptions()
-	txnOpts.SetSetSnapshot(true)
-	defer txnOpts.Destroy()
+	take := removeLast
+	if fromLeft {
+		take = removeFirst
+	}
+	put := func(l *elementList, element interface{}) error {
+		if toLeft {
+			return l.unshift(element)
+		}
+		return l.push(element)
+	}
 
-	txn := db.TransactionDB.TransactionBegin(opts.WriteOptions, txnOpts, nil)
-	defer txn.Destroy()
+	source := ListOpOptions{ColumnFamily: opts.SourceFamily, Key: opts.SourceKey}
+	destination := ListOpOptions{ColumnFamily: opts.DestinationFamily, Key: opts.DestinationKey}
+	if err := db.checkListTurn(source.ColumnFamily, source.Key); err != nil {
+		return nil, err
+	}
 
-	readOpts := grocksdb.NewDefaultReadOptions()
-	readOpts.SetSnapshot(txn.GetSnapshot())
-	readOpts.SetFillCache(false)
-	defer readOpts.Destroy()
+	var moved interface{}
+	err = db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		if source == destination {
+			_, err := tc.modifyList(source, func(l *elementList) (interface{}, error) {
+				element, err := take(l)
+				if err != nil {
+					return nil, err
+				}
+				moved = element
+				return nil, put(l, element)
+			})
+			return err
+		}
 
-	val, err := txn.GetWithCF(readOpts, handle, []byte(opts.Key))
+		element, err := tc.modifyList(source, take)
+		if err != nil {
+			return err
+		}
+		moved = element
+		_, err = tc.modifyList(destination, func(l *elementList) (interface{}, error) {
+			return nil, put(l, element)
+		})
+		return err
+	})
 	if err != nil {
-		txn.Rollback()
 		return nil, err
 	}
-	defer val.Free()
+	return moved, nil
+}
+
+// parseListEnd reports whether a list end names the head ("left") rather than the tail ("right").
+func parseListEnd(end string) (bool, error) {
+	switch end {
+	case "left":
+		return true, nil
+	case "right", "":
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w: list end must be 'left' or 'right'", ErrInvalidListOperation)
+	}
+}
 
-	if !val.Exists() {
-		txn.Rollback()
-		return nil, ErrKeyNotFound
+// PopFromList removes the last element from a list document and returns it. The list reads as
+// empty while clients are blocked on it.
+func (db *DB) PopFromList(opts ListOpOptions) (interface{}, error) {
+	if err := db.checkListTurn(opts.ColumnFamily, opts.Key); err != nil {
+		return nil, err
+	}
+	return db.withListTransaction(opts, removeLast)
+}
+
+// ShiftFromList removes the first element from a list document and returns it. The list reads
+// as empty while clients are blocked on it.
+func (db *DB) ShiftFromList(opts ListOpOptions) (interface{}, error) {
+	if err := db.checkListTurn(opts.ColumnFamily, opts.Key); err != nil {
+		return nil, err
 	}
+	return db.withListTransaction(opts, removeFirst)
+}
+
+// appendElement returns a list modifier that appends element.
+func appendElement(element interface{}) func(*elementList) (interface{}, error) {
+	return func(l *elementList) (interface{}, error) {
+		return nil, l.push(element)
+	}
+}
 
-	var doc model.Document
-	if err := json.Unmarshal(val.Data(), &doc); err != nil {
-		txn.Rollback()
-		return nil, fmt.Errorf("failed to decode document: %w", err)
+// prependElement returns a list modifier that inserts element at the head.
+func prependElement(element interface{}) func(*elementList) (interface{}, error) {
+	return func(l *elementList) (interface{}, error) {
+		return nil, l.unshift(element)
 	}
+}
+
+// removeLast removes and returns the last element of a list.
+func removeLast(l *elementList) (interface{}, error) {
+	return l.pop()
+}
 
-	if model.IsExpired(doc.Meta) {
-		txn.Rollback()
+// removeFirst removes and returns the first element of a list.
+func removeFirst(l *elementList) (interface{}, error) {
+	return l.shift()
+}
+
+// withListTransaction applies a list-modifying function transactionally to a list document.
+func (db *DB) withListTransaction(
+	opts ListOpOptions,
+	modifier func(*elementList) (interface{}, error),
+) (interface{}, error) {
+	var result interface{}
+	err := db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		var err error
+		result, err = tc.modifyList(opts, modifier)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// modifyList applies a list-modifying function to a list document inside a transaction.
+//
+// The list is stored as one key per element; an inline list is converted first. Only the
+// elements the modifier touches and the document header are written.
+func (tc *txnContext) modifyList(
+	opts ListOpOptions,
+	modifier func(*elementList) (interface{}, error),
+) (interface{}, error) {
+	handle, err := tc.family(opts.ColumnFamily, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := tc.getForWrite(handle, opts.ColumnFamily, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
 		return nil, ErrKeyNotFound
 	}
+	if opts.Cas != "" && doc.Meta.Rev != opts.Cas {
+		return nil, ErrRevisionMismatch
+	}
 
 	metaCopy := doc.Meta
 
-	list, ok := doc.Value.([]interface{})
-	if !ok {
-		txn.Rollback()
+	ok, err := tc.convertToElements(opts.ColumnFamily, doc, model.ElementsList)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || doc.Meta.Elements.Kind != model.ElementsList {
 		return nil, ErrInvalidListType
 	}
+	elements, err := tc.db.EnsureSystemColumnFamily(CFSystemElements)
+	if err != nil {
+		return nil, err
+	}
 
-	newList, result, err := modifier(list)
+	// The header is copied so the previous metadata keeps the old bounds.
+	header := *doc.Meta.Elements
+	result, err := modifier(&elementList{tc: tc, handle: elements, cf: opts.ColumnFamily, key: opts.Key, header: &header})
 	if err != nil {
-		txn.Rollback()
 		return nil, err
 	}
 
-	doc.Value = newList
-	doc.Meta.Rev = uuid.NewString()
+	doc.Meta.Elements = &header
 	doc.Meta.UpdatedAt = time.Now()
 
 	if opts.Expiration != nil {
 		if err := model.ValidateExpiration(*opts.Expiration); err != nil {
-			txn.Rollback()
 			return nil, err
 		}
 		doc.Meta.Expiration = *opts.Expiration
 	}
 
-	data, err := json.Marshal(doc)
-	if err != nil {
-		txn.Rollback()
-		return nil, fmt.Errorf("failed to marshal document: %w", err)
-	}
-
-	if err := txn.PutCF(handle, []byte(opts.Key), data); err != nil {
-		txn.Rollback()
-		return nil, fmt.Errorf("failed to write document: %w", err)
-	}
-
-	if err := events.PublishChangeEvent(events.ChangeEventOptions{
-		Txn:                txn,
-		CFName:             opts.ColumnFamily,
-		Key:                opts.Key,
-		Document:           &doc,
+	if err := tc.writeDocument(handle, opts.ColumnFamily, doc, events.ChangeEventOptions{
 		Operation:          events.OpMutate,
 		PreviousMeta:       &metaCopy,
 		ExplicitExpiration: opts.Expiration,
 	}); err != nil {
-		txn.Rollback()
-		return nil, fmt.Errorf("failed to enqueue change event: %w", err)
-	}
-
-	if err := txn.Commit(); err != nil {
-		txn.Rollback()
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, err
 	}
 
 	return result, nil
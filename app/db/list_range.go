@@ -1,14 +1,90 @@
 package db
 
 import (
-	"encoding/json"
 	"fmt"
 	"mithrildb/model"
 )
 
 // GetListRange returns a slice of a list document between the given start and end indices (inclusive).
 func (db *DB) GetListRange(opts ListRangeOptions) ([]interface{}, error) {
-	handle, ok := db.Families[opts.ColumnFamily]
+	doc, err := db.readList(ListReadOptions{ColumnFamily: opts.ColumnFamily, Key: opts.Key, ReadOptions: opts.ReadOptions})
+	if err != nil {
+		return nil, err
+	}
+	if opts.ReadOptions == nil {
+		opts.ReadOptions = db.DefaultReadOptions
+	}
+
+	if header := doc.Meta.Elements; header != nil {
+		start, end, ok := clampListRange(opts.Start, opts.End, int(header.Len))
+		if !ok {
+			return []interface{}{}, nil
+		}
+		return db.listRange(opts.ReadOptions, opts.ColumnFamily, opts.Key, header, start, end)
+	}
+
+	list := doc.Value.([]interface{})
+	start, end, ok := clampListRange(opts.Start, opts.End, len(list))
+	if !ok {
+		return []interface{}{}, nil
+	}
+	return list[start : end+1], nil
+}
+
+// GetListLength returns the number of elements of a list document.
+func (db *DB) GetListLength(opts ListReadOptions) (int64, error) {
+	doc, err := db.readList(opts)
+	if err != nil {
+		return 0, err
+	}
+	return listLength(doc), nil
+}
+
+// GetListIndex returns the element at an index of a list document. Negative indices count
+// from the end.
+func (db *DB) GetListIndex(opts ListIndexOptions) (interface{}, error) {
+	doc, err := db.readList(opts.ListReadOptions)
+	if err != nil {
+		return nil, err
+	}
+	if opts.ReadOptions == nil {
+		opts.ReadOptions = db.DefaultReadOptions
+	}
+
+	n := listLength(doc)
+	i := int64(opts.Index)
+	if i < 0 {
+		i += n
+	}
+	if i < 0 || i >= n {
+		return nil, ErrListIndexOutOfRange
+	}
+
+	if header := doc.Meta.Elements; header != nil {
+		items, err := db.listRange(opts.ReadOptions, opts.ColumnFamily, opts.Key, header, int(i), int(i))
+		if err != nil {
+			return nil, err
+		}
+		if len(items) == 0 {
+			return nil, ErrListIndexOutOfRange
+		}
+		return items[0], nil
+	}
+	return doc.Value.([]interface{})[i], nil
+}
+
+// listLength returns the number of elements of a list document read by readList.
+func listLength(doc *model.Document) int64 {
+	if header := doc.Meta.Elements; header != nil {
+		return header.Len
+	}
+	return int64(len(doc.Value.([]interface{})))
+}
+
+// readList loads a list document, either inline or as a header of elements stored under their
+// own keys.
+func (db *DB) readList(opts ListReadOptions) (*model.Document, error) {
+	handle, ok := db.Family(opts.ColumnFamily)
 	if !ok {
 		return nil, ErrInvalidColumnFamily
 	}
@@ -32,27 +108,28 @@ func (db *DB) GetListRange(opts ListRangeOptions) ([]interface{}, error) {
 	}
 
 	var doc model.Document
-	if err := json.Unmarshal(val.Data(), &doc); err != nil {
+	if err := decodeDocument(val.Data(), &doc); err != nil {
 		return nil, fmt.Errorf("failed to decode document: %w", err)
 	}
 
-	list, ok := doc.Value.([]interface{})
-	if !ok {
+	if header := doc.Meta.Elements; header != nil {
+		if header.Kind != model.ElementsList {
+			return nil, ErrInvalidListType
+		}
+	} else if _, ok := doc.Value.([]interface{}); !ok {
 		return nil, ErrInvalidListType
 	}
+	return &doc, nil
+}
 
-	start := opts.Start
-	end := opts.End
-
+// clampListRange bounds a requested range to a list of length n. A negative end means the
+// end of the list. It reports false when the range is empty.
+func clampListRange(start, end, n int) (int, int, bool) {
 	if start < 0 {
 		start = 0
 	}
-	if end < 0 || end >= len(list) {
-		end = len(list) - 1
-	}
-	if start > end {
-		return []interface{}{}, nil
+	if end < 0 || end >= n {
+		end = n - 1
 	}
-
-	return list[start : end+1], nil
+	return start, end, start <= end
 }
@@ -0,0 +1,166 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PopFromListBlocking removes and returns the last element of a list document, or the first
+// with fromHead, waiting up to timeout for an element to be pushed while the list is empty or
+// does not exist yet.
+//
+// Waiting clients of a list are served in arrival order: only the first in line pops, and a
+// client that arrives while others wait queues behind them even if an element is available.
+// It gives up with ErrEmptyList when the timeout elapses, and with the context's error when
+// ctx is cancelled.
+func (db *DB) PopFromListBlocking(ctx context.Context, opts ListOpOptions, fromHead bool, timeout time.Duration) (interface{}, error) {
+	if opts.TxnID != "" {
+		return nil, fmt.Errorf("%w: blocking pops cannot run inside a transaction", ErrInvalidListOperation)
+	}
+	modifier := removeLast
+	if fromHead {
+		modifier = removeFirst
+	}
+
+	id := listWaitKey(opts.ColumnFamily, opts.Key)
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	// Queueing before the first attempt means a push committed after it always wakes us.
+	w := db.listWaiters.add(id)
+	defer db.listWaiters.leave(id, w)
+	for {
+		if db.listWaiters.first(id, w) {
+			element, err := db.withListTransaction(opts, modifier)
+			if !errors.Is(err, ErrEmptyList) && !errors.Is(err, ErrKeyNotFound) {
+				return element, err
+			}
+		}
+
+		select {
+		case <-w.ready:
+		case <-timer.C:
+			return nil, ErrEmptyList
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// checkListTurn fails with ErrEmptyList while clients are blocked on a list, so pops that do
+// not wait queue behind them with no timeout instead of taking the elements pushed for them.
+func (db *DB) checkListTurn(cf, key string) error {
+	if db.listWaiters.busy(listWaitKey(cf, key)) {
+		return ErrEmptyList
+	}
+	return nil
+}
+
+// listWaitKey identifies a list document among the waiters.
+func listWaitKey(cf, key string) string {
+	return cf + "\x00" + key
+}
+
+// listPush records a list left with count elements by a transaction, to wake waiters once it
+// commits. Appends to a stream wake every reader, with a negative count.
+type listPush struct {
+	id    string
+	count int64
+}
+
+// listWaiter is a client blocked on an empty list.
+type listWaiter struct {
+	ready chan struct{}
+}
+
+// signal wakes the waiter, unless a wake-up is already pending.
+func (w *listWaiter) signal() {
+	select {
+	case w.ready <- struct{}{}:
+	default:
+	}
+}
+
+// listWaiters tracks the clients blocked popping empty lists, in arrival order. Only the first
+// waiter of a list pops; it is woken by pushes and, once it leaves, wakes the next one.
+type listWaiters struct {
+	mu      sync.Mutex
+	waiting map[string][]*listWaiter
+}
+
+// add queues a waiter at the back of a list's line.
+func (lw *listWaiters) add(id string) *listWaiter {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	if lw.waiting == nil {
+		lw.waiting = make(map[string][]*listWaiter)
+	}
+	w := &listWaiter{ready: make(chan struct{}, 1)}
+	lw.waiting[id] = append(lw.waiting[id], w)
+	return w
+}
+
+// first reports whether a waiter is first in line.
+func (lw *listWaiters) first(id string, w *listWaiter) bool {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	queue := lw.waiting[id]
+	return len(queue) > 0 && queue[0] == w
+}
+
+// busy reports whether clients are waiting on a list.
+func (lw *listWaiters) busy(id string) bool {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	return len(lw.waiting[id]) > 0
+}
+
+// leave removes a waiter that is done. When it was first in line, the next waiter takes its
+// turn and is woken to try, since elements may be left for it.
+func (lw *listWaiters) leave(id string, w *listWaiter) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	queue := lw.waiting[id]
+	for i, q := range queue {
+		if q != w {
+			continue
+		}
+		queue = append(queue[:i], queue[i+1:]...)
+		if len(queue) == 0 {
+			delete(lw.waiting, id)
+			return
+		}
+		lw.waiting[id] = queue
+		if i == 0 {
+			queue[0].signal()
+		}
+		return
+	}
+}
+
+// wake wakes the first waiter of a list after n elements were pushed, or every waiter when n
+// is negative.
+func (lw *listWaiters) wake(id string, n int64) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	queue := lw.waiting[id]
+	if len(queue) == 0 || n == 0 {
+		return
+	}
+	if n > 0 {
+		queue = queue[:1]
+	}
+	for _, w := range queue {
+		w.signal()
+	}
+}
+
+// wakeListWaiters wakes the clients waiting on the lists a committed transaction pushed to.
+func (db *DB) wakeListWaiters(pushes []listPush) {
+	for _, p := range pushes {
+		db.listWaiters.wake(p.id, p.count)
+	}
+}
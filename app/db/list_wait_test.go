@@ -0,0 +1,58 @@
+package db
+
+import "testing"
+
+func TestListWaitersServeInArrivalOrder(t *testing.T) {
+	var lw listWaiters
+	first := lw.add("l")
+	second := lw.add("l")
+
+	if !lw.first("l", first) || lw.first("l", second) {
+		t.Fatal("only the oldest waiter should be first in line")
+	}
+	if !lw.busy("l") || lw.busy("other") {
+		t.Fatal("busy should report lists with waiters only")
+	}
+
+	// A push wakes the first waiter only.
+	lw.wake("l", 2)
+	select {
+	case <-first.ready:
+	default:
+		t.Fatal("first waiter was not woken")
+	}
+	select {
+	case <-second.ready:
+		t.Fatal("second waiter was woken before its turn")
+	default:
+	}
+
+	// Once served, the first waiter hands its turn on.
+	lw.leave("l", first)
+	if !lw.first("l", second) {
+		t.Fatal("second waiter should be first in line")
+	}
+	select {
+	case <-second.ready:
+	default:
+		t.Fatal("second waiter was not woken when its turn came")
+	}
+
+	lw.leave("l", second)
+	if lw.busy("l") {
+		t.Fatal("list should have no waiters left")
+	}
+}
+
+func TestListWaitersWakeEveryStreamReader(t *testing.T) {
+	var lw listWaiters
+	readers := []*listWaiter{lw.add("s"), lw.add("s"), lw.add("s")}
+	lw.wake("s", -1)
+	for i, w := range readers {
+		select {
+		case <-w.ready:
+		default:
+			t.Errorf("reader %d was not woken", i)
+		}
+	}
+}
@@ -2,8 +2,10 @@ package db
 
 import (
 	"mithrildb/config"
+	"mithrildb/model"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/linxGnu/grocksdb"
 )
@@ -13,6 +15,46 @@ type DocumentReadOptions struct {
 	ColumnFamily string
 	Key          string
 	ReadOptions  *grocksdb.ReadOptions
+	TxnID        string // Interactive transaction to run in, if any
+}
+
+// DocumentPathReadOptions contains options for reading selected paths of a document.
+type DocumentPathReadOptions struct {
+	ColumnFamily string
+	Key          string
+	Paths        []string
+	ReadOptions  *grocksdb.ReadOptions
+	TxnID        string // Interactive transaction to run in, if any
+}
+
+// DocumentHistoryOptions contains options for listing the revisions of a document.
+type DocumentHistoryOptions struct {
+	ColumnFamily  string
+	Key           string
+	Limit         int  // Maximum number of revisions returned, newest first (0 = all)
+	IncludeValues bool // Return the stored document of each revision
+	ReadOptions   *grocksdb.ReadOptions
+}
+
+// DocumentRevisionReadOptions contains options for reading a past version of a document.
+// Either Rev or AsOf selects the version.
+type DocumentRevisionReadOptions struct {
+	ColumnFamily string
+	Key          string
+	Rev          string    // Revision to return
+	AsOf         time.Time // Return the version that was current at this time
+	ReadOptions  *grocksdb.ReadOptions
+}
+
+// DocumentRevertOptions defines parameters for restoring a past revision of a document.
+type DocumentRevertOptions struct {
+	ColumnFamily string
+	Key          string
+	Rev          string // Revision whose value is restored
+	Cas          string
+	Expiration   *int64
+	WriteOptions *grocksdb.WriteOptions
+	TxnID        string // Interactive transaction to run in, if any
 }
 
 // DocumentWriteOptions defines configurable parameters for document insertion or update.
@@ -22,16 +64,99 @@ type DocumentWriteOptions struct {
 	Value        interface{}
 	Cas          string
 	Type         string
+	ContentType  string // Media type recorded for blob documents
 	Expiration   *int64
 	WriteOptions *grocksdb.WriteOptions
+	TxnID        string // Interactive transaction to run in, if any
+}
+
+// DocumentMutateOptions defines parameters for applying sub-document operations.
+type DocumentMutateOptions struct {
+	ColumnFamily string
+	Key          string
+	Operations   []model.MutationOp
+	Cas          string
+	Expiration   *int64
+	WriteOptions *grocksdb.WriteOptions
+	TxnID        string // Interactive transaction to run in, if any
+}
+
+// DocumentPatchOptions defines parameters for applying a merge patch or JSON patch.
+type DocumentPatchOptions struct {
+	ColumnFamily string
+	Key          string
+	PatchType    string // model.PatchTypeMerge or model.PatchTypeJSON
+	Patch        []byte // Raw patch document
+	Cas          string
+	Expiration   *int64
+	WriteOptions *grocksdb.WriteOptions
+	TxnID        string // Interactive transaction to run in, if any
+}
+
+// BulkPutEntry describes one document written by BulkPutDocuments.
+type BulkPutEntry struct {
+	Key        string
+	Value      interface{}
+	Type       string // Document type (default: json)
+	Cas        string // Optional revision the document must have
+	Expiration *int64 // Overrides the shared expiration
 }
 
 // BulkWriteOptions contains parameters for inserting or replacing multiple documents.
 type BulkWriteOptions struct {
 	ColumnFamily string
-	Documents    map[string]interface{}
+	Entries      []BulkPutEntry
+	Expiration   *int64 // Expiration applied to entries without their own
+	Partial      bool   // Commit the entries that succeed instead of failing the whole request
+	WriteOptions *grocksdb.WriteOptions
+	TxnID        string // Interactive transaction to run in, if any
+}
+
+// BulkKeyEntry identifies one document of a bulk delete or touch.
+type BulkKeyEntry struct {
+	Key        string
+	Cas        string // Optional revision the document must have
+	Expiration *int64 // Bulk touch only; overrides the shared expiration
+}
+
+// BulkKeyOptions contains parameters for deleting or touching multiple documents.
+type BulkKeyOptions struct {
+	ColumnFamily string
+	Entries      []BulkKeyEntry
+	Expiration   *int64 // Expiration applied by bulk touch to entries without their own
+	Actor        string // Who deleted the documents, recorded in the trash by bulk delete
+	WriteOptions *grocksdb.WriteOptions
+	TxnID        string // Interactive transaction to run in, if any
+}
+
+// RangeDeleteOptions selects the documents removed by DeleteDocumentRange.
+// Either Prefix or both Start and End must be set.
+type RangeDeleteOptions struct {
+	ColumnFamily string
+	Prefix       string
+	Start        string // First key to delete (inclusive)
+	End          string // Key where deletion stops (exclusive)
+	WriteOptions *grocksdb.WriteOptions
+}
+
+// BatchOperation describes one operation of an atomic batch.
+type BatchOperation struct {
+	Op           string // One of the BatchOp* constants
+	ColumnFamily string
+	Key          string
+	Value        interface{} // Document value for put, insert and replace
+	Type         string      // Document type for put, insert and replace
+	Cas          string      // Optional revision the document must have
 	Expiration   *int64
+	Delta        int64       // Counter delta for counter_delta
+	Element      interface{} // Element for list and set operations
+}
+
+// BatchOptions contains the operations executed by ExecuteBatch.
+type BatchOptions struct {
+	Operations   []BatchOperation
 	WriteOptions *grocksdb.WriteOptions
+	TxnID        string // Interactive transaction to run in, if any
 }
 
 // BulkReadOptions contains parameters for reading multiple documents.
@@ -41,20 +166,84 @@ type BulkReadOptions struct {
 	ReadOptions  *grocksdb.ReadOptions
 }
 
+// CounterBounds limits the value of a counter. Nil bounds are not enforced.
+type CounterBounds struct {
+	Min   interface{} // Lowest value allowed, an int64 or a float64
+	Max   interface{} // Highest value allowed, an int64 or a float64
+	Clamp bool        // Clamp values out of the bounds instead of failing with ErrCounterOutOfRange
+}
+
 // CounterIncrementOptions holds the parameters for incrementing a counter document.
 type CounterIncrementOptions struct {
 	ColumnFamily string
 	Key          string
-	Delta        int64
+	Delta        interface{} // An int64, or a float64 to make the counter a float counter
+	Initial      interface{} // Value of the counter created when it does not exist; nil to fail with ErrKeyNotFound
+	CounterBounds
+	Cas          string // Optional revision the counter must have
+	Expiration   *int64
+	WriteOptions *grocksdb.WriteOptions
+	TxnID        string // Interactive transaction to run in, if any
+}
+
+// CounterSetOptions holds the parameters for replacing the value of a counter document and
+// getting the previous one.
+type CounterSetOptions struct {
+	ColumnFamily string
+	Key          string
+	Value        interface{} // An int64 or a float64
+	Cas          string      // Optional revision the counter must have
+	Expiration   *int64
+	WriteOptions *grocksdb.WriteOptions
+	TxnID        string // Interactive transaction to run in, if any
+}
+
+// CounterIncrementEntry describes one counter incremented by IncrementCounters.
+type CounterIncrementEntry struct {
+	Key     string
+	Delta   interface{} // An int64 or a float64
+	Initial interface{} // Value of the counter created when it does not exist; nil to fail with ErrKeyNotFound
+	CounterBounds
+	Cas string // Optional revision the counter must have
+}
+
+// CounterMultiIncrementOptions holds the parameters for incrementing several counters at once.
+type CounterMultiIncrementOptions struct {
+	ColumnFamily string
+	Entries      []CounterIncrementEntry
 	Expiration   *int64
 	WriteOptions *grocksdb.WriteOptions
+	TxnID        string // Interactive transaction to run in, if any
 }
 
 // DocumentDeleteOptions contains parameters for deleting a document.
 type DocumentDeleteOptions struct {
 	ColumnFamily string
 	Key          string
+	Cas          string // Optional revision the document must have
+	MustExist    bool   // Fail with ErrRevisionMismatch when the document does not exist
+	Permanent    bool   // Skip the column family's trash
+	Actor        string // Who deleted the document, recorded in the trash
 	WriteOptions *grocksdb.WriteOptions
+	TxnID        string // Interactive transaction to run in, if any
+}
+
+// DocumentUndeleteOptions defines parameters for restoring a document from the trash.
+type DocumentUndeleteOptions struct {
+	ColumnFamily string
+	Key          string
+	Expiration   *int64 // Replaces the expiration the document had when deleted
+	WriteOptions *grocksdb.WriteOptions
+	TxnID        string // Interactive transaction to run in, if any
+}
+
+// TrashListOptions defines parameters to list the soft-deleted documents of a column family.
+type TrashListOptions struct {
+	ColumnFamily string
+	Prefix       string
+	StartAfter   string
+	Limit        int
+	ReadOptions  *grocksdb.ReadOptions
 }
 
 // KeyListOptions defines parameters to list document keys from a column family.
@@ -75,6 +264,19 @@ type ListRangeOptions struct {
 	ReadOptions  *grocksdb.ReadOptions
 }
 
+// ListReadOptions defines base parameters for list reads.
+type ListReadOptions struct {
+	ColumnFamily string
+	Key          string
+	ReadOptions  *grocksdb.ReadOptions
+}
+
+// ListIndexOptions defines parameters for reading the list element at an index.
+type ListIndexOptions struct {
+	ListReadOptions
+	Index int // Negative indices count from the end
+}
+
 // SetContainsOptions defines parameters to check if a set contains an element.
 type SetContainsOptions struct {
 	ColumnFamily string
@@ -83,23 +285,329 @@ type SetContainsOptions struct {
 	ReadOptions  *grocksdb.ReadOptions
 }
 
+// SetReadOptions defines base parameters for set reads.
+type SetReadOptions struct {
+	ColumnFamily string
+	Key          string
+	ReadOptions  *grocksdb.ReadOptions
+}
+
+// SetMembersOptions defines parameters for reading a page of set members in member order.
+type SetMembersOptions struct {
+	SetReadOptions
+	Cursor string // Opaque cursor returned by the previous page
+	Limit  int    // Maximum number of members (0 = no limit)
+}
+
+// SetAlgebraOptions defines parameters for combining several set documents of a column family.
+type SetAlgebraOptions struct {
+	ColumnFamily string
+	Keys         []string
+	Store        string // Key of a set document to write the result to, replacing it; empty to only return it
+	Expiration   *int64 // Expiration of the stored result
+	ReadOptions  *grocksdb.ReadOptions
+	WriteOptions *grocksdb.WriteOptions
+	TxnID        string
+}
+
 // ListOpOptions defines base parameters for list operations.
 type ListOpOptions struct {
 	ColumnFamily string
 	Key          string
+	Cas          string // Optional revision the document must have
 	WriteOptions *grocksdb.WriteOptions
+	TxnID        string // Interactive transaction to run in, if any
 	Expiration   *int64
 }
 
 // ListPushOptions extends ListOpOptions with an element to push/unshift.
 type ListPushOptions struct {
+	ListOpOptions
+	Element  interface{}
+	Elements []interface{} // Several elements pushed in order, used instead of Element when set
+}
+
+// ListSetOptions defines parameters for replacing the list element at an index.
+type ListSetOptions struct {
+	ListOpOptions
+	Index   int // Negative indices count from the end
+	Element interface{}
+}
+
+// ListInsertOptions defines parameters for inserting an element next to a pivot element.
+type ListInsertOptions struct {
+	ListOpOptions
+	Pivot   interface{}
+	Element interface{}
+	After   bool // Insert after the pivot instead of before it
+}
+
+// ListRemoveOptions defines parameters for removing elements equal to a value.
+type ListRemoveOptions struct {
 	ListOpOptions
 	Element interface{}
+	Count   int // >0 removes from the head, <0 from the tail, 0 removes every match
+}
+
+// ListTrimOptions defines parameters for trimming a list to a range. Negative indices count
+// from the end and both ends are inclusive.
+type ListTrimOptions struct {
+	ListOpOptions
+	Start int
+	Stop  int
+}
+
+// ListMoveOptions defines parameters for moving an element between two lists.
+type ListMoveOptions struct {
+	SourceFamily      string
+	SourceKey         string
+	DestinationFamily string
+	DestinationKey    string
+	From              string // "left" or "right" end of the source
+	To                string // "left" or "right" end of the destination
+	WriteOptions      *grocksdb.WriteOptions
+	TxnID             string // Interactive transaction to run in, if any
 }
 
 // SetOpOptions defines base parameters for set operations.
 type SetOpOptions = ListOpOptions
 
+// SetPopOptions defines parameters for removing random members of a set.
+type SetPopOptions struct {
+	SetOpOptions
+	Count int
+}
+
+// ZSetOpOptions defines base parameters for sorted set write operations.
+type ZSetOpOptions = ListOpOptions
+
+// ZAddOptions defines parameters for adding members to a sorted set.
+type ZAddOptions struct {
+	ZSetOpOptions
+	Entries []model.ZSetEntry
+	NX      bool // Only add new members
+	XX      bool // Only update existing members
+	GT      bool // Only update a member when the new score is greater
+	LT      bool // Only update a member when the new score is lower
+}
+
+// ZIncrByOptions defines parameters for incrementing the score of a sorted set member.
+type ZIncrByOptions struct {
+	ZSetOpOptions
+	Member    string
+	Increment float64
+}
+
+// ZRemOptions defines parameters for removing members from a sorted set.
+type ZRemOptions struct {
+	ZSetOpOptions
+	Members []string
+}
+
+// ZPopOptions defines parameters for removing the lowest or highest scored members.
+type ZPopOptions struct {
+	ZSetOpOptions
+	Count int  // Number of members to pop (default 1)
+	Max   bool // Pop the highest scores instead of the lowest
+}
+
+// ZSetReadOptions defines base parameters for sorted set reads.
+type ZSetReadOptions struct {
+	ColumnFamily string
+	Key          string
+	ReadOptions  *grocksdb.ReadOptions
+}
+
+// ZRangeOptions defines parameters for reading a sorted set by rank. Negative ranks count
+// from the end; both ends are inclusive.
+type ZRangeOptions struct {
+	ZSetReadOptions
+	Start   int
+	Stop    int
+	Reverse bool // Rank from the highest score
+}
+
+// ZRangeByScoreOptions defines parameters for reading a sorted set by score.
+type ZRangeByScoreOptions struct {
+	ZSetReadOptions
+	Min     model.ScoreBound
+	Max     model.ScoreBound
+	Offset  int
+	Limit   int  // Maximum number of entries (0 = no limit)
+	Reverse bool // Return the highest scores first
+}
+
+// HashOpOptions defines base parameters for hash write operations.
+type HashOpOptions = ListOpOptions
+
+// HSetOptions defines parameters for setting hash fields.
+type HSetOptions struct {
+	HashOpOptions
+	Fields map[string]interface{}
+}
+
+// HDelOptions defines parameters for removing hash fields.
+type HDelOptions struct {
+	HashOpOptions
+	Fields []string
+}
+
+// HIncrByOptions defines parameters for incrementing an integer hash field.
+type HIncrByOptions struct {
+	HashOpOptions
+	Field string
+	Delta int64
+}
+
+// HashReadOptions defines base parameters for hash reads.
+type HashReadOptions struct {
+	ColumnFamily string
+	Key          string
+	ReadOptions  *grocksdb.ReadOptions
+}
+
+// HGetAllOptions defines parameters for reading a page of hash fields in field order.
+type HGetAllOptions struct {
+	HashReadOptions
+	StartAfter string // Only return fields sorting after this one
+	Limit      int    // Maximum number of fields (0 = no limit)
+}
+
+// StreamOpOptions defines base parameters for stream write operations.
+type StreamOpOptions = ListOpOptions
+
+// StreamTrimLimits bounds the entries kept by a stream. Zero values set no limit.
+type StreamTrimLimits struct {
+	MaxLen int64         // Entries to keep
+	MaxAge time.Duration // Drop entries with IDs older than this
+	MinID  string        // Drop entries with IDs lower than this
+}
+
+// StreamAppendOptions defines parameters for appending an entry to a stream.
+type StreamAppendOptions struct {
+	StreamOpOptions
+	ID    string // Entry ID; empty or "*" to generate it from the clock, "<ms>-*" to generate its sequence number
+	Value interface{}
+	Trim  StreamTrimLimits // Applied after the entry is appended
+}
+
+// StreamTrimOptions defines parameters for trimming the oldest entries of a stream.
+type StreamTrimOptions struct {
+	StreamOpOptions
+	StreamTrimLimits
+}
+
+// StreamReadOptions defines base parameters for stream reads.
+type StreamReadOptions struct {
+	ColumnFamily string
+	Key          string
+	ReadOptions  *grocksdb.ReadOptions
+}
+
+// StreamRangeOptions defines parameters for reading the entries of a stream between two IDs,
+// inclusive. "-" and "+" stand for the first and last entries.
+type StreamRangeOptions struct {
+	StreamReadOptions
+	Start   string
+	End     string
+	Limit   int  // Maximum number of entries (0 = no limit)
+	Reverse bool // Return the newest entries first
+}
+
+// StreamTailOptions defines parameters for following the entries appended to a stream.
+type StreamTailOptions struct {
+	StreamReadOptions
+	After string // Only return entries after this ID; empty or "$" for the last ID of the stream
+	Limit int    // Maximum number of entries (0 = no limit)
+}
+
+// StreamGroupOptions defines parameters for creating a consumer group.
+type StreamGroupOptions struct {
+	StreamOpOptions
+	Group   string
+	StartID string // Deliver entries after this ID; empty or "$" for only new entries, "0" for all
+}
+
+// StreamGroupReadOptions defines parameters for reading new entries as a consumer of a group.
+type StreamGroupReadOptions struct {
+	StreamOpOptions
+	Group    string
+	Consumer string
+	Limit    int // Maximum number of entries (0 = no limit)
+}
+
+// StreamAckOptions defines parameters for acknowledging entries delivered to a group.
+type StreamAckOptions struct {
+	StreamOpOptions
+	Group string
+	IDs   []string
+}
+
+// StreamClaimOptions defines parameters for taking over the pending entries of a group that
+// have not been acknowledged for a while.
+type StreamClaimOptions struct {
+	StreamOpOptions
+	Group    string
+	Consumer string        // Consumer the entries are delivered to
+	MinIdle  time.Duration // Only claim entries delivered at least this long ago
+	Limit    int           // Maximum number of entries (0 = no limit)
+}
+
+// StreamPendingOptions defines parameters for listing the pending entries of a group.
+type StreamPendingOptions struct {
+	StreamReadOptions
+	Group    string
+	Consumer string // Only list the entries of this consumer, if set
+	After    string // Only list entries after this ID
+	Limit    int    // Maximum number of entries (0 = no limit)
+}
+
+// TimeSeriesOpOptions defines base parameters for time series write operations.
+type TimeSeriesOpOptions = ListOpOptions
+
+// TimeSeriesAddOptions defines parameters for adding samples to a time series.
+type TimeSeriesAddOptions struct {
+	TimeSeriesOpOptions
+	Samples []model.TimeSeriesSample // A sample replaces the one with the same timestamp
+}
+
+// TimeSeriesDeleteOptions defines parameters for removing the samples of a time series
+// between two timestamps, inclusive.
+type TimeSeriesDeleteOptions struct {
+	TimeSeriesOpOptions
+	From int64
+	To   int64
+}
+
+// TimeSeriesConfigureOptions defines parameters for setting the retention and compaction
+// rules of a time series.
+type TimeSeriesConfigureOptions struct {
+	TimeSeriesOpOptions
+	Settings model.TimeSeriesSettings
+}
+
+// TimeSeriesReadOptions defines base parameters for time series reads.
+type TimeSeriesReadOptions = StreamReadOptions
+
+// TimeSeriesRangeOptions defines parameters for reading the samples of a time series between
+// two timestamps, inclusive.
+type TimeSeriesRangeOptions struct {
+	TimeSeriesReadOptions
+	From    int64
+	To      int64
+	Labels  map[string]string // Only return samples with these labels
+	Limit   int               // Maximum number of samples (0 = no limit)
+	Reverse bool              // Return the newest samples first
+}
+
+// TimeSeriesAggregateOptions defines parameters for aggregating the samples of a time series
+// between two timestamps, inclusive, into buckets.
+type TimeSeriesAggregateOptions struct {
+	TimeSeriesRangeOptions               // Limit counts buckets
+	Aggregation            string        // min, max, avg, sum or count
+	Bucket                 time.Duration // Bucket duration; buckets start at multiples of it since the Unix epoch
+}
+
 func HasWriteOptions(r *http.Request) bool {
 	return r.URL.Query().Has("sync") || r.URL.Query().Has("disable_wal") || r.URL.Query().Has("no_slowdown")
 }
@@ -0,0 +1,359 @@
+package db
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"mithrildb/model"
+
+	"github.com/google/uuid"
+)
+
+// maxQueuePromotions bounds how many delayed or timed out messages one receive makes ready;
+// the rest are made ready by the following receives.
+const maxQueuePromotions = 1000
+
+// EnqueueOptions defines a message to add to a job queue.
+type EnqueueOptions struct {
+	Body     interface{}
+	Delay    time.Duration // Time before the message can be received
+	Priority int           // 0 to 255; higher priorities are received first
+}
+
+// Enqueue adds a message to a queue, creating the queue with default settings if needed.
+func (m *QueueManager) Enqueue(name string, opts EnqueueOptions) (*model.QueueMessage, error) {
+	if err := ValidateQueueName(name); err != nil {
+		return nil, err
+	}
+	body, err := model.NormalizeValue(opts.Body)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, ErrNilValue
+	}
+	if opts.Priority < 0 || opts.Priority > maxQueuePriority {
+		return nil, fmt.Errorf("%w: priority must be between 0 and %d", ErrInvalidQueueOperation, maxQueuePriority)
+	}
+	if opts.Delay < 0 || opts.Delay > m.cfg.MaxDelay {
+		return nil, fmt.Errorf("%w: delay must be between 0 and %s", ErrInvalidQueueOperation, m.cfg.MaxDelay)
+	}
+
+	var msg *model.QueueMessage
+	err = m.db.runInTransaction("", nil, func(tc *txnContext) error {
+		q, err := m.open(tc, name, true)
+		if err != nil {
+			return err
+		}
+		msg, err = q.enqueue(body, opts.Priority, opts.Delay)
+		if err != nil {
+			return err
+		}
+		return q.save()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// Receive leases up to max ready messages, highest priority first, for a visibility timeout.
+// A zero max receives one message and a zero visibility uses the queue's timeout. The returned
+// messages carry the receipt needed to ack or nack them.
+func (m *QueueManager) Receive(name string, max int, visibility time.Duration) ([]model.QueueMessage, error) {
+	if err := ValidateQueueName(name); err != nil {
+		return nil, err
+	}
+	if max == 0 {
+		max = 1
+	}
+	if max < 0 || max > m.cfg.MaxReceive {
+		return nil, fmt.Errorf("%w: max must be between 1 and %d", ErrInvalidQueueOperation, m.cfg.MaxReceive)
+	}
+	if visibility < 0 || visibility > m.cfg.MaxVisibility {
+		return nil, fmt.Errorf("%w: visibility timeout must be positive and at most %s", ErrInvalidQueueOperation, m.cfg.MaxVisibility)
+	}
+
+	var received []model.QueueMessage
+	err := m.db.runInTransaction("", nil, func(tc *txnContext) error {
+		q, err := m.open(tc, name, false)
+		if err != nil {
+			return err
+		}
+		if visibility == 0 {
+			visibility = q.limits.visibility
+		}
+		now := time.Now()
+		if err := q.promote(now); err != nil {
+			return err
+		}
+		if received, err = q.receive(max, now.Add(visibility)); err != nil {
+			return err
+		}
+		return q.save()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return received, nil
+}
+
+// Ack removes a received message. The receipt must be the one of its current delivery.
+func (m *QueueManager) Ack(name string, id uint64, receipt string) error {
+	if err := ValidateQueueName(name); err != nil {
+		return err
+	}
+	return m.db.runInTransaction("", nil, func(tc *txnContext) error {
+		q, err := m.open(tc, name, false)
+		if err != nil {
+			return err
+		}
+		msg, err := q.leased(id, receipt)
+		if err != nil {
+			return err
+		}
+		if err := q.remove(msg); err != nil {
+			return err
+		}
+		q.state.Stats.InFlight--
+		q.state.Stats.Acked++
+		return q.save()
+	})
+}
+
+// Nack gives a received message back for a retry, after delay or, when delay is nil, after
+// the queue's retry backoff. A message out of attempts is dead-lettered instead. The receipt
+// must be the one of its current delivery.
+func (m *QueueManager) Nack(name string, id uint64, receipt string, delay *time.Duration) error {
+	if err := ValidateQueueName(name); err != nil {
+		return err
+	}
+	if delay != nil && (*delay < 0 || *delay > m.cfg.MaxDelay) {
+		return fmt.Errorf("%w: delay must be between 0 and %s", ErrInvalidQueueOperation, m.cfg.MaxDelay)
+	}
+	return m.db.runInTransaction("", nil, func(tc *txnContext) error {
+		q, err := m.open(tc, name, false)
+		if err != nil {
+			return err
+		}
+		msg, err := q.leased(id, receipt)
+		if err != nil {
+			return err
+		}
+		if err := q.tc.txn.DeleteCF(q.handle, queueWaitKey(q.name, msg.VisibleAt, msg.ID)); err != nil {
+			return fmt.Errorf("failed to update queue message: %w", conflictError(err))
+		}
+		q.state.Stats.InFlight--
+
+		wait := q.backoff(msg.Attempts)
+		if delay != nil {
+			wait = *delay
+		}
+		if err := q.retry(msg, time.Now().Add(wait)); err != nil {
+			return err
+		}
+		return q.save()
+	})
+}
+
+// enqueue adds a new message to the queue.
+func (q *openQueue) enqueue(body interface{}, priority int, delay time.Duration) (*model.QueueMessage, error) {
+	now := time.Now()
+	msg := &model.QueueMessage{
+		ID:         q.state.NextID,
+		Body:       body,
+		Priority:   priority,
+		EnqueuedAt: now,
+		VisibleAt:  now.Add(delay),
+	}
+	q.state.NextID++
+	q.state.Stats.Enqueued++
+
+	if delay > 0 {
+		q.state.Stats.Delayed++
+		return msg, q.put(msg, queueWaitKey(q.name, msg.VisibleAt, msg.ID))
+	}
+	q.state.Stats.Ready++
+	return msg, q.put(msg, queueReadyKey(q.name, msg.Priority, msg.ID))
+}
+
+// promote makes ready the delayed messages whose delay has passed, and gives back or
+// dead-letters the in-flight messages whose visibility timeout has ended.
+func (q *openQueue) promote(now time.Time) error {
+	until := appendUint64(q.prefix('w'), uint64(now.UnixNano())+1)
+	keys, err := q.scan(q.prefix('w'), until, maxQueuePromotions)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		msg, err := q.load(binary.BigEndian.Uint64(k[len(k)-8:]))
+		if err != nil {
+			return err
+		}
+		if err := q.tc.txn.DeleteCF(q.handle, k); err != nil {
+			return fmt.Errorf("failed to update queue message: %w", conflictError(err))
+		}
+
+		if msg.Receipt == "" {
+			q.state.Stats.Delayed--
+			q.state.Stats.Ready++
+			if err := q.put(msg, queueReadyKey(q.name, msg.Priority, msg.ID)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// The receiver did not ack in time.
+		q.state.Stats.InFlight--
+		if err := q.retry(msg, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// receive leases up to max ready messages until visibleAt.
+func (q *openQueue) receive(max int, visibleAt time.Time) ([]model.QueueMessage, error) {
+	keys, err := q.scan(q.prefix('r'), nil, max)
+	if err != nil {
+		return nil, err
+	}
+	received := make([]model.QueueMessage, 0, len(keys))
+	for _, k := range keys {
+		msg, err := q.load(binary.BigEndian.Uint64(k[len(k)-8:]))
+		if err != nil {
+			return nil, err
+		}
+		if err := q.tc.txn.DeleteCF(q.handle, k); err != nil {
+			return nil, fmt.Errorf("failed to update queue message: %w", conflictError(err))
+		}
+
+		msg.Attempts++
+		msg.Receipt = uuid.NewString()
+		msg.VisibleAt = visibleAt
+		if err := q.put(msg, queueWaitKey(q.name, msg.VisibleAt, msg.ID)); err != nil {
+			return nil, err
+		}
+		q.state.Stats.Ready--
+		q.state.Stats.InFlight++
+		q.state.Stats.Received++
+		received = append(received, *msg)
+	}
+	return received, nil
+}
+
+// retry schedules a message whose delivery failed to be received again at visibleAt, right
+// away if that has passed, or moves it to the dead-letter queue when it is out of attempts.
+// The message must already be out of the delivery indexes.
+func (q *openQueue) retry(msg *model.QueueMessage, visibleAt time.Time) error {
+	if q.limits.maxAttempts > 0 && msg.Attempts >= q.limits.maxAttempts {
+		return q.deadLetter(msg)
+	}
+	q.state.Stats.Retried++
+	msg.Receipt = ""
+	msg.VisibleAt = visibleAt
+	if !visibleAt.After(time.Now()) {
+		q.state.Stats.Ready++
+		return q.put(msg, queueReadyKey(q.name, msg.Priority, msg.ID))
+	}
+	q.state.Stats.Delayed++
+	return q.put(msg, queueWaitKey(q.name, msg.VisibleAt, msg.ID))
+}
+
+// deadLetter removes a message that ran out of attempts and enqueues it to the dead-letter
+// queue, if the queue has one.
+func (q *openQueue) deadLetter(msg *model.QueueMessage) error {
+	if err := q.tc.txn.DeleteCF(q.handle, queueMessageKey(q.name, msg.ID)); err != nil {
+		return fmt.Errorf("failed to delete queue message: %w", conflictError(err))
+	}
+	q.state.Stats.DeadLettered++
+
+	target := q.state.Settings.DeadLetterQueue
+	if target == "" {
+		return nil
+	}
+	dlq, err := q.m.open(q.tc, target, true)
+	if err != nil {
+		return err
+	}
+	moved, err := dlq.enqueue(msg.Body, msg.Priority, 0)
+	if err != nil {
+		return err
+	}
+	moved.DeadLetteredFrom = q.name
+	if err := dlq.put(moved, nil); err != nil {
+		return err
+	}
+	return dlq.save()
+}
+
+// leased loads a message and checks that receipt is the lease of its current delivery.
+func (q *openQueue) leased(id uint64, receipt string) (*model.QueueMessage, error) {
+	msg, err := q.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if receipt == "" || msg.Receipt != receipt {
+		return nil, ErrQueueReceiptMismatch
+	}
+	return msg, nil
+}
+
+// load reads a message.
+func (q *openQueue) load(id uint64) (*model.QueueMessage, error) {
+	val, err := q.tc.txn.GetWithCF(q.tc.readOpts, q.handle, queueMessageKey(q.name, id))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	if !val.Exists() {
+		return nil, ErrQueueMessageNotFound
+	}
+	var msg model.QueueMessage
+	if err := decodeQueueMessage(val.Data(), &msg); err != nil {
+		return nil, fmt.Errorf("failed to decode queue message: %w", err)
+	}
+	return &msg, nil
+}
+
+// put writes a message and, when index is set, its delivery index entry.
+func (q *openQueue) put(msg *model.QueueMessage, index []byte) error {
+	data, err := encodeRecord(msg)
+	if err != nil {
+		return fmt.Errorf("failed to serialize queue message: %w", err)
+	}
+	if err := q.tc.txn.PutCF(q.handle, queueMessageKey(q.name, msg.ID), data); err != nil {
+		return fmt.Errorf("failed to write queue message: %w", conflictError(err))
+	}
+	if index != nil {
+		if err := q.tc.txn.PutCF(q.handle, index, nil); err != nil {
+			return fmt.Errorf("failed to write queue message: %w", conflictError(err))
+		}
+	}
+	return nil
+}
+
+// remove deletes an in-flight message.
+func (q *openQueue) remove(msg *model.QueueMessage) error {
+	if err := q.tc.txn.DeleteCF(q.handle, queueWaitKey(q.name, msg.VisibleAt, msg.ID)); err != nil {
+		return fmt.Errorf("failed to delete queue message: %w", conflictError(err))
+	}
+	if err := q.tc.txn.DeleteCF(q.handle, queueMessageKey(q.name, msg.ID)); err != nil {
+		return fmt.Errorf("failed to delete queue message: %w", conflictError(err))
+	}
+	return nil
+}
+
+// backoff returns the delay before retrying a message after its given number of attempts:
+// the retry backoff, doubled for every attempt after the first, up to the maximum.
+func (q *openQueue) backoff(attempts int) time.Duration {
+	wait := q.limits.backoff
+	for i := 1; i < attempts && wait < q.limits.maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > q.limits.maxBackoff {
+		wait = q.limits.maxBackoff
+	}
+	return wait
+}
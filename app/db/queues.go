@@ -0,0 +1,439 @@
+package db
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"mithrildb/config"
+	"mithrildb/model"
+
+	"github.com/linxGnu/grocksdb"
+)
+
+// CFSystemQueues stores job queues.
+//
+// Like events.RocksQueue, messages are keyed by a big-endian sequence number, under
+// "m\x00<queue>\x00<id>". Two indexes order them for delivery: "r\x00<queue>\x00" holds ready
+// messages by priority and id, and "w\x00<queue>\x00" holds delayed and in-flight messages by
+// the time they become visible. The queue record, under "q\x00<queue>", keeps the settings,
+// the next id and the stats; every operation locks it, so operations on one queue are
+// serialized.
+const CFSystemQueues = "system.queues"
+
+// maxQueuePriority is the highest message priority.
+const maxQueuePriority = 255
+
+// QueueManagerConfig holds the defaults and limits applied to job queues.
+type QueueManagerConfig struct {
+	DefaultVisibility  time.Duration // How long received messages stay hidden by default
+	MaxVisibility      time.Duration // Longest visibility timeout a client may request
+	MaxDelay           time.Duration // Longest delay a message may be enqueued with
+	DefaultMaxAttempts int           // Deliveries before a message is dead-lettered (0 = no limit)
+	RetryBackoff       time.Duration // Default delay before retrying a nacked message
+	MaxRetryBackoff    time.Duration // Default longest delay between retries
+	MaxReceive         int           // Maximum messages returned by one receive
+}
+
+// BuildQueueManagerConfig parses the [Queues] section of the application config.
+func BuildQueueManagerConfig(raw config.QueuesConfig) (QueueManagerConfig, error) {
+	var cfg QueueManagerConfig
+	for _, d := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"DefaultVisibilityTimeout", raw.DefaultVisibilityTimeout, &cfg.DefaultVisibility},
+		{"MaxVisibilityTimeout", raw.MaxVisibilityTimeout, &cfg.MaxVisibility},
+		{"MaxDelay", raw.MaxDelay, &cfg.MaxDelay},
+		{"RetryBackoff", raw.RetryBackoff, &cfg.RetryBackoff},
+		{"MaxRetryBackoff", raw.MaxRetryBackoff, &cfg.MaxRetryBackoff},
+	} {
+		v, err := time.ParseDuration(d.value)
+		if err != nil || v < 0 {
+			return QueueManagerConfig{}, fmt.Errorf("invalid queues.%s: %q", d.name, d.value)
+		}
+		*d.dst = v
+	}
+	if cfg.DefaultVisibility <= 0 || cfg.DefaultVisibility > cfg.MaxVisibility {
+		return QueueManagerConfig{}, fmt.Errorf("queues.DefaultVisibilityTimeout must be positive and at most queues.MaxVisibilityTimeout")
+	}
+	if cfg.RetryBackoff > cfg.MaxRetryBackoff {
+		return QueueManagerConfig{}, fmt.Errorf("queues.RetryBackoff cannot exceed queues.MaxRetryBackoff")
+	}
+	if raw.DefaultMaxAttempts < 0 || raw.MaxReceive < 1 {
+		return QueueManagerConfig{}, fmt.Errorf("queues.DefaultMaxAttempts cannot be negative and queues.MaxReceive must be positive")
+	}
+	cfg.DefaultMaxAttempts = raw.DefaultMaxAttempts
+	cfg.MaxReceive = raw.MaxReceive
+	return cfg, nil
+}
+
+// QueueInfo describes a job queue.
+type QueueInfo struct {
+	Name             string              `json:"name"`
+	Settings         model.QueueSettings `json:"settings"`
+	Stats            model.QueueStats    `json:"stats"`
+	Depth            int64               `json:"depth"`              // Messages not yet acked or dead-lettered
+	OldestAgeSeconds float64             `json:"oldest_age_seconds"` // Age of the oldest message in the queue
+	CreatedAt        time.Time           `json:"created_at"`
+}
+
+// QueueManager runs durable job queues with visibility timeouts, acknowledgements, retries
+// and dead-letter queues.
+//
+// A received message is leased to the receiver until its visibility timeout ends. Acking it
+// with the receipt of that delivery removes it; nacking it, or letting the timeout pass,
+// schedules a retry until the queue's attempts run out and the message moves to the
+// dead-letter queue. Delivery is at least once: a worker that dies mid-job gets its message
+// delivered again instead of losing it.
+type QueueManager struct {
+	db  *DB
+	cfg QueueManagerConfig
+}
+
+// NewQueueManager creates a manager for job queues on db.
+func NewQueueManager(db *DB, cfg QueueManagerConfig) *QueueManager {
+	return &QueueManager{db: db, cfg: cfg}
+}
+
+// queueState is the stored record of a queue.
+type queueState struct {
+	Settings  model.QueueSettings `json:"settings"`
+	NextID    uint64              `json:"next_id"`
+	Stats     model.QueueStats    `json:"stats"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// queueLimits are the effective settings of a queue.
+type queueLimits struct {
+	visibility  time.Duration
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+}
+
+// ValidateQueueName ensures a queue name follows the document key rules.
+func ValidateQueueName(name string) error {
+	if err := model.ValidateDocumentKey(name); err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidQueueName, name)
+	}
+	return nil
+}
+
+// Configure creates a queue or replaces its settings.
+func (m *QueueManager) Configure(name string, settings model.QueueSettings) (*QueueInfo, error) {
+	if err := ValidateQueueName(name); err != nil {
+		return nil, err
+	}
+	if _, err := m.limits(settings); err != nil {
+		return nil, err
+	}
+	if settings.DeadLetterQueue != "" {
+		if err := ValidateQueueName(settings.DeadLetterQueue); err != nil {
+			return nil, fmt.Errorf("%w: dead_letter_queue: %v", ErrInvalidQueueSettings, err)
+		}
+		if settings.DeadLetterQueue == name {
+			return nil, fmt.Errorf("%w: a queue cannot be its own dead-letter queue", ErrInvalidQueueSettings)
+		}
+	}
+
+	var info *QueueInfo
+	err := m.db.runInTransaction("", nil, func(tc *txnContext) error {
+		q, err := m.open(tc, name, true)
+		if err != nil {
+			return err
+		}
+		q.state.Settings = settings
+		if err := q.save(); err != nil {
+			return err
+		}
+		info, err = q.info()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// Get describes a queue.
+func (m *QueueManager) Get(name string) (*QueueInfo, error) {
+	if err := ValidateQueueName(name); err != nil {
+		return nil, err
+	}
+	var info *QueueInfo
+	err := m.db.runInTransaction("", nil, func(tc *txnContext) error {
+		q, err := m.open(tc, name, false)
+		if err != nil {
+			return err
+		}
+		info, err = q.info()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// List describes every queue, in name order.
+func (m *QueueManager) List() ([]QueueInfo, error) {
+	handle, ok := m.db.Family(CFSystemQueues)
+	if !ok {
+		return []QueueInfo{}, nil
+	}
+
+	var names []string
+	prefix := []byte("q\x00")
+	iter := m.db.TransactionDB.NewIteratorCF(m.db.DefaultReadOptions, handle)
+	for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+		k := iter.Key()
+		names = append(names, string(k.Data()[len(prefix):]))
+		k.Free()
+	}
+	err := iter.Err()
+	iter.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	queues := make([]QueueInfo, 0, len(names))
+	for _, name := range names {
+		info, err := m.Get(name)
+		if errors.Is(err, ErrQueueNotFound) {
+			continue // Deleted meanwhile
+		}
+		if err != nil {
+			return nil, err
+		}
+		queues = append(queues, *info)
+	}
+	return queues, nil
+}
+
+// Delete removes a queue and all its messages.
+func (m *QueueManager) Delete(name string) error {
+	if err := ValidateQueueName(name); err != nil {
+		return err
+	}
+	return m.db.runInTransaction("", nil, func(tc *txnContext) error {
+		q, err := m.open(tc, name, false)
+		if err != nil {
+			return err
+		}
+		for _, kind := range []byte{'m', 'r', 'w'} {
+			if err := q.deleteAll(q.prefix(kind)); err != nil {
+				return err
+			}
+		}
+		if err := tc.txn.DeleteCF(q.handle, queueKey(name)); err != nil {
+			return fmt.Errorf("failed to delete queue: %w", conflictError(err))
+		}
+		return nil
+	})
+}
+
+// limits resolves the effective settings of a queue, failing when they are invalid.
+func (m *QueueManager) limits(s model.QueueSettings) (queueLimits, error) {
+	l := queueLimits{
+		visibility:  m.cfg.DefaultVisibility,
+		maxAttempts: m.cfg.DefaultMaxAttempts,
+		backoff:     m.cfg.RetryBackoff,
+		maxBackoff:  m.cfg.MaxRetryBackoff,
+	}
+	for _, d := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"visibility_timeout", s.VisibilityTimeout, &l.visibility},
+		{"retry_backoff", s.RetryBackoff, &l.backoff},
+		{"max_retry_backoff", s.MaxRetryBackoff, &l.maxBackoff},
+	} {
+		if d.value == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.value)
+		if err != nil || v < 0 {
+			return queueLimits{}, fmt.Errorf("%w: %s must be a non-negative duration", ErrInvalidQueueSettings, d.name)
+		}
+		*d.dst = v
+	}
+	if l.visibility <= 0 || l.visibility > m.cfg.MaxVisibility {
+		return queueLimits{}, fmt.Errorf("%w: visibility_timeout must be positive and at most %s", ErrInvalidQueueSettings, m.cfg.MaxVisibility)
+	}
+	if l.backoff > l.maxBackoff {
+		return queueLimits{}, fmt.Errorf("%w: retry_backoff cannot exceed max_retry_backoff", ErrInvalidQueueSettings)
+	}
+	if s.MaxAttempts < 0 {
+		return queueLimits{}, fmt.Errorf("%w: max_attempts cannot be negative", ErrInvalidQueueSettings)
+	}
+	if s.MaxAttempts > 0 {
+		l.maxAttempts = s.MaxAttempts
+	}
+	return l, nil
+}
+
+// openQueue is a queue locked by a transaction.
+type openQueue struct {
+	m      *QueueManager
+	tc     *txnContext
+	handle *grocksdb.ColumnFamilyHandle
+	name   string
+	state  queueState
+	limits queueLimits
+}
+
+// open locks and loads a queue, creating it with default settings when create is set.
+func (m *QueueManager) open(tc *txnContext, name string, create bool) (*openQueue, error) {
+	handle, err := m.db.EnsureSystemColumnFamily(CFSystemQueues)
+	if err != nil {
+		return nil, err
+	}
+	q := &openQueue{m: m, tc: tc, handle: handle, name: name}
+
+	val, err := tc.txn.GetForUpdateWithCF(tc.readOpts, handle, queueKey(name))
+	if err != nil {
+		return nil, conflictError(err)
+	}
+	defer val.Free()
+
+	switch {
+	case val.Exists():
+		if err := decodeRecord(val.Data(), &q.state); err != nil {
+			return nil, fmt.Errorf("failed to decode queue %q: %w", name, err)
+		}
+	case create:
+		q.state = queueState{NextID: 1, CreatedAt: time.Now()}
+		if err := q.save(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrQueueNotFound
+	}
+
+	// Stored settings were validated when set; defaults apply when they no longer are.
+	if q.limits, err = m.limits(q.state.Settings); err != nil {
+		q.limits, _ = m.limits(model.QueueSettings{})
+	}
+	return q, nil
+}
+
+// save writes the queue record.
+func (q *openQueue) save() error {
+	data, err := encodeRecord(&q.state)
+	if err != nil {
+		return fmt.Errorf("failed to serialize queue: %w", err)
+	}
+	if err := q.tc.txn.PutCF(q.handle, queueKey(q.name), data); err != nil {
+		return fmt.Errorf("failed to write queue: %w", conflictError(err))
+	}
+	return nil
+}
+
+// info describes the queue as seen by the transaction.
+func (q *openQueue) info() (*QueueInfo, error) {
+	s := q.state.Stats
+	info := &QueueInfo{
+		Name:      q.name,
+		Settings:  q.state.Settings,
+		Stats:     s,
+		Depth:     s.Ready + s.Delayed + s.InFlight,
+		CreatedAt: q.state.CreatedAt,
+	}
+
+	// Ids grow with time, so the first message is the oldest.
+	prefix := q.prefix('m')
+	iter := q.tc.txn.NewIteratorCF(q.tc.readOpts, q.handle)
+	defer iter.Close()
+	if iter.Seek(prefix); iter.ValidForPrefix(prefix) {
+		v := iter.Value()
+		var msg model.QueueMessage
+		err := decodeQueueMessage(v.Data(), &msg)
+		v.Free()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode queue message: %w", err)
+		}
+		info.OldestAgeSeconds = time.Since(msg.EnqueuedAt).Seconds()
+	}
+	return info, iter.Err()
+}
+
+// prefix returns the key prefix of one kind of queue entry.
+func (q *openQueue) prefix(kind byte) []byte {
+	return queuePrefix(kind, q.name)
+}
+
+// deleteAll deletes every key with a prefix.
+func (q *openQueue) deleteAll(prefix []byte) error {
+	keys, err := q.scan(prefix, nil, 0)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := q.tc.txn.DeleteCF(q.handle, k); err != nil {
+			return fmt.Errorf("failed to delete queue entry: %w", conflictError(err))
+		}
+	}
+	return nil
+}
+
+// scan returns the keys with a prefix, in order, up to but excluding until when it is set,
+// and at most limit keys unless limit is 0.
+func (q *openQueue) scan(prefix, until []byte, limit int) ([][]byte, error) {
+	var keys [][]byte
+	iter := q.tc.txn.NewIteratorCF(q.tc.readOpts, q.handle)
+	defer iter.Close()
+	for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+		if limit > 0 && len(keys) == limit {
+			break
+		}
+		k := iter.Key()
+		key := append([]byte(nil), k.Data()...)
+		k.Free()
+		if until != nil && bytes.Compare(key, until) >= 0 {
+			break
+		}
+		keys = append(keys, key)
+	}
+	return keys, iter.Err()
+}
+
+// queueKey returns the key of a queue record.
+func queueKey(name string) []byte {
+	return []byte("q\x00" + name)
+}
+
+// queuePrefix returns the prefix of one kind of entry of a queue: 'm' messages, 'r' the ready
+// index and 'w' the waiting index.
+func queuePrefix(kind byte, name string) []byte {
+	prefix := make([]byte, 0, len(name)+3)
+	prefix = append(prefix, kind, 0)
+	prefix = append(prefix, name...)
+	return append(prefix, 0)
+}
+
+// queueMessageKey returns the key of a message.
+func queueMessageKey(name string, id uint64) []byte {
+	return appendUint64(queuePrefix('m', name), id)
+}
+
+// queueReadyKey returns the ready index key of a message; higher priorities sort first.
+func queueReadyKey(name string, priority int, id uint64) []byte {
+	return appendUint64(append(queuePrefix('r', name), byte(maxQueuePriority-priority)), id)
+}
+
+// queueWaitKey returns the waiting index key of a message that becomes visible at visibleAt.
+func queueWaitKey(name string, visibleAt time.Time, id uint64) []byte {
+	return appendUint64(appendUint64(queuePrefix('w', name), uint64(visibleAt.UnixNano())), id)
+}
+
+// appendUint64 appends the big-endian encoding of v.
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
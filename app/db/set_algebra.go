@@ -0,0 +1,245 @@
+package db
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"time"
+
+	"mithrildb/events"
+	"mithrildb/model"
+)
+
+// SetUnion returns the members found in any of the sets, in member order, and writes them to
+// Store when it is set.
+func (db *DB) SetUnion(opts SetAlgebraOptions) ([]interface{}, error) {
+	return db.combineSets(opts, unionMembers)
+}
+
+// SetIntersect returns the members found in every one of the sets, in member order, and writes
+// them to Store when it is set.
+func (db *DB) SetIntersect(opts SetAlgebraOptions) ([]interface{}, error) {
+	return db.combineSets(opts, intersectMembers)
+}
+
+// SetDiff returns the members of the first set that are in none of the others, in member
+// order, and writes them to Store when it is set.
+func (db *DB) SetDiff(opts SetAlgebraOptions) ([]interface{}, error) {
+	return db.combineSets(opts, diffMembers)
+}
+
+// combineSets reads the sets named by Keys and combines their members.
+//
+// When the result is stored, or inside an interactive transaction, the sets are read and the
+// result written in one transaction, so the stored set matches the sources at a single point.
+// Otherwise the sets are read with the given read options; pin a snapshot for a consistent view.
+func (db *DB) combineSets(opts SetAlgebraOptions, combine func([][]setMember) []setMember) ([]interface{}, error) {
+	if len(opts.Keys) == 0 {
+		return nil, fmt.Errorf("%w: at least one key is required", ErrInvalidSetOperation)
+	}
+
+	sets := make([][]setMember, len(opts.Keys))
+	var result []setMember
+	if opts.Store == "" && opts.TxnID == "" {
+		for i, key := range opts.Keys {
+			doc, err := db.readSet(SetReadOptions{ColumnFamily: opts.ColumnFamily, Key: key, ReadOptions: opts.ReadOptions})
+			if err != nil {
+				return nil, err
+			}
+			sets[i], err = collectMembers(func(fn func(setMember) bool) error {
+				return db.scanSet(opts.ReadOptions, opts.ColumnFamily, doc, nil, fn)
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		result = combine(sets)
+	} else {
+		err := db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+			for i, key := range opts.Keys {
+				doc, err := tc.readSet(opts.ColumnFamily, key)
+				if err != nil {
+					return err
+				}
+				sets[i], err = collectMembers(func(fn func(setMember) bool) error {
+					return tc.scanSet(opts.ColumnFamily, doc, nil, fn)
+				})
+				if err != nil {
+					return err
+				}
+			}
+			result = combine(sets)
+			if opts.Store == "" {
+				return nil
+			}
+			return tc.storeSet(opts.ColumnFamily, opts.Store, result, opts.Expiration)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	members := make([]interface{}, len(result))
+	for i, m := range result {
+		members[i] = m.value
+	}
+	return members, nil
+}
+
+// readSet loads and locks a set document inside a transaction.
+func (tc *txnContext) readSet(cf, key string) (*model.Document, error) {
+	handle, err := tc.family(cf, key)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := tc.getForUpdate(handle, key)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return nil, ErrKeyNotFound
+	}
+	if err := checkSetDocument(doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// storeSet replaces a document with a set of the given members, stored as elements.
+func (tc *txnContext) storeSet(cf, key string, members []setMember, expiration *int64) error {
+	handle, err := tc.family(cf, key)
+	if err != nil {
+		return err
+	}
+	exp := int64(0)
+	if expiration != nil {
+		if err := model.ValidateExpiration(*expiration); err != nil {
+			return err
+		}
+		exp = *expiration
+	}
+
+	existing, err := tc.getForWrite(handle, cf, key)
+	if err != nil {
+		return err
+	}
+	var prevMeta *model.Metadata
+	if existing != nil {
+		metaCopy := existing.Meta
+		prevMeta = &metaCopy
+		// The replacement has its own generation, so the old elements are not dropped on write.
+		if existing.Meta.Elements != nil {
+			if err := tc.clearElements(cf, key, existing.Meta.Elements); err != nil {
+				return err
+			}
+		}
+	}
+
+	elements, err := tc.db.EnsureSystemColumnFamily(CFSystemElements)
+	if err != nil {
+		return err
+	}
+	header := &model.ElementsHeader{Kind: model.ElementsSet, Gen: uint64(tc.db.clock.Now())}
+	for _, m := range members {
+		if err := tc.txn.PutCF(elements, setMemberKey(cf, key, header.Gen, m.data), m.data); err != nil {
+			return fmt.Errorf("failed to write set member: %w", conflictError(err))
+		}
+		header.Len++
+	}
+
+	doc := &model.Document{
+		Key: key,
+		Meta: model.Metadata{
+			Type:       model.DocTypeSet,
+			UpdatedAt:  time.Now(),
+			Expiration: exp,
+			Elements:   header,
+		},
+	}
+	return tc.writeDocument(handle, cf, doc, events.ChangeEventOptions{
+		Operation:          events.OpPut,
+		PreviousMeta:       prevMeta,
+		ExplicitExpiration: expiration,
+	})
+}
+
+// collectMembers reads every member of a set from a scan.
+func collectMembers(scan func(func(setMember) bool) error) ([]setMember, error) {
+	var members []setMember
+	err := scan(func(m setMember) bool {
+		members = append(members, m)
+		return true
+	})
+	return members, err
+}
+
+// memberIndex returns the encodings of a set's members.
+func memberIndex(members []setMember) map[string]bool {
+	index := make(map[string]bool, len(members))
+	for _, m := range members {
+		index[string(m.data)] = true
+	}
+	return index
+}
+
+// unionMembers combines sets into the members found in any of them.
+func unionMembers(sets [][]setMember) []setMember {
+	seen := make(map[string]bool)
+	var result []setMember
+	for _, set := range sets {
+		for _, m := range set {
+			if !seen[string(m.data)] {
+				seen[string(m.data)] = true
+				result = append(result, m)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return bytes.Compare(result[i].data, result[j].data) < 0
+	})
+	return result
+}
+
+// intersectMembers combines sets into the members of the first found in all the others.
+func intersectMembers(sets [][]setMember) []setMember {
+	others := make([]map[string]bool, 0, len(sets)-1)
+	for _, set := range sets[1:] {
+		others = append(others, memberIndex(set))
+	}
+	var result []setMember
+	for _, m := range sets[0] {
+		found := true
+		for _, other := range others {
+			if !other[string(m.data)] {
+				found = false
+				break
+			}
+		}
+		if found {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// diffMembers combines sets into the members of the first found in none of the others.
+func diffMembers(sets [][]setMember) []setMember {
+	others := make([]map[string]bool, 0, len(sets)-1)
+	for _, set := range sets[1:] {
+		others = append(others, memberIndex(set))
+	}
+	var result []setMember
+	for _, m := range sets[0] {
+		found := false
+		for _, other := range others {
+			if other[string(m.data)] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, m)
+		}
+	}
+	return result
+}
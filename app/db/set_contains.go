@@ -1,7 +1,6 @@
 package db
 
 import (
-	"encoding/json"
 	"fmt"
 	"mithrildb/model"
 	"reflect"
@@ -9,7 +8,7 @@ import (
 
 // CheckSetContains returns true if the given element exists in the set document.
 func (db *DB) CheckSetContains(opts SetContainsOptions) (bool, error) {
-	handle, ok := db.Families[opts.ColumnFamily]
+	handle, ok := db.Family(opts.ColumnFamily)
 	if !ok {
 		return false, ErrInvalidColumnFamily
 	}
@@ -22,6 +21,11 @@ func (db *DB) CheckSetContains(opts SetContainsOptions) (bool, error) {
 		return false, err
 	}
 
+	element, err := model.NormalizeValue(opts.Element)
+	if err != nil {
+		return false, err
+	}
+
 	val, err := db.TransactionDB.GetCF(opts.ReadOptions, handle, []byte(opts.Key))
 	if err != nil {
 		return false, err
@@ -33,17 +37,24 @@ func (db *DB) CheckSetContains(opts SetContainsOptions) (bool, error) {
 	}
 
 	var doc model.Document
-	if err := json.Unmarshal(val.Data(), &doc); err != nil {
+	if err := decodeDocument(val.Data(), &doc); err != nil {
 		return false, fmt.Errorf("failed to decode document: %w", err)
 	}
 
+	if header := doc.Meta.Elements; header != nil {
+		if header.Kind != model.ElementsSet {
+			return false, ErrInvalidSetType
+		}
+		return db.setContains(opts.ReadOptions, opts.ColumnFamily, opts.Key, header, element)
+	}
+
 	set, ok := doc.Value.([]interface{})
 	if !ok {
 		return false, ErrInvalidSetType
 	}
 
 	for _, item := range set {
-		if reflect.DeepEqual(item, opts.Element) {
+		if reflect.DeepEqual(item, element) {
 			return true, nil
 		}
 	}
@@ -1,144 +1,139 @@
 package db
 
 import (
-	"encoding/json"
 	"fmt"
 	"mithrildb/events"
 	"mithrildb/model"
 	"time"
-
-	"github.com/google/uuid"
-	"github.com/linxGnu/grocksdb"
 )
 
 // AddToSet adds an element to a set-type document.
 func (db *DB) AddToSet(opts SetOpOptions, element interface{}) (interface{}, error) {
-	return db.withSetTransaction(opts, func(set map[interface{}]bool) (map[interface{}]bool, interface{}, error) {
-		set[element] = true
-		return set, nil, nil
-	})
+	element, err := model.NormalizeValue(element)
+	if err != nil {
+		return nil, err
+	}
+	return db.withSetTransaction(opts, addMember(element))
 }
 
 // RemoveFromSet removes an element from a set-type document.
 func (db *DB) RemoveFromSet(opts SetOpOptions, element interface{}) (interface{}, error) {
-	return db.withSetTransaction(opts, func(set map[interface{}]bool) (map[interface{}]bool, interface{}, error) {
-		delete(set, element)
-		return set, nil, nil
+	element, err := model.NormalizeValue(element)
+	if err != nil {
+		return nil, err
+	}
+	return db.withSetTransaction(opts, removeMember(element))
+}
+
+// PopFromSet removes up to Count members chosen at random from a set-type document and
+// returns them.
+func (db *DB) PopFromSet(opts SetPopOptions) ([]interface{}, error) {
+	if opts.Count < 1 {
+		return nil, fmt.Errorf("%w: count must be positive", ErrInvalidSetOperation)
+	}
+	result, err := db.withSetTransaction(opts.SetOpOptions, func(s *elementSet) (interface{}, error) {
+		return s.removeRandom(opts.Count)
 	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]interface{}), nil
+}
+
+// addMember returns a set modifier that adds element.
+func addMember(element interface{}) func(*elementSet) (interface{}, error) {
+	return func(s *elementSet) (interface{}, error) {
+		_, err := s.add(element)
+		return nil, err
+	}
+}
+
+// removeMember returns a set modifier that removes element.
+func removeMember(element interface{}) func(*elementSet) (interface{}, error) {
+	return func(s *elementSet) (interface{}, error) {
+		_, err := s.remove(element)
+		return nil, err
+	}
 }
 
 // withSetTransaction applies a transactional update to a set document.
 func (db *DB) withSetTransaction(
 	opts SetOpOptions,
-	modifier func(map[interface{}]bool) (map[interface{}]bool, interface{}, error),
+	modifier func(*elementSet) (interface{}, error),
 ) (interface{}, error) {
-	handle, ok := db.Families[opts.ColumnFamily]
-	if !ok {
-		return nil, ErrInvalidColumnFamily
+	var result interface{}
+	err := db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		var err error
+		result, err = tc.modifySet(opts, modifier)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return result, nil
+}
 
-	if err := model.ValidateDocumentKey(opts.Key); err != nil {
+// modifySet applies an update to a set document inside a transaction.
+//
+// The set is stored as one key per member; an inline set is converted first, dropping
+// duplicate members. Only the members the modifier touches and the document header are written.
+func (tc *txnContext) modifySet(
+	opts SetOpOptions,
+	modifier func(*elementSet) (interface{}, error),
+) (interface{}, error) {
+	handle, err := tc.family(opts.ColumnFamily, opts.Key)
+	if err != nil {
 		return nil, err
 	}
 
-	txnOpts := grocksdb.NewDefaultTransactionOptions()
-	txnOpts.SetSetSnapshot(true)
-	defer txnOpts.Destroy()
-
-	txn := db.TransactionDB.TransactionBegin(opts.WriteOptions, txnOpts, nil)
-	defer txn.Destroy()
-
-	readOpts := grocksdb.NewDefaultReadOptions()
-	readOpts.SetSnapshot(txn.GetSnapshot())
-	readOpts.SetFillCache(false)
-	defer readOpts.Destroy()
-
-	val, err := txn.GetWithCF(readOpts, handle, []byte(opts.Key))
+	doc, err := tc.getForWrite(handle, opts.ColumnFamily, opts.Key)
 	if err != nil {
-		txn.Rollback()
 		return nil, err
 	}
-	defer val.Free()
-
-	if !val.Exists() {
-		txn.Rollback()
+	if doc == nil {
 		return nil, ErrKeyNotFound
 	}
-
-	var doc model.Document
-	if err := json.Unmarshal(val.Data(), &doc); err != nil {
-		txn.Rollback()
-		return nil, fmt.Errorf("failed to decode document: %w", err)
-	}
-
-	if model.IsExpired(doc.Meta) {
-		txn.Rollback()
-		return nil, ErrKeyNotFound
+	if opts.Cas != "" && doc.Meta.Rev != opts.Cas {
+		return nil, ErrRevisionMismatch
 	}
 
 	metaCopy := doc.Meta
 
-	slice, ok := doc.Value.([]interface{})
-	if !ok {
-		txn.Rollback()
-		return nil, ErrInvalidSetType
+	ok, err := tc.convertToElements(opts.ColumnFamily, doc, model.ElementsSet)
+	if err != nil {
+		return nil, err
 	}
-
-	set := make(map[interface{}]bool)
-	for _, v := range slice {
-		set[v] = true
+	if !ok || doc.Meta.Elements.Kind != model.ElementsSet {
+		return nil, ErrInvalidSetType
 	}
-
-	newSet, result, err := modifier(set)
+	elements, err := tc.db.EnsureSystemColumnFamily(CFSystemElements)
 	if err != nil {
-		txn.Rollback()
 		return nil, err
 	}
 
-	newSlice := make([]interface{}, 0, len(newSet))
-	for k := range newSet {
-		newSlice = append(newSlice, k)
+	// The header is copied so the previous metadata keeps the old length.
+	header := *doc.Meta.Elements
+	result, err := modifier(&elementSet{tc: tc, handle: elements, cf: opts.ColumnFamily, key: opts.Key, header: &header})
+	if err != nil {
+		return nil, err
 	}
 
-	doc.Value = newSlice
-	doc.Meta.Rev = uuid.NewString()
+	doc.Meta.Elements = &header
 	doc.Meta.UpdatedAt = time.Now()
 
 	if opts.Expiration != nil {
 		if err := model.ValidateExpiration(*opts.Expiration); err != nil {
-			txn.Rollback()
 			return nil, err
 		}
 		doc.Meta.Expiration = *opts.Expiration
 	}
 
-	data, err := json.Marshal(doc)
-	if err != nil {
-		txn.Rollback()
-		return nil, fmt.Errorf("failed to marshal document: %w", err)
-	}
-
-	if err := txn.PutCF(handle, []byte(opts.Key), data); err != nil {
-		txn.Rollback()
-		return nil, fmt.Errorf("failed to write document: %w", err)
-	}
-
-	if err := events.PublishChangeEvent(events.ChangeEventOptions{
-		Txn:                txn,
-		CFName:             opts.ColumnFamily,
-		Key:                opts.Key,
-		Document:           &doc,
+	if err := tc.writeDocument(handle, opts.ColumnFamily, doc, events.ChangeEventOptions{
 		Operation:          events.OpMutate,
 		PreviousMeta:       &metaCopy,
 		ExplicitExpiration: opts.Expiration,
 	}); err != nil {
-		txn.Rollback()
-		return nil, fmt.Errorf("failed to enqueue change event: %w", err)
-	}
-
-	if err := txn.Commit(); err != nil {
-		txn.Rollback()
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, err
 	}
 
 	return result, nil
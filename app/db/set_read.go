@@ -0,0 +1,180 @@
+package db
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"mithrildb/model"
+)
+
+// GetSetCardinality returns the number of members of a set document.
+func (db *DB) GetSetCardinality(opts SetReadOptions) (int64, error) {
+	doc, err := db.readSet(opts)
+	if err != nil {
+		return 0, err
+	}
+	if header := doc.Meta.Elements; header != nil {
+		return header.Len, nil
+	}
+
+	// Inline sets may still hold duplicates, which are only dropped on conversion.
+	var n int64
+	err = db.scanSet(opts.ReadOptions, opts.ColumnFamily, doc, nil, func(setMember) bool {
+		n++
+		return true
+	})
+	return n, err
+}
+
+// GetSetMembers returns a page of set members in member order. next is an opaque cursor
+// returned when more members follow, and can be passed as Cursor to read the next page.
+//
+// Member order is the order of the members' binary encoding, which is stable but otherwise
+// unrelated to their values.
+func (db *DB) GetSetMembers(opts SetMembersOptions) (members []interface{}, next string, err error) {
+	if opts.Limit < 0 {
+		return nil, "", fmt.Errorf("%w: limit cannot be negative", ErrInvalidSetOperation)
+	}
+	after, err := base64.RawURLEncoding.DecodeString(opts.Cursor)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: invalid cursor", ErrInvalidSetOperation)
+	}
+	doc, err := db.readSet(opts.SetReadOptions)
+	if err != nil {
+		return nil, "", err
+	}
+
+	members = []interface{}{}
+	var last []byte
+	more := false
+	err = db.scanSet(opts.ReadOptions, opts.ColumnFamily, doc, after, func(m setMember) bool {
+		if opts.Limit > 0 && len(members) == opts.Limit {
+			more = true
+			return false
+		}
+		members = append(members, m.value)
+		last = m.data
+		return true
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if more {
+		next = base64.RawURLEncoding.EncodeToString(last)
+	}
+	return members, next, nil
+}
+
+// RandomSetMembers returns up to count distinct members of a set document chosen at random.
+func (db *DB) RandomSetMembers(opts SetReadOptions, count int) ([]interface{}, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("%w: count must be positive", ErrInvalidSetOperation)
+	}
+	doc, err := db.readSet(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	picked, err := sampleMembers(func(fn func(setMember) bool) error {
+		return db.scanSet(opts.ReadOptions, opts.ColumnFamily, doc, nil, fn)
+	}, count)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]interface{}, len(picked))
+	for i, m := range picked {
+		members[i] = m.value
+	}
+	return members, nil
+}
+
+// CheckSetMembers reports, for each element in order, whether it is a member of a set document.
+func (db *DB) CheckSetMembers(opts SetReadOptions, elements []interface{}) ([]bool, error) {
+	doc, err := db.readSet(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Inline sets are read once; sets stored as elements are looked up member by member.
+	var inline map[string]bool
+	if doc.Meta.Elements == nil {
+		inline = make(map[string]bool)
+		err = db.scanSet(opts.ReadOptions, opts.ColumnFamily, doc, nil, func(m setMember) bool {
+			inline[string(m.data)] = true
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]bool, len(elements))
+	for i, element := range elements {
+		element, err := model.NormalizeValue(element)
+		if err != nil {
+			return nil, err
+		}
+		if inline == nil {
+			if results[i], err = db.setContains(opts.ReadOptions, opts.ColumnFamily, opts.Key, doc.Meta.Elements, element); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		data, err := encodeRecord(element)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = inline[string(data)]
+	}
+	return results, nil
+}
+
+// readSet loads a set document, which may be stored inline or as elements.
+func (db *DB) readSet(opts SetReadOptions) (*model.Document, error) {
+	handle, ok := db.Family(opts.ColumnFamily)
+	if !ok {
+		return nil, ErrInvalidColumnFamily
+	}
+	if opts.ReadOptions == nil {
+		opts.ReadOptions = db.DefaultReadOptions
+	}
+	if err := model.ValidateDocumentKey(opts.Key); err != nil {
+		return nil, err
+	}
+
+	val, err := db.TransactionDB.GetCF(opts.ReadOptions, handle, []byte(opts.Key))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+
+	if !val.Exists() || val.Size() == 0 {
+		return nil, ErrKeyNotFound
+	}
+
+	var doc model.Document
+	if err := decodeDocument(val.Data(), &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	if model.IsExpired(doc.Meta) {
+		return nil, ErrKeyNotFound
+	}
+	if err := checkSetDocument(&doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// checkSetDocument verifies that a document holds a set, inline or as elements.
+func checkSetDocument(doc *model.Document) error {
+	if header := doc.Meta.Elements; header != nil {
+		if header.Kind != model.ElementsSet {
+			return ErrInvalidSetType
+		}
+		return nil
+	}
+	if _, ok := doc.Value.([]interface{}); !ok {
+		return ErrInvalidSetType
+	}
+	return nil
+}
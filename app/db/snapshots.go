@@ -0,0 +1,278 @@
+package db
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"mithrildb/config"
+
+	"github.com/google/uuid"
+	"github.com/linxGnu/grocksdb"
+)
+
+// SnapshotManagerConfig holds the limits applied to pinned snapshots.
+type SnapshotManagerConfig struct {
+	MaxOpen      int           // Maximum number of snapshots pinned at the same time
+	DefaultLease time.Duration // Lease of snapshots created without one
+	MaxLease     time.Duration // Longest lease a client may request
+}
+
+// BuildSnapshotManagerConfig parses the [Snapshots] section of the application config.
+func BuildSnapshotManagerConfig(raw config.SnapshotsConfig) (SnapshotManagerConfig, error) {
+	def, err := time.ParseDuration(raw.DefaultLease)
+	if err != nil {
+		return SnapshotManagerConfig{}, fmt.Errorf("invalid snapshots.DefaultLease: %w", err)
+	}
+	max, err := time.ParseDuration(raw.MaxLease)
+	if err != nil {
+		return SnapshotManagerConfig{}, fmt.Errorf("invalid snapshots.MaxLease: %w", err)
+	}
+	if def > max {
+		return SnapshotManagerConfig{}, fmt.Errorf("snapshots.DefaultLease cannot exceed snapshots.MaxLease")
+	}
+
+	return SnapshotManagerConfig{
+		MaxOpen:      raw.MaxOpen,
+		DefaultLease: def,
+		MaxLease:     max,
+	}, nil
+}
+
+// SnapshotInfo describes a pinned snapshot.
+type SnapshotInfo struct {
+	ID        string    `json:"snapshot"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"` // When the snapshot is released unless released earlier
+}
+
+// SnapshotStats reports pinned snapshot activity.
+type SnapshotStats struct {
+	Open             int     `json:"open"`
+	MaxOpen          int     `json:"max_open"`
+	Created          uint64  `json:"created"`
+	Released         uint64  `json:"released"`
+	Expired          uint64  `json:"expired"`
+	OldestAgeSeconds float64 `json:"oldest_age_seconds"` // Age of the oldest open snapshot
+}
+
+// SnapshotManager pins RocksDB snapshots so several requests can read one consistent view.
+//
+// Snapshots are released explicitly or when their lease ends. A snapshot in use by a read is
+// freed once that read finishes.
+type SnapshotManager struct {
+	db        *DB
+	cfg       SnapshotManagerConfig
+	snapshots map[string]*pinnedSnapshot
+	stats     SnapshotStats
+	stop      chan struct{}
+	mu        sync.Mutex
+}
+
+// pinnedSnapshot is a snapshot handed out to clients. Its fields are guarded by the manager lock.
+type pinnedSnapshot struct {
+	id        string
+	snapshot  *grocksdb.Snapshot
+	createdAt time.Time
+	expiresAt time.Time
+	readers   int  // Reads currently using the snapshot
+	released  bool // Removed from the manager; freed when the last reader finishes
+}
+
+// NewSnapshotManager creates a manager for pinned snapshots on db.
+func NewSnapshotManager(db *DB, cfg SnapshotManagerConfig) *SnapshotManager {
+	return &SnapshotManager{
+		db:        db,
+		cfg:       cfg,
+		snapshots: make(map[string]*pinnedSnapshot),
+		stop:      make(chan struct{}),
+	}
+}
+
+// Start launches the background reaper that releases snapshots whose lease has ended.
+func (m *SnapshotManager) Start() {
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.expire(time.Now())
+			case <-m.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the reaper and releases every snapshot.
+func (m *SnapshotManager) Close() {
+	close(m.stop)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, s := range m.snapshots {
+		delete(m.snapshots, id)
+		m.db.TransactionDB.ReleaseSnapshot(s.snapshot)
+	}
+}
+
+// Create pins a snapshot of the current database state for the given lease. A zero lease uses
+// the configured default.
+func (m *SnapshotManager) Create(lease time.Duration) (*SnapshotInfo, error) {
+	if lease == 0 {
+		lease = m.cfg.DefaultLease
+	}
+	if lease < 0 || lease > m.cfg.MaxLease {
+		return nil, fmt.Errorf("%w: must be positive and at most %s", ErrInvalidSnapshotLease, m.cfg.MaxLease)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cfg.MaxOpen > 0 && len(m.snapshots) >= m.cfg.MaxOpen {
+		return nil, ErrTooManySnapshots
+	}
+
+	now := time.Now()
+	s := &pinnedSnapshot{
+		id:        uuid.NewString(),
+		snapshot:  m.db.TransactionDB.NewSnapshot(),
+		createdAt: now,
+		expiresAt: now.Add(lease),
+	}
+	m.snapshots[s.id] = s
+	m.stats.Created++
+
+	return s.info(), nil
+}
+
+// List describes the open snapshots.
+func (m *SnapshotManager) List() []SnapshotInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	infos := make([]SnapshotInfo, 0, len(m.snapshots))
+	for _, s := range m.snapshots {
+		infos = append(infos, *s.info())
+	}
+	return infos
+}
+
+// Release unpins a snapshot.
+func (m *SnapshotManager) Release(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.snapshots[id]
+	if !ok {
+		return ErrSnapshotNotFound
+	}
+	m.remove(s)
+	m.stats.Released++
+	return nil
+}
+
+// Stats returns a snapshot of pinned snapshot counters.
+func (m *SnapshotManager) Stats() SnapshotStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := m.stats
+	stats.Open = len(m.snapshots)
+	stats.MaxOpen = m.cfg.MaxOpen
+	now := time.Now()
+	for _, s := range m.snapshots {
+		if age := now.Sub(s.createdAt).Seconds(); age > stats.OldestAgeSeconds {
+			stats.OldestAgeSeconds = age
+		}
+	}
+	return stats
+}
+
+// acquire looks up a live snapshot and registers a reader on it. The caller must call done.
+func (m *SnapshotManager) acquire(id string) (*pinnedSnapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.snapshots[id]
+	if !ok || !time.Now().Before(s.expiresAt) {
+		return nil, ErrSnapshotNotFound
+	}
+	s.readers++
+	return s, nil
+}
+
+// done unregisters a reader, freeing the snapshot if it was released meanwhile.
+func (m *SnapshotManager) done(s *pinnedSnapshot) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s.readers--
+	if s.released && s.readers == 0 {
+		m.db.TransactionDB.ReleaseSnapshot(s.snapshot)
+	}
+}
+
+// expire releases the snapshots whose lease ended before now.
+func (m *SnapshotManager) expire(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, s := range m.snapshots {
+		if now.Before(s.expiresAt) {
+			continue
+		}
+		m.remove(s)
+		m.stats.Expired++
+	}
+}
+
+// remove drops a snapshot from the manager and frees it unless a read still uses it.
+// The caller must hold the manager lock.
+func (m *SnapshotManager) remove(s *pinnedSnapshot) {
+	delete(m.snapshots, s.id)
+	s.released = true
+	if s.readers == 0 {
+		m.db.TransactionDB.ReleaseSnapshot(s.snapshot)
+	}
+}
+
+// info describes a snapshot. The caller must hold the manager lock.
+func (s *pinnedSnapshot) info() *SnapshotInfo {
+	return &SnapshotInfo{
+		ID:        s.id,
+		CreatedAt: s.createdAt,
+		ExpiresAt: s.expiresAt,
+	}
+}
+
+// ResolveReadOptions returns the read options for a request: the database defaults, options
+// built from the fill_cache and read_tier parameters, or options reading from the pinned
+// snapshot named by the 'snapshot' parameter. release must be called once the read is done.
+func (db *DB) ResolveReadOptions(r *http.Request, defaults config.ReadOptionsConfig) (*grocksdb.ReadOptions, func(), error) {
+	id := r.URL.Query().Get("snapshot")
+	if id == "" {
+		if !HasReadOptions(r) {
+			return db.DefaultReadOptions, func() {}, nil
+		}
+		opts := BuildReadOptions(r, defaults)
+		return opts, opts.Destroy, nil
+	}
+
+	if db.Snapshots == nil {
+		return nil, nil, ErrSnapshotNotFound
+	}
+	s, err := db.Snapshots.acquire(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts := BuildReadOptions(r, defaults)
+	opts.SetSnapshot(s.snapshot)
+	return opts, func() {
+		opts.Destroy()
+		db.Snapshots.done(s)
+	}, nil
+}
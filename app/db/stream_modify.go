@@ -0,0 +1,356 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"mithrildb/model"
+)
+
+// AppendToStream appends an entry to a stream document, creating the stream if needed, trims
+// it to the given limits and returns the ID of the entry.
+func (db *DB) AppendToStream(opts StreamAppendOptions) (string, error) {
+	value, err := model.NormalizeValue(opts.Value)
+	if err != nil {
+		return "", err
+	}
+	if value == nil {
+		return "", ErrNilValue
+	}
+	minID, err := opts.Trim.minID(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	result, err := db.withStreamTransaction(opts.StreamOpOptions, true, func(s *elementStream) (interface{}, error) {
+		id, err := s.nextID(opts.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.append(id, value); err != nil {
+			return nil, err
+		}
+		if _, err := s.trim(opts.Trim.MaxLen, minID); err != nil {
+			return nil, err
+		}
+		return id.String(), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.(string), nil
+}
+
+// TrimStream removes the oldest entries of a stream document beyond the given limits and
+// returns how many it removed. Removed entries pending in a consumer group stay pending until
+// they are acknowledged or claimed.
+func (db *DB) TrimStream(opts StreamTrimOptions) (int64, error) {
+	minID, err := opts.StreamTrimLimits.minID(time.Now())
+	if err != nil {
+		return 0, err
+	}
+	result, err := db.withStreamTransaction(opts.StreamOpOptions, false, func(s *elementStream) (interface{}, error) {
+		return s.trim(opts.MaxLen, minID)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int64), nil
+}
+
+// minID returns the lowest ID kept by the limits at the given time.
+func (l StreamTrimLimits) minID(now time.Time) (model.StreamID, error) {
+	if l.MaxLen < 0 {
+		return model.StreamID{}, fmt.Errorf("%w: maxlen cannot be negative", ErrInvalidStreamOperation)
+	}
+	if l.MaxAge < 0 {
+		return model.StreamID{}, fmt.Errorf("%w: max age cannot be negative", ErrInvalidStreamOperation)
+	}
+	var minID model.StreamID
+	if l.MinID != "" {
+		var err error
+		if minID, err = parseStreamID(l.MinID); err != nil {
+			return model.StreamID{}, err
+		}
+	}
+	if l.MaxAge > 0 {
+		if oldest := now.Add(-l.MaxAge).UnixMilli(); oldest > 0 && uint64(oldest) > minID.Ms {
+			minID = model.StreamID{Ms: uint64(oldest)}
+		}
+	}
+	return minID, nil
+}
+
+// CreateStreamGroup creates a consumer group of a stream document, creating the stream if
+// needed. The group is delivered the entries after StartID.
+func (db *DB) CreateStreamGroup(opts StreamGroupOptions) (*StreamGroup, error) {
+	if err := validateStreamName("group", opts.Group); err != nil {
+		return nil, err
+	}
+	result, err := db.withStreamTransaction(opts.StreamOpOptions, true, func(s *elementStream) (interface{}, error) {
+		if _, err := s.group(opts.Group); !errors.Is(err, ErrStreamGroupNotFound) {
+			if err == nil {
+				return nil, ErrStreamGroupExists
+			}
+			return nil, err
+		}
+
+		var start model.StreamID
+		switch opts.StartID {
+		case "", streamLatestID:
+			start = lastStreamID(s.header)
+		case streamFirstID:
+		default:
+			var err error
+			if start, err = parseStreamID(opts.StartID); err != nil {
+				return nil, err
+			}
+		}
+
+		g := &StreamGroup{
+			Name:            opts.Group,
+			LastDeliveredID: start.String(),
+			Consumers:       make(map[string]int64),
+			CreatedAt:       time.Now(),
+		}
+		if err := s.saveGroup(g); err != nil {
+			return nil, err
+		}
+		return g, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*StreamGroup), nil
+}
+
+// DeleteStreamGroup removes a consumer group of a stream document along with its pending
+// entries.
+func (db *DB) DeleteStreamGroup(opts StreamGroupOptions) error {
+	_, err := db.withStreamTransaction(opts.StreamOpOptions, false, func(s *elementStream) (interface{}, error) {
+		if _, err := s.group(opts.Group); err != nil {
+			return nil, err
+		}
+		var pending []model.StreamID
+		err := s.scanPending(opts.Group, model.StreamID{}, func(p *StreamPendingEntry) bool {
+			id, _ := model.ParseStreamID(p.ID)
+			pending = append(pending, id)
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range pending {
+			if err := s.deletePending(opts.Group, id); err != nil {
+				return nil, err
+			}
+		}
+		if err := s.tc.txn.DeleteCF(s.handle, streamGroupKey(s.cf, s.key, s.header.Gen, opts.Group)); err != nil {
+			return nil, fmt.Errorf("failed to delete consumer group: %w", conflictError(err))
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// ReadStreamGroup delivers the entries of a stream document that the consumer group has not
+// been delivered yet to one of its consumers, and records them as pending until they are
+// acknowledged. Each entry is delivered to a single consumer of the group.
+//
+// With a timeout, it waits up to that long for entries to be appended while there are none,
+// giving up with an empty result, or with the context's error when ctx is cancelled.
+func (db *DB) ReadStreamGroup(ctx context.Context, opts StreamGroupReadOptions, timeout time.Duration) ([]model.StreamEntry, error) {
+	if err := validateStreamName("consumer", opts.Consumer); err != nil {
+		return nil, err
+	}
+	if opts.Limit < 0 {
+		return nil, fmt.Errorf("%w: count cannot be negative", ErrInvalidStreamOperation)
+	}
+	if timeout > 0 && opts.TxnID != "" {
+		return nil, fmt.Errorf("%w: blocking reads cannot run inside a transaction", ErrInvalidStreamOperation)
+	}
+
+	var entries []model.StreamEntry
+	attempt := func() (bool, error) {
+		result, err := db.withStreamTransaction(opts.StreamOpOptions, false, func(s *elementStream) (interface{}, error) {
+			return s.deliver(opts.Group, opts.Consumer, opts.Limit)
+		})
+		if err != nil {
+			return false, err
+		}
+		entries = result.([]model.StreamEntry)
+		return len(entries) > 0, nil
+	}
+
+	if timeout <= 0 {
+		_, err := attempt()
+		return entries, err
+	}
+	if _, err := db.waitForStream(ctx, opts.ColumnFamily, opts.Key, timeout, attempt); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// deliver hands the next entries of a group to a consumer.
+func (s *elementStream) deliver(group, consumer string, limit int) ([]model.StreamEntry, error) {
+	g, err := s.group(group)
+	if err != nil {
+		return nil, err
+	}
+	after, err := parseStreamID(g.LastDeliveredID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []model.StreamEntry{}
+	var ids []model.StreamID
+	var decodeErr error
+	err = s.scan(after.Next(), streamMaxID, false, func(id model.StreamID, data []byte) bool {
+		value, err := decodeElement(data)
+		if err != nil {
+			decodeErr = fmt.Errorf("failed to decode stream entry: %w", err)
+			return false
+		}
+		ids = append(ids, id)
+		entries = append(entries, model.StreamEntry{ID: id.String(), Value: value})
+		return limit == 0 || len(entries) < limit
+	})
+	if err != nil {
+		return nil, err
+	}
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+
+	now := time.Now()
+	for _, id := range ids {
+		p := &StreamPendingEntry{ID: id.String(), Consumer: consumer, DeliveredAt: now, Deliveries: 1}
+		if err := s.putPending(group, id, p); err != nil {
+			return nil, err
+		}
+	}
+
+	_, known := g.Consumers[consumer]
+	if len(entries) == 0 && known {
+		return entries, nil
+	}
+	g.Consumers[consumer] += int64(len(entries))
+	g.Pending += int64(len(entries))
+	if len(entries) > 0 {
+		g.LastDeliveredID = entries[len(entries)-1].ID
+	}
+	if err := s.saveGroup(g); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// AckStream acknowledges entries delivered to a consumer group, removing them from its
+// pending entries, and returns how many were pending.
+func (db *DB) AckStream(opts StreamAckOptions) (int64, error) {
+	ids := make([]model.StreamID, len(opts.IDs))
+	for i, s := range opts.IDs {
+		var err error
+		if ids[i], err = parseStreamID(s); err != nil {
+			return 0, err
+		}
+	}
+
+	result, err := db.withStreamTransaction(opts.StreamOpOptions, false, func(s *elementStream) (interface{}, error) {
+		g, err := s.group(opts.Group)
+		if err != nil {
+			return nil, err
+		}
+		var acked int64
+		for _, id := range ids {
+			p, err := s.pending(opts.Group, id)
+			if err != nil {
+				return nil, err
+			}
+			if p == nil {
+				continue
+			}
+			if err := s.deletePending(opts.Group, id); err != nil {
+				return nil, err
+			}
+			g.Consumers[p.Consumer]--
+			g.Pending--
+			acked++
+		}
+		if acked == 0 {
+			return acked, nil
+		}
+		return acked, s.saveGroup(g)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int64), nil
+}
+
+// ClaimStream hands the pending entries of a consumer group that were delivered at least
+// MinIdle ago, oldest ID first, to another consumer and returns them. It lets a consumer take
+// over the work of one that stopped without acknowledging its entries. Pending entries whose
+// entry was trimmed meanwhile are acknowledged instead.
+func (db *DB) ClaimStream(opts StreamClaimOptions) ([]model.StreamEntry, error) {
+	if err := validateStreamName("consumer", opts.Consumer); err != nil {
+		return nil, err
+	}
+	if opts.MinIdle < 0 || opts.Limit < 0 {
+		return nil, fmt.Errorf("%w: min idle time and count cannot be negative", ErrInvalidStreamOperation)
+	}
+
+	result, err := db.withStreamTransaction(opts.StreamOpOptions, false, func(s *elementStream) (interface{}, error) {
+		g, err := s.group(opts.Group)
+		if err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		var idle []*StreamPendingEntry
+		err = s.scanPending(opts.Group, model.StreamID{}, func(p *StreamPendingEntry) bool {
+			if now.Sub(p.DeliveredAt) >= opts.MinIdle {
+				idle = append(idle, p)
+			}
+			return opts.Limit == 0 || len(idle) < opts.Limit
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		claimed := []model.StreamEntry{}
+		for _, p := range idle {
+			id, _ := model.ParseStreamID(p.ID)
+			value, err := s.entry(id)
+			if err != nil {
+				return nil, err
+			}
+			g.Consumers[p.Consumer]--
+			if value == nil {
+				g.Pending--
+				if err := s.deletePending(opts.Group, id); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			p.Consumer = opts.Consumer
+			p.DeliveredAt = now
+			p.Deliveries++
+			if err := s.putPending(opts.Group, id, p); err != nil {
+				return nil, err
+			}
+			g.Consumers[opts.Consumer]++
+			claimed = append(claimed, model.StreamEntry{ID: p.ID, Value: value})
+		}
+		if len(idle) == 0 {
+			return claimed, nil
+		}
+		return claimed, s.saveGroup(g)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]model.StreamEntry), nil
+}
@@ -0,0 +1,224 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"mithrildb/model"
+
+	"github.com/linxGnu/grocksdb"
+)
+
+// GetStreamRange returns the entries of a stream document whose IDs are between Start and
+// End, inclusive, oldest first or, with Reverse, newest first.
+func (db *DB) GetStreamRange(opts StreamRangeOptions) ([]model.StreamEntry, error) {
+	if opts.Limit < 0 {
+		return nil, fmt.Errorf("%w: count cannot be negative", ErrInvalidStreamOperation)
+	}
+	start, err := parseStreamBound(opts.Start, true)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseStreamBound(opts.End, false)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := db.readStream(opts.StreamReadOptions)
+	if err != nil {
+		return nil, err
+	}
+	return db.streamEntries(opts.ReadOptions, opts.ColumnFamily, doc, start, end, opts.Reverse, opts.Limit)
+}
+
+// TailStream returns the entries of a stream document appended after the ID After, and the ID
+// to pass as After to follow the stream from there. A stream that does not exist yet is
+// followed from its first entry.
+//
+// With a timeout, it waits up to that long for entries to be appended while there are none,
+// giving up with an empty result, or with the context's error when ctx is cancelled.
+func (db *DB) TailStream(ctx context.Context, opts StreamTailOptions, timeout time.Duration) (entries []model.StreamEntry, last string, err error) {
+	if opts.Limit < 0 {
+		return nil, "", fmt.Errorf("%w: count cannot be negative", ErrInvalidStreamOperation)
+	}
+
+	var after model.StreamID
+	if opts.After == "" || opts.After == streamLatestID {
+		doc, err := db.readStream(opts.StreamReadOptions)
+		if err != nil && !errors.Is(err, ErrKeyNotFound) {
+			return nil, "", err
+		}
+		if doc != nil {
+			if after, err = streamDocumentLastID(doc); err != nil {
+				return nil, "", err
+			}
+		}
+	} else if after, err = parseStreamID(opts.After); err != nil {
+		return nil, "", err
+	}
+
+	attempt := func() (bool, error) {
+		doc, err := db.readStream(opts.StreamReadOptions)
+		if errors.Is(err, ErrKeyNotFound) {
+			entries = []model.StreamEntry{}
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		entries, err = db.streamEntries(opts.ReadOptions, opts.ColumnFamily, doc, after.Next(), streamMaxID, false, opts.Limit)
+		return len(entries) > 0, err
+	}
+	if timeout <= 0 {
+		_, err = attempt()
+	} else {
+		_, err = db.waitForStream(ctx, opts.ColumnFamily, opts.Key, timeout, attempt)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	last = after.String()
+	if len(entries) > 0 {
+		last = entries[len(entries)-1].ID
+	}
+	return entries, last, nil
+}
+
+// GetStreamInfo returns the length, first and last IDs and consumer groups of a stream document.
+func (db *DB) GetStreamInfo(opts StreamReadOptions) (*StreamInfo, error) {
+	doc, err := db.readStream(opts)
+	if err != nil {
+		return nil, err
+	}
+	first, err := db.streamEntries(opts.ReadOptions, opts.ColumnFamily, doc, model.StreamID{}, streamMaxID, false, 1)
+	if err != nil {
+		return nil, err
+	}
+	last, err := streamDocumentLastID(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &StreamInfo{Groups: []StreamGroup{}}
+	if len(first) > 0 {
+		info.FirstID = first[0].ID
+	}
+	if !last.IsZero() {
+		info.LastID = last.String()
+	}
+	header := doc.Meta.Elements
+	if header == nil {
+		entries, _ := model.ParseStreamEntries(doc.Value)
+		info.Length = int64(len(entries))
+		return info, nil
+	}
+	info.Length = header.Len
+
+	handle, ok := db.Family(CFSystemElements)
+	if !ok {
+		return nil, fmt.Errorf("column family %q not available", CFSystemElements)
+	}
+	iter := db.TransactionDB.NewIteratorCF(db.streamReadOptions(opts.ReadOptions), handle)
+	defer iter.Close()
+
+	prefix := streamPrefix(opts.ColumnFamily, opts.Key, header.Gen, streamGroupKind)
+	for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+		v := iter.Value()
+		var g StreamGroup
+		err := decodeRecord(v.Data(), &g)
+		v.Free()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode consumer group: %w", err)
+		}
+		if g.Consumers == nil {
+			g.Consumers = make(map[string]int64)
+		}
+		info.Groups = append(info.Groups, g)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// GetStreamPending returns the entries delivered to a consumer group and not yet acknowledged,
+// in ID order.
+func (db *DB) GetStreamPending(opts StreamPendingOptions) ([]StreamPendingEntry, error) {
+	if opts.Limit < 0 {
+		return nil, fmt.Errorf("%w: count cannot be negative", ErrInvalidStreamOperation)
+	}
+	if err := validateStreamName("group", opts.Group); err != nil {
+		return nil, err
+	}
+	var after model.StreamID
+	if opts.After != "" {
+		var err error
+		if after, err = parseStreamID(opts.After); err != nil {
+			return nil, err
+		}
+	}
+	doc, err := db.readStream(opts.StreamReadOptions)
+	if err != nil {
+		return nil, err
+	}
+	header := doc.Meta.Elements
+	if header == nil {
+		return nil, ErrStreamGroupNotFound
+	}
+	handle, ok := db.Family(CFSystemElements)
+	if !ok {
+		return nil, fmt.Errorf("column family %q not available", CFSystemElements)
+	}
+	readOpts := db.streamReadOptions(opts.ReadOptions)
+
+	val, err := db.TransactionDB.GetCF(readOpts, handle, streamGroupKey(opts.ColumnFamily, opts.Key, header.Gen, opts.Group))
+	if err != nil {
+		return nil, err
+	}
+	exists := val.Exists()
+	val.Free()
+	if !exists {
+		return nil, ErrStreamGroupNotFound
+	}
+
+	iter := db.TransactionDB.NewIteratorCF(readOpts, handle)
+	defer iter.Close()
+
+	pending := []StreamPendingEntry{}
+	err = scanStreamPending(iter, streamPendingPrefix(opts.ColumnFamily, opts.Key, header.Gen, opts.Group), after, func(p *StreamPendingEntry) bool {
+		if opts.Consumer == "" || p.Consumer == opts.Consumer {
+			pending = append(pending, *p)
+		}
+		return opts.Limit == 0 || len(pending) < opts.Limit
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// streamReadOptions returns the read options to use for a stream read.
+func (db *DB) streamReadOptions(readOpts *grocksdb.ReadOptions) *grocksdb.ReadOptions {
+	if readOpts == nil {
+		return db.DefaultReadOptions
+	}
+	return readOpts
+}
+
+// streamDocumentLastID returns the last ID given to an entry of a stream document, inline or
+// stored as elements.
+func streamDocumentLastID(doc *model.Document) (model.StreamID, error) {
+	if doc.Meta.Elements != nil {
+		return lastStreamID(doc.Meta.Elements), nil
+	}
+	entries, err := model.ParseStreamEntries(doc.Value)
+	if err != nil {
+		return model.StreamID{}, ErrInvalidStreamType
+	}
+	if len(entries) == 0 {
+		return model.StreamID{}, nil
+	}
+	return model.ParseStreamID(entries[len(entries)-1].ID)
+}
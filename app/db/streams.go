@@ -0,0 +1,673 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"mithrildb/events"
+	"mithrildb/model"
+
+	"github.com/linxGnu/grocksdb"
+)
+
+// Stream documents keep their entries in CFSystemElements, under the prefix of the document's
+// element generation followed by a kind byte:
+//
+//	'e' <id>                 entry value, with the 16-byte ID so entries sort in ID order
+//	'g' <group>              consumer group record
+//	'p' <group> \x00 <id>    entry delivered to a group and not yet acknowledged
+//
+// Consumer groups and pending entries share the generation of the entries, so deleting,
+// expiring or replacing a stream drops them too.
+const (
+	streamEntryKind   = 'e'
+	streamGroupKind   = 'g'
+	streamPendingKind = 'p'
+)
+
+// Special IDs accepted by stream operations.
+const (
+	streamFirstID   = "-"  // Before every entry
+	streamLastID    = "+"  // After every entry
+	streamLatestID  = "$"  // The last ID of the stream
+	streamAutoID    = "*"  // Generate the next ID
+	streamAutoSeqID = "-*" // Suffix generating the sequence number within a given millisecond
+)
+
+// streamMaxID sorts after every entry ID.
+var streamMaxID = model.StreamID{Ms: math.MaxUint64, Seq: math.MaxUint64}
+
+// StreamGroup describes a consumer group of a stream.
+type StreamGroup struct {
+	Name            string           `json:"name"`
+	LastDeliveredID string           `json:"last_delivered_id"` // Entries after this ID are the next delivered to the group
+	Pending         int64            `json:"pending"`           // Entries delivered and not yet acknowledged
+	Consumers       map[string]int64 `json:"consumers"`         // Pending entries of each consumer that read from the group
+	CreatedAt       time.Time        `json:"created_at"`
+}
+
+// StreamPendingEntry is an entry delivered to a consumer of a group and not yet acknowledged.
+type StreamPendingEntry struct {
+	ID          string    `json:"id"`
+	Consumer    string    `json:"consumer"`
+	DeliveredAt time.Time `json:"delivered_at"` // When the entry was last delivered
+	Deliveries  int       `json:"deliveries"`   // Times the entry was delivered, claims included
+}
+
+// StreamInfo describes a stream document.
+type StreamInfo struct {
+	Length  int64         `json:"length"`
+	FirstID string        `json:"first_id,omitempty"` // ID of the oldest entry kept
+	LastID  string        `json:"last_id,omitempty"`  // ID of the last entry appended, even if trimmed since
+	Groups  []StreamGroup `json:"groups"`
+}
+
+// streamPrefix returns the key prefix of one kind of stream record.
+func streamPrefix(cf, key string, gen uint64, kind byte) []byte {
+	return append(elementsPrefix(cf, key, gen), kind)
+}
+
+// streamEntryKey returns the key of the stream entry with the given ID.
+func streamEntryKey(cf, key string, gen uint64, id model.StreamID) []byte {
+	return append(streamPrefix(cf, key, gen, streamEntryKind), id.Bytes()...)
+}
+
+// streamGroupKey returns the key of a consumer group record.
+func streamGroupKey(cf, key string, gen uint64, group string) []byte {
+	return append(streamPrefix(cf, key, gen, streamGroupKind), group...)
+}
+
+// streamPendingPrefix returns the key prefix of the pending entries of a consumer group.
+func streamPendingPrefix(cf, key string, gen uint64, group string) []byte {
+	prefix := append(streamPrefix(cf, key, gen, streamPendingKind), group...)
+	return append(prefix, 0)
+}
+
+// streamPendingKey returns the key of a pending entry of a consumer group.
+func streamPendingKey(cf, key string, gen uint64, group string, id model.StreamID) []byte {
+	return append(streamPendingPrefix(cf, key, gen, group), id.Bytes()...)
+}
+
+// validateStreamName checks a consumer group or consumer name.
+func validateStreamName(kind, name string) error {
+	if name == "" || len(name) > 250 || strings.ContainsRune(name, 0) {
+		return fmt.Errorf("%w: invalid %s name %q", ErrInvalidStreamOperation, kind, name)
+	}
+	return nil
+}
+
+// parseStreamBound parses the start or end of an ID range, where "-" and "+" stand for the
+// first and last possible IDs.
+func parseStreamBound(s string, start bool) (model.StreamID, error) {
+	switch {
+	case s == streamFirstID || (s == "" && start):
+		return model.StreamID{}, nil
+	case s == streamLastID || (s == "" && !start):
+		return streamMaxID, nil
+	}
+	id, err := model.ParseStreamID(s)
+	if err != nil {
+		return model.StreamID{}, fmt.Errorf("%w: invalid stream ID %q", ErrInvalidStreamOperation, s)
+	}
+	// A bare millisecond end bound includes every entry of that millisecond.
+	if !start && !strings.Contains(s, "-") {
+		id.Seq = math.MaxUint64
+	}
+	return id, nil
+}
+
+// parseStreamID parses an entry ID given by a client.
+func parseStreamID(s string) (model.StreamID, error) {
+	id, err := model.ParseStreamID(s)
+	if err != nil {
+		return model.StreamID{}, fmt.Errorf("%w: invalid stream ID %q", ErrInvalidStreamOperation, s)
+	}
+	return id, nil
+}
+
+// lastStreamID returns the last ID given to an entry of a stream, or 0-0 for none.
+func lastStreamID(header *model.ElementsHeader) model.StreamID {
+	if header == nil || header.LastID == "" {
+		return model.StreamID{}
+	}
+	id, _ := model.ParseStreamID(header.LastID)
+	return id
+}
+
+// elementStream is a stream document stored as elements, modified inside a transaction.
+type elementStream struct {
+	tc       *txnContext
+	handle   *grocksdb.ColumnFamilyHandle
+	cf       string
+	key      string
+	header   *model.ElementsHeader
+	changed  bool // The entries or the header changed, so the document is written
+	appended bool // Entries were appended, so waiting readers are woken on commit
+}
+
+// nextID returns the ID of the next entry: the requested one, which must be greater than the
+// last ID, or one generated from the clock for "*", or for "<ms>-*" within that millisecond.
+func (s *elementStream) nextID(requested string) (model.StreamID, error) {
+	last := lastStreamID(s.header)
+	var id model.StreamID
+	switch {
+	case requested == "" || requested == streamAutoID:
+		now := uint64(time.Now().UnixMilli())
+		if now > last.Ms {
+			id = model.StreamID{Ms: now}
+		} else {
+			id = model.StreamID{Ms: last.Ms, Seq: last.Seq + 1}
+		}
+	case strings.HasSuffix(requested, streamAutoSeqID):
+		ms, err := parseStreamID(strings.TrimSuffix(requested, streamAutoSeqID))
+		if err != nil {
+			return id, err
+		}
+		id = model.StreamID{Ms: ms.Ms}
+		if ms.Ms == last.Ms {
+			id.Seq = last.Seq + 1
+		}
+	default:
+		var err error
+		if id, err = parseStreamID(requested); err != nil {
+			return id, err
+		}
+	}
+	if id.IsZero() || id.Compare(last) <= 0 {
+		return id, fmt.Errorf("%w: entry ID must be greater than the last ID %s", ErrInvalidStreamOperation, last)
+	}
+	return id, nil
+}
+
+// append stores an entry with the given ID, which must follow the last ID.
+func (s *elementStream) append(id model.StreamID, value interface{}) error {
+	data, err := encodeRecord(value)
+	if err != nil {
+		return fmt.Errorf("failed to serialize stream entry: %w", err)
+	}
+	if err := s.tc.txn.PutCF(s.handle, streamEntryKey(s.cf, s.key, s.header.Gen, id), data); err != nil {
+		return fmt.Errorf("failed to write stream entry: %w", conflictError(err))
+	}
+	s.header.Len++
+	s.header.LastID = id.String()
+	s.changed = true
+	s.appended = true
+	return nil
+}
+
+// trim removes the oldest entries until at most maxLen are left (0 = no limit) and none is
+// older than minID, and returns how many it removed.
+func (s *elementStream) trim(maxLen int64, minID model.StreamID) (int64, error) {
+	var removed int64
+	var stale [][]byte
+	err := s.scan(model.StreamID{}, streamMaxID, false, func(id model.StreamID, _ []byte) bool {
+		if (maxLen == 0 || s.header.Len-removed <= maxLen) && id.Compare(minID) >= 0 {
+			return false
+		}
+		stale = append(stale, streamEntryKey(s.cf, s.key, s.header.Gen, id))
+		removed++
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, k := range stale {
+		if err := s.tc.txn.DeleteCF(s.handle, k); err != nil {
+			return 0, fmt.Errorf("failed to delete stream entry: %w", conflictError(err))
+		}
+	}
+	if removed > 0 {
+		s.header.Len -= removed
+		s.changed = true
+	}
+	return removed, nil
+}
+
+// scan calls fn with the encoded entries whose IDs are between start and end, inclusive, in
+// ID order or, with reverse, newest first. It stops early when fn returns false.
+func (s *elementStream) scan(start, end model.StreamID, reverse bool, fn func(model.StreamID, []byte) bool) error {
+	iter := s.tc.txn.NewIteratorCF(s.tc.readOpts, s.handle)
+	defer iter.Close()
+	return scanStreamElements(iter, s.cf, s.key, s.header, start, end, reverse, fn)
+}
+
+// entry reads the value of an entry, or nil when it was trimmed.
+func (s *elementStream) entry(id model.StreamID) (interface{}, error) {
+	val, err := s.tc.txn.GetWithCF(s.tc.readOpts, s.handle, streamEntryKey(s.cf, s.key, s.header.Gen, id))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	if !val.Exists() {
+		return nil, nil
+	}
+	return decodeElement(val.Data())
+}
+
+// group loads a consumer group.
+func (s *elementStream) group(name string) (*StreamGroup, error) {
+	if err := validateStreamName("group", name); err != nil {
+		return nil, err
+	}
+	val, err := s.tc.txn.GetWithCF(s.tc.readOpts, s.handle, streamGroupKey(s.cf, s.key, s.header.Gen, name))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	if !val.Exists() {
+		return nil, ErrStreamGroupNotFound
+	}
+	var g StreamGroup
+	if err := decodeRecord(val.Data(), &g); err != nil {
+		return nil, fmt.Errorf("failed to decode consumer group: %w", err)
+	}
+	if g.Consumers == nil {
+		g.Consumers = make(map[string]int64)
+	}
+	return &g, nil
+}
+
+// saveGroup writes a consumer group.
+func (s *elementStream) saveGroup(g *StreamGroup) error {
+	data, err := encodeRecord(g)
+	if err != nil {
+		return fmt.Errorf("failed to serialize consumer group: %w", err)
+	}
+	if err := s.tc.txn.PutCF(s.handle, streamGroupKey(s.cf, s.key, s.header.Gen, g.Name), data); err != nil {
+		return fmt.Errorf("failed to write consumer group: %w", conflictError(err))
+	}
+	return nil
+}
+
+// pending loads a pending entry of a group, or nil when the entry is not pending.
+func (s *elementStream) pending(group string, id model.StreamID) (*StreamPendingEntry, error) {
+	val, err := s.tc.txn.GetWithCF(s.tc.readOpts, s.handle, streamPendingKey(s.cf, s.key, s.header.Gen, group, id))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	if !val.Exists() {
+		return nil, nil
+	}
+	var p StreamPendingEntry
+	if err := decodeRecord(val.Data(), &p); err != nil {
+		return nil, fmt.Errorf("failed to decode pending entry: %w", err)
+	}
+	return &p, nil
+}
+
+// putPending writes a pending entry of a group.
+func (s *elementStream) putPending(group string, id model.StreamID, p *StreamPendingEntry) error {
+	data, err := encodeRecord(p)
+	if err != nil {
+		return fmt.Errorf("failed to serialize pending entry: %w", err)
+	}
+	if err := s.tc.txn.PutCF(s.handle, streamPendingKey(s.cf, s.key, s.header.Gen, group, id), data); err != nil {
+		return fmt.Errorf("failed to write pending entry: %w", conflictError(err))
+	}
+	return nil
+}
+
+// deletePending removes a pending entry of a group.
+func (s *elementStream) deletePending(group string, id model.StreamID) error {
+	if err := s.tc.txn.DeleteCF(s.handle, streamPendingKey(s.cf, s.key, s.header.Gen, group, id)); err != nil {
+		return fmt.Errorf("failed to delete pending entry: %w", conflictError(err))
+	}
+	return nil
+}
+
+// scanPending calls fn with the pending entries of a group in ID order, starting after the
+// ID after. It stops early when fn returns false.
+func (s *elementStream) scanPending(group string, after model.StreamID, fn func(*StreamPendingEntry) bool) error {
+	iter := s.tc.txn.NewIteratorCF(s.tc.readOpts, s.handle)
+	defer iter.Close()
+	return scanStreamPending(iter, streamPendingPrefix(s.cf, s.key, s.header.Gen, group), after, fn)
+}
+
+// scanStreamElements calls fn with the encoded entries of a stream stored as elements whose IDs
+// are between start and end, inclusive, in ID order or, with reverse, newest first.
+func scanStreamElements(iter *grocksdb.Iterator, cf, key string, header *model.ElementsHeader, start, end model.StreamID, reverse bool, fn func(model.StreamID, []byte) bool) error {
+	prefix := streamPrefix(cf, key, header.Gen, streamEntryKind)
+	first := streamEntryKey(cf, key, header.Gen, start)
+	last := streamEntryKey(cf, key, header.Gen, end)
+
+	if reverse {
+		iter.SeekForPrev(last)
+	} else {
+		iter.Seek(first)
+	}
+	for ; iter.ValidForPrefix(prefix); stepIterator(iter, reverse) {
+		k := iter.Key()
+		id := model.StreamIDFromBytes(k.Data()[len(prefix):])
+		k.Free()
+		if (!reverse && id.Compare(end) > 0) || (reverse && id.Compare(start) < 0) {
+			break
+		}
+		v := iter.Value()
+		data := append([]byte(nil), v.Data()...)
+		v.Free()
+		if !fn(id, data) {
+			return nil
+		}
+	}
+	return iter.Err()
+}
+
+// scanStreamPending calls fn with the pending entries under a group's prefix in ID order,
+// starting after the ID after.
+func scanStreamPending(iter *grocksdb.Iterator, prefix []byte, after model.StreamID, fn func(*StreamPendingEntry) bool) error {
+	seek := append(prefix[:len(prefix):len(prefix)], after.Next().Bytes()...)
+	for iter.Seek(seek); iter.ValidForPrefix(prefix); iter.Next() {
+		v := iter.Value()
+		var p StreamPendingEntry
+		err := decodeRecord(v.Data(), &p)
+		v.Free()
+		if err != nil {
+			return fmt.Errorf("failed to decode pending entry: %w", err)
+		}
+		if !fn(&p) {
+			return nil
+		}
+	}
+	return iter.Err()
+}
+
+// stepIterator moves an iterator forward, or backward with reverse.
+func stepIterator(iter *grocksdb.Iterator, reverse bool) {
+	if reverse {
+		iter.Prev()
+	} else {
+		iter.Next()
+	}
+}
+
+// scanInlineStream is scanStreamElements for a stream still stored inline.
+func scanInlineStream(entries []model.StreamEntry, start, end model.StreamID, reverse bool, fn func(model.StreamID, interface{}) bool) {
+	for i := range entries {
+		e := entries[i]
+		if reverse {
+			e = entries[len(entries)-1-i]
+		}
+		id, _ := model.ParseStreamID(e.ID)
+		if id.Compare(start) < 0 || id.Compare(end) > 0 {
+			continue
+		}
+		if !fn(id, e.Value) {
+			return
+		}
+	}
+}
+
+// fillStreamEntries reads every entry of a stream stored as elements into its value.
+func fillStreamEntries(iter *grocksdb.Iterator, cf string, doc *model.Document) error {
+	entries := make([]model.StreamEntry, 0, doc.Meta.Elements.Len)
+	var decodeErr error
+	err := scanStreamElements(iter, cf, doc.Key, doc.Meta.Elements, model.StreamID{}, streamMaxID, false, func(id model.StreamID, data []byte) bool {
+		value, err := decodeElement(data)
+		if err != nil {
+			decodeErr = fmt.Errorf("failed to decode stream entry of %q: %w", doc.Key, err)
+			return false
+		}
+		entries = append(entries, model.StreamEntry{ID: id.String(), Value: value})
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if decodeErr != nil {
+		return decodeErr
+	}
+
+	doc.Value = model.StreamValue(entries)
+	doc.Meta.Elements = nil
+	return nil
+}
+
+// convertStream moves the inline entries of a stream document to element keys and gives the
+// document a stream header. Documents already stored as elements are left untouched.
+func (tc *txnContext) convertStream(cf string, doc *model.Document) error {
+	if doc.Meta.Elements != nil {
+		if doc.Meta.Elements.Kind != model.ElementsStream {
+			return ErrInvalidStreamType
+		}
+		return nil
+	}
+	entries, err := model.ParseStreamEntries(doc.Value)
+	if err != nil {
+		return ErrInvalidStreamType
+	}
+	handle, err := tc.db.EnsureSystemColumnFamily(CFSystemElements)
+	if err != nil {
+		return err
+	}
+
+	header := &model.ElementsHeader{Kind: model.ElementsStream, Gen: uint64(tc.db.clock.Now())}
+	s := &elementStream{tc: tc, handle: handle, cf: cf, key: doc.Key, header: header}
+	for _, e := range entries {
+		id, _ := model.ParseStreamID(e.ID)
+		if err := s.append(id, e.Value); err != nil {
+			return err
+		}
+	}
+
+	doc.Value = nil
+	doc.Meta.Elements = header
+	return nil
+}
+
+// withStreamTransaction applies a transactional update to a stream document.
+func (db *DB) withStreamTransaction(
+	opts StreamOpOptions,
+	create bool,
+	modifier func(*elementStream) (interface{}, error),
+) (interface{}, error) {
+	var result interface{}
+	err := db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		var err error
+		result, err = tc.modifyStream(opts, create, modifier)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// modifyStream applies a stream-modifying function to a stream document inside a transaction,
+// creating an empty stream first when create is set and the document does not exist.
+//
+// The document is locked for the whole update, which serializes the appends and consumer
+// group reads of a stream. It is only written when its entries or expiration change, so
+// consumer group bookkeeping does not create revisions.
+func (tc *txnContext) modifyStream(
+	opts StreamOpOptions,
+	create bool,
+	modifier func(*elementStream) (interface{}, error),
+) (interface{}, error) {
+	handle, err := tc.family(opts.ColumnFamily, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := tc.getForWrite(handle, opts.ColumnFamily, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+	var prevMeta *model.Metadata
+	if doc == nil {
+		if !create {
+			return nil, ErrKeyNotFound
+		}
+		doc = &model.Document{
+			Key: opts.Key,
+			Meta: model.Metadata{
+				Type:     model.DocTypeStream,
+				Elements: &model.ElementsHeader{Kind: model.ElementsStream, Gen: uint64(tc.db.clock.Now())},
+			},
+		}
+	} else {
+		if opts.Cas != "" && doc.Meta.Rev != opts.Cas {
+			return nil, ErrRevisionMismatch
+		}
+		if doc.Meta.Type != model.DocTypeStream {
+			return nil, ErrInvalidStreamType
+		}
+		metaCopy := doc.Meta
+		prevMeta = &metaCopy
+		if err := tc.convertStream(opts.ColumnFamily, doc); err != nil {
+			return nil, err
+		}
+	}
+	elements, err := tc.db.EnsureSystemColumnFamily(CFSystemElements)
+	if err != nil {
+		return nil, err
+	}
+
+	// The header is copied so the previous metadata keeps the old length.
+	header := *doc.Meta.Elements
+	s := &elementStream{tc: tc, handle: elements, cf: opts.ColumnFamily, key: opts.Key, header: &header}
+	result, err := modifier(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.appended {
+		tc.pushed = append(tc.pushed, listPush{id: listWaitKey(opts.ColumnFamily, opts.Key), count: -1})
+	}
+	if !s.changed && prevMeta != nil && prevMeta.Elements != nil && opts.Expiration == nil {
+		return result, nil
+	}
+	doc.Meta.Elements = &header
+	doc.Meta.UpdatedAt = time.Now()
+
+	if opts.Expiration != nil {
+		if err := model.ValidateExpiration(*opts.Expiration); err != nil {
+			return nil, err
+		}
+		doc.Meta.Expiration = *opts.Expiration
+	}
+
+	operation := events.OpMutate
+	if prevMeta == nil {
+		operation = events.OpPut
+	}
+	if err := tc.writeDocument(handle, opts.ColumnFamily, doc, events.ChangeEventOptions{
+		Operation:          operation,
+		PreviousMeta:       prevMeta,
+		ExplicitExpiration: opts.Expiration,
+	}); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// readStream loads a stream document, which may be stored inline or as elements.
+func (db *DB) readStream(opts StreamReadOptions) (*model.Document, error) {
+	handle, ok := db.Family(opts.ColumnFamily)
+	if !ok {
+		return nil, ErrInvalidColumnFamily
+	}
+	if opts.ReadOptions == nil {
+		opts.ReadOptions = db.DefaultReadOptions
+	}
+	if err := model.ValidateDocumentKey(opts.Key); err != nil {
+		return nil, err
+	}
+
+	val, err := db.TransactionDB.GetCF(opts.ReadOptions, handle, []byte(opts.Key))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+
+	if !val.Exists() || val.Size() == 0 {
+		return nil, ErrKeyNotFound
+	}
+
+	var doc model.Document
+	if err := decodeDocument(val.Data(), &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	if model.IsExpired(doc.Meta) {
+		return nil, ErrKeyNotFound
+	}
+	if doc.Meta.Type != model.DocTypeStream {
+		return nil, ErrInvalidStreamType
+	}
+	return &doc, nil
+}
+
+// streamEntries reads up to limit entries of a stream document whose IDs are between start
+// and end, inclusive, in ID order or, with reverse, newest first (limit 0 = no limit).
+func (db *DB) streamEntries(readOpts *grocksdb.ReadOptions, cf string, doc *model.Document, start, end model.StreamID, reverse bool, limit int) ([]model.StreamEntry, error) {
+	entries := []model.StreamEntry{}
+	header := doc.Meta.Elements
+	if header == nil {
+		inline, err := model.ParseStreamEntries(doc.Value)
+		if err != nil {
+			return nil, ErrInvalidStreamType
+		}
+		scanInlineStream(inline, start, end, reverse, func(id model.StreamID, value interface{}) bool {
+			entries = append(entries, model.StreamEntry{ID: id.String(), Value: value})
+			return limit == 0 || len(entries) < limit
+		})
+		return entries, nil
+	}
+
+	handle, ok := db.Family(CFSystemElements)
+	if !ok {
+		return nil, fmt.Errorf("column family %q not available", CFSystemElements)
+	}
+	iter := db.TransactionDB.NewIteratorCF(db.streamReadOptions(readOpts), handle)
+	defer iter.Close()
+
+	var decodeErr error
+	err := scanStreamElements(iter, cf, doc.Key, header, start, end, reverse, func(id model.StreamID, data []byte) bool {
+		value, err := decodeElement(data)
+		if err != nil {
+			decodeErr = fmt.Errorf("failed to decode stream entry: %w", err)
+			return false
+		}
+		entries = append(entries, model.StreamEntry{ID: id.String(), Value: value})
+		return limit == 0 || len(entries) < limit
+	})
+	if err != nil {
+		return nil, err
+	}
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+	return entries, nil
+}
+
+// waitForStream calls attempt until it reports done, waiting between attempts for entries to
+// be appended to the stream, up to timeout. It returns done=false when the timeout elapses.
+func (db *DB) waitForStream(ctx context.Context, cf, key string, timeout time.Duration, attempt func() (bool, error)) (bool, error) {
+	id := listWaitKey(cf, key)
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		// Registering before the attempt means an append committed after it always wakes us.
+		w := db.listWaiters.add(id)
+		done, err := attempt()
+		if err != nil || done {
+			db.listWaiters.leave(id, w)
+			return done, err
+		}
+
+		select {
+		case <-w.ready:
+		case <-timer.C:
+			db.listWaiters.leave(id, w)
+			return false, nil
+		case <-ctx.Done():
+			db.listWaiters.leave(id, w)
+			return false, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,547 @@
+package db
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"mithrildb/events"
+	"mithrildb/model"
+
+	"github.com/linxGnu/grocksdb"
+)
+
+// Time series documents keep their samples in CFSystemElements, under the prefix of the
+// document's element generation followed by a kind byte:
+//
+//	'c'          settings record (retention and compaction rules)
+//	's' <ts>     sample, with the 8-byte big-endian timestamp so samples sort in time order
+//
+// The settings share the generation of the samples, so deleting, expiring or replacing a
+// series drops them too.
+const (
+	timeSeriesSettingsKind = 'c'
+	timeSeriesSampleKind   = 's'
+)
+
+// maxTimeSeriesRuleDepth bounds how many series a sample can be compacted through, which also
+// stops compaction rules that form a cycle.
+const maxTimeSeriesRuleDepth = 8
+
+// TimeSeriesInfo describes a time series document.
+type TimeSeriesInfo struct {
+	Length         int64                    `json:"length"`
+	FirstTimestamp *int64                   `json:"first_timestamp,omitempty"` // Timestamp of the oldest sample
+	LastTimestamp  *int64                   `json:"last_timestamp,omitempty"`  // Timestamp of the newest sample
+	Settings       model.TimeSeriesSettings `json:"settings"`
+}
+
+// timeSeriesLimits are the parsed settings of a time series.
+type timeSeriesLimits struct {
+	retention int64 // Milliseconds of samples kept before the newest one (0 = forever)
+	rules     []timeSeriesRule
+}
+
+// timeSeriesRule is a parsed compaction rule.
+type timeSeriesRule struct {
+	model.TimeSeriesRule
+	bucket int64 // Bucket duration in milliseconds
+}
+
+// timeSeriesPrefix returns the key prefix of one kind of time series record.
+func timeSeriesPrefix(cf, key string, gen uint64, kind byte) []byte {
+	return append(elementsPrefix(cf, key, gen), kind)
+}
+
+// timeSeriesSampleKey returns the key of the sample with the given timestamp.
+func timeSeriesSampleKey(cf, key string, gen uint64, ts int64) []byte {
+	var t [8]byte
+	binary.BigEndian.PutUint64(t[:], uint64(ts))
+	return append(timeSeriesPrefix(cf, key, gen, timeSeriesSampleKind), t[:]...)
+}
+
+// parseTimeSeriesSettings validates the settings of the time series key.
+func parseTimeSeriesSettings(key string, settings model.TimeSeriesSettings) (timeSeriesLimits, error) {
+	var limits timeSeriesLimits
+	if settings.Retention != "" {
+		retention, err := time.ParseDuration(settings.Retention)
+		if err != nil || retention < time.Millisecond {
+			return limits, fmt.Errorf("%w: retention must be a duration of at least 1ms", ErrInvalidTimeSeriesOperation)
+		}
+		limits.retention = retention.Milliseconds()
+	}
+
+	destinations := make(map[string]bool, len(settings.Rules))
+	for _, r := range settings.Rules {
+		if err := model.ValidateDocumentKey(r.Destination); err != nil {
+			return limits, fmt.Errorf("%w: invalid rule destination %q", ErrInvalidTimeSeriesOperation, r.Destination)
+		}
+		if r.Destination == key || destinations[r.Destination] {
+			return limits, fmt.Errorf("%w: rule destination %q must be another series, used by a single rule", ErrInvalidTimeSeriesOperation, r.Destination)
+		}
+		destinations[r.Destination] = true
+		if !model.ValidAggregation(r.Aggregation) {
+			return limits, fmt.Errorf("%w: unknown aggregation %q", ErrInvalidTimeSeriesOperation, r.Aggregation)
+		}
+		bucket, err := time.ParseDuration(r.Bucket)
+		if err != nil || bucket < time.Millisecond {
+			return limits, fmt.Errorf("%w: rule bucket must be a duration of at least 1ms", ErrInvalidTimeSeriesOperation)
+		}
+		limits.rules = append(limits.rules, timeSeriesRule{TimeSeriesRule: r, bucket: bucket.Milliseconds()})
+	}
+	return limits, nil
+}
+
+// bucketStart returns the start of the bucket holding the timestamp ts.
+func bucketStart(ts, bucket int64) int64 {
+	return ts - ts%bucket
+}
+
+// bucketEnd returns the last timestamp of the bucket starting at start.
+func bucketEnd(start, bucket int64) int64 {
+	if start > math.MaxInt64-bucket {
+		return math.MaxInt64
+	}
+	return start + bucket - 1
+}
+
+// elementSeries is a time series document stored as elements, modified inside a transaction.
+type elementSeries struct {
+	tc       *txnContext
+	handle   *grocksdb.ColumnFamilyHandle
+	cf       string
+	key      string
+	header   *model.ElementsHeader
+	settings model.TimeSeriesSettings
+	limits   timeSeriesLimits
+	touched  map[int64]bool // Timestamps added or removed, whose buckets the compaction rules recompute
+	changed  bool           // The samples or the header changed, so the document is written
+}
+
+// loadSettings reads the settings of the series.
+func (s *elementSeries) loadSettings() error {
+	val, err := s.tc.txn.GetWithCF(s.tc.readOpts, s.handle, timeSeriesPrefix(s.cf, s.key, s.header.Gen, timeSeriesSettingsKind))
+	if err != nil {
+		return err
+	}
+	defer val.Free()
+	if !val.Exists() {
+		return nil
+	}
+	if err := decodeRecord(val.Data(), &s.settings); err != nil {
+		return fmt.Errorf("failed to decode time series settings: %w", err)
+	}
+	s.limits, err = parseTimeSeriesSettings(s.key, s.settings)
+	return err
+}
+
+// saveSettings validates and writes the settings of the series.
+func (s *elementSeries) saveSettings(settings model.TimeSeriesSettings) error {
+	limits, err := parseTimeSeriesSettings(s.key, settings)
+	if err != nil {
+		return err
+	}
+	data, err := encodeRecord(settings)
+	if err != nil {
+		return fmt.Errorf("failed to serialize time series settings: %w", err)
+	}
+	if err := s.tc.txn.PutCF(s.handle, timeSeriesPrefix(s.cf, s.key, s.header.Gen, timeSeriesSettingsKind), data); err != nil {
+		return fmt.Errorf("failed to write time series settings: %w", conflictError(err))
+	}
+	s.settings = settings
+	s.limits = limits
+	return nil
+}
+
+// add stores samples, replacing those with the same timestamps, then drops the samples that
+// fall out of the retention period. Samples already out of it are rejected or, unless strict,
+// skipped.
+func (s *elementSeries) add(samples []model.TimeSeriesSample, strict bool) error {
+	newest, ok, err := s.newest()
+	if err != nil {
+		return err
+	}
+	for _, sample := range samples {
+		if !ok || sample.Timestamp > newest {
+			newest, ok = sample.Timestamp, true
+		}
+	}
+	for _, sample := range samples {
+		if s.limits.retention > 0 && sample.Timestamp < newest-s.limits.retention {
+			if strict {
+				return fmt.Errorf("%w: sample at %d is older than the retention period", ErrInvalidTimeSeriesOperation, sample.Timestamp)
+			}
+			continue
+		}
+		if err := s.put(sample); err != nil {
+			return err
+		}
+	}
+	return s.applyRetention()
+}
+
+// put stores a sample, replacing the one with the same timestamp.
+func (s *elementSeries) put(sample model.TimeSeriesSample) error {
+	k := timeSeriesSampleKey(s.cf, s.key, s.header.Gen, sample.Timestamp)
+	val, err := s.tc.txn.GetWithCF(s.tc.readOpts, s.handle, k)
+	if err != nil {
+		return err
+	}
+	exists := val.Exists()
+	val.Free()
+
+	data, err := encodeRecord(sample)
+	if err != nil {
+		return fmt.Errorf("failed to serialize time series sample: %w", err)
+	}
+	if err := s.tc.txn.PutCF(s.handle, k, data); err != nil {
+		return fmt.Errorf("failed to write time series sample: %w", conflictError(err))
+	}
+	if !exists {
+		s.header.Len++
+	}
+	s.touch(sample.Timestamp)
+	s.changed = true
+	return nil
+}
+
+// remove deletes the samples between from and to, inclusive, and returns how many it removed.
+// Unless track is set, compaction rules do not see the removal, which keeps the downsampled
+// samples of the buckets dropped by retention.
+func (s *elementSeries) remove(from, to int64, track bool) (int64, error) {
+	var stale []int64
+	err := s.scan(from, to, false, func(sample model.TimeSeriesSample) bool {
+		stale = append(stale, sample.Timestamp)
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, ts := range stale {
+		if err := s.tc.txn.DeleteCF(s.handle, timeSeriesSampleKey(s.cf, s.key, s.header.Gen, ts)); err != nil {
+			return 0, fmt.Errorf("failed to delete time series sample: %w", conflictError(err))
+		}
+		if track {
+			s.touch(ts)
+		}
+	}
+	if len(stale) > 0 {
+		s.header.Len -= int64(len(stale))
+		s.changed = true
+	}
+	return int64(len(stale)), nil
+}
+
+// applyRetention drops the samples older than the retention period before the newest sample.
+func (s *elementSeries) applyRetention() error {
+	if s.limits.retention == 0 {
+		return nil
+	}
+	newest, ok, err := s.newest()
+	if err != nil || !ok || newest-s.limits.retention <= 0 {
+		return err
+	}
+	_, err = s.remove(0, newest-s.limits.retention-1, false)
+	return err
+}
+
+// touch records that the sample at ts was added or removed.
+func (s *elementSeries) touch(ts int64) {
+	if s.touched == nil {
+		s.touched = make(map[int64]bool)
+	}
+	s.touched[ts] = true
+}
+
+// newest returns the timestamp of the newest sample, if any.
+func (s *elementSeries) newest() (int64, bool, error) {
+	var newest int64
+	var found bool
+	err := s.scan(0, math.MaxInt64, true, func(sample model.TimeSeriesSample) bool {
+		newest, found = sample.Timestamp, true
+		return false
+	})
+	return newest, found, err
+}
+
+// scan calls fn with the samples whose timestamps are between from and to, inclusive, in time
+// order or, with reverse, newest first. It stops early when fn returns false.
+func (s *elementSeries) scan(from, to int64, reverse bool, fn func(model.TimeSeriesSample) bool) error {
+	iter := s.tc.txn.NewIteratorCF(s.tc.readOpts, s.handle)
+	defer iter.Close()
+	return scanTimeSeriesElements(iter, s.cf, s.key, s.header, from, to, reverse, fn)
+}
+
+// compact recomputes, for every compaction rule, the buckets holding the samples added or
+// removed and writes them to the rule's destination series, creating it if needed. depth is
+// the number of series the samples were compacted through so far.
+func (s *elementSeries) compact(opts TimeSeriesOpOptions, depth int) error {
+	if len(s.touched) == 0 || len(s.limits.rules) == 0 {
+		return nil
+	}
+	if depth >= maxTimeSeriesRuleDepth {
+		return fmt.Errorf("%w: compaction rules chain more than %d series or form a cycle", ErrInvalidTimeSeriesOperation, maxTimeSeriesRuleDepth)
+	}
+
+	for _, rule := range s.limits.rules {
+		starts := make(map[int64]bool)
+		for ts := range s.touched {
+			starts[bucketStart(ts, rule.bucket)] = true
+		}
+		buckets := make([]int64, 0, len(starts))
+		for start := range starts {
+			buckets = append(buckets, start)
+		}
+		sort.Slice(buckets, func(i, j int) bool { return buckets[i] < buckets[j] })
+
+		var samples []model.TimeSeriesSample
+		var emptied []int64
+		for _, start := range buckets {
+			var agg model.Aggregator
+			err := s.scan(start, bucketEnd(start, rule.bucket), false, func(sample model.TimeSeriesSample) bool {
+				agg.Add(sample.Value)
+				return true
+			})
+			if err != nil {
+				return err
+			}
+			if agg.Count == 0 {
+				emptied = append(emptied, start)
+				continue
+			}
+			samples = append(samples, model.TimeSeriesSample{Timestamp: start, Value: agg.Result(rule.Aggregation)})
+		}
+
+		dest := TimeSeriesOpOptions{
+			ColumnFamily: opts.ColumnFamily,
+			Key:          rule.Destination,
+			WriteOptions: opts.WriteOptions,
+			TxnID:        opts.TxnID,
+		}
+		_, err := s.tc.modifyTimeSeries(dest, true, depth+1, func(d *elementSeries) (interface{}, error) {
+			for _, start := range emptied {
+				if _, err := d.remove(start, start, true); err != nil {
+					return nil, err
+				}
+			}
+			return nil, d.add(samples, false)
+		})
+		if err != nil {
+			return fmt.Errorf("compaction into %q failed: %w", rule.Destination, err)
+		}
+	}
+	return nil
+}
+
+// scanTimeSeriesElements calls fn with the samples of a time series stored as elements whose
+// timestamps are between from and to, inclusive, in time order or, with reverse, newest first.
+func scanTimeSeriesElements(iter *grocksdb.Iterator, cf, key string, header *model.ElementsHeader, from, to int64, reverse bool, fn func(model.TimeSeriesSample) bool) error {
+	if from < 0 {
+		from = 0
+	}
+	if to < from {
+		return nil
+	}
+	prefix := timeSeriesPrefix(cf, key, header.Gen, timeSeriesSampleKind)
+	if reverse {
+		iter.SeekForPrev(timeSeriesSampleKey(cf, key, header.Gen, to))
+	} else {
+		iter.Seek(timeSeriesSampleKey(cf, key, header.Gen, from))
+	}
+	for ; iter.ValidForPrefix(prefix); stepIterator(iter, reverse) {
+		k := iter.Key()
+		ts := int64(binary.BigEndian.Uint64(k.Data()[len(prefix):]))
+		k.Free()
+		if (!reverse && ts > to) || (reverse && ts < from) {
+			break
+		}
+		v := iter.Value()
+		var sample model.TimeSeriesSample
+		err := decodeRecord(v.Data(), &sample)
+		v.Free()
+		if err != nil {
+			return fmt.Errorf("failed to decode time series sample: %w", err)
+		}
+		if !fn(sample) {
+			return nil
+		}
+	}
+	return iter.Err()
+}
+
+// scanInlineTimeSeries is scanTimeSeriesElements for a time series still stored inline.
+func scanInlineTimeSeries(samples []model.TimeSeriesSample, from, to int64, reverse bool, fn func(model.TimeSeriesSample) bool) {
+	for i := range samples {
+		sample := samples[i]
+		if reverse {
+			sample = samples[len(samples)-1-i]
+		}
+		if sample.Timestamp < from || sample.Timestamp > to {
+			continue
+		}
+		if !fn(sample) {
+			return
+		}
+	}
+}
+
+// fillTimeSeriesSamples reads every sample of a time series stored as elements into its value.
+func fillTimeSeriesSamples(iter *grocksdb.Iterator, cf string, doc *model.Document) error {
+	samples := make([]model.TimeSeriesSample, 0, doc.Meta.Elements.Len)
+	err := scanTimeSeriesElements(iter, cf, doc.Key, doc.Meta.Elements, 0, math.MaxInt64, false, func(sample model.TimeSeriesSample) bool {
+		samples = append(samples, sample)
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read time series %q: %w", doc.Key, err)
+	}
+
+	doc.Value = model.TimeSeriesValue(samples)
+	doc.Meta.Elements = nil
+	return nil
+}
+
+// convertTimeSeries moves the inline samples of a time series document to element keys and
+// gives the document a time series header. Documents already stored as elements are left
+// untouched.
+func (tc *txnContext) convertTimeSeries(cf string, doc *model.Document) error {
+	if doc.Meta.Elements != nil {
+		if doc.Meta.Elements.Kind != model.ElementsTimeSeries {
+			return ErrInvalidTimeSeriesType
+		}
+		return nil
+	}
+	samples, err := model.ParseTimeSeriesSamples(doc.Value)
+	if err != nil {
+		return ErrInvalidTimeSeriesType
+	}
+	handle, err := tc.db.EnsureSystemColumnFamily(CFSystemElements)
+	if err != nil {
+		return err
+	}
+
+	header := &model.ElementsHeader{Kind: model.ElementsTimeSeries, Gen: uint64(tc.db.clock.Now())}
+	s := &elementSeries{tc: tc, handle: handle, cf: cf, key: doc.Key, header: header}
+	for _, sample := range samples {
+		if err := s.put(sample); err != nil {
+			return err
+		}
+	}
+
+	doc.Value = nil
+	doc.Meta.Elements = header
+	return nil
+}
+
+// withTimeSeriesTransaction applies a transactional update to a time series document.
+func (db *DB) withTimeSeriesTransaction(
+	opts TimeSeriesOpOptions,
+	create bool,
+	modifier func(*elementSeries) (interface{}, error),
+) (interface{}, error) {
+	var result interface{}
+	err := db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		var err error
+		result, err = tc.modifyTimeSeries(opts, create, 0, modifier)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// modifyTimeSeries applies a time-series-modifying function to a time series document inside
+// a transaction, creating an empty series first when create is set and the document does not
+// exist, then applies the compaction rules of the series to the samples added or removed.
+//
+// The document is only written when its samples or expiration change, so updating the
+// settings alone does not create a revision.
+func (tc *txnContext) modifyTimeSeries(
+	opts TimeSeriesOpOptions,
+	create bool,
+	depth int,
+	modifier func(*elementSeries) (interface{}, error),
+) (interface{}, error) {
+	handle, err := tc.family(opts.ColumnFamily, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := tc.getForWrite(handle, opts.ColumnFamily, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+	var prevMeta *model.Metadata
+	if doc == nil {
+		if !create {
+			return nil, ErrKeyNotFound
+		}
+		doc = &model.Document{
+			Key: opts.Key,
+			Meta: model.Metadata{
+				Type:     model.DocTypeTimeSeries,
+				Elements: &model.ElementsHeader{Kind: model.ElementsTimeSeries, Gen: uint64(tc.db.clock.Now())},
+			},
+		}
+	} else {
+		if opts.Cas != "" && doc.Meta.Rev != opts.Cas {
+			return nil, ErrRevisionMismatch
+		}
+		if doc.Meta.Type != model.DocTypeTimeSeries {
+			return nil, ErrInvalidTimeSeriesType
+		}
+		metaCopy := doc.Meta
+		prevMeta = &metaCopy
+		if err := tc.convertTimeSeries(opts.ColumnFamily, doc); err != nil {
+			return nil, err
+		}
+	}
+	elements, err := tc.db.EnsureSystemColumnFamily(CFSystemElements)
+	if err != nil {
+		return nil, err
+	}
+
+	// The header is copied so the previous metadata keeps the old length.
+	header := *doc.Meta.Elements
+	s := &elementSeries{tc: tc, handle: elements, cf: opts.ColumnFamily, key: opts.Key, header: &header}
+	if err := s.loadSettings(); err != nil {
+		return nil, err
+	}
+	result, err := modifier(s)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.compact(opts, depth); err != nil {
+		return nil, err
+	}
+
+	if !s.changed && prevMeta != nil && prevMeta.Elements != nil && opts.Expiration == nil {
+		return result, nil
+	}
+	doc.Meta.Elements = &header
+	doc.Meta.UpdatedAt = time.Now()
+
+	if opts.Expiration != nil {
+		if err := model.ValidateExpiration(*opts.Expiration); err != nil {
+			return nil, err
+		}
+		doc.Meta.Expiration = *opts.Expiration
+	}
+
+	operation := events.OpMutate
+	if prevMeta == nil {
+		operation = events.OpPut
+	}
+	if err := tc.writeDocument(handle, opts.ColumnFamily, doc, events.ChangeEventOptions{
+		Operation:          operation,
+		PreviousMeta:       prevMeta,
+		ExplicitExpiration: opts.Expiration,
+	}); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
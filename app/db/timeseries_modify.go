@@ -0,0 +1,62 @@
+package db
+
+import "fmt"
+
+// AddTimeSeriesSamples adds samples to a time series document, creating the series if needed,
+// and returns its length. A sample replaces the one with the same timestamp. Samples older
+// than the retention period before the newest sample are rejected, and the samples that fall
+// out of it are dropped. The compaction rules of the series update their destination series
+// in the same transaction.
+func (db *DB) AddTimeSeriesSamples(opts TimeSeriesAddOptions) (int64, error) {
+	if len(opts.Samples) == 0 {
+		return 0, fmt.Errorf("%w: no samples to add", ErrInvalidTimeSeriesOperation)
+	}
+	for _, sample := range opts.Samples {
+		if sample.Timestamp < 0 {
+			return 0, fmt.Errorf("%w: sample timestamp cannot be negative", ErrInvalidTimeSeriesOperation)
+		}
+	}
+
+	result, err := db.withTimeSeriesTransaction(opts.TimeSeriesOpOptions, true, func(s *elementSeries) (interface{}, error) {
+		if err := s.add(opts.Samples, true); err != nil {
+			return nil, err
+		}
+		return s.header.Len, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int64), nil
+}
+
+// DeleteTimeSeriesRange removes the samples of a time series document between From and To,
+// inclusive, and returns how many it removed. Compaction rules recompute the buckets of the
+// removed samples.
+func (db *DB) DeleteTimeSeriesRange(opts TimeSeriesDeleteOptions) (int64, error) {
+	if opts.To < opts.From {
+		return 0, fmt.Errorf("%w: to cannot be lower than from", ErrInvalidTimeSeriesOperation)
+	}
+	result, err := db.withTimeSeriesTransaction(opts.TimeSeriesOpOptions, false, func(s *elementSeries) (interface{}, error) {
+		return s.remove(opts.From, opts.To, true)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int64), nil
+}
+
+// ConfigureTimeSeries sets the retention period and compaction rules of a time series
+// document, creating the series if needed, and drops the samples out of the new retention
+// period. New rules only apply to samples added or removed from then on.
+func (db *DB) ConfigureTimeSeries(opts TimeSeriesConfigureOptions) error {
+	if _, err := parseTimeSeriesSettings(opts.Key, opts.Settings); err != nil {
+		return err
+	}
+	_, err := db.withTimeSeriesTransaction(opts.TimeSeriesOpOptions, true, func(s *elementSeries) (interface{}, error) {
+		if err := s.saveSettings(opts.Settings); err != nil {
+			return nil, err
+		}
+		return nil, s.applyRetention()
+	})
+	return err
+}
@@ -0,0 +1,202 @@
+package db
+
+import (
+	"fmt"
+	"math"
+
+	"mithrildb/model"
+)
+
+// GetTimeSeriesRange returns the samples of a time series document with timestamps between
+// From and To, inclusive, and every label of Labels, oldest first or, with Reverse, newest
+// first.
+func (db *DB) GetTimeSeriesRange(opts TimeSeriesRangeOptions) ([]model.TimeSeriesSample, error) {
+	if opts.Limit < 0 {
+		return nil, fmt.Errorf("%w: count cannot be negative", ErrInvalidTimeSeriesOperation)
+	}
+	doc, err := db.readTimeSeries(opts.TimeSeriesReadOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := []model.TimeSeriesSample{}
+	err = db.timeSeriesSamples(opts.TimeSeriesReadOptions, doc, opts.From, opts.To, opts.Reverse, func(sample model.TimeSeriesSample) bool {
+		if sample.MatchLabels(opts.Labels) {
+			samples = append(samples, sample)
+		}
+		return opts.Limit == 0 || len(samples) < opts.Limit
+	})
+	if err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// AggregateTimeSeries aggregates the samples of a time series document with timestamps
+// between From and To, inclusive, and every label of Labels into buckets of the given
+// duration, oldest first or, with Reverse, newest first. Buckets without samples are left out.
+func (db *DB) AggregateTimeSeries(opts TimeSeriesAggregateOptions) ([]model.TimeSeriesBucket, error) {
+	if opts.Limit < 0 {
+		return nil, fmt.Errorf("%w: count cannot be negative", ErrInvalidTimeSeriesOperation)
+	}
+	if !model.ValidAggregation(opts.Aggregation) {
+		return nil, fmt.Errorf("%w: unknown aggregation %q", ErrInvalidTimeSeriesOperation, opts.Aggregation)
+	}
+	bucket := opts.Bucket.Milliseconds()
+	if bucket < 1 {
+		return nil, fmt.Errorf("%w: bucket must be at least 1ms", ErrInvalidTimeSeriesOperation)
+	}
+	doc, err := db.readTimeSeries(opts.TimeSeriesReadOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := []model.TimeSeriesBucket{}
+	var agg model.Aggregator
+	current := int64(-1)
+	flush := func() {
+		if agg.Count > 0 {
+			buckets = append(buckets, model.TimeSeriesBucket{Timestamp: current, Value: agg.Result(opts.Aggregation), Count: agg.Count})
+		}
+		agg = model.Aggregator{}
+	}
+	err = db.timeSeriesSamples(opts.TimeSeriesReadOptions, doc, opts.From, opts.To, opts.Reverse, func(sample model.TimeSeriesSample) bool {
+		if !sample.MatchLabels(opts.Labels) {
+			return true
+		}
+		if start := bucketStart(sample.Timestamp, bucket); start != current {
+			flush()
+			if opts.Limit > 0 && len(buckets) == opts.Limit {
+				return false
+			}
+			current = start
+		}
+		agg.Add(sample.Value)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if opts.Limit == 0 || len(buckets) < opts.Limit {
+		flush()
+	}
+	return buckets, nil
+}
+
+// GetTimeSeriesInfo returns the length, first and last timestamps and settings of a time
+// series document.
+func (db *DB) GetTimeSeriesInfo(opts TimeSeriesReadOptions) (*TimeSeriesInfo, error) {
+	doc, err := db.readTimeSeries(opts)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := db.timeSeriesSettings(opts, doc)
+	if err != nil {
+		return nil, err
+	}
+	info := &TimeSeriesInfo{Settings: settings}
+
+	if doc.Meta.Elements != nil {
+		info.Length = doc.Meta.Elements.Len
+	} else {
+		samples, _ := model.ParseTimeSeriesSamples(doc.Value)
+		info.Length = int64(len(samples))
+	}
+	for _, reverse := range []bool{false, true} {
+		err := db.timeSeriesSamples(opts, doc, 0, math.MaxInt64, reverse, func(sample model.TimeSeriesSample) bool {
+			ts := sample.Timestamp
+			if reverse {
+				info.LastTimestamp = &ts
+			} else {
+				info.FirstTimestamp = &ts
+			}
+			return false
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return info, nil
+}
+
+// readTimeSeries loads a time series document, which may be stored inline or as elements.
+func (db *DB) readTimeSeries(opts TimeSeriesReadOptions) (*model.Document, error) {
+	handle, ok := db.Family(opts.ColumnFamily)
+	if !ok {
+		return nil, ErrInvalidColumnFamily
+	}
+	if err := model.ValidateDocumentKey(opts.Key); err != nil {
+		return nil, err
+	}
+
+	val, err := db.TransactionDB.GetCF(db.streamReadOptions(opts.ReadOptions), handle, []byte(opts.Key))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+
+	if !val.Exists() || val.Size() == 0 {
+		return nil, ErrKeyNotFound
+	}
+
+	var doc model.Document
+	if err := decodeDocument(val.Data(), &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	if model.IsExpired(doc.Meta) {
+		return nil, ErrKeyNotFound
+	}
+	if doc.Meta.Type != model.DocTypeTimeSeries {
+		return nil, ErrInvalidTimeSeriesType
+	}
+	return &doc, nil
+}
+
+// timeSeriesSamples calls fn with the samples of a time series document whose timestamps are
+// between from and to, inclusive, in time order or, with reverse, newest first. It stops
+// early when fn returns false.
+func (db *DB) timeSeriesSamples(opts TimeSeriesReadOptions, doc *model.Document, from, to int64, reverse bool, fn func(model.TimeSeriesSample) bool) error {
+	header := doc.Meta.Elements
+	if header == nil {
+		inline, err := model.ParseTimeSeriesSamples(doc.Value)
+		if err != nil {
+			return ErrInvalidTimeSeriesType
+		}
+		scanInlineTimeSeries(inline, from, to, reverse, fn)
+		return nil
+	}
+
+	handle, ok := db.Family(CFSystemElements)
+	if !ok {
+		return fmt.Errorf("column family %q not available", CFSystemElements)
+	}
+	iter := db.TransactionDB.NewIteratorCF(db.streamReadOptions(opts.ReadOptions), handle)
+	defer iter.Close()
+	return scanTimeSeriesElements(iter, opts.ColumnFamily, doc.Key, header, from, to, reverse, fn)
+}
+
+// timeSeriesSettings returns the settings of a time series document, which are empty while it
+// is stored inline.
+func (db *DB) timeSeriesSettings(opts TimeSeriesReadOptions, doc *model.Document) (model.TimeSeriesSettings, error) {
+	var settings model.TimeSeriesSettings
+	header := doc.Meta.Elements
+	if header == nil {
+		return settings, nil
+	}
+	handle, ok := db.Family(CFSystemElements)
+	if !ok {
+		return settings, fmt.Errorf("column family %q not available", CFSystemElements)
+	}
+	val, err := db.TransactionDB.GetCF(db.streamReadOptions(opts.ReadOptions), handle, timeSeriesPrefix(opts.ColumnFamily, opts.Key, header.Gen, timeSeriesSettingsKind))
+	if err != nil {
+		return settings, err
+	}
+	defer val.Free()
+	if !val.Exists() {
+		return settings, nil
+	}
+	if err := decodeRecord(val.Data(), &settings); err != nil {
+		return settings, fmt.Errorf("failed to decode time series settings: %w", err)
+	}
+	return settings, nil
+}
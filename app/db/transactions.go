@@ -0,0 +1,314 @@
+package db
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"mithrildb/config"
+	"mithrildb/events"
+
+	"github.com/google/uuid"
+	"github.com/linxGnu/grocksdb"
+)
+
+// TransactionManagerConfig holds the limits applied to interactive transactions.
+type TransactionManagerConfig struct {
+	MaxOpen     int           // Maximum number of concurrently open transactions
+	IdleTimeout time.Duration // Transactions idle for longer are rolled back
+	LockTimeout time.Duration // How long a transaction waits for a locked key
+}
+
+// BuildTransactionManagerConfig parses the [Transactions] section of the application config.
+func BuildTransactionManagerConfig(raw config.TransactionsConfig) (TransactionManagerConfig, error) {
+	idle, err := time.ParseDuration(raw.IdleTimeout)
+	if err != nil {
+		return TransactionManagerConfig{}, fmt.Errorf("invalid transactions.IdleTimeout: %w", err)
+	}
+	lock, err := time.ParseDuration(raw.LockTimeout)
+	if err != nil {
+		return TransactionManagerConfig{}, fmt.Errorf("invalid transactions.LockTimeout: %w", err)
+	}
+
+	return TransactionManagerConfig{
+		MaxOpen:     raw.MaxOpen,
+		IdleTimeout: idle,
+		LockTimeout: lock,
+	}, nil
+}
+
+// TransactionInfo describes an open interactive transaction.
+type TransactionInfo struct {
+	ID        string    `json:"txn"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"` // When the transaction is rolled back unless used again
+}
+
+// TransactionStats reports interactive transaction activity.
+type TransactionStats struct {
+	Open       int    `json:"open"`
+	MaxOpen    int    `json:"max_open"`
+	Committed  uint64 `json:"committed"`
+	RolledBack uint64 `json:"rolled_back"`
+	Expired    uint64 `json:"expired"`
+}
+
+// TransactionManager keeps interactive transactions open across requests.
+//
+// Each transaction takes a snapshot when it begins. Documents read or modified inside it are
+// locked until commit or rollback, and locking a document that changed after the snapshot fails
+// with ErrTransactionConflict.
+type TransactionManager struct {
+	db       *DB
+	cfg      TransactionManagerConfig
+	sessions map[string]*txnSession
+	stats    TransactionStats
+	stop     chan struct{}
+	mu       sync.Mutex
+}
+
+// txnSession is an open interactive transaction. Its mutex serializes requests using it.
+type txnSession struct {
+	id        string
+	tc        *txnContext
+	createdAt time.Time
+	lastUsed  time.Time
+	closed    bool
+	mu        sync.Mutex
+}
+
+// NewTransactionManager creates a manager for interactive transactions on db.
+func NewTransactionManager(db *DB, cfg TransactionManagerConfig) *TransactionManager {
+	return &TransactionManager{
+		db:       db,
+		cfg:      cfg,
+		sessions: make(map[string]*txnSession),
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start launches the background reaper that rolls back idle transactions.
+func (m *TransactionManager) Start() {
+	interval := m.cfg.IdleTimeout / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.expireIdle(time.Now())
+			case <-m.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the reaper and rolls back every open transaction.
+func (m *TransactionManager) Close() {
+	close(m.stop)
+
+	m.mu.Lock()
+	sessions := m.sessions
+	m.sessions = make(map[string]*txnSession)
+	m.mu.Unlock()
+
+	for _, s := range sessions {
+		s.mu.Lock()
+		s.end(false)
+		s.mu.Unlock()
+	}
+}
+
+// Begin opens a new interactive transaction and returns its description.
+func (m *TransactionManager) Begin(writeOpts *grocksdb.WriteOptions) (*TransactionInfo, error) {
+	if writeOpts == nil {
+		writeOpts = m.db.DefaultWriteOptions
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cfg.MaxOpen > 0 && len(m.sessions) >= m.cfg.MaxOpen {
+		return nil, ErrTooManyTransactions
+	}
+
+	tc := m.db.beginTxn(writeOpts, m.cfg.LockTimeout.Milliseconds())
+	tc.snapshot = m.db.TransactionDB.NewSnapshot()
+	tc.snapshotOpts = grocksdb.NewDefaultReadOptions()
+	tc.snapshotOpts.SetFillCache(false)
+	tc.snapshotOpts.SetSnapshot(tc.snapshot)
+	tc.validated = make(map[lockedKey]bool)
+
+	now := time.Now()
+	s := &txnSession{
+		id:        uuid.NewString(),
+		tc:        tc,
+		createdAt: now,
+		lastUsed:  now,
+	}
+	m.sessions[s.id] = s
+
+	return m.info(s), nil
+}
+
+// Commit publishes the transaction's change events and commits it.
+func (m *TransactionManager) Commit(id string) error {
+	s, err := m.take(id)
+	if err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	err = s.end(true)
+	m.mu.Lock()
+	if err == nil {
+		m.stats.Committed++
+	} else {
+		m.stats.RolledBack++
+	}
+	m.mu.Unlock()
+	return err
+}
+
+// Rollback discards every change made in the transaction.
+func (m *TransactionManager) Rollback(id string) error {
+	s, err := m.take(id)
+	if err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	s.end(false)
+	m.mu.Lock()
+	m.stats.RolledBack++
+	m.mu.Unlock()
+	return nil
+}
+
+// Stats returns a snapshot of interactive transaction counters.
+func (m *TransactionManager) Stats() TransactionStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := m.stats
+	stats.Open = len(m.sessions)
+	stats.MaxOpen = m.cfg.MaxOpen
+	return stats
+}
+
+// run executes fn inside an open transaction. When fn fails, only its own changes are undone
+// and the transaction stays open.
+func (m *TransactionManager) run(id string, fn func(tc *txnContext) error) error {
+	s, err := m.acquire(id)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		s.lastUsed = time.Now()
+		s.mu.Unlock()
+	}()
+
+	sp := s.tc.setSavePoint()
+	if err := fn(s.tc); err != nil {
+		if rbErr := s.tc.rollbackToSavePoint(sp); rbErr != nil {
+			return rbErr
+		}
+		return err
+	}
+	return nil
+}
+
+// acquire looks up an open transaction and locks it for the caller.
+func (m *TransactionManager) acquire(id string) (*txnSession, error) {
+	m.mu.Lock()
+	s, ok := m.sessions[id]
+	m.mu.Unlock()
+	if !ok {
+		return nil, ErrTransactionNotFound
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, ErrTransactionNotFound
+	}
+	return s, nil
+}
+
+// take acquires an open transaction and removes it from the manager.
+func (m *TransactionManager) take(id string) (*txnSession, error) {
+	s, err := m.acquire(id)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	delete(m.sessions, id)
+	m.mu.Unlock()
+	return s, nil
+}
+
+// expireIdle rolls back transactions that have not been used within the idle timeout.
+// Transactions busy serving a request are skipped.
+func (m *TransactionManager) expireIdle(now time.Time) {
+	m.mu.Lock()
+	var expired []*txnSession
+	for id, s := range m.sessions {
+		if !s.mu.TryLock() {
+			continue
+		}
+		if now.Sub(s.lastUsed) < m.cfg.IdleTimeout {
+			s.mu.Unlock()
+			continue
+		}
+		delete(m.sessions, id)
+		expired = append(expired, s)
+	}
+	m.stats.Expired += uint64(len(expired))
+	m.mu.Unlock()
+
+	for _, s := range expired {
+		s.end(false)
+		s.mu.Unlock()
+	}
+}
+
+// info describes a session. The caller must hold the session or manager lock.
+func (m *TransactionManager) info(s *txnSession) *TransactionInfo {
+	return &TransactionInfo{
+		ID:        s.id,
+		CreatedAt: s.createdAt,
+		ExpiresAt: s.lastUsed.Add(m.cfg.IdleTimeout),
+	}
+}
+
+// end commits or rolls back the session's transaction and releases it. The caller must hold
+// the session lock.
+func (s *txnSession) end(commit bool) error {
+	s.closed = true
+	tc := s.tc
+	defer tc.destroy()
+
+	if !commit {
+		tc.txn.Rollback()
+		return nil
+	}
+
+	for _, event := range tc.pending {
+		if err := events.PublishChangeEvent(event); err != nil {
+			tc.txn.Rollback()
+			return fmt.Errorf("failed to enqueue change event: %w", err)
+		}
+	}
+	if err := tc.txn.Commit(); err != nil {
+		tc.txn.Rollback()
+		return fmt.Errorf("failed to commit transaction: %w", conflictError(err))
+	}
+	tc.db.wakeListWaiters(tc.pushed)
+	return nil
+}
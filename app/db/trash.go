@@ -0,0 +1,234 @@
+package db
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"mithrildb/events"
+	"mithrildb/model"
+
+	"github.com/linxGnu/grocksdb"
+)
+
+// CFTrashPrefix prefixes the column families holding soft-deleted documents, one per user
+// column family. Trashed documents are keyed by their original key.
+const CFTrashPrefix = "system.trash."
+
+// maxSystemFamilyName is the longest name a system column family can have.
+const maxSystemFamilyName = 64
+
+// TrashFamily returns the name of the trash column family of cf. Names that would exceed the
+// system column family limit keep a prefix of cf followed by a hash of the full name, so every
+// user column family can have a trash.
+func TrashFamily(cf string) string {
+	name := CFTrashPrefix + cf
+	if len(name) <= maxSystemFamilyName {
+		return name
+	}
+	sum := sha256.Sum256([]byte(cf))
+	suffix := "-" + hex.EncodeToString(sum[:8])
+	return name[:maxSystemFamilyName-len(suffix)] + suffix
+}
+
+// moveToTrash keeps a copy of a document being deleted when its column family has a trash.
+// When a retention is set, the expiration job purges the copy once it has passed.
+func (tc *txnContext) moveToTrash(cf string, doc *model.Document, actor string) error {
+	settings := tc.db.familySettings(cf).Trash
+	if !settings.Enabled {
+		return nil
+	}
+	handle, err := tc.db.EnsureSystemColumnFamily(TrashFamily(cf))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	entry := model.TrashedDocument{
+		Document:  *doc,
+		DeletedAt: now.UTC(),
+		DeletedBy: actor,
+	}
+	// The trash keeps lists and sets inline; their element keys are deleted with the document.
+	if err := tc.loadElements(cf, &entry.Document); err != nil {
+		return err
+	}
+	if retention, err := time.ParseDuration(settings.Retention); err == nil {
+		entry.PurgeAt = now.Add(retention).Unix()
+	}
+
+	data, err := encodeRecord(&entry)
+	if err != nil {
+		return fmt.Errorf("failed to serialize trashed document: %w", err)
+	}
+	if err := tc.txn.PutCF(handle, []byte(doc.Key), data); err != nil {
+		return fmt.Errorf("failed to write trash: %w", conflictError(err))
+	}
+	if entry.PurgeAt > 0 {
+		return tc.scheduleExpiration(TrashFamily(cf), doc.Key, entry.PurgeAt)
+	}
+	return nil
+}
+
+// ListTrash returns the soft-deleted documents of a column family, ordered by key.
+func (db *DB) ListTrash(opts TrashListOptions) ([]model.TrashedDocument, error) {
+	if _, ok := db.Family(opts.ColumnFamily); !ok {
+		return nil, ErrInvalidColumnFamily
+	}
+	entries := []model.TrashedDocument{}
+	handle, ok := db.Family(TrashFamily(opts.ColumnFamily))
+	if !ok {
+		return entries, nil
+	}
+
+	if opts.ReadOptions == nil {
+		opts.ReadOptions = db.DefaultReadOptions
+	}
+	iter := db.TransactionDB.NewIteratorCF(opts.ReadOptions, handle)
+	defer iter.Close()
+
+	start := opts.Prefix
+	if after := opts.StartAfter + "\x00"; opts.StartAfter != "" && after > start {
+		start = after
+	}
+	iter.Seek([]byte(start))
+
+	for ; iter.ValidForPrefix([]byte(opts.Prefix)); iter.Next() {
+		if opts.Limit > 0 && len(entries) >= opts.Limit {
+			break
+		}
+		v := iter.Value()
+		var entry model.TrashedDocument
+		err := decodeTrashedDocument(v.Data(), &entry)
+		v.Free()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode trashed document: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// UndeleteDocument restores a soft-deleted document from the trash with a fresh revision.
+// It fails with ErrKeyAlreadyExists when a live document has taken the key since.
+func (db *DB) UndeleteDocument(opts DocumentUndeleteOptions) (*model.Document, error) {
+	var doc *model.Document
+	err := db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		var err error
+		doc, err = tc.undeleteDocument(opts)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// undeleteDocument restores a soft-deleted document inside a transaction. Without an explicit
+// expiration it keeps the one it had when deleted, unless that time has passed since.
+func (tc *txnContext) undeleteDocument(opts DocumentUndeleteOptions) (*model.Document, error) {
+	handle, err := tc.family(opts.ColumnFamily, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Expiration != nil {
+		if err := model.ValidateExpiration(*opts.Expiration); err != nil {
+			return nil, err
+		}
+	}
+	trash, ok := tc.db.Family(TrashFamily(opts.ColumnFamily))
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+
+	entry, err := tc.getTrashedForUpdate(trash, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, ErrKeyNotFound
+	}
+
+	existing, err := tc.getForWrite(handle, opts.ColumnFamily, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrKeyAlreadyExists
+	}
+
+	doc := &entry.Document
+	// The deletion used the sequence number after the trashed revision.
+	prevMeta := doc.Meta
+	prevMeta.Seq++
+	doc.Meta.UpdatedAt = time.Now()
+	expiration := opts.Expiration
+	switch {
+	case expiration != nil:
+		doc.Meta.Expiration = *expiration
+	case model.IsExpired(doc.Meta):
+		// An expiration that passed while in the trash would expire the document on restore.
+		doc.Meta.Expiration = 0
+	case doc.Meta.Expiration > 0:
+		// The delete dropped the document from the TTL index; a kept expiration is indexed again.
+		kept := doc.Meta.Expiration
+		expiration = &kept
+	}
+
+	if err := tc.txn.DeleteCF(trash, []byte(opts.Key)); err != nil {
+		return nil, fmt.Errorf("failed to remove document from trash: %w", conflictError(err))
+	}
+	if err := tc.writeDocument(handle, opts.ColumnFamily, doc, events.ChangeEventOptions{
+		Operation:          events.OpUndelete,
+		PreviousMeta:       &prevMeta,
+		ExplicitExpiration: expiration,
+	}); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// getTrashedForUpdate locks and returns a trashed document, or nil when there is none.
+func (tc *txnContext) getTrashedForUpdate(handle *grocksdb.ColumnFamilyHandle, key string) (*model.TrashedDocument, error) {
+	val, err := tc.txn.GetForUpdateWithCF(tc.readOpts, handle, []byte(key))
+	if err != nil {
+		return nil, conflictError(err)
+	}
+	defer val.Free()
+
+	if !val.Exists() || val.Size() == 0 {
+		return nil, nil
+	}
+	var entry model.TrashedDocument
+	if err := decodeTrashedDocument(val.Data(), &entry); err != nil {
+		return nil, fmt.Errorf("failed to decode trashed document: %w", err)
+	}
+	return &entry, nil
+}
+
+// purgeTrashed removes a trashed document if it is still due to be purged at the given time.
+// It reports false when the document was restored or deleted again since.
+func (db *DB) purgeTrashed(trashCF, key string, purgeAt int64) (bool, error) {
+	handle, ok := db.Family(trashCF)
+	if !ok {
+		return false, nil
+	}
+
+	purged := false
+	err := db.runInTransaction("", db.DefaultWriteOptions, func(tc *txnContext) error {
+		entry, err := tc.getTrashedForUpdate(handle, key)
+		if err != nil || entry == nil || entry.PurgeAt != purgeAt {
+			return err
+		}
+		if err := tc.txn.DeleteCF(handle, []byte(key)); err != nil {
+			return fmt.Errorf("failed to purge trashed document: %w", conflictError(err))
+		}
+		purged = true
+		return nil
+	})
+	return purged, err
+}
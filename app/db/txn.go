@@ -0,0 +1,350 @@
+package db
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"mithrildb/events"
+	"mithrildb/model"
+
+	"github.com/linxGnu/grocksdb"
+)
+
+// txnLockTimeoutMs bounds how long a transaction waits for a key locked by another transaction.
+const txnLockTimeoutMs = 1000
+
+// txnContext bundles a running transaction with the options used to read inside it.
+//
+// Document operations are written against txnContext so the same logic serves single
+// operations, batches and interactive transactions.
+type txnContext struct {
+	db       *DB
+	txn      *grocksdb.Transaction
+	readOpts *grocksdb.ReadOptions
+
+	// Interactive transactions only: the snapshot taken at begin, the keys already checked
+	// against it and the change events held back until commit.
+	snapshot     *grocksdb.Snapshot
+	snapshotOpts *grocksdb.ReadOptions
+	validated    map[lockedKey]bool
+	locked       []lockedKey
+	pending      []events.ChangeEventOptions
+
+	// pushed lists the lists left with elements, to wake blocked pops after the commit.
+	pushed []listPush
+
+	// savePoints counts the savepoints set on txn, which cannot be released individually.
+	savePoints int
+}
+
+// lockedKey identifies a document locked by an interactive transaction.
+type lockedKey struct {
+	handle *grocksdb.ColumnFamilyHandle
+	key    string
+}
+
+// runInTransaction begins a pessimistic transaction, runs fn and commits it.
+// Any error returned by fn rolls the transaction back.
+//
+// When txnID names an interactive transaction, fn runs inside it instead and nothing is
+// committed; a failing fn only undoes its own changes.
+func (db *DB) runInTransaction(txnID string, writeOpts *grocksdb.WriteOptions, fn func(tc *txnContext) error) error {
+	if txnID != "" {
+		if db.Transactions == nil {
+			return ErrTransactionNotFound
+		}
+		return db.Transactions.run(txnID, fn)
+	}
+
+	if writeOpts == nil {
+		writeOpts = db.DefaultWriteOptions
+	}
+
+	tc := db.beginTxn(writeOpts, txnLockTimeoutMs)
+	defer tc.destroy()
+
+	if err := fn(tc); err != nil {
+		tc.txn.Rollback()
+		return err
+	}
+
+	if err := tc.txn.Commit(); err != nil {
+		tc.txn.Rollback()
+		return fmt.Errorf("failed to commit transaction: %w", conflictError(err))
+	}
+	db.wakeListWaiters(tc.pushed)
+	return nil
+}
+
+// beginTxn starts a transaction. Keys are locked as they are read for update, so reads see the
+// latest committed data and concurrent writers of the same key wait instead of conflicting.
+func (db *DB) beginTxn(writeOpts *grocksdb.WriteOptions, lockTimeoutMs int64) *txnContext {
+	txnOpts := grocksdb.NewDefaultTransactionOptions()
+	txnOpts.SetLockTimeout(lockTimeoutMs)
+	txnOpts.SetDeadlockDetect(true)
+	defer txnOpts.Destroy()
+
+	readOpts := grocksdb.NewDefaultReadOptions()
+	readOpts.SetFillCache(false)
+
+	return &txnContext{
+		db:       db,
+		txn:      db.TransactionDB.TransactionBegin(writeOpts, txnOpts, nil),
+		readOpts: readOpts,
+	}
+}
+
+// destroy releases the transaction resources.
+func (tc *txnContext) destroy() {
+	if tc.snapshot != nil {
+		tc.snapshotOpts.Destroy()
+		tc.db.TransactionDB.ReleaseSnapshot(tc.snapshot)
+	}
+	tc.readOpts.Destroy()
+	tc.txn.Destroy()
+}
+
+// family resolves a column family handle and validates the document key.
+func (tc *txnContext) family(cf, key string) (*grocksdb.ColumnFamilyHandle, error) {
+	handle, ok := tc.db.Family(cf)
+	if !ok {
+		return nil, ErrInvalidColumnFamily
+	}
+	if err := model.ValidateDocumentKey(key); err != nil {
+		return nil, err
+	}
+	return handle, nil
+}
+
+// getForUpdate locks a key and returns its live document, or nil when the key does not exist
+// or the document has expired.
+func (tc *txnContext) getForUpdate(handle *grocksdb.ColumnFamilyHandle, key string) (*model.Document, error) {
+	doc, err := tc.getStoredForUpdate(handle, key)
+	if err != nil || doc == nil {
+		return nil, err
+	}
+	if model.IsExpired(doc.Meta) {
+		return nil, nil
+	}
+	return doc, nil
+}
+
+// getForWrite locks a key for a write that may replace its document and returns the live
+// document like getForUpdate. The elements of an expired document that was not purged yet are
+// dropped, since no header will point at them once the write takes its place.
+func (tc *txnContext) getForWrite(handle *grocksdb.ColumnFamilyHandle, cf, key string) (*model.Document, error) {
+	doc, err := tc.getStoredForUpdate(handle, key)
+	if err != nil || doc == nil {
+		return nil, err
+	}
+	if !model.IsExpired(doc.Meta) {
+		return doc, nil
+	}
+	if doc.Meta.Elements != nil {
+		if err := tc.clearElements(cf, key, doc.Meta.Elements); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// getStoredForUpdate locks a key and returns the document as stored, including an expired one.
+func (tc *txnContext) getStoredForUpdate(handle *grocksdb.ColumnFamilyHandle, key string) (*model.Document, error) {
+	val, err := tc.txn.GetForUpdateWithCF(tc.readOpts, handle, []byte(key))
+	if err != nil {
+		return nil, conflictError(err)
+	}
+	defer val.Free()
+
+	if err := tc.validateSnapshot(handle, key); err != nil {
+		return nil, err
+	}
+
+	if !val.Exists() || val.Size() == 0 {
+		return nil, nil
+	}
+
+	var doc model.Document
+	if err := decodeDocument(val.Data(), &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	return &doc, nil
+}
+
+// writeDocument stores a document and publishes its change event in the transaction.
+// Callers fill in Operation, PreviousMeta, ExplicitExpiration and any extra event fields.
+//
+// The document gets its next revision here: the sequence number following PreviousMeta (or the
+// document's own metadata when there is none) and a fresh HLC timestamp. Column families with
+// history enabled also keep a copy of the new revision.
+func (tc *txnContext) writeDocument(handle *grocksdb.ColumnFamilyHandle, cf string, doc *model.Document, event events.ChangeEventOptions) error {
+	prev := doc.Meta
+	if event.PreviousMeta != nil {
+		prev = *event.PreviousMeta
+	}
+	prev, err := tc.previousRevision(cf, doc.Key, prev)
+	if err != nil {
+		return err
+	}
+	doc.Meta.Seq, doc.Meta.HLC = tc.nextRevision(prev)
+	doc.Meta.Rev = model.FormatRevision(doc.Meta.Seq, doc.Meta.HLC)
+
+	if err := tc.dropReplacedElements(cf, doc, event.PreviousMeta); err != nil {
+		return err
+	}
+
+	data, err := tc.db.encodeDocument(doc)
+	if err != nil {
+		return fmt.Errorf("failed to serialize document: %w", err)
+	}
+
+	if err := tc.txn.PutCF(handle, []byte(doc.Key), data); err != nil {
+		return fmt.Errorf("failed to write document: %w", conflictError(err))
+	}
+
+	// Documents stored as elements are recorded with their metadata and element header only:
+	// reading every element back would make each push or add cost as much as the whole value.
+	if err := tc.recordHistory(cf, doc.Key, doc.Meta.Seq, doc.Meta.HLC, data); err != nil {
+		return err
+	}
+
+	// Any write that leaves elements in a list, including the one creating it, wakes the
+	// clients blocked on it.
+	if doc.Meta.Type == model.DocTypeList {
+		if n := listLength(doc); n > 0 {
+			tc.pushed = append(tc.pushed, listPush{id: listWaitKey(cf, doc.Key), count: n})
+		}
+	}
+
+	event.CFName = cf
+	event.Key = doc.Key
+	event.Document = doc
+	if err := tc.publish(event); err != nil {
+		return fmt.Errorf("failed to enqueue change event: %w", err)
+	}
+	return nil
+}
+
+// dropReplacedElements deletes the element keys a write leaves unused: those of a document
+// given an inline value again, or those of the previous document when the new one does not
+// take them over.
+func (tc *txnContext) dropReplacedElements(cf string, doc *model.Document, prev *model.Metadata) error {
+	var stale *model.ElementsHeader
+	switch {
+	case doc.Meta.Elements != nil && doc.Value != nil:
+		stale = doc.Meta.Elements
+		doc.Meta.Elements = nil
+	case doc.Meta.Elements == nil && prev != nil && prev.Elements != nil:
+		stale = prev.Elements
+	}
+	if stale == nil {
+		return nil
+	}
+	return tc.clearElements(cf, doc.Key, stale)
+}
+
+// nextRevision returns the sequence number and HLC timestamp of the write following prev.
+// Legacy documents without a sequence number start at 1.
+func (tc *txnContext) nextRevision(prev model.Metadata) (uint64, model.HLC) {
+	return prev.Seq + 1, tc.db.clock.Next(prev.HLC)
+}
+
+// publish enqueues a change event in the transaction. Interactive transactions hold events
+// back until commit, so the shared event queue is only locked for the duration of the commit.
+func (tc *txnContext) publish(event events.ChangeEventOptions) error {
+	event.Txn = tc.txn
+	if event.Document != nil {
+		event.Seq, event.HLC = event.Document.Meta.Seq, event.Document.Meta.HLC
+	} else if event.HLC == 0 {
+		event.HLC = tc.db.clock.Now()
+	}
+	if tc.snapshot != nil {
+		tc.pending = append(tc.pending, event)
+		return nil
+	}
+	return events.PublishChangeEvent(event)
+}
+
+// validateSnapshot fails with ErrTransactionConflict when a key locked by an interactive
+// transaction was committed by someone else after the transaction's snapshot.
+// Each key is checked once, when it is first locked.
+func (tc *txnContext) validateSnapshot(handle *grocksdb.ColumnFamilyHandle, key string) error {
+	if tc.snapshot == nil {
+		return nil
+	}
+	lk := lockedKey{handle: handle, key: key}
+	if tc.validated[lk] {
+		return nil
+	}
+
+	before, err := tc.db.TransactionDB.GetCF(tc.snapshotOpts, handle, []byte(key))
+	if err != nil {
+		return err
+	}
+	defer before.Free()
+
+	latest, err := tc.db.TransactionDB.GetCF(tc.readOpts, handle, []byte(key))
+	if err != nil {
+		return err
+	}
+	defer latest.Free()
+
+	if before.Exists() != latest.Exists() || !bytes.Equal(before.Data(), latest.Data()) {
+		return fmt.Errorf("%w: key %q changed after the transaction began", ErrTransactionConflict, key)
+	}
+
+	tc.validated[lk] = true
+	tc.locked = append(tc.locked, lk)
+	return nil
+}
+
+// txnSavePoint records how much interactive state existed when a savepoint was set.
+type txnSavePoint struct {
+	depth   int
+	pending int
+	locked  int
+	pushed  int
+}
+
+// setSavePoint marks the current state of the transaction so a failing step can be undone
+// with rollbackToSavePoint.
+func (tc *txnContext) setSavePoint() txnSavePoint {
+	tc.txn.SetSavePoint()
+	tc.savePoints++
+	return txnSavePoint{depth: tc.savePoints, pending: len(tc.pending), locked: len(tc.locked), pushed: len(tc.pushed)}
+}
+
+// rollbackToSavePoint undoes every change made since sp, including held-back change events.
+// Savepoints set after sp are unwound as well.
+func (tc *txnContext) rollbackToSavePoint(sp txnSavePoint) error {
+	for tc.savePoints >= sp.depth {
+		if err := tc.txn.RollbackToSavePoint(); err != nil {
+			return fmt.Errorf("failed to roll back to savepoint: %w", err)
+		}
+		tc.savePoints--
+	}
+	// Keys locked after the savepoint were released, so they must be validated again.
+	for _, lk := range tc.locked[sp.locked:] {
+		delete(tc.validated, lk)
+	}
+	tc.locked = tc.locked[:sp.locked]
+	tc.pending = tc.pending[:sp.pending]
+	tc.pushed = tc.pushed[:sp.pushed]
+	return nil
+}
+
+// conflictError marks RocksDB lock timeouts and snapshot validation failures as
+// ErrTransactionConflict so callers can retry them.
+func conflictError(err error) error {
+	if err == nil || errors.Is(err, ErrTransactionConflict) {
+		return err
+	}
+	msg := err.Error()
+	if strings.HasPrefix(msg, "Resource busy") || strings.HasPrefix(msg, "Operation timed out") ||
+		strings.HasPrefix(msg, "Operation aborted") {
+		return fmt.Errorf("%w: %v", ErrTransactionConflict, err)
+	}
+	return err
+}
@@ -0,0 +1,321 @@
+package db
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	"mithrildb/events"
+	"mithrildb/model"
+
+	"github.com/linxGnu/grocksdb"
+)
+
+// Sorted set documents keep their members in CFSystemElements, under the prefix of the
+// document's element generation followed by a kind byte:
+//
+//	'm' <member>           score of the member, so a score lookup is a single read
+//	's' <score> <member>   score index, with the 8-byte encoded score so members sort by
+//	                       score and ties by member
+//
+// Both keys of a member are written and deleted together, so ranges by score or rank seek
+// into the index instead of reading the whole set.
+const (
+	zsetMemberKind = 'm'
+	zsetScoreKind  = 's'
+)
+
+// zsetPrefix returns the key prefix of one kind of sorted set record.
+func zsetPrefix(cf, key string, gen uint64, kind byte) []byte {
+	return append(elementsPrefix(cf, key, gen), kind)
+}
+
+// zsetMemberKey returns the key of the score record of a member.
+func zsetMemberKey(cf, key string, gen uint64, member string) []byte {
+	return append(zsetPrefix(cf, key, gen, zsetMemberKind), member...)
+}
+
+// zsetScoreKey returns the index key of a member with an encoded score.
+func zsetScoreKey(cf, key string, gen uint64, score uint64, member string) []byte {
+	var s [8]byte
+	binary.BigEndian.PutUint64(s[:], score)
+	k := append(zsetPrefix(cf, key, gen, zsetScoreKind), s[:]...)
+	return append(k, member...)
+}
+
+// encodeScore encodes a score so its big-endian bytes sort in numeric order: positive scores
+// get their sign bit set and negative scores have every bit flipped.
+func encodeScore(score float64) uint64 {
+	if score == 0 {
+		score = 0 // -0 sorts and compares as 0
+	}
+	bits := math.Float64bits(score)
+	if bits&(1<<63) != 0 {
+		return ^bits
+	}
+	return bits | 1<<63
+}
+
+// decodeScore reverses encodeScore.
+func decodeScore(encoded uint64) float64 {
+	if encoded&(1<<63) != 0 {
+		return math.Float64frombits(encoded &^ (1 << 63))
+	}
+	return math.Float64frombits(^encoded)
+}
+
+// elementZSet is a sorted set document stored as elements, modified inside a transaction.
+type elementZSet struct {
+	tc     *txnContext
+	handle *grocksdb.ColumnFamilyHandle
+	cf     string
+	key    string
+	header *model.ElementsHeader
+}
+
+// score returns the score of a member, or false when it is not in the set.
+func (z *elementZSet) score(member string) (float64, bool, error) {
+	val, err := z.tc.txn.GetWithCF(z.tc.readOpts, z.handle, zsetMemberKey(z.cf, z.key, z.header.Gen, member))
+	if err != nil {
+		return 0, false, err
+	}
+	defer val.Free()
+	if !val.Exists() {
+		return 0, false, nil
+	}
+	if val.Size() != 8 {
+		return 0, false, fmt.Errorf("%w: invalid score of member %q", ErrInvalidZSetType, member)
+	}
+	return decodeScore(binary.BigEndian.Uint64(val.Data())), true, nil
+}
+
+// put stores a member with its score. previous is the current score of the member, or nil
+// when it is not in the set.
+func (z *elementZSet) put(entry model.ZSetEntry, previous *float64) error {
+	if previous != nil {
+		if err := z.tc.txn.DeleteCF(z.handle, zsetScoreKey(z.cf, z.key, z.header.Gen, encodeScore(*previous), entry.Member)); err != nil {
+			return fmt.Errorf("failed to delete sorted set member: %w", conflictError(err))
+		}
+	}
+	score := encodeScore(entry.Score)
+	var s [8]byte
+	binary.BigEndian.PutUint64(s[:], score)
+	if err := z.tc.txn.PutCF(z.handle, zsetMemberKey(z.cf, z.key, z.header.Gen, entry.Member), s[:]); err != nil {
+		return fmt.Errorf("failed to write sorted set member: %w", conflictError(err))
+	}
+	if err := z.tc.txn.PutCF(z.handle, zsetScoreKey(z.cf, z.key, z.header.Gen, score, entry.Member), nil); err != nil {
+		return fmt.Errorf("failed to write sorted set member: %w", conflictError(err))
+	}
+	if previous == nil {
+		z.header.Len++
+	}
+	return nil
+}
+
+// remove deletes a member and reports whether it was present.
+func (z *elementZSet) remove(member string) (bool, error) {
+	score, exists, err := z.score(member)
+	if err != nil || !exists {
+		return false, err
+	}
+	if err := z.tc.txn.DeleteCF(z.handle, zsetMemberKey(z.cf, z.key, z.header.Gen, member)); err != nil {
+		return false, fmt.Errorf("failed to delete sorted set member: %w", conflictError(err))
+	}
+	if err := z.tc.txn.DeleteCF(z.handle, zsetScoreKey(z.cf, z.key, z.header.Gen, encodeScore(score), member)); err != nil {
+		return false, fmt.Errorf("failed to delete sorted set member: %w", conflictError(err))
+	}
+	z.header.Len--
+	return true, nil
+}
+
+// scan calls fn with the members whose score lies between min and max, in score order or,
+// with reverse, highest first. It stops early when fn returns false.
+func (z *elementZSet) scan(min, max model.ScoreBound, reverse bool, fn func(model.ZSetEntry) bool) error {
+	iter := z.tc.txn.NewIteratorCF(z.tc.readOpts, z.handle)
+	defer iter.Close()
+	return scanZSetElements(iter, z.cf, z.key, z.header, min, max, reverse, fn)
+}
+
+// scanZSetElements calls fn with the members of a sorted set stored as elements whose score
+// lies between min and max, in score order or, with reverse, highest first.
+func scanZSetElements(iter *grocksdb.Iterator, cf, key string, header *model.ElementsHeader, min, max model.ScoreBound, reverse bool, fn func(model.ZSetEntry) bool) error {
+	prefix := zsetPrefix(cf, key, header.Gen, zsetScoreKind)
+	if reverse {
+		// The first key past every member scored max.
+		iter.SeekForPrev(zsetScoreKey(cf, key, header.Gen, encodeScore(max.Value)+1, ""))
+	} else {
+		iter.Seek(zsetScoreKey(cf, key, header.Gen, encodeScore(min.Value), ""))
+	}
+	for ; iter.ValidForPrefix(prefix); stepIterator(iter, reverse) {
+		k := iter.Key()
+		data := k.Data()[len(prefix):]
+		if len(data) < 8 {
+			k.Free()
+			return fmt.Errorf("%w: invalid score index key", ErrInvalidZSetType)
+		}
+		entry := model.ZSetEntry{
+			Member: string(data[8:]),
+			Score:  decodeScore(binary.BigEndian.Uint64(data[:8])),
+		}
+		k.Free()
+
+		if !min.AboveMin(entry.Score) {
+			if reverse {
+				break
+			}
+			continue
+		}
+		if !max.BelowMax(entry.Score) {
+			if reverse {
+				continue
+			}
+			break
+		}
+		if !fn(entry) {
+			return nil
+		}
+	}
+	return iter.Err()
+}
+
+// scanInlineZSet is scanZSetElements for a sorted set still stored inline.
+func scanInlineZSet(z model.ZSet, min, max model.ScoreBound, reverse bool, fn func(model.ZSetEntry) bool) {
+	lo, hi := z.ScoreRange(min, max)
+	for i := lo; i < hi; i++ {
+		entry := z[i]
+		if reverse {
+			entry = z[lo+hi-1-i]
+		}
+		if !fn(entry) {
+			return
+		}
+	}
+}
+
+// fillZSetEntries reads every member of a sorted set stored as elements into its value.
+func fillZSetEntries(iter *grocksdb.Iterator, cf string, doc *model.Document) error {
+	entries := make(model.ZSet, 0, doc.Meta.Elements.Len)
+	err := scanZSetElements(iter, cf, doc.Key, doc.Meta.Elements, model.LowestScore, model.HighestScore, false, func(entry model.ZSetEntry) bool {
+		entries = append(entries, entry)
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read sorted set %q: %w", doc.Key, err)
+	}
+
+	doc.Value = entries.Value()
+	doc.Meta.Elements = nil
+	return nil
+}
+
+// convertZSet moves the inline members of a sorted set document to element keys and gives
+// the document a sorted set header. Documents already stored as elements are left untouched.
+func (tc *txnContext) convertZSet(cf string, doc *model.Document) error {
+	if doc.Meta.Elements != nil {
+		if doc.Meta.Elements.Kind != model.ElementsZSet {
+			return ErrInvalidZSetType
+		}
+		return nil
+	}
+	entries, err := model.ParseZSet(doc.Value)
+	if err != nil {
+		return ErrInvalidZSetType
+	}
+	handle, err := tc.db.EnsureSystemColumnFamily(CFSystemElements)
+	if err != nil {
+		return err
+	}
+
+	header := &model.ElementsHeader{Kind: model.ElementsZSet, Gen: uint64(tc.db.clock.Now())}
+	z := &elementZSet{tc: tc, handle: handle, cf: cf, key: doc.Key, header: header}
+	for _, entry := range entries {
+		if err := z.put(entry, nil); err != nil {
+			return err
+		}
+	}
+
+	doc.Value = nil
+	doc.Meta.Elements = header
+	return nil
+}
+
+// withZSetTransaction applies a transactional update to a sorted set document.
+func (db *DB) withZSetTransaction(
+	opts ZSetOpOptions,
+	modifier func(z *elementZSet) (interface{}, error),
+) (interface{}, error) {
+	var result interface{}
+	err := db.runInTransaction(opts.TxnID, opts.WriteOptions, func(tc *txnContext) error {
+		var err error
+		result, err = tc.modifyZSet(opts, modifier)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// modifyZSet applies an update to a sorted set document inside a transaction, converting it
+// to elements first when it is stored inline.
+func (tc *txnContext) modifyZSet(
+	opts ZSetOpOptions,
+	modifier func(z *elementZSet) (interface{}, error),
+) (interface{}, error) {
+	handle, err := tc.family(opts.ColumnFamily, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := tc.getForWrite(handle, opts.ColumnFamily, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return nil, ErrKeyNotFound
+	}
+	if opts.Cas != "" && doc.Meta.Rev != opts.Cas {
+		return nil, ErrRevisionMismatch
+	}
+	if doc.Meta.Type != model.DocTypeZSet {
+		return nil, ErrInvalidZSetType
+	}
+
+	metaCopy := doc.Meta
+	if err := tc.convertZSet(opts.ColumnFamily, doc); err != nil {
+		return nil, err
+	}
+	elements, err := tc.db.EnsureSystemColumnFamily(CFSystemElements)
+	if err != nil {
+		return nil, err
+	}
+
+	// The header is copied so the previous metadata keeps the old length.
+	header := *doc.Meta.Elements
+	z := &elementZSet{tc: tc, handle: elements, cf: opts.ColumnFamily, key: opts.Key, header: &header}
+	result, err := modifier(z)
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Meta.Elements = &header
+	doc.Meta.UpdatedAt = time.Now()
+
+	if opts.Expiration != nil {
+		if err := model.ValidateExpiration(*opts.Expiration); err != nil {
+			return nil, err
+		}
+		doc.Meta.Expiration = *opts.Expiration
+	}
+
+	if err := tc.writeDocument(handle, opts.ColumnFamily, doc, events.ChangeEventOptions{
+		Operation:          events.OpMutate,
+		PreviousMeta:       &metaCopy,
+		ExplicitExpiration: opts.Expiration,
+	}); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
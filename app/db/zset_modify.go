@@ -0,0 +1,147 @@
+package db
+
+import (
+	"fmt"
+	"math"
+
+	"mithrildb/model"
+)
+
+// ZAddResult reports how a sorted set changed after ZAdd.
+type ZAddResult struct {
+	Added   int `json:"added"`   // Members that were not in the set
+	Updated int `json:"updated"` // Existing members whose score changed
+}
+
+// ZAdd adds members to a sorted set or updates their scores, honouring the NX, XX, GT and LT
+// flags like Redis ZADD.
+func (db *DB) ZAdd(opts ZAddOptions) (*ZAddResult, error) {
+	if opts.NX && (opts.XX || opts.GT || opts.LT) {
+		return nil, fmt.Errorf("%w: nx cannot be combined with xx, gt or lt", ErrInvalidZSetOperation)
+	}
+	if opts.GT && opts.LT {
+		return nil, fmt.Errorf("%w: gt and lt are mutually exclusive", ErrInvalidZSetOperation)
+	}
+	if len(opts.Entries) == 0 {
+		return nil, fmt.Errorf("%w: no entries given", ErrInvalidZSetOperation)
+	}
+	for _, e := range opts.Entries {
+		if _, err := model.ParseScore(e.Score); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := db.withZSetTransaction(opts.ZSetOpOptions, func(z *elementZSet) (interface{}, error) {
+		res := &ZAddResult{}
+		for _, e := range opts.Entries {
+			current, exists, err := z.score(e.Member)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case !exists:
+				if opts.XX {
+					continue
+				}
+				if err := z.put(e, nil); err != nil {
+					return nil, err
+				}
+				res.Added++
+			case opts.NX:
+				continue
+			default:
+				if current == e.Score || (opts.GT && e.Score <= current) || (opts.LT && e.Score >= current) {
+					continue
+				}
+				if err := z.put(e, &current); err != nil {
+					return nil, err
+				}
+				res.Updated++
+			}
+		}
+		return res, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*ZAddResult), nil
+}
+
+// ZIncrBy adds an increment to the score of a member, adding the member when it is missing,
+// and returns the new score.
+func (db *DB) ZIncrBy(opts ZIncrByOptions) (float64, error) {
+	result, err := db.withZSetTransaction(opts.ZSetOpOptions, func(z *elementZSet) (interface{}, error) {
+		current, exists, err := z.score(opts.Member)
+		if err != nil {
+			return nil, err
+		}
+		score := opts.Increment
+		var previous *float64
+		if exists {
+			score += current
+			previous = &current
+		}
+		if math.IsInf(score, 0) || math.IsNaN(score) {
+			return nil, fmt.Errorf("%w: resulting score is not finite", ErrInvalidZSetOperation)
+		}
+		if err := z.put(model.ZSetEntry{Member: opts.Member, Score: score}, previous); err != nil {
+			return nil, err
+		}
+		return score, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(float64), nil
+}
+
+// ZRem removes members from a sorted set and returns how many were present.
+func (db *DB) ZRem(opts ZRemOptions) (int, error) {
+	result, err := db.withZSetTransaction(opts.ZSetOpOptions, func(z *elementZSet) (interface{}, error) {
+		removed := 0
+		for _, member := range opts.Members {
+			ok, err := z.remove(member)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				removed++
+			}
+		}
+		return removed, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
+
+// ZPop removes and returns up to Count members with the lowest scores, or the highest with
+// Max. Popped entries are returned in pop order.
+func (db *DB) ZPop(opts ZPopOptions) ([]model.ZSetEntry, error) {
+	count := opts.Count
+	if count <= 0 {
+		count = 1
+	}
+	result, err := db.withZSetTransaction(opts.ZSetOpOptions, func(z *elementZSet) (interface{}, error) {
+		popped := make([]model.ZSetEntry, 0)
+		err := z.scan(model.LowestScore, model.HighestScore, opts.Max, func(entry model.ZSetEntry) bool {
+			popped = append(popped, entry)
+			return len(popped) < count
+		})
+		if err != nil {
+			return nil, err
+		}
+		// The members are removed once the scan has closed its iterator.
+		for _, entry := range popped {
+			if _, err := z.remove(entry.Member); err != nil {
+				return nil, err
+			}
+		}
+		return popped, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]model.ZSetEntry), nil
+}
@@ -0,0 +1,240 @@
+package db
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"mithrildb/model"
+)
+
+// ZScore returns the score of a member of a sorted set.
+func (db *DB) ZScore(opts ZSetReadOptions, member string) (float64, error) {
+	doc, err := db.readZSet(opts)
+	if err != nil {
+		return 0, err
+	}
+	score, ok, err := db.zsetScore(opts, doc, member)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, ErrMemberNotFound
+	}
+	return score, nil
+}
+
+// ZRank returns the rank of a member, counting from the lowest score or, with reverse, from
+// the highest.
+func (db *DB) ZRank(opts ZSetReadOptions, member string, reverse bool) (int, error) {
+	doc, err := db.readZSet(opts)
+	if err != nil {
+		return 0, err
+	}
+	score, ok, err := db.zsetScore(opts, doc, member)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, ErrMemberNotFound
+	}
+
+	// Only the members scored up to the member's score, or from it with reverse, come first.
+	min, max := model.LowestScore, model.ScoreBound{Value: score}
+	if reverse {
+		min, max = model.ScoreBound{Value: score}, model.HighestScore
+	}
+	rank := 0
+	err = db.zsetEntries(opts, doc, min, max, reverse, func(entry model.ZSetEntry) bool {
+		if entry.Member == member {
+			return false
+		}
+		rank++
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	return rank, nil
+}
+
+// ZRange returns the entries between two ranks, inclusive. Negative ranks count from the end.
+func (db *DB) ZRange(opts ZRangeOptions) ([]model.ZSetEntry, error) {
+	doc, err := db.readZSet(opts.ZSetReadOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	n := zsetLen(doc)
+	start, stop := opts.Start, opts.Stop
+	if start < 0 {
+		start += n
+	}
+	if stop < 0 {
+		stop += n
+	}
+	if start < 0 {
+		start = 0
+	}
+	if stop >= n {
+		stop = n - 1
+	}
+	if start > stop {
+		return []model.ZSetEntry{}, nil
+	}
+
+	entries := make([]model.ZSetEntry, 0, stop-start+1)
+	rank := 0
+	err = db.zsetEntries(opts.ZSetReadOptions, doc, model.LowestScore, model.HighestScore, opts.Reverse, func(entry model.ZSetEntry) bool {
+		if rank >= start {
+			entries = append(entries, entry)
+		}
+		rank++
+		return rank <= stop
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ZRangeByScore returns the entries whose score lies between Min and Max, in score order or,
+// with Reverse, highest first. Offset and Limit page through the matching entries.
+func (db *DB) ZRangeByScore(opts ZRangeByScoreOptions) ([]model.ZSetEntry, error) {
+	if opts.Offset < 0 || opts.Limit < 0 {
+		return nil, fmt.Errorf("%w: offset and limit cannot be negative", ErrInvalidZSetOperation)
+	}
+	doc, err := db.readZSet(opts.ZSetReadOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []model.ZSetEntry{}
+	skipped := 0
+	err = db.zsetEntries(opts.ZSetReadOptions, doc, opts.Min, opts.Max, opts.Reverse, func(entry model.ZSetEntry) bool {
+		if skipped < opts.Offset {
+			skipped++
+			return true
+		}
+		entries = append(entries, entry)
+		return opts.Limit == 0 || len(entries) < opts.Limit
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// readZSet loads a sorted set document, which may be stored inline or as elements.
+func (db *DB) readZSet(opts ZSetReadOptions) (*model.Document, error) {
+	handle, ok := db.Family(opts.ColumnFamily)
+	if !ok {
+		return nil, ErrInvalidColumnFamily
+	}
+	if opts.ReadOptions == nil {
+		opts.ReadOptions = db.DefaultReadOptions
+	}
+	if err := model.ValidateDocumentKey(opts.Key); err != nil {
+		return nil, err
+	}
+
+	val, err := db.TransactionDB.GetCF(opts.ReadOptions, handle, []byte(opts.Key))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+
+	if !val.Exists() || val.Size() == 0 {
+		return nil, ErrKeyNotFound
+	}
+
+	var doc model.Document
+	if err := decodeDocument(val.Data(), &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	if model.IsExpired(doc.Meta) {
+		return nil, ErrKeyNotFound
+	}
+	if doc.Meta.Type != model.DocTypeZSet {
+		return nil, ErrInvalidZSetType
+	}
+	if header := doc.Meta.Elements; header != nil && header.Kind != model.ElementsZSet {
+		return nil, ErrInvalidZSetType
+	}
+	return &doc, nil
+}
+
+// zsetLen returns the number of members of a sorted set document.
+func zsetLen(doc *model.Document) int {
+	if doc.Meta.Elements != nil {
+		return int(doc.Meta.Elements.Len)
+	}
+	z, _ := model.ParseZSet(doc.Value)
+	return len(z)
+}
+
+// zsetScore returns the score of a member of a sorted set document, or false when it is not
+// in the set.
+func (db *DB) zsetScore(opts ZSetReadOptions, doc *model.Document, member string) (float64, bool, error) {
+	header := doc.Meta.Elements
+	if header == nil {
+		z, err := model.ParseZSet(doc.Value)
+		if err != nil {
+			return 0, false, ErrInvalidZSetType
+		}
+		for _, entry := range z {
+			if entry.Member == member {
+				return entry.Score, true, nil
+			}
+		}
+		return 0, false, nil
+	}
+
+	handle, ok := db.Family(CFSystemElements)
+	if !ok {
+		return 0, false, fmt.Errorf("column family %q not available", CFSystemElements)
+	}
+	if opts.ReadOptions == nil {
+		opts.ReadOptions = db.DefaultReadOptions
+	}
+	val, err := db.TransactionDB.GetCF(opts.ReadOptions, handle, zsetMemberKey(opts.ColumnFamily, opts.Key, header.Gen, member))
+	if err != nil {
+		return 0, false, err
+	}
+	defer val.Free()
+	if !val.Exists() {
+		return 0, false, nil
+	}
+	if val.Size() != 8 {
+		return 0, false, fmt.Errorf("%w: invalid score of member %q", ErrInvalidZSetType, member)
+	}
+	return decodeScore(binary.BigEndian.Uint64(val.Data())), true, nil
+}
+
+// zsetEntries calls fn with the members of a sorted set document whose score lies between min
+// and max, in score order or, with reverse, highest first. It stops early when fn returns
+// false.
+//
+// Unless the read options pin a snapshot, members changed while they are read may or may not
+// be included.
+func (db *DB) zsetEntries(opts ZSetReadOptions, doc *model.Document, min, max model.ScoreBound, reverse bool, fn func(model.ZSetEntry) bool) error {
+	header := doc.Meta.Elements
+	if header == nil {
+		z, err := model.ParseZSet(doc.Value)
+		if err != nil {
+			return ErrInvalidZSetType
+		}
+		scanInlineZSet(z, min, max, reverse, fn)
+		return nil
+	}
+
+	handle, ok := db.Family(CFSystemElements)
+	if !ok {
+		return fmt.Errorf("column family %q not available", CFSystemElements)
+	}
+	if opts.ReadOptions == nil {
+		opts.ReadOptions = db.DefaultReadOptions
+	}
+	iter := db.TransactionDB.NewIteratorCF(opts.ReadOptions, handle)
+	defer iter.Close()
+	return scanZSetElements(iter, opts.ColumnFamily, doc.Key, header, min, max, reverse, fn)
+}
@@ -15,6 +15,98 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/batch": {
+            "post": {
+                "description": "Executes an ordered list of heterogeneous operations (put, insert, replace, delete, touch, counter_delta, list_push, list_unshift, list_pop, list_shift, set_add, set_remove) across user column families in a single transaction. Either all operations are applied or none is.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "batch"
+                ],
+                "summary": "Execute an atomic batch",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Write option: sync",
+                        "name": "sync",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable WAL",
+                        "name": "disable_wal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: no slowdown",
+                        "name": "no_slowdown",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Operations to execute",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.batchRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.batchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid operation",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.batchErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.batchErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Key already exists or counter overflow",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.batchErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "CAS mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.batchErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/config": {
             "get": {
                 "description": "Returns the current server configuration used by the database",
@@ -87,9 +179,11 @@ const docTemplate = `{
         },
         "/documents": {
             "get": {
-                "description": "Retrieves a document by key, including its value and metadata.",
+                "description": "Retrieves a document by key, including its value and metadata. When one or more 'path' parameters are given, only those paths of the value are returned (as model.DocumentPaths) with per-path existence flags. The revision is returned as the ETag header; an If-None-Match header listing the current revision yields 304 Not Modified. In column families with history enabled, 'rev' returns a past revision and 'as_of' the version that was current at a point in time. Past revisions of lists, sets, sorted sets, streams and time series are not kept and answer 409; only their current revision can be read.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
                     "documents"
@@ -109,6 +203,16 @@ const docTemplate = `{
                         "name": "cf",
                         "in": "query"
                     },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Paths to return, e.g. 'profile.address.city' or 'items[3]'. Repeat for multiple paths",
+                        "name": "path",
+                        "in": "query"
+                    },
                     {
                         "type": "boolean",
                         "description": "Optional RocksDB fill cache read option",
@@ -120,6 +224,36 @@ const docTemplate = `{
                         "description": "Optional RocksDB read tier (e.g. 'all', 'cache-only')",
                         "name": "read_tier",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return 304 when the document still has one of these revisions (ETags)",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return this past revision of the document",
+                        "name": "rev",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return the version current at this time: Unix timestamp in seconds or RFC 3339",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -129,14 +263,23 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Document"
                         }
                     },
+                    "304": {
+                        "description": "Document not modified"
+                    },
                     "400": {
-                        "description": "Missing or invalid key",
+                        "description": "Missing or invalid key, or history not enabled",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Document not found",
+                        "description": "Document or revision not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Past revision of a document stored as elements",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -150,12 +293,17 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Stores a document in the specified column family. Supports optimistic concurrency via CAS.",
+                "description": "Stores a document in the specified column family. Supports optimistic concurrency via CAS, given as the 'cas' parameter or an If-Match header. \"If-None-Match: *\" only creates the document and \"If-Match: *\" only overwrites an existing one; both answer 412 when the condition fails. The new revision is returned as the ETag header.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor",
+                    "application/octet-stream"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
                     "documents"
@@ -183,7 +331,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Document type (e.g. 'json', 'counter', 'list')",
+                        "description": "Document type (e.g. 'json', 'counter', 'list', 'zset', 'hash', 'blob', 'stream', 'timeseries'). application/octet-stream bodies are stored as blobs",
                         "name": "type",
                         "in": "query"
                     },
@@ -193,6 +341,18 @@ const docTemplate = `{
                         "name": "cas",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "Revision (ETag) the document must have, or '*' to require that it exists",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "'*' to only create the document",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
                     {
                         "description": "Document value (JSON-encoded)",
                         "name": "body",
@@ -202,6 +362,12 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -223,8 +389,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "CAS mismatch",
+                    "412": {
+                        "description": "CAS mismatch or precondition failed",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Body larger than the configured maximum",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -238,7 +410,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Deletes a document by key within a specified column family.",
+                "description": "Deletes a document by key within a specified column family. In column families with a trash the document is moved there, recording the deletion time and 'actor', and can be restored with POST /documents/undelete; 'permanent=true' skips the trash.",
                 "produces": [
                     "application/json"
                 ],
@@ -259,6 +431,36 @@ const docTemplate = `{
                         "description": "Column family (default: 'default')",
                         "name": "cf",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CAS (revision) the document must have to be deleted",
+                        "name": "cas",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Revision (ETag) the document must have, or '*' to require that it exists; alternative to the cas parameter",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who deletes the document, recorded in the trash",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Delete the document without keeping it in the trash",
+                        "name": "permanent",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -277,6 +479,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "412": {
+                        "description": "CAS mismatch, or a CAS or If-Match given for a missing document",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -284,22 +492,30 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/documents/bulk/get": {
-            "post": {
-                "description": "Retrieves multiple documents with metadata by key. Missing keys will be returned with null values.",
+            },
+            "patch": {
+                "description": "Applies an RFC 7396 JSON Merge Patch (application/merge-patch+json) or an RFC 6902 JSON Patch (application/json-patch+json) to an existing JSON document in a single transaction. JSON Patch 'test' operations can be used for conditional updates.",
                 "consumes": [
-                    "application/json"
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
                     "documents"
                 ],
-                "summary": "Bulk document fetch",
+                "summary": "Patch a document",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "Column family (default: 'default')",
@@ -307,88 +523,72 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "description": "List of keys to retrieve",
+                        "type": "string",
+                        "description": "CAS (revision) for concurrency control",
+                        "name": "cas",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Revision (ETag) the document must have; alternative to the cas parameter",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Merge patch object or array of JSON Patch operations",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.multiGetRequest"
+                            "type": "object"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.MultiGetResponse"
+                            "$ref": "#/definitions/model.Document"
                         }
                     },
                     "400": {
-                        "description": "Invalid JSON or missing key list",
+                        "description": "Invalid patch or document type",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Document not found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/documents/bulk/put": {
-            "post": {
-                "description": "Stores multiple documents in a single call. Each document is defined by a key and a value/type pair.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "documents"
-                ],
-                "summary": "Bulk insert documents",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Column family (defaults to 'default')",
-                        "name": "cf",
-                        "in": "query"
                     },
-                    {
-                        "type": "integer",
-                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
-                        "name": "expiration",
-                        "in": "query"
-                    },
-                    {
-                        "description": "Map of key to value/type entry",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
+                    "412": {
+                        "description": "CAS mismatch or failed test operation",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "$ref": "#/definitions/handlers.BulkPutRequestEntry"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "413": {
+                        "description": "Body larger than the configured maximum",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "$ref": "#/definitions/model.Document"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Invalid input or empty payload",
+                    "415": {
+                        "description": "Unsupported patch media type",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -402,19 +602,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/documents/counters/delta": {
-            "post": {
-                "description": "Increments or decrements a counter document by a given integer value.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/documents/blob": {
+            "get": {
+                "description": "Serves the raw content of a blob document with its stored Content-Type. Supports Range requests and conditional requests via ETag.",
                 "produces": [
-                    "application/json"
+                    "application/octet-stream"
                 ],
                 "tags": [
-                    "counters"
+                    "documents"
                 ],
-                "summary": "Modify counter",
+                "summary": "Download blob content",
                 "parameters": [
                     {
                         "type": "string",
@@ -430,30 +627,45 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
-                        "name": "expiration",
+                        "type": "boolean",
+                        "description": "Optional RocksDB fill cache read option",
+                        "name": "fill_cache",
                         "in": "query"
                     },
                     {
-                        "description": "Delta value for increment or decrement",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.incrementRequest"
-                        }
+                        "type": "string",
+                        "description": "Optional RocksDB read tier (e.g. 'all', 'cache-only')",
+                        "name": "read_tier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte range to return (e.g. 'bytes=0-1023')",
+                        "name": "Range",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Blob content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial blob content",
                         "schema": {
-                            "$ref": "#/definitions/handlers.incrementResponse"
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Invalid parameters or JSON body",
+                        "description": "Missing key or document is not a blob",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -464,6 +676,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "416": {
+                        "description": "Requested range not satisfiable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -473,27 +691,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/documents/insert": {
+        "/documents/bulk/delete": {
             "post": {
-                "description": "Insert a new document only if the key does not already exist",
+                "description": "Deletes a list of keys atomically: either every document is deleted or none is. Entries with a 'cas' must exist with that revision. Emits a delete change event per key. In column families with a trash the documents are moved there.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
                     "documents"
                 ],
-                "summary": "Insert a document",
+                "summary": "Bulk delete documents",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Document key",
-                        "name": "key",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "Column family (defaults to 'default')",
@@ -502,42 +717,23 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Document type (json, counter, list, set)",
-                        "name": "type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to store without expiration.",
-                        "name": "expiration",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Write option: sync",
-                        "name": "sync",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Write option: disable WAL",
-                        "name": "disable_wal",
+                        "description": "Who deletes the documents, recorded in the trash",
+                        "name": "actor",
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Write option: no slowdown",
-                        "name": "no_slowdown",
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
                         "in": "query"
                     },
                     {
-                        "description": "Document value body. Must contain 'value' field",
-                        "name": "document",
+                        "description": "Keys to delete",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/handlers.bulkKeysRequest"
                         }
                     }
                 ],
@@ -545,23 +741,29 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Document"
+                            "$ref": "#/definitions/handlers.bulkDeleteResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid input; 'index' identifies the failing key",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/handlers.batchErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "404": {
+                        "description": "Document with a CAS not found",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/handlers.batchErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "CAS mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.batchErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -569,62 +771,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/documents/keys": {
-            "get": {
-                "description": "Returns a list of keys within the specified column family, optionally filtered by prefix and pagination options.",
+        "/documents/bulk/delete-range": {
+            "post": {
+                "description": "Removes every document whose key starts with 'prefix', or lies between 'start' (inclusive) and 'end' (exclusive), using a RocksDB range delete. Documents are not read first, so this is suited to large purges. A single delete_range change event is emitted. The purge is not transactional and should not race with writes to the same keys. It skips the trash and history of the column family: purged documents cannot be restored and no revision records their deletion.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
-                    "keys"
+                    "documents"
                 ],
-                "summary": "List document keys",
+                "summary": "Delete documents by prefix or key range",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Column family (default: 'default')",
+                        "description": "Column family (defaults to 'default')",
                         "name": "cf",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Only return keys with this prefix",
-                        "name": "prefix",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Return keys after this key (for pagination)",
-                        "name": "start_after",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Maximum number of keys to return (default: 100)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Whether to fill RocksDB read cache",
-                        "name": "fill_cache",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "RocksDB read tier (e.g. 'all', 'cache-only')",
-                        "name": "read_tier",
-                        "in": "query"
+                        "description": "Prefix or key range to delete",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.bulkDeleteRangeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Purged key range",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.bulkDeleteRangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid prefix or range",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
@@ -636,16 +827,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/documents/list": {
-            "get": {
-                "description": "Returns a map of documents filtered by optional prefix and paginated using start_after and limit.",
+        "/documents/bulk/get": {
+            "post": {
+                "description": "Retrieves multiple documents with metadata by key. Missing keys will be returned with null values.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
                     "documents"
                 ],
-                "summary": "List documents",
+                "summary": "Bulk document fetch",
                 "parameters": [
                     {
                         "type": "string",
@@ -654,33 +852,18 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Filter documents whose keys begin with this prefix",
-                        "name": "prefix",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Skip documents until this key (exclusive)",
-                        "name": "start_after",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Maximum number of documents to return (default: 100)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Whether to fill RocksDB read cache",
-                        "name": "fill_cache",
-                        "in": "query"
+                        "description": "List of keys to retrieve",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.multiGetRequest"
+                        }
                     },
                     {
                         "type": "string",
-                        "description": "RocksDB read tier (e.g., 'all', 'cache-only')",
-                        "name": "read_tier",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
                         "in": "query"
                     }
                 ],
@@ -688,10 +871,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "$ref": "#/definitions/model.Document"
-                            }
+                            "$ref": "#/definitions/handlers.MultiGetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON or missing key list",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
@@ -703,74 +889,82 @@ const docTemplate = `{
                 }
             }
         },
-        "/documents/lists/pop": {
+        "/documents/bulk/put": {
             "post": {
-                "description": "Removes and returns the last element of a list-type document.",
+                "description": "Stores multiple documents in a single transaction. Each entry carries its own value, type, and optional expiration and CAS. Array bodies are written in order; map bodies in key order. By default the request is atomic and the first failing entry aborts it; with partial=true, failing entries are skipped and reported while the rest are committed. Array bodies and partial requests return the result of every entry in request order; atomic map bodies return a map of key to stored document.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
-                    "lists"
+                    "documents"
                 ],
-                "summary": "Pop element from list",
+                "summary": "Bulk insert documents",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Key of the list document",
-                        "name": "key",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Column family (default: 'default')",
+                        "description": "Column family (defaults to 'default')",
                         "name": "cf",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "description": "Expiration for entries without their own. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to store without expiration.",
                         "name": "expiration",
                         "in": "query"
                     },
                     {
                         "type": "boolean",
-                        "description": "Write option: sync write to disk",
-                        "name": "sync",
+                        "description": "Commit successful entries and report per-key errors instead of failing the whole request",
+                        "name": "partial",
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Write option: disable write-ahead log",
-                        "name": "disable_wal",
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Write option: disable slowdown on write buffer full",
-                        "name": "no_slowdown",
-                        "in": "query"
+                        "description": "Ordered entries, or a map of key to entry",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.BulkPutRequestEntry"
+                            }
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns the popped element",
+                        "description": "Result of every entry; atomic map bodies get a map of key to model.Document instead",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/handlers.bulkPutResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request or missing key",
+                        "description": "Invalid input or empty payload; 'index' identifies the failing entry",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/handlers.batchErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Document not found",
+                    "412": {
+                        "description": "CAS mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.batchErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Body larger than the configured maximum",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -784,87 +978,78 @@ const docTemplate = `{
                 }
             }
         },
-        "/documents/lists/push": {
+        "/documents/bulk/touch": {
             "post": {
-                "description": "Adds an element to the end of an existing list-type document.",
+                "description": "Updates the expiration of a list of keys atomically. Each key uses its own 'expiration' when given and the 'expiration' parameter otherwise. Emits a touch change event per key so the TTL index stays current.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
-                    "lists"
+                    "documents"
                 ],
-                "summary": "Push element to list",
+                "summary": "Bulk touch documents",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Key of the list document",
-                        "name": "key",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Column family (default: 'default')",
+                        "description": "Column family (defaults to 'default')",
                         "name": "cf",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "description": "Expiration for keys without their own. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d)",
                         "name": "expiration",
                         "in": "query"
                     },
                     {
-                        "description": "Element to add",
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Keys to touch",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.listElementRequest"
+                            "$ref": "#/definitions/handlers.bulkKeysRequest"
                         }
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Write option: sync write to disk",
-                        "name": "sync",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Write option: disable write-ahead log",
-                        "name": "disable_wal",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Write option: disable slowdown on write buffer full",
-                        "name": "no_slowdown",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Status message",
+                        "description": "Touched documents, in request order",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Document"
                             }
                         }
                     },
                     "400": {
-                        "description": "Invalid request or JSON body",
+                        "description": "Invalid input or missing expiration; 'index' identifies the failing key",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/handlers.batchErrorResponse"
                         }
                     },
                     "404": {
                         "description": "Document not found",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/handlers.batchErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "CAS mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.batchErrorResponse"
                         }
                     },
                     "500": {
@@ -876,23 +1061,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/documents/lists/range": {
-            "get": {
-                "description": "Returns a slice of elements from a list document, based on start and end indices.",
+        "/documents/counters/delta": {
+            "post": {
+                "description": "Increments or decrements a counter document by a given value. Integer counters stay integers; a float delta or value makes a float counter, which stays a float counter even when its value is whole. Float deltas are rejected with 400 on integer counters beyond ±2^53, where they would lose precision. With initial, a missing counter is created with that value instead of failing, without applying the delta. With min and/or max, a value out of the bounds fails with 409, or is clamped to them with clamp.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
-                    "lists"
+                    "counters"
                 ],
-                "summary": "Get elements from a list",
+                "summary": "Modify counter",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Key of the list document",
+                        "description": "Document key",
                         "name": "key",
                         "in": "query",
                         "required": true
@@ -905,41 +1094,35 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Start index (inclusive, 0-based)",
-                        "name": "start",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "End index (inclusive, -1 for end of list)",
-                        "name": "end",
-                        "in": "query",
-                        "required": true
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Read option: whether to fill RocksDB cache",
-                        "name": "fill_cache",
-                        "in": "query"
+                        "description": "Delta value for increment or decrement",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.incrementRequest"
+                        }
                     },
                     {
                         "type": "string",
-                        "description": "Read option: 'all' or 'cache-only'",
-                        "name": "read_tier",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List content",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/db.CounterResult"
                         }
                     },
                     "400": {
-                        "description": "Invalid input parameters",
+                        "description": "Invalid parameters or JSON body",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -950,6 +1133,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Counter would overflow or leave its bounds",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -959,33 +1148,31 @@ const docTemplate = `{
                 }
             }
         },
-        "/documents/lists/shift": {
+        "/documents/counters/getset": {
             "post": {
-                "description": "Removes and returns the first element of a list. Returns an error if the list is empty.",
+                "description": "Atomically sets a counter document to a value, 0 when omitted, and returns its previous value. The counter is created if it does not exist, in which case old is null.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
-                    "lists"
+                    "counters"
                 ],
-                "summary": "Shift list (remove first element)",
+                "summary": "Set or reset counter",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Key of the list document",
+                        "description": "Document key",
                         "name": "key",
                         "in": "query",
                         "required": true
                     },
-                    {
-                        "type": "integer",
-                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
-                        "name": "expiration",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
                         "description": "Column family (default: 'default')",
@@ -993,40 +1180,48 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Write option: wait for sync",
-                        "name": "sync",
+                        "type": "string",
+                        "description": "CAS (revision) the counter must have",
+                        "name": "cas",
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Write option: disable WAL",
-                        "name": "disable_wal",
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Write option: disable slowdown retries",
-                        "name": "no_slowdown",
+                        "description": "New value",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.getSetCounterRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Removed element from the list",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/db.CounterResult"
                         }
                     },
                     "400": {
-                        "description": "Invalid input parameters",
+                        "description": "Invalid parameters or body, or document is not a counter",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Document not found or list is empty",
+                    "412": {
+                        "description": "Revision mismatch",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1040,27 +1235,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/documents/lists/unshift": {
+        "/documents/counters/multi-delta": {
             "post": {
-                "description": "Adds a new element to the beginning of a list document. The element can be of any JSON-compatible type.",
+                "description": "Increments or decrements several counter documents of a column family in order, in a single transaction, and returns the result of each. Every entry accepts the options of /documents/counters/delta. Either every counter is updated or none is.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
-                    "lists"
+                    "counters"
                 ],
-                "summary": "Unshift list (add to start)",
+                "summary": "Modify several counters",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Key of the list document",
-                        "name": "key",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "Column family (default: 'default')",
@@ -1069,54 +1261,54 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "description": "Optional expiration applied to every counter. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
                         "name": "expiration",
                         "in": "query"
                     },
                     {
-                        "description": "Element to insert at the beginning",
+                        "description": "Counters and their deltas",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.listElementRequest"
+                            "$ref": "#/definitions/handlers.multiIncrementRequest"
                         }
                     },
                     {
-                        "type": "boolean",
-                        "description": "Write option: wait for sync",
-                        "name": "sync",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Write option: disable WAL",
-                        "name": "disable_wal",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Write option: disable slowdown retries",
-                        "name": "no_slowdown",
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Insertion successful",
+                        "description": "Result of each counter, in request order",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid input or JSON body",
+                        "description": "Invalid parameters or body",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Document not found",
+                        "description": "A counter was not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A counter would overflow or leave its bounds",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Revision mismatch",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1130,19 +1322,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/documents/replace": {
-            "post": {
-                "description": "Replaces a document if it already exists. Fails if the key does not exist. Supports CAS for concurrency control.",
+        "/documents/hashes/exists": {
+            "get": {
+                "description": "Checks whether a field exists in a \"hash\" document.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
-                    "documents"
+                    "hashes"
                 ],
-                "summary": "Replace an existing document",
+                "summary": "Check if hash field exists",
                 "parameters": [
                     {
                         "type": "string",
@@ -1157,56 +1353,36 @@ const docTemplate = `{
                         "name": "cf",
                         "in": "query"
                     },
-                    {
-                        "type": "integer",
-                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
-                        "name": "expiration",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Document type (e.g. 'json', 'counter', 'list')",
-                        "name": "type",
-                        "in": "query"
+                        "description": "Field to check",
+                        "name": "field",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "CAS (revision) for concurrency control",
-                        "name": "cas",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
                         "in": "query"
-                    },
-                    {
-                        "description": "New value for the document",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Whether the field exists",
                         "schema": {
-                            "$ref": "#/definitions/model.Document"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid request or missing value",
+                        "description": "Missing or invalid parameters",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Key not found or column family missing",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "CAS mismatch",
+                        "description": "Document not found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1220,19 +1396,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/documents/sets/add": {
-            "post": {
-                "description": "Adds a new element to a document of type \"set\". If the element already exists, it will not be duplicated.",
+        "/documents/hashes/get": {
+            "get": {
+                "description": "Returns the value of a field of a \"hash\" document.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
-                    "sets"
+                    "hashes"
                 ],
-                "summary": "Add element to set",
+                "summary": "Get hash field",
                 "parameters": [
                     {
                         "type": "string",
@@ -1248,39 +1428,35 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
-                        "name": "expiration",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Field to read",
+                        "name": "field",
+                        "in": "query",
+                        "required": true
                     },
                     {
-                        "description": "Element to add to the set",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.SetElementRequest"
-                        }
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Operation successful",
+                        "description": "Value of the field",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid parameters or body",
+                        "description": "Missing or invalid parameters",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Document not found",
+                        "description": "Document or field not found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1294,19 +1470,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/documents/sets/contains": {
+        "/documents/hashes/getall": {
             "get": {
-                "description": "Checks whether a given element exists within a set-type document.",
+                "description": "Returns the fields of a \"hash\" document with their values, paginated in field order. When more fields follow, 'next' holds the value to pass as start_after for the next page.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
-                    "sets"
+                    "hashes"
                 ],
-                "summary": "Check if element exists in set",
+                "summary": "Get hash fields",
                 "parameters": [
                     {
                         "type": "string",
@@ -1323,18 +1503,29 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Element to check",
-                        "name": "element",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Status and whether the element exists",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                        "description": "Only return fields sorting after this one",
+                        "name": "start_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of fields to return (default: no limit)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fields with their values, and the next cursor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -1358,19 +1549,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/documents/sets/remove": {
+        "/documents/hashes/incrby": {
             "post": {
-                "description": "Removes a specific element from a set-type document.",
+                "description": "Atomically adds a delta to an integer field of a \"hash\" document. A missing field counts as 0.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
-                    "sets"
+                    "hashes"
                 ],
-                "summary": "Remove element from set",
+                "summary": "Increment hash field",
                 "parameters": [
                     {
                         "type": "string",
@@ -1392,28 +1587,31 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "description": "Element to remove from the set",
+                        "description": "Field and delta",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/handlers.HIncrByRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success message",
+                        "description": "New value of the field",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid request or missing parameters",
+                        "description": "Invalid parameters, body or non-integer field",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1433,19 +1631,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/documents/touch": {
-            "post": {
-                "description": "Updates the expiration time of an existing document without modifying its content. Fails if the key does not exist.",
+        "/documents/hashes/keys": {
+            "get": {
+                "description": "Returns the field names of a \"hash\" document in sorted order.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
-                    "documents"
+                    "hashes"
                 ],
-                "summary": "Update document expiration",
+                "summary": "List hash fields",
                 "parameters": [
                     {
                         "type": "string",
@@ -1461,27 +1663,28 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
-                        "name": "expiration",
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Field names",
                         "schema": {
-                            "$ref": "#/definitions/model.Document"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Missing or invalid parameters",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Key not found or already expired",
+                        "description": "Document not found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1495,75 +1698,60 @@ const docTemplate = `{
                 }
             }
         },
-        "/families": {
+        "/documents/hashes/len": {
             "get": {
-                "description": "Retrieves the names of all available column families.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "families"
-                ],
-                "summary": "List column families",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Creates a new column family with the specified name.",
+                "description": "Returns the number of fields of a \"hash\" document.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
-                    "families"
+                    "hashes"
                 ],
-                "summary": "Create column family",
+                "summary": "Count hash fields",
                 "parameters": [
                     {
-                        "description": "Name of the column family to create",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.createFamilyRequest"
-                        }
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created column family name",
+                    "200": {
+                        "description": "Number of fields",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid or missing column family name",
+                        "description": "Missing or invalid parameters",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Column family already exists",
+                    "404": {
+                        "description": "Document not found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1577,27 +1765,75 @@ const docTemplate = `{
                 }
             }
         },
-        "/indexes": {
+        "/documents/hashes/mget": {
             "get": {
-                "description": "Retrieves all existing secondary index definitions.",
+                "description": "Returns the values of several fields of a \"hash\" document in the order requested, with null for missing fields.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
-                    "indexes"
+                    "hashes"
+                ],
+                "summary": "Get several hash fields",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Fields to read (repeat the parameter for each field)",
+                        "name": "field",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
                 ],
-                "summary": "List indexes",
                 "responses": {
                     "200": {
-                        "description": "List of index definitions",
+                        "description": "Values of the fields",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
+                    "400": {
+                        "description": "Missing or invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1605,42 +1841,77 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/documents/hashes/remove": {
             "post": {
-                "description": "Defines a new secondary index with projection and condition.",
+                "description": "Removes fields from a document of type \"hash\". Fields that do not exist are ignored.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
-                    "indexes"
+                    "hashes"
                 ],
-                "summary": "Create index",
+                "summary": "Remove hash fields",
                 "parameters": [
                     {
-                        "description": "Index definition",
-                        "name": "index",
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Fields to remove",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.IndexDefinition"
+                            "$ref": "#/definitions/handlers.HDelRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Index created successfully",
+                    "200": {
+                        "description": "Number of fields removed",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid index definition",
+                        "description": "Invalid parameters or body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1654,88 +1925,267 @@ const docTemplate = `{
                 }
             }
         },
-        "/indexes/{name}": {
-            "get": {
-                "description": "Retrieves the definition of a specific secondary index.",
+        "/documents/hashes/set": {
+            "post": {
+                "description": "Sets fields of a document of type \"hash\", leaving its other fields untouched.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
-                    "indexes"
+                    "hashes"
                 ],
-                "summary": "Get index",
+                "summary": "Set hash fields",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Index name",
-                        "name": "name",
-                        "in": "path",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Fields to set",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HSetRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Index definition",
+                        "description": "Number of fields that were added",
                         "schema": {
-                            "$ref": "#/definitions/model.IndexDefinition"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid index name",
+                        "description": "Invalid parameters or body",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Index not found",
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes a secondary index and its definition from the system.",
+            }
+        },
+        "/documents/history": {
+            "get": {
+                "description": "Lists the revisions kept for a document, newest first, in a column family with history enabled. Deletions appear as revisions flagged 'deleted'. Use 'values=true' to include the stored document of each revision. Revisions of documents stored as elements (lists, sets, sorted sets, streams and time series changed through their endpoints) keep their metadata and element header but no value.",
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "documents"
+                ],
+                "summary": "List document revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of revisions to return (default: all)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the stored document of each revision",
+                        "name": "values",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Optional RocksDB fill cache read option",
+                        "name": "fill_cache",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Optional RocksDB read tier (e.g. 'all', 'cache-only')",
+                        "name": "read_tier",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.documentHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid key or history not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/insert": {
+            "post": {
+                "description": "Insert a new document only if the key does not already exist",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor",
+                    "application/octet-stream"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
-                    "indexes"
+                    "documents"
                 ],
-                "summary": "Delete index",
+                "summary": "Insert a document",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Index name",
-                        "name": "name",
-                        "in": "path",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (defaults to 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Document type (json, counter, list, set, zset, hash, blob, stream, timeseries). application/octet-stream bodies are stored as blobs",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to store without expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: sync",
+                        "name": "sync",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable WAL",
+                        "name": "disable_wal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: no slowdown",
+                        "name": "no_slowdown",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Document value body. Must contain 'value' field",
+                        "name": "document",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Index deleted successfully",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/model.Document"
                         }
                     },
                     "400": {
-                        "description": "Invalid index name",
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1743,284 +2193,6984 @@ const docTemplate = `{
                 }
             }
         },
-        "/metrics": {
+        "/documents/keys": {
             "get": {
-                "description": "Returns server-level, RocksDB and expiration system metrics including memory usage, uptime, compaction stats, and TTL cleanup activity.",
+                "description": "Returns a list of keys within the specified column family, optionally filtered by prefix and pagination options.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
                 ],
                 "tags": [
-                    "monitoring"
+                    "keys"
+                ],
+                "summary": "List document keys",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return keys with this prefix",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return keys after this key (for pagination)",
+                        "name": "start_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of keys to return (default: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Whether to fill RocksDB read cache",
+                        "name": "fill_cache",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RocksDB read tier (e.g. 'all', 'cache-only')",
+                        "name": "read_tier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Retrieve internal metrics",
                 "responses": {
                     "200": {
-                        "description": "Detailed metrics of the server, database and expiration subsystem",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/metrics.FullMetrics"
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
-                        "description": "Failed to collect or serialize metrics",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "config.AppConfig": {
-            "description": "Global server and database configuration.",
+        },
+        "/documents/list": {
+            "get": {
+                "description": "Returns a map of documents filtered by optional prefix and paginated using start_after and limit.",
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "documents"
+                ],
+                "summary": "List documents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter documents whose keys begin with this prefix",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Skip documents until this key (exclusive)",
+                        "name": "start_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of documents to return (default: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Whether to fill RocksDB read cache",
+                        "name": "fill_cache",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RocksDB read tier (e.g., 'all', 'cache-only')",
+                        "name": "read_tier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/model.Document"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/lists/index": {
+            "get": {
+                "description": "Returns the element at an index of a list document. Negative indices count from the end (-1 is the last element).",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Get list element by index",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key of the list document",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Index of the element",
+                        "name": "index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Read option: whether to fill RocksDB cache",
+                        "name": "fill_cache",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Read option: 'all' or 'cache-only'",
+                        "name": "read_tier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The element",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or index out of range",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/lists/insert": {
+            "post": {
+                "description": "Inserts an element before or after the first element equal to the pivot. Returns the new length, or -1 when the pivot is not in the list.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Insert element next to pivot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key of the list document",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Pivot, element and position",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.listInsertRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: sync write to disk",
+                        "name": "sync",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable write-ahead log",
+                        "name": "disable_wal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable slowdown on write buffer full",
+                        "name": "no_slowdown",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New length, or -1 when the pivot was not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/lists/len": {
+            "get": {
+                "description": "Returns the number of elements of a list document.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Get list length",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key of the list document",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List length",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/lists/move": {
+            "post": {
+                "description": "Atomically pops an element from one end of the source list and pushes it to one end of the destination list. The lists may be the same document or live in different column families; both must exist.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Move element between lists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key of the source list",
+                        "name": "source",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family of the source list (default: 'default')",
+                        "name": "source_cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key of the destination list",
+                        "name": "destination",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family of the destination list (default: 'default')",
+                        "name": "destination_cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the source to take from: 'left' or 'right' (default)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the destination to put at: 'left' or 'right' (default)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: sync write to disk",
+                        "name": "sync",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable write-ahead log",
+                        "name": "disable_wal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable slowdown on write buffer full",
+                        "name": "no_slowdown",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The moved element",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or empty source list",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Source or destination not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/lists/pop": {
+            "post": {
+                "description": "Removes and returns the last element of a list-type document. With ` + "`" + `block` + "`" + `, an empty or missing list is waited on until an element is pushed or the timeout elapses; waiting clients are served in arrival order. While clients are waiting, the list reads as empty to pops that do not block.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Pop element from list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key of the list document",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: sync write to disk",
+                        "name": "sync",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable write-ahead log",
+                        "name": "disable_wal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable slowdown on write buffer full",
+                        "name": "no_slowdown",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Seconds to wait for an element while the list is empty or missing (max 300). Not allowed with txn.",
+                        "name": "block",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns the popped element",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or missing key",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found, without block",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/lists/push": {
+            "post": {
+                "description": "Adds an element, or several in order, to the end of an existing list-type document and returns the new length.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Push element to list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key of the list document",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Element or elements to add",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.listElementRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: sync write to disk",
+                        "name": "sync",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable write-ahead log",
+                        "name": "disable_wal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable slowdown on write buffer full",
+                        "name": "no_slowdown",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status and new length",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or JSON body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/lists/range": {
+            "get": {
+                "description": "Returns a slice of elements from a list document, based on start and end indices.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Get elements from a list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key of the list document",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Start index (inclusive, 0-based)",
+                        "name": "start",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "End index (inclusive, -1 for end of list)",
+                        "name": "end",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Read option: whether to fill RocksDB cache",
+                        "name": "fill_cache",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Read option: 'all' or 'cache-only'",
+                        "name": "read_tier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List content",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/lists/remove": {
+            "post": {
+                "description": "Removes elements equal to a value. A positive count removes up to count matches from the head, a negative count from the tail, and 0 removes every match.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Remove elements by value",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key of the list document",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Element and count",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.listRemoveRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: sync write to disk",
+                        "name": "sync",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable write-ahead log",
+                        "name": "disable_wal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable slowdown on write buffer full",
+                        "name": "no_slowdown",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of elements removed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/lists/set": {
+            "post": {
+                "description": "Replaces the element at an index of a list document. Negative indices count from the end.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Set list element by index",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key of the list document",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Index of the element to replace",
+                        "name": "index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "description": "New element",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.listElementRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: sync write to disk",
+                        "name": "sync",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable write-ahead log",
+                        "name": "disable_wal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable slowdown on write buffer full",
+                        "name": "no_slowdown",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or index out of range",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/lists/shift": {
+            "post": {
+                "description": "Removes and returns the first element of a list. Returns an error if the list is empty. With ` + "`" + `block` + "`" + `, an empty or missing list is waited on until an element is pushed or the timeout elapses; waiting clients are served in arrival order. While clients are waiting, the list reads as empty to pops that do not block.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Shift list (remove first element)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key of the list document",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: wait for sync",
+                        "name": "sync",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable WAL",
+                        "name": "disable_wal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable slowdown retries",
+                        "name": "no_slowdown",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Seconds to wait for an element while the list is empty or missing (max 300). Not allowed with txn.",
+                        "name": "block",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Removed element from the list",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found, without block",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/lists/trim": {
+            "post": {
+                "description": "Keeps only the elements between start and stop, both inclusive. Negative indices count from the end; a range outside the list empties it.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Trim list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key of the list document",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "First index to keep",
+                        "name": "start",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Last index to keep",
+                        "name": "stop",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: sync write to disk",
+                        "name": "sync",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable write-ahead log",
+                        "name": "disable_wal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable slowdown on write buffer full",
+                        "name": "no_slowdown",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/lists/unshift": {
+            "post": {
+                "description": "Adds a new element to the beginning of a list document and returns the new length. The element can be of any JSON-compatible type. Several elements are inserted one after the other, so they end up in reverse order.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Unshift list (add to start)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key of the list document",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Element or elements to insert at the beginning",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.listElementRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: wait for sync",
+                        "name": "sync",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable WAL",
+                        "name": "disable_wal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable slowdown retries",
+                        "name": "no_slowdown",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status and new length",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or JSON body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/mutate": {
+            "post": {
+                "description": "Atomically applies a list of path operations (set, insert, replace, remove, array_append, array_insert, increment) to a JSON document. Values may be null, which writes null at the path. Honors CAS and expiration and returns the updated document with its new revision.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "documents"
+                ],
+                "summary": "Mutate document paths",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CAS (revision) for concurrency control",
+                        "name": "cas",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Revision (ETag) the document must have; alternative to the cas parameter",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Operations to apply",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.mutateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Document"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid operation, path or document type",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Path already exists (insert)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "CAS mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/replace": {
+            "post": {
+                "description": "Replaces a document if it already exists. Fails if the key does not exist. Supports CAS for concurrency control via the cas parameter or an If-Match header.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor",
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "documents"
+                ],
+                "summary": "Replace an existing document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Document type (e.g. 'json', 'counter', 'list', 'zset', 'hash', 'blob', 'stream', 'timeseries'). application/octet-stream bodies are stored as blobs",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CAS (revision) for concurrency control",
+                        "name": "cas",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Revision (ETag) the document must have; alternative to the cas parameter",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "New value for the document",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Document"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or missing value",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Key not found or column family missing",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "CAS mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Body larger than the configured maximum",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/revert": {
+            "post": {
+                "description": "Writes the value of a past revision back as the document's newest revision, in a column family with history enabled. Works for deleted documents too. Like a put, the restored document has no expiration unless one is given. Revisions of documents stored as elements cannot be restored.",
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "documents"
+                ],
+                "summary": "Revert a document to a past revision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Revision to restore",
+                        "name": "rev",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d)",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CAS (revision) the current document must have",
+                        "name": "cas",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Revision (ETag) the current document must have; alternative to the cas parameter",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: sync",
+                        "name": "sync",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable WAL",
+                        "name": "disable_wal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: no slowdown",
+                        "name": "no_slowdown",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Document"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or history not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Revision not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Revision of a document stored as elements",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "CAS mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/sets/add": {
+            "post": {
+                "description": "Adds a new element to a document of type \"set\". If the element already exists, it will not be duplicated.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "sets"
+                ],
+                "summary": "Add element to set",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Element to add to the set",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SetElementRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Operation successful",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/sets/card": {
+            "get": {
+                "description": "Returns the number of members of a set-type document.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "sets"
+                ],
+                "summary": "Count set members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status and number of members",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/sets/contains": {
+            "get": {
+                "description": "Checks whether a given element exists within a set-type document.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "sets"
+                ],
+                "summary": "Check if element exists in set",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Element to check",
+                        "name": "element",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status and whether the element exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/sets/diff": {
+            "post": {
+                "description": "Returns the members of the first listed set document that are in none of the others. With ` + "`" + `store` + "`" + `, the result replaces that document atomically with the reads and only its size is returned.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "sets"
+                ],
+                "summary": "Difference of sets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Column family of every set (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Expiration of the stored result. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d).",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Sets to combine and optional store target",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SetAlgebraRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from, without store",
+                        "name": "snapshot",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Members, or the stored count",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/sets/intersect": {
+            "post": {
+                "description": "Returns the members found in every one of the listed set documents. With ` + "`" + `store` + "`" + `, the result replaces that document atomically with the reads and only its size is returned.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "sets"
+                ],
+                "summary": "Intersection of sets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Column family of every set (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Expiration of the stored result. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d).",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Sets to combine and optional store target",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SetAlgebraRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from, without store",
+                        "name": "snapshot",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Members, or the stored count",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/sets/mcontains": {
+            "get": {
+                "description": "Checks, for each ` + "`" + `element` + "`" + ` parameter in order, whether it exists within a set-type document.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "sets"
+                ],
+                "summary": "Check several elements in set",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Elements to check (repeat the parameter)",
+                        "name": "element",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Whether each element exists, in request order",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/sets/members": {
+            "get": {
+                "description": "Returns a page of the members of a set-type document. Members come in a stable order; pass the returned ` + "`" + `next` + "`" + ` cursor to read the following page.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "sets"
+                ],
+                "summary": "List set members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of members to return (0 = all)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Members and, when more follow, the next cursor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/sets/pop": {
+            "post": {
+                "description": "Removes up to ` + "`" + `count` + "`" + ` members of a set-type document chosen at random and returns them.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "sets"
+                ],
+                "summary": "Pop random set members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of members to remove (default: 1)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Removed members",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/sets/random": {
+            "get": {
+                "description": "Returns up to ` + "`" + `count` + "`" + ` distinct members of a set-type document chosen at random, without removing them.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "sets"
+                ],
+                "summary": "Get random set members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of members to return (default: 1)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Random members",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/sets/remove": {
+            "post": {
+                "description": "Removes a specific element from a set-type document.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "sets"
+                ],
+                "summary": "Remove element from set",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Element to remove from the set",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or missing parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/sets/union": {
+            "post": {
+                "description": "Returns the members found in any of the listed set documents. With ` + "`" + `store` + "`" + `, the result replaces that document atomically with the reads and only its size is returned.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "sets"
+                ],
+                "summary": "Union of sets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Column family of every set (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Expiration of the stored result. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d).",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Sets to combine and optional store target",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SetAlgebraRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from, without store",
+                        "name": "snapshot",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Members, or the stored count",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/streams/append": {
+            "post": {
+                "description": "Appends an entry to a document of type \"stream\", creating the stream if it does not exist, and returns its ID. IDs are \"\u003cms\u003e-\u003cseq\u003e\": the append time in Unix milliseconds and a sequence number, always increasing. maxlen, max_age and min_id trim the oldest entries in the same write.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "streams"
+                ],
+                "summary": "Append to a stream",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Trim the stream to this many entries",
+                        "name": "maxlen",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trim entries older than this, as a duration (e.g. '24h') or in seconds",
+                        "name": "max_age",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trim entries with lower IDs",
+                        "name": "min_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Entry to append",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StreamAppendRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ID of the appended entry",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters, body or ID, or document is not a stream",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/streams/groups/ack": {
+            "post": {
+                "description": "Removes entries from the pending entries of a consumer group once they are processed, and returns how many were pending.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "streams"
+                ],
+                "summary": "Acknowledge stream entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Consumer group name",
+                        "name": "group",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Entry IDs",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StreamAckRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of entries acknowledged",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters, body or IDs",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document or consumer group not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/streams/groups/claim": {
+            "post": {
+                "description": "Hands the pending entries of a consumer group delivered at least ` + "`" + `min_idle` + "`" + ` ago, oldest ID first, to ` + "`" + `consumer` + "`" + ` and returns them, so a consumer can take over the work of one that stopped. Pending entries whose stream entry was trimmed are acknowledged instead of returned.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "streams"
+                ],
+                "summary": "Claim idle pending entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Consumer group name",
+                        "name": "group",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Consumer to hand the entries to",
+                        "name": "consumer",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only claim entries delivered at least this long ago, as a duration (e.g. '5m') or in seconds (default: 0)",
+                        "name": "min_idle",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of entries (default: no limit)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Claimed entries with their IDs",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or document is not a stream",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document or consumer group not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/streams/groups/create": {
+            "post": {
+                "description": "Creates a consumer group on a \"stream\" document, creating the stream if it does not exist. The group is delivered the entries after ` + "`" + `start` + "`" + `: '$' (default) for only new entries, '-' for every entry, or an ID.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "streams"
+                ],
+                "summary": "Create a consumer group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Consumer group name",
+                        "name": "group",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deliver entries after this ID (default: '$')",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.StreamGroup"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or document is not a stream",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Consumer group already exists",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/streams/groups/delete": {
+            "post": {
+                "description": "Deletes a consumer group of a \"stream\" document along with its pending entries. The stream entries are kept.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "streams"
+                ],
+                "summary": "Delete a consumer group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Consumer group name",
+                        "name": "group",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Consumer group deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or document is not a stream",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document or consumer group not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/streams/groups/pending": {
+            "get": {
+                "description": "Lists the entries delivered to a consumer group and not yet acknowledged, in ID order, with their consumer, last delivery time and number of deliveries.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "streams"
+                ],
+                "summary": "List pending entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Consumer group name",
+                        "name": "group",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only list the entries of this consumer",
+                        "name": "consumer",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only list entries after this ID",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of entries (default: no limit)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pending entries",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or document is not a stream",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document or consumer group not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/streams/groups/read": {
+            "post": {
+                "description": "Delivers the entries of a \"stream\" document not yet delivered to the consumer group to ` + "`" + `consumer` + "`" + `, and keeps them pending until they are acknowledged. Each entry goes to a single consumer of the group. With ` + "`" + `block` + "`" + `, waits for entries while there are none.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "streams"
+                ],
+                "summary": "Read as a group consumer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Consumer group name",
+                        "name": "group",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Consumer name",
+                        "name": "consumer",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of entries (default: no limit)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Seconds to wait for entries while there are none (max 300). Not allowed with txn.",
+                        "name": "block",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delivered entries with their IDs",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or document is not a stream",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document or consumer group not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/streams/info": {
+            "get": {
+                "description": "Returns the length, first and last entry IDs and consumer groups of a \"stream\" document.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "streams"
+                ],
+                "summary": "Describe a stream",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.StreamInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or document is not a stream",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/streams/range": {
+            "get": {
+                "description": "Returns the entries of a \"stream\" document with IDs between start and end, inclusive, oldest first or, with reverse, newest first. \"-\" and \"+\" stand for the first and last entries; a bare \"\u003cms\u003e\" end includes every entry of that millisecond. Use reverse with count to read the latest entries.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "streams"
+                ],
+                "summary": "Read stream entries by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First ID (default: '-')",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last ID (default: '+')",
+                        "name": "end",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of entries (default: no limit)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return the newest entries first",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Entries with their IDs",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or document is not a stream",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/streams/tail": {
+            "get": {
+                "description": "Returns the entries of a \"stream\" document appended after the ID ` + "`" + `after` + "`" + `, or after its latest entry when omitted, and ` + "`" + `last` + "`" + `, the ID to pass as ` + "`" + `after` + "`" + ` on the next call. With ` + "`" + `block` + "`" + `, waits for entries while there are none. A stream that does not exist yet is followed from its first entry.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "streams"
+                ],
+                "summary": "Follow a stream",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return entries after this ID (default: '$', the latest entry)",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of entries (default: no limit)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Seconds to wait for entries while there are none (max 300). Not allowed with snapshot.",
+                        "name": "block",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Entries with their IDs, and the ID to continue after",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or document is not a stream",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/streams/trim": {
+            "post": {
+                "description": "Removes the oldest entries of a \"stream\" document beyond maxlen entries, older than max_age or with IDs lower than min_id, and returns how many were removed. Removed entries still pending in a consumer group stay pending until acknowledged or claimed.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "streams"
+                ],
+                "summary": "Trim a stream",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entries to keep",
+                        "name": "maxlen",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Remove entries older than this, as a duration (e.g. '24h') or in seconds",
+                        "name": "max_age",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Remove entries with lower IDs",
+                        "name": "min_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of entries removed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or document is not a stream",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/timeseries/add": {
+            "post": {
+                "description": "Adds samples to a document of type \"timeseries\", creating the series if it does not exist, and returns its length. A sample replaces the one with the same timestamp. Samples older than the retention period before the newest sample are rejected; the compaction rules of the series update their destination series in the same write.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "timeseries"
+                ],
+                "summary": "Add samples to a time series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Samples to add",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TimeSeriesAddRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Length of the series",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters, body or samples, or document is not a time series",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/timeseries/config": {
+            "post": {
+                "description": "Sets the retention period and compaction rules of a \"timeseries\" document, creating the series if it does not exist. Samples older than the retention period before the newest sample are dropped. A compaction rule keeps another series in the same column family updated with one aggregated sample per bucket of the samples added from then on.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "timeseries"
+                ],
+                "summary": "Configure a time series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Retention and compaction rules; omitted settings are cleared",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TimeSeriesSettings"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Settings of the series",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or settings, or document is not a time series",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/timeseries/delete": {
+            "post": {
+                "description": "Removes the samples of a \"timeseries\" document with timestamps between from and to, inclusive, and returns how many were removed. Compaction rules recompute the buckets of the removed samples.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "timeseries"
+                ],
+                "summary": "Delete time series samples",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "First timestamp, in Unix milliseconds (default: the oldest sample)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Last timestamp, in Unix milliseconds (default: the newest sample)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of samples removed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or document is not a time series",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/timeseries/info": {
+            "get": {
+                "description": "Returns the length, first and last sample timestamps, retention and compaction rules of a \"timeseries\" document.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "timeseries"
+                ],
+                "summary": "Describe a time series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TimeSeriesInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or document is not a time series",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/timeseries/range": {
+            "get": {
+                "description": "Returns the samples of a \"timeseries\" document with timestamps between from and to, inclusive, oldest first or, with reverse, newest first. label filters keep the samples with every given label. With aggregation and bucket, returns one aggregate per bucket with samples instead, buckets starting at multiples of the bucket duration since the Unix epoch.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "timeseries"
+                ],
+                "summary": "Read time series samples",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "First timestamp, in Unix milliseconds (default: the oldest sample)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Last timestamp, in Unix milliseconds (default: the newest sample)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Label filter as 'name:value'; repeat for several labels",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Aggregate samples with min, max, avg, sum or count",
+                        "name": "aggregation",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket duration for aggregation (e.g. '1h') or in seconds",
+                        "name": "bucket",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of samples or buckets (default: no limit)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return the newest samples or buckets first",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Samples, or buckets with aggregation",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or document is not a time series",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/touch": {
+            "post": {
+                "description": "Updates the expiration time of an existing document without modifying its content. Fails if the key does not exist.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "documents"
+                ],
+                "summary": "Update document expiration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CAS (revision) for concurrency control",
+                        "name": "cas",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Revision (ETag) the document must have; alternative to the cas parameter",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Document"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Key not found or already expired",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "CAS mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/undelete": {
+            "post": {
+                "description": "Restores a document from the trash of its column family. The document gets a fresh revision and keeps the expiration it had when deleted unless 'expiration' is given; an expiration that has passed since is cleared. Fails if a live document has taken the key since.",
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "documents"
+                ],
+                "summary": "Restore a deleted document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d)",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: sync",
+                        "name": "sync",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: disable WAL",
+                        "name": "disable_wal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write option: no slowdown",
+                        "name": "no_slowdown",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Document"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Key already exists",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/zsets/add": {
+            "post": {
+                "description": "Adds members to a document of type \"zset\" or updates their scores. nx only adds new members, xx only updates existing ones, and gt/lt only update a score when the new one is greater/lower.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "zsets"
+                ],
+                "summary": "Add members to sorted set",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Members and scores to add",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ZAddRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of members added and updated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters, body or flag combination",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/zsets/incrby": {
+            "post": {
+                "description": "Adds an increment to the score of a member of a \"zset\" document, adding the member with the increment as its score when missing.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "zsets"
+                ],
+                "summary": "Increment sorted set member score",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Member and increment",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ZIncrByRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New score of the member",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/zsets/popmax": {
+            "post": {
+                "description": "Removes and returns up to count members with the highest scores from a \"zset\" document.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "zsets"
+                ],
+                "summary": "Pop highest scored members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of members to pop (default: 1)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Popped members with their scores, highest first",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/zsets/popmin": {
+            "post": {
+                "description": "Removes and returns up to count members with the lowest scores from a \"zset\" document.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "zsets"
+                ],
+                "summary": "Pop lowest scored members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of members to pop (default: 1)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Popped members with their scores, lowest first",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/zsets/range": {
+            "get": {
+                "description": "Returns the members of a \"zset\" document between two ranks, both inclusive. Negative ranks count from the end (-1 is the last member).",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "zsets"
+                ],
+                "summary": "Get sorted set members by rank",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Start rank (inclusive, default: 0)",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Stop rank (inclusive, default: -1)",
+                        "name": "stop",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Rank from the highest score",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Read option: whether to fill RocksDB cache",
+                        "name": "fill_cache",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Read option: 'all' or 'cache-only'",
+                        "name": "read_tier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Members with their scores",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/zsets/range-by-score": {
+            "get": {
+                "description": "Returns the members of a \"zset\" document whose score lies between min and max. Bounds accept \"-inf\" and \"+inf\", and a \"(\" prefix makes them exclusive.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "zsets"
+                ],
+                "summary": "Get sorted set members by score",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lowest score (default: -inf)",
+                        "name": "min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Highest score (default: +inf)",
+                        "name": "max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of matching members to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of members to return (default: no limit)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return the highest scores first",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Read option: whether to fill RocksDB cache",
+                        "name": "fill_cache",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Read option: 'all' or 'cache-only'",
+                        "name": "read_tier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Members with their scores",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/zsets/rank": {
+            "get": {
+                "description": "Returns the 0-based rank of a member of a \"zset\" document, counting from the lowest score or, with reverse, from the highest.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "zsets"
+                ],
+                "summary": "Get sorted set member rank",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Member to look up",
+                        "name": "member",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Rank from the highest score",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rank of the member",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document or member not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/zsets/remove": {
+            "post": {
+                "description": "Removes members from a document of type \"zset\". Members not in the set are ignored.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "zsets"
+                ],
+                "summary": "Remove members from sorted set",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d). Omit to keep existing expiration.",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Members to remove",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ZRemRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interactive transaction ID returned by POST /transactions",
+                        "name": "txn",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of members removed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/zsets/score": {
+            "get": {
+                "description": "Returns the score of a member of a \"zset\" document.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "zsets"
+                ],
+                "summary": "Get sorted set member score",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column family (default: 'default')",
+                        "name": "cf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Member to look up",
+                        "name": "member",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pinned snapshot ID returned by POST /snapshots to read from",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Score of the member",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Document or member not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/families": {
+            "get": {
+                "description": "Retrieves the names of all available column families.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "families"
+                ],
+                "summary": "List column families",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new column family with the specified name and, optionally, its settings.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "families"
+                ],
+                "summary": "Create column family",
+                "parameters": [
+                    {
+                        "description": "Name and optional settings of the column family to create",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.createFamilyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created column family name",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or missing column family name, or invalid settings",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Column family already exists",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/families/settings": {
+            "get": {
+                "description": "Returns the settings of a user column family, such as its document history and trash modes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "families"
+                ],
+                "summary": "Get column family settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Column family",
+                        "name": "cf",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.FamilySettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid column family",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the settings of a user column family. With history enabled, every write and delete keeps a revision of the document, pruned to 'max_revisions' per document and to revisions younger than 'retention'. Range deletes are not recorded. Disabling history keeps the stored revisions but makes them unavailable until it is enabled again. With the trash enabled, deleted documents, except those purged by range deletes, are moved to system.trash.\u003ccf\u003e and purged after the trash 'retention'.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "families"
+                ],
+                "summary": "Update column family settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Column family",
+                        "name": "cf",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "New settings",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.FamilySettings"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.FamilySettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid column family or settings",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/families/{cf}/trash": {
+            "get": {
+                "description": "Lists the documents deleted from a column family with a trash, with their deletion time and actor, ordered by key. Documents stay in the trash until restored or purged after the trash retention.",
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "families"
+                ],
+                "summary": "List deleted documents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Column family",
+                        "name": "cf",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return documents whose key has this prefix",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return documents after this key (for pagination)",
+                        "name": "start_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of documents to return (default: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Whether to fill RocksDB read cache",
+                        "name": "fill_cache",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RocksDB read tier (e.g. 'all', 'cache-only')",
+                        "name": "read_tier",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.trashListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid column family",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/indexes": {
+            "get": {
+                "description": "Retrieves all existing secondary index definitions.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "indexes"
+                ],
+                "summary": "List indexes",
+                "responses": {
+                    "200": {
+                        "description": "List of index definitions",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Defines a new secondary index with projection and condition.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "indexes"
+                ],
+                "summary": "Create index",
+                "parameters": [
+                    {
+                        "description": "Index definition",
+                        "name": "index",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.IndexDefinition"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Index created successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid index definition",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/indexes/{name}": {
+            "get": {
+                "description": "Retrieves the definition of a specific secondary index.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "indexes"
+                ],
+                "summary": "Get index",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Index name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Index definition",
+                        "schema": {
+                            "$ref": "#/definitions/model.IndexDefinition"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid index name",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Index not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a secondary index and its definition from the system.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "indexes"
+                ],
+                "summary": "Delete index",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Index name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Index deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid index name",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "description": "Returns server-level, RocksDB, expiration, transaction, pinned snapshot and job queue metrics including memory usage, uptime, compaction stats, and TTL cleanup activity.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "monitoring"
+                ],
+                "summary": "Retrieve internal metrics",
+                "responses": {
+                    "200": {
+                        "description": "Detailed metrics of the server, database and expiration subsystem",
+                        "schema": {
+                            "$ref": "#/definitions/metrics.FullMetrics"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to collect or serialize metrics",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/queues": {
+            "get": {
+                "description": "Lists the job queues with their settings, message counts and the age of their oldest message.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queues"
+                ],
+                "summary": "List job queues",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/db.QueueInfo"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/queues/{name}": {
+            "get": {
+                "description": "Returns a queue's settings, message counts by state, activity counters and the age of its oldest message.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queues"
+                ],
+                "summary": "Get a job queue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Queue name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.QueueInfo"
+                        }
+                    },
+                    "404": {
+                        "description": "Queue not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Creates a queue or replaces its settings. Empty settings use the server defaults. Changes apply to the following deliveries; messages already received keep their visibility timeout.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queues"
+                ],
+                "summary": "Create or configure a job queue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Queue name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Queue settings",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.QueueSettings"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.QueueInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid name or settings",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a queue and all its messages, including the ones in flight.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queues"
+                ],
+                "summary": "Delete a job queue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Queue name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Queue deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Queue not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/queues/{name}/ack": {
+            "post": {
+                "description": "Removes a received message once its job is done. The receipt must be the one of the message's latest delivery; a message whose visibility timeout ended may have been delivered again.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queues"
+                ],
+                "summary": "Acknowledge a message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Queue name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Message ID and receipt",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.QueueAckRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Message acknowledged",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Queue or message not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Receipt does not match the latest delivery",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/queues/{name}/enqueue": {
+            "post": {
+                "description": "Adds a message to a queue, creating the queue with default settings if it does not exist. A delayed message is not received before its delay passes; among ready messages, higher priorities are received first and equal priorities in enqueue order.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "queues"
+                ],
+                "summary": "Enqueue a message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Queue name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Message",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.EnqueueRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.QueueMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid name, body, delay or priority",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/queues/{name}/nack": {
+            "post": {
+                "description": "Gives a received message back for a retry after ` + "`" + `delay` + "`" + ` or, when omitted, the queue's retry backoff, doubled on each attempt. A message out of attempts moves to the queue's dead-letter queue, or is dropped if it has none.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queues"
+                ],
+                "summary": "Reject a message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Queue name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Message ID, receipt and optional retry delay",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.QueueAckRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Message rejected",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Queue or message not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Receipt does not match the latest delivery",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/queues/{name}/receive": {
+            "post": {
+                "description": "Leases up to ` + "`" + `max` + "`" + ` ready messages, highest priority first. They stay hidden from other receivers until the visibility timeout ends, then are delivered again unless acked. Each message carries the receipt needed to ack or nack it. Returns an empty list when no message is ready.",
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/cbor"
+                ],
+                "tags": [
+                    "queues"
+                ],
+                "summary": "Receive messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Queue name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum messages to receive (default: 1)",
+                        "name": "max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Visibility timeout as a duration (e.g. '30s', '5m') or in seconds (default: the queue's)",
+                        "name": "visibility",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Received messages",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid max or visibility timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Queue not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/snapshots": {
+            "get": {
+                "description": "Lists the snapshots currently pinned and when their leases end.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "snapshots"
+                ],
+                "summary": "List pinned snapshots",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/db.SnapshotInfo"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Pins a consistent view of the database and returns its ID. Pass the ID as 'snapshot' to document, bulk get, list, keys, list range and set contains reads so paginated scans and multi-step reads all see the same data. The snapshot is released with DELETE /snapshots/{id} or when its lease ends. Pinned snapshots keep old data from being compacted, so keep leases short.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "snapshots"
+                ],
+                "summary": "Pin a snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Lease as a duration (e.g. '30s', '5m') or in seconds (default from config)",
+                        "name": "lease",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/db.SnapshotInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid lease",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many open snapshots",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/snapshots/{id}": {
+            "delete": {
+                "description": "Releases a pinned snapshot. Reads already using it complete normally.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "snapshots"
+                ],
+                "summary": "Release a snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Snapshot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Snapshot released",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Snapshot not found or already released",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions": {
+            "post": {
+                "description": "Opens a transaction with a snapshot and returns its ID. Pass the ID as 'txn' to document, counter, list, set and batch endpoints to run them inside the transaction, then commit or roll it back. Documents read or written in the transaction are locked until it ends; locking a document that changed after the transaction began fails with 409. Idle transactions are rolled back automatically.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Begin an interactive transaction",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Optional RocksDB sync write option used at commit",
+                        "name": "sync",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Optional RocksDB disable WAL write option",
+                        "name": "disable_wal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Optional RocksDB no slowdown write option",
+                        "name": "no_slowdown",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/db.TransactionInfo"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many open transactions",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/commit": {
+            "post": {
+                "description": "Commits every change made in the transaction atomically and releases its locks.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Commit an interactive transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "txn",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transaction committed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing transaction ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found or already finished",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Transaction conflict; the transaction was rolled back",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/rollback": {
+            "post": {
+                "description": "Discards every change made in the transaction and releases its locks.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Roll back an interactive transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "txn",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transaction rolled back",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing transaction ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found or already finished",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "config.AppConfig": {
+            "description": "Global server and database configuration.",
+            "type": "object",
+            "properties": {
+                "expiration": {
+                    "$ref": "#/definitions/config.ExpirationConfig"
+                },
+                "queues": {
+                    "$ref": "#/definitions/config.QueuesConfig"
+                },
+                "read_defaults": {
+                    "$ref": "#/definitions/config.ReadOptionsConfig"
+                },
+                "rocksdb": {
+                    "$ref": "#/definitions/config.RocksDBConfig"
+                },
+                "server": {
+                    "$ref": "#/definitions/config.ServerConfig"
+                },
+                "snapshots": {
+                    "$ref": "#/definitions/config.SnapshotsConfig"
+                },
+                "transactions": {
+                    "$ref": "#/definitions/config.TransactionsConfig"
+                },
+                "write_defaults": {
+                    "$ref": "#/definitions/config.WriteOptionsConfig"
+                }
+            }
+        },
+        "config.ExpirationConfig": {
+            "description": "Expiration (TTL) service configuration.",
+            "type": "object",
+            "properties": {
+                "auto_scale": {
+                    "description": "Enable or disable automatic scaling",
+                    "type": "boolean"
+                },
+                "max_per_cycle": {
+                    "description": "Maximum documents processed per cycle",
+                    "type": "integer"
+                },
+                "max_per_cycle_limit": {
+                    "description": "Maximum limit when scaling up",
+                    "type": "integer"
+                },
+                "min_per_cycle": {
+                    "description": "Minimum limit when scaling down",
+                    "type": "integer"
+                },
+                "scale_down_threshold": {
+                    "description": "Scale down if deletions \u003c MaxPerCycle * threshold",
+                    "type": "number"
+                },
+                "scale_step": {
+                    "description": "Step amount to increase/decrease",
+                    "type": "integer"
+                },
+                "scale_up_factor": {
+                    "description": "Scale up if duration \u003c TickInterval * factor",
+                    "type": "number"
+                },
+                "tick_interval": {
+                    "description": "Interval between expiration runs (e.g. \"1m\", \"30s\")",
+                    "type": "string"
+                }
+            }
+        },
+        "config.QueuesConfig": {
+            "description": "Job queue configuration.",
+            "type": "object",
+            "properties": {
+                "default_max_attempts": {
+                    "description": "Deliveries before a message is dead-lettered (0 = no limit)",
+                    "type": "integer"
+                },
+                "default_visibility_timeout": {
+                    "description": "How long received messages stay hidden by default (e.g. \"30s\")",
+                    "type": "string"
+                },
+                "max_delay": {
+                    "description": "Longest delay a message may be enqueued with (e.g. \"24h\")",
+                    "type": "string"
+                },
+                "max_receive": {
+                    "description": "Maximum messages returned by one receive",
+                    "type": "integer"
+                },
+                "max_retry_backoff": {
+                    "description": "Default longest delay between retries (e.g. \"5m\")",
+                    "type": "string"
+                },
+                "max_visibility_timeout": {
+                    "description": "Longest visibility timeout a client may request (e.g. \"12h\")",
+                    "type": "string"
+                },
+                "retry_backoff": {
+                    "description": "Default delay before retrying a nacked message (e.g. \"1s\")",
+                    "type": "string"
+                }
+            }
+        },
+        "config.ReadOptionsConfig": {
+            "description": "Controls how data is read from the database.",
+            "type": "object",
+            "properties": {
+                "fill_cache": {
+                    "description": "Whether to fill cache on reads",
+                    "type": "boolean"
+                },
+                "read_tier": {
+                    "description": "Read level: all or cache-only",
+                    "type": "string"
+                }
+            }
+        },
+        "config.RocksDBConfig": {
+            "description": "Detailed configuration for the RocksDB storage backend.",
+            "type": "object",
+            "properties": {
+                "blob_file_size": {
+                    "description": "Size limit for blob files in bytes",
+                    "type": "integer"
+                },
+                "block_cache_size": {
+                    "description": "Size of the block cache in bytes",
+                    "type": "integer"
+                },
+                "compression_type": {
+                    "description": "Compression type: snappy, zstd, lz4, none",
+                    "type": "string"
+                },
+                "create_if_missing": {
+                    "description": "Whether to create the DB if it doesn't exist",
+                    "type": "boolean"
+                },
+                "db_path": {
+                    "description": "Path to the data directory",
+                    "type": "string"
+                },
+                "document_format": {
+                    "description": "Encoding of stored documents: json or msgpack",
+                    "type": "string"
+                },
+                "enable_blob_files": {
+                    "description": "Store large values in separate blob files (BlobDB)",
+                    "type": "boolean"
+                },
+                "enable_blob_gc": {
+                    "description": "Garbage collect blob files during compaction",
+                    "type": "boolean"
+                },
+                "enable_compression": {
+                    "description": "Whether compression is enabled",
+                    "type": "boolean"
+                },
+                "max_open_files": {
+                    "description": "Maximum number of open files",
+                    "type": "integer"
+                },
+                "max_write_buffer_num": {
+                    "description": "Maximum number of write buffers",
+                    "type": "integer"
+                },
+                "min_blob_size": {
+                    "description": "Values of at least this size in bytes go to blob files",
+                    "type": "integer"
+                },
+                "stats_dump_period": {
+                    "description": "Frequency for dumping RocksDB statistics (e.g. \"30s\", \"1m\")",
+                    "type": "string"
+                },
+                "write_buffer_size": {
+                    "description": "Size of the write buffer in bytes",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.ServerConfig": {
+            "description": "Listening port configuration for the REST API.",
+            "type": "object",
+            "properties": {
+                "max_body_size": {
+                    "description": "Largest request body accepted, in bytes (0 = no limit)",
+                    "type": "integer"
+                },
+                "port": {
+                    "description": "Port where the HTTP server listens",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.SnapshotsConfig": {
+            "description": "Pinned snapshot configuration.",
+            "type": "object",
+            "properties": {
+                "default_lease": {
+                    "description": "Lease of snapshots created without one (e.g. \"1m\")",
+                    "type": "string"
+                },
+                "max_lease": {
+                    "description": "Longest lease a client may request (e.g. \"10m\")",
+                    "type": "string"
+                },
+                "max_open": {
+                    "description": "Maximum number of snapshots pinned at the same time",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.TransactionsConfig": {
+            "description": "Interactive transaction configuration.",
+            "type": "object",
+            "properties": {
+                "idle_timeout": {
+                    "description": "Transactions idle for longer are rolled back (e.g. \"30s\")",
+                    "type": "string"
+                },
+                "lock_timeout": {
+                    "description": "How long a transaction waits for a locked key (e.g. \"1s\")",
+                    "type": "string"
+                },
+                "max_open": {
+                    "description": "Maximum number of concurrently open transactions",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.UpdateResult": {
+            "description": "Outcome when applying changes to the configuration file.",
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "description": "Parameters applied immediately",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rejected": {
+                    "description": "Parameters rejected with reasons",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "requires_restart": {
+                    "description": "Parameters that require a restart",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "config.WriteOptionsConfig": {
+            "description": "Controls the write behavior in RocksDB.",
+            "type": "object",
+            "properties": {
+                "disable_wal": {
+                    "description": "Disable the Write-Ahead Log",
+                    "type": "boolean"
+                },
+                "no_slowdown": {
+                    "description": "Avoid blocking if RocksDB is busy",
+                    "type": "boolean"
+                },
+                "sync": {
+                    "description": "Wait for disk sync on write",
+                    "type": "boolean"
+                }
+            }
+        },
+        "db.BatchResult": {
+            "type": "object",
+            "properties": {
+                "cf": {
+                    "type": "string"
+                },
+                "document": {
+                    "description": "Resulting document for put, insert, replace and touch",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Document"
+                        }
+                    ]
+                },
+                "element": {
+                    "description": "Removed element for list_pop and list_shift"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "new": {
+                    "description": "New counter value for counter_delta"
+                },
+                "old": {
+                    "description": "Previous counter value for counter_delta"
+                },
+                "op": {
+                    "type": "string"
+                }
+            }
+        },
+        "db.CounterResult": {
+            "type": "object",
+            "properties": {
+                "clamped": {
+                    "description": "The value was clamped to the bounds",
+                    "type": "boolean"
+                },
+                "created": {
+                    "description": "The counter did not exist and was created",
+                    "type": "boolean"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "new": {
+                    "description": "Value written"
+                },
+                "old": {
+                    "description": "Previous value, or nil when the counter was created"
+                }
+            }
+        },
+        "db.QueueInfo": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "depth": {
+                    "description": "Messages not yet acked or dead-lettered",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "oldest_age_seconds": {
+                    "description": "Age of the oldest message in the queue",
+                    "type": "number"
+                },
+                "settings": {
+                    "$ref": "#/definitions/model.QueueSettings"
+                },
+                "stats": {
+                    "$ref": "#/definitions/model.QueueStats"
+                }
+            }
+        },
+        "db.SnapshotInfo": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "When the snapshot is released unless released earlier",
+                    "type": "string"
+                },
+                "snapshot": {
+                    "type": "string"
+                }
+            }
+        },
+        "db.StreamGroup": {
+            "type": "object",
+            "properties": {
+                "consumers": {
+                    "description": "Pending entries of each consumer that read from the group",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "last_delivered_id": {
+                    "description": "Entries after this ID are the next delivered to the group",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pending": {
+                    "description": "Entries delivered and not yet acknowledged",
+                    "type": "integer"
+                }
+            }
+        },
+        "db.StreamInfo": {
+            "type": "object",
+            "properties": {
+                "first_id": {
+                    "description": "ID of the oldest entry kept",
+                    "type": "string"
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.StreamGroup"
+                    }
+                },
+                "last_id": {
+                    "description": "ID of the last entry appended, even if trimmed since",
+                    "type": "string"
+                },
+                "length": {
+                    "type": "integer"
+                }
+            }
+        },
+        "db.TimeSeriesInfo": {
+            "type": "object",
+            "properties": {
+                "first_timestamp": {
+                    "description": "Timestamp of the oldest sample",
+                    "type": "integer"
+                },
+                "last_timestamp": {
+                    "description": "Timestamp of the newest sample",
+                    "type": "integer"
+                },
+                "length": {
+                    "type": "integer"
+                },
+                "settings": {
+                    "$ref": "#/definitions/model.TimeSeriesSettings"
+                }
+            }
+        },
+        "db.TransactionInfo": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "When the transaction is rolled back unless used again",
+                    "type": "string"
+                },
+                "txn": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.BulkPutRequestEntry": {
+            "type": "object",
+            "properties": {
+                "cas": {
+                    "description": "Optional CAS (revision) the existing document must have.",
+                    "type": "string"
+                },
+                "expiration": {
+                    "description": "Optional expiration for this document, overriding the 'expiration' parameter.\nTTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d).",
+                    "type": "integer"
+                },
+                "key": {
+                    "description": "Document key. Required in the array form; ignored in the map form.",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Optional document type (e.g., \"json\", \"counter\", \"list\", \"set\").\nIf omitted, \"json\" is assumed.",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "The value to store for the document.\nCan be a string, number, object, array, etc."
+                }
+            }
+        },
+        "handlers.EnqueueRequest": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "description": "Message payload"
+                },
+                "delay": {
+                    "description": "Time before the message can be received, as a duration or in seconds",
+                    "type": "string",
+                    "example": "5s"
+                },
+                "priority": {
+                    "description": "0 to 255; higher priorities are received first",
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "handlers.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.HDelRequest": {
+            "description": "Names of the fields to remove.",
+            "type": "object",
+            "properties": {
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.HIncrByRequest": {
+            "description": "Field and the amount to add to it.",
+            "type": "object",
+            "properties": {
+                "delta": {
+                    "type": "integer"
+                },
+                "field": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.HSetRequest": {
+            "description": "Fields to set, with their values.",
+            "type": "object",
+            "properties": {
+                "fields": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "handlers.MultiGetResponse": {
+            "description": "Map of keys to documents or null for missing entries.",
+            "type": "object",
+            "additionalProperties": {
+                "$ref": "#/definitions/model.Document"
+            }
+        },
+        "handlers.QueueAckRequest": {
+            "type": "object",
+            "properties": {
+                "delay": {
+                    "description": "Nack only: retry delay overriding the queue's backoff",
+                    "type": "string",
+                    "example": "10s"
+                },
+                "id": {
+                    "description": "Message ID",
+                    "type": "string",
+                    "example": "42"
+                },
+                "receipt": {
+                    "description": "Receipt of the delivery being acknowledged",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.SetAlgebraRequest": {
+            "description": "Request body listing the sets to combine.",
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "description": "Set documents to combine, in order",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "store": {
+                    "description": "Set document to replace with the result",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.SetElementRequest": {
+            "description": "Request body containing the element to operate with.",
+            "type": "object",
+            "properties": {
+                "element": {}
+            }
+        },
+        "handlers.StreamAckRequest": {
+            "description": "IDs of the entries to acknowledge.",
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.StreamAppendRequest": {
+            "description": "Value to append and, optionally, its ID.",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Entry ID as \"\u003cms\u003e-\u003cseq\u003e\", or \"\u003cms\u003e-*\" to generate the sequence number (default: generated from the clock)",
+                    "type": "string",
+                    "example": "*"
+                },
+                "value": {}
+            }
+        },
+        "handlers.TimeSeriesAddRequest": {
+            "description": "A single sample, or several in samples.",
+            "type": "object",
+            "properties": {
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "samples": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.TimeSeriesSampleRequest"
+                    }
+                },
+                "timestamp": {
+                    "description": "Unix time in milliseconds (default: now)",
+                    "type": "integer",
+                    "example": 1718035200000
+                },
+                "value": {
+                    "type": "number",
+                    "example": 21.5
+                }
+            }
+        },
+        "handlers.TimeSeriesSampleRequest": {
+            "description": "Sample value with an optional timestamp and labels.",
+            "type": "object",
+            "properties": {
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "timestamp": {
+                    "description": "Unix time in milliseconds (default: now)",
+                    "type": "integer",
+                    "example": 1718035200000
+                },
+                "value": {
+                    "type": "number",
+                    "example": 21.5
+                }
+            }
+        },
+        "handlers.ZAddRequest": {
+            "description": "Members with their scores, and flags controlling which members are changed.",
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ZSetEntry"
+                    }
+                },
+                "gt": {
+                    "description": "Only update when the new score is greater",
+                    "type": "boolean"
+                },
+                "lt": {
+                    "description": "Only update when the new score is lower",
+                    "type": "boolean"
+                },
+                "nx": {
+                    "description": "Only add new members",
+                    "type": "boolean"
+                },
+                "xx": {
+                    "description": "Only update existing members",
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.ZIncrByRequest": {
+            "description": "Member and the amount to add to its score.",
+            "type": "object",
+            "properties": {
+                "increment": {
+                    "type": "number"
+                },
+                "member": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ZRemRequest": {
+            "description": "Members to remove.",
+            "type": "object",
+            "properties": {
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.batchErrorResponse": {
             "type": "object",
             "properties": {
-                "expiration": {
-                    "$ref": "#/definitions/config.ExpirationConfig"
-                },
-                "read_defaults": {
-                    "$ref": "#/definitions/config.ReadOptionsConfig"
-                },
-                "rocksdb": {
-                    "$ref": "#/definitions/config.RocksDBConfig"
+                "error": {
+                    "type": "string"
                 },
-                "server": {
-                    "$ref": "#/definitions/config.ServerConfig"
+                "index": {
+                    "description": "Position of the failing operation",
+                    "type": "integer"
                 },
-                "write_defaults": {
-                    "$ref": "#/definitions/config.WriteOptionsConfig"
+                "op": {
+                    "description": "Name of the failing operation",
+                    "type": "string"
                 }
             }
         },
-        "config.ExpirationConfig": {
-            "description": "Expiration (TTL) service configuration.",
+        "handlers.batchOperationRequest": {
             "type": "object",
             "properties": {
-                "auto_scale": {
-                    "description": "Enable or disable automatic scaling",
-                    "type": "boolean"
-                },
-                "max_per_cycle": {
-                    "description": "Maximum documents processed per cycle",
-                    "type": "integer"
+                "cas": {
+                    "description": "Optional CAS (revision) the document must have",
+                    "type": "string"
                 },
-                "max_per_cycle_limit": {
-                    "description": "Maximum limit when scaling up",
-                    "type": "integer"
+                "cf": {
+                    "description": "Column family (default: 'default')",
+                    "type": "string"
                 },
-                "min_per_cycle": {
-                    "description": "Minimum limit when scaling down",
+                "delta": {
+                    "description": "Counter delta for counter_delta",
                     "type": "integer"
                 },
-                "scale_down_threshold": {
-                    "description": "Scale down if deletions \u003c MaxPerCycle * threshold",
-                    "type": "number"
+                "element": {
+                    "description": "Element for list and set operations"
                 },
-                "scale_step": {
-                    "description": "Step amount to increase/decrease",
+                "expiration": {
+                    "description": "Optional expiration. TTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d)",
                     "type": "integer"
                 },
-                "scale_up_factor": {
-                    "description": "Scale up if duration \u003c TickInterval * factor",
-                    "type": "number"
+                "key": {
+                    "description": "Document key",
+                    "type": "string"
                 },
-                "tick_interval": {
-                    "description": "Interval between expiration runs (e.g. \"1m\", \"30s\")",
+                "op": {
+                    "description": "Operation: put, insert, replace, delete, touch, counter_delta, list_push, list_unshift, list_pop, list_shift, set_add, set_remove",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Document type for put, insert and replace (default: 'json')",
                     "type": "string"
+                },
+                "value": {
+                    "description": "Document value for put, insert and replace"
                 }
             }
         },
-        "config.ReadOptionsConfig": {
-            "description": "Controls how data is read from the database.",
+        "handlers.batchRequest": {
             "type": "object",
             "properties": {
-                "fill_cache": {
-                    "description": "Whether to fill cache on reads",
-                    "type": "boolean"
-                },
-                "read_tier": {
-                    "description": "Read level: all or cache-only",
-                    "type": "string"
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.batchOperationRequest"
+                    }
                 }
             }
         },
-        "config.RocksDBConfig": {
-            "description": "Detailed configuration for the RocksDB storage backend.",
+        "handlers.batchResponse": {
             "type": "object",
             "properties": {
-                "block_cache_size": {
-                    "description": "Size of the block cache in bytes",
-                    "type": "integer"
-                },
-                "compression_type": {
-                    "description": "Compression type: snappy, zstd, lz4, none",
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.BatchResult"
+                    }
+                }
+            }
+        },
+        "handlers.bulkDeleteRangeRequest": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "description": "Key where deletion stops (exclusive); requires start",
                     "type": "string"
                 },
-                "create_if_missing": {
-                    "description": "Whether to create the DB if it doesn't exist",
-                    "type": "boolean"
-                },
-                "db_path": {
-                    "description": "Path to the data directory",
+                "prefix": {
+                    "description": "Delete every key starting with this prefix",
                     "type": "string"
                 },
-                "enable_compression": {
-                    "description": "Whether compression is enabled",
-                    "type": "boolean"
+                "start": {
+                    "description": "First key to delete (inclusive); requires end",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.bulkDeleteRangeResponse": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string"
                 },
-                "max_open_files": {
-                    "description": "Maximum number of open files",
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.bulkDeleteResponse": {
+            "type": "object",
+            "properties": {
+                "deleted": {
                     "type": "integer"
+                }
+            }
+        },
+        "handlers.bulkKeyEntry": {
+            "type": "object",
+            "properties": {
+                "cas": {
+                    "description": "Optional CAS (revision) the document must have",
+                    "type": "string"
                 },
-                "max_write_buffer_num": {
-                    "description": "Maximum number of write buffers",
+                "expiration": {
+                    "description": "Bulk touch only: expiration for this key, overriding the 'expiration' parameter.\nTTL in seconds (\u003c= 30d) or absolute Unix timestamp (\u003e 30d)",
                     "type": "integer"
                 },
-                "stats_dump_period": {
-                    "description": "Frequency for dumping RocksDB statistics (e.g. \"30s\", \"1m\")",
+                "key": {
+                    "description": "Document key",
                     "type": "string"
-                },
-                "write_buffer_size": {
-                    "description": "Size of the write buffer in bytes",
-                    "type": "integer"
                 }
             }
         },
-        "config.ServerConfig": {
-            "description": "Listening port configuration for the REST API.",
+        "handlers.bulkKeysRequest": {
             "type": "object",
             "properties": {
-                "port": {
-                    "description": "Port where the HTTP server listens",
-                    "type": "integer"
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.bulkKeyEntry"
+                    }
                 }
             }
         },
-        "config.UpdateResult": {
-            "description": "Outcome when applying changes to the configuration file.",
+        "handlers.bulkPutResponse": {
             "type": "object",
             "properties": {
-                "applied": {
-                    "description": "Parameters applied immediately",
+                "results": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/handlers.bulkPutResult"
                     }
+                }
+            }
+        },
+        "handlers.bulkPutResult": {
+            "type": "object",
+            "properties": {
+                "document": {
+                    "description": "Stored document on success",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Document"
+                        }
+                    ]
                 },
-                "rejected": {
-                    "description": "Parameters rejected with reasons",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
+                "error": {
+                    "description": "Failure reason in partial mode",
+                    "type": "string"
                 },
-                "requires_restart": {
-                    "description": "Parameters that require a restart",
+                "key": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "HTTP status of this entry",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.createFamilyRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "logs"
+                },
+                "settings": {
+                    "$ref": "#/definitions/model.FamilySettings"
+                }
+            }
+        },
+        "handlers.documentHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "revisions": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/model.DocumentRevision"
                     }
                 }
             }
         },
-        "config.WriteOptionsConfig": {
-            "description": "Controls the write behavior in RocksDB.",
+        "handlers.getSetCounterRequest": {
             "type": "object",
             "properties": {
-                "disable_wal": {
-                    "description": "Disable the Write-Ahead Log",
+                "value": {
+                    "description": "New value of the counter (default: 0)",
+                    "type": "number",
+                    "example": 0
+                }
+            }
+        },
+        "handlers.incrementRequest": {
+            "type": "object",
+            "properties": {
+                "clamp": {
+                    "description": "Clamp the value to the bounds instead of failing with 409",
                     "type": "boolean"
                 },
-                "no_slowdown": {
-                    "description": "Avoid blocking if RocksDB is busy",
-                    "type": "boolean"
+                "delta": {
+                    "description": "The amount to increment (positive) or decrement (negative) the counter.",
+                    "type": "number",
+                    "example": 5
                 },
-                "sync": {
-                    "description": "Wait for disk sync on write",
-                    "type": "boolean"
+                "initial": {
+                    "description": "Value of the counter created when it does not exist. Omit to fail with 404 instead.",
+                    "type": "number",
+                    "example": 0
+                },
+                "max": {
+                    "description": "Highest value allowed",
+                    "type": "number"
+                },
+                "min": {
+                    "description": "Lowest value allowed",
+                    "type": "number"
                 }
             }
         },
-        "handlers.BulkPutRequestEntry": {
+        "handlers.listElementRequest": {
             "type": "object",
             "properties": {
-                "type": {
-                    "description": "Optional document type (e.g., \"json\", \"counter\", \"list\", \"set\").\nIf omitted, \"json\" is assumed.",
-                    "type": "string"
+                "element": {
+                    "description": "Element to add to the list (can be string, number, object, etc.)"
                 },
-                "value": {
-                    "description": "The value to store for the document.\nCan be a string, number, object, array, etc."
+                "elements": {
+                    "description": "Elements to add in order, instead of a single element",
+                    "type": "array",
+                    "items": {}
                 }
             }
         },
-        "handlers.ErrorResponse": {
+        "handlers.listInsertRequest": {
             "type": "object",
             "properties": {
-                "error": {
+                "element": {},
+                "pivot": {},
+                "position": {
+                    "description": "\"before\" (default) or \"after\"",
                     "type": "string"
                 }
             }
         },
-        "handlers.MultiGetResponse": {
-            "description": "Map of keys to documents or null for missing entries.",
-            "type": "object",
-            "additionalProperties": {
-                "$ref": "#/definitions/model.Document"
-            }
-        },
-        "handlers.SetElementRequest": {
-            "description": "Request body containing the element to operate with.",
+        "handlers.listRemoveRequest": {
             "type": "object",
             "properties": {
+                "count": {
+                    "description": "\u003e0 from the head, \u003c0 from the tail, 0 removes every match",
+                    "type": "integer"
+                },
                 "element": {}
             }
         },
-        "handlers.createFamilyRequest": {
+        "handlers.multiGetRequest": {
             "type": "object",
             "properties": {
-                "name": {
-                    "type": "string",
-                    "example": "logs"
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "handlers.incrementRequest": {
+        "handlers.multiIncrementEntry": {
             "type": "object",
             "properties": {
+                "cas": {
+                    "description": "Optional CAS (revision) the counter must have",
+                    "type": "string"
+                },
+                "clamp": {
+                    "description": "Clamp the value to the bounds instead of failing with 409",
+                    "type": "boolean"
+                },
                 "delta": {
                     "description": "The amount to increment (positive) or decrement (negative) the counter.",
-                    "type": "integer"
+                    "type": "number",
+                    "example": 5
+                },
+                "initial": {
+                    "description": "Value of the counter created when it does not exist. Omit to fail with 404 instead.",
+                    "type": "number",
+                    "example": 0
+                },
+                "key": {
+                    "description": "Counter key",
+                    "type": "string"
+                },
+                "max": {
+                    "description": "Highest value allowed",
+                    "type": "number"
+                },
+                "min": {
+                    "description": "Lowest value allowed",
+                    "type": "number"
                 }
             }
         },
-        "handlers.incrementResponse": {
+        "handlers.multiIncrementRequest": {
             "type": "object",
             "properties": {
-                "new": {
-                    "description": "New value",
-                    "type": "integer"
-                },
-                "old": {
-                    "description": "Previous value",
-                    "type": "integer"
+                "counters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.multiIncrementEntry"
+                    }
                 }
             }
         },
-        "handlers.listElementRequest": {
+        "handlers.mutateRequest": {
             "type": "object",
             "properties": {
-                "element": {
-                    "description": "Element to add to the list (can be string, number, object, etc.)"
+                "operations": {
+                    "description": "Operations applied in order; all succeed or none is applied.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.MutationOp"
+                    }
                 }
             }
         },
-        "handlers.multiGetRequest": {
+        "handlers.trashListResponse": {
             "type": "object",
             "properties": {
-                "keys": {
+                "cf": {
+                    "type": "string"
+                },
+                "documents": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/model.TrashedDocument"
                     }
                 }
             }
@@ -2085,12 +9235,50 @@ const docTemplate = `{
                 "expiration": {
                     "$ref": "#/definitions/metrics.ExpirationMetrics"
                 },
+                "job_queues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/metrics.JobQueueMetrics"
+                    }
+                },
                 "rocksdb": {
                     "type": "object",
                     "additionalProperties": {}
                 },
                 "server": {
                     "$ref": "#/definitions/metrics.ServerMetrics"
+                },
+                "snapshots": {
+                    "$ref": "#/definitions/metrics.SnapshotMetrics"
+                },
+                "transactions": {
+                    "$ref": "#/definitions/metrics.TransactionMetrics"
+                }
+            }
+        },
+        "metrics.JobQueueMetrics": {
+            "type": "object",
+            "properties": {
+                "dead_lettered": {
+                    "type": "integer"
+                },
+                "delayed": {
+                    "type": "integer"
+                },
+                "depth": {
+                    "type": "integer"
+                },
+                "in_flight": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "oldest_age_seconds": {
+                    "type": "number"
+                },
+                "ready": {
+                    "type": "integer"
                 }
             }
         },
@@ -2143,6 +9331,49 @@ const docTemplate = `{
                 }
             }
         },
+        "metrics.SnapshotMetrics": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "expired": {
+                    "type": "integer"
+                },
+                "max_open": {
+                    "type": "integer"
+                },
+                "oldest_age_seconds": {
+                    "type": "number"
+                },
+                "open": {
+                    "type": "integer"
+                },
+                "released": {
+                    "type": "integer"
+                }
+            }
+        },
+        "metrics.TransactionMetrics": {
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "type": "integer"
+                },
+                "expired": {
+                    "type": "integer"
+                },
+                "max_open": {
+                    "type": "integer"
+                },
+                "open": {
+                    "type": "integer"
+                },
+                "rolled_back": {
+                    "type": "integer"
+                }
+            }
+        },
         "model.Document": {
             "type": "object",
             "properties": {
@@ -2163,6 +9394,95 @@ const docTemplate = `{
                 }
             }
         },
+        "model.DocumentRevision": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "description": "The revision records a deletion",
+                    "type": "boolean"
+                },
+                "document": {
+                    "description": "The stored version, when values are requested",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Document"
+                        }
+                    ]
+                },
+                "hlc": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "rev": {
+                    "type": "string"
+                },
+                "seq": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "description": "Physical time of the HLC",
+                    "type": "string"
+                }
+            }
+        },
+        "model.ElementsHeader": {
+            "type": "object",
+            "properties": {
+                "gen": {
+                    "description": "Namespace of the element keys, unique per conversion",
+                    "type": "integer"
+                },
+                "head": {
+                    "description": "Index of the first list element",
+                    "type": "integer"
+                },
+                "kind": {
+                    "description": "ElementsList, ElementsSet, ElementsZSet, ElementsStream or ElementsTimeSeries",
+                    "type": "string"
+                },
+                "last_id": {
+                    "description": "ID of the last entry appended to a stream, kept when it is trimmed",
+                    "type": "string"
+                },
+                "len": {
+                    "description": "Number of elements",
+                    "type": "integer"
+                },
+                "tail": {
+                    "description": "Index after the last list element",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.FamilySettings": {
+            "type": "object",
+            "properties": {
+                "history": {
+                    "$ref": "#/definitions/model.HistorySettings"
+                },
+                "trash": {
+                    "$ref": "#/definitions/model.TrashSettings"
+                }
+            }
+        },
+        "model.HistorySettings": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "max_revisions": {
+                    "description": "Revisions kept per document (0 = no limit)",
+                    "type": "integer",
+                    "example": 10
+                },
+                "retention": {
+                    "description": "How long revisions are kept, as a Go duration (empty = forever)",
+                    "type": "string",
+                    "example": "720h"
+                }
+            }
+        },
         "model.IndexDefinition": {
             "type": "object",
             "properties": {
@@ -2198,16 +9518,45 @@ const docTemplate = `{
         "model.Metadata": {
             "type": "object",
             "properties": {
+                "checksum": {
+                    "description": "Hex encoded SHA-256 of blob content",
+                    "type": "string"
+                },
+                "content_type": {
+                    "description": "Media type of blob documents",
+                    "type": "string"
+                },
+                "elements": {
+                    "description": "Set when the value is stored as one key per element",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ElementsHeader"
+                        }
+                    ]
+                },
                 "expiration": {
                     "description": "TTL as Unix timestamp (0 = never)",
                     "type": "integer"
                 },
+                "hlc": {
+                    "description": "Hybrid logical clock timestamp of the last write",
+                    "type": "string",
+                    "example": "0"
+                },
                 "rev": {
-                    "description": "Revision ID for conflict resolution",
+                    "description": "Revision ID for conflict resolution: \"\u003cseq\u003e-\u003chlc hex\u003e\"",
                     "type": "string"
                 },
+                "seq": {
+                    "description": "Per-document revision counter, incremented on every write",
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "Blob content length in bytes",
+                    "type": "integer"
+                },
                 "type": {
-                    "description": "Document type (json, counter, list, set)",
+                    "description": "Document type (json, counter, list, set, zset, hash, blob, stream, timeseries)",
                     "type": "string"
                 },
                 "updated_at": {
@@ -2215,6 +9564,206 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "model.MutationOp": {
+            "type": "object",
+            "properties": {
+                "op": {
+                    "description": "Operation name (set, insert, replace, remove, array_append, array_insert, increment)",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Target path, e.g. \"profile.address.city\" or \"items[3]\"",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Operand; the delta for increment. May be null"
+                }
+            }
+        },
+        "model.QueueMessage": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Deliveries so far",
+                    "type": "integer"
+                },
+                "body": {
+                    "description": "Payload given on enqueue"
+                },
+                "dead_lettered_from": {
+                    "description": "Queue the message ran out of attempts in",
+                    "type": "string"
+                },
+                "enqueued_at": {
+                    "description": "When the message was enqueued",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Sequence number within the queue",
+                    "type": "string",
+                    "example": "0"
+                },
+                "priority": {
+                    "description": "Higher priorities are received first",
+                    "type": "integer"
+                },
+                "receipt": {
+                    "description": "Lease of the current delivery, required to ack or nack it",
+                    "type": "string"
+                },
+                "visible_at": {
+                    "description": "When the message can next be received",
+                    "type": "string"
+                }
+            }
+        },
+        "model.QueueSettings": {
+            "type": "object",
+            "properties": {
+                "dead_letter_queue": {
+                    "description": "Queue receiving messages that ran out of attempts (empty = drop them)",
+                    "type": "string",
+                    "example": "jobs-dlq"
+                },
+                "max_attempts": {
+                    "description": "Deliveries before a message is dead-lettered (0 = server default)",
+                    "type": "integer",
+                    "example": 5
+                },
+                "max_retry_backoff": {
+                    "description": "Longest delay between retries",
+                    "type": "string",
+                    "example": "5m"
+                },
+                "retry_backoff": {
+                    "description": "Delay before a nacked message is retried, doubled on each further attempt",
+                    "type": "string",
+                    "example": "1s"
+                },
+                "visibility_timeout": {
+                    "description": "How long a received message stays hidden before it is delivered again, as a Go duration",
+                    "type": "string",
+                    "example": "30s"
+                }
+            }
+        },
+        "model.QueueStats": {
+            "type": "object",
+            "properties": {
+                "acked": {
+                    "description": "Messages acknowledged",
+                    "type": "integer"
+                },
+                "dead_lettered": {
+                    "description": "Messages that ran out of attempts",
+                    "type": "integer"
+                },
+                "delayed": {
+                    "description": "Waiting for their delay or retry backoff to pass",
+                    "type": "integer"
+                },
+                "enqueued": {
+                    "description": "Messages enqueued",
+                    "type": "integer"
+                },
+                "in_flight": {
+                    "description": "Received and not yet acked, nacked or timed out",
+                    "type": "integer"
+                },
+                "ready": {
+                    "description": "Waiting to be received",
+                    "type": "integer"
+                },
+                "received": {
+                    "description": "Deliveries",
+                    "type": "integer"
+                },
+                "retried": {
+                    "description": "Deliveries nacked or timed out and scheduled again",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TimeSeriesRule": {
+            "type": "object",
+            "properties": {
+                "aggregation": {
+                    "description": "min, max, avg, sum or count",
+                    "type": "string",
+                    "example": "avg"
+                },
+                "bucket": {
+                    "description": "Bucket duration, as a Go duration",
+                    "type": "string",
+                    "example": "1h"
+                },
+                "destination": {
+                    "description": "Key of the downsampled series, in the same column family",
+                    "type": "string",
+                    "example": "cpu:1h"
+                }
+            }
+        },
+        "model.TimeSeriesSettings": {
+            "type": "object",
+            "properties": {
+                "retention": {
+                    "description": "Samples older than this relative to the newest sample are dropped, as a Go duration",
+                    "type": "string",
+                    "example": "720h"
+                },
+                "rules": {
+                    "description": "Compaction rules applied to every sample added",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TimeSeriesRule"
+                    }
+                }
+            }
+        },
+        "model.TrashSettings": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "retention": {
+                    "description": "How long deleted documents are kept, as a Go duration (empty = forever)",
+                    "type": "string",
+                    "example": "720h"
+                }
+            }
+        },
+        "model.TrashedDocument": {
+            "type": "object",
+            "properties": {
+                "deleted_at": {
+                    "type": "string"
+                },
+                "deleted_by": {
+                    "description": "Actor given with the delete request",
+                    "type": "string"
+                },
+                "document": {
+                    "$ref": "#/definitions/model.Document"
+                },
+                "purge_at": {
+                    "description": "Unix time the document is purged (0 = never)",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ZSetEntry": {
+            "type": "object",
+            "properties": {
+                "member": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                }
+            }
         }
     }
 }`
@@ -35,13 +35,8 @@ func StartFanout(queue QueueReader) {
 				}
 
 				matched = true
-				payload, err := json.Marshal(event)
-				if err != nil {
-					log.Printf("[fanout] ❌ serialize error for listener %s: %v", listener.Name, err)
-					continue
-				}
-
-				if err := listener.Queue.Enqueue(payload); err != nil {
+				// Forward the original payload so document values are not re-encoded lossily.
+				if err := listener.Queue.Enqueue(val); err != nil {
 					log.Printf("[fanout] ❌ enqueue error for listener %s: %v", listener.Name, err)
 					continue
 				}
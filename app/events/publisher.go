@@ -11,12 +11,18 @@ import (
 )
 
 const (
-	OpPut     = "put"
-	OpDelete  = "delete"
-	OpMutate  = "mutate"
-	OpReplace = "replace"
-	OpInsert  = "insert"
-	OpTouch   = "touch"
+	OpPut      = "put"
+	OpDelete   = "delete"
+	OpMutate   = "mutate"
+	OpReplace  = "replace"
+	OpInsert   = "insert"
+	OpTouch    = "touch"
+	OpPatch    = "patch"
+	OpRevert   = "revert"
+	OpUndelete = "undelete"
+
+	// OpDeleteRange removes every key from Key (inclusive) to RangeEnd (exclusive).
+	OpDeleteRange = "delete_range"
 )
 
 const EventQueueCF = "system.eventqueue"
@@ -39,6 +45,11 @@ type DocumentChangeEvent struct {
 	Document           *model.Document `json:"document,omitempty"`
 	PreviousMeta       *model.Metadata `json:"previous_meta,omitempty"`
 	ExplicitExpiration *int64          `json:"explicit_expiration,omitempty"`
+	PatchType          string          `json:"patch_type,omitempty"`
+	Patch              json.RawMessage `json:"patch,omitempty"`
+	RangeEnd           string          `json:"range_end,omitempty"`
+	Seq                uint64          `json:"seq,omitempty"`        // Document sequence number after the change
+	HLC                model.HLC       `json:"hlc,string,omitempty"` // HLC timestamp of the change
 }
 
 type ChangeEventOptions struct {
@@ -49,6 +60,11 @@ type ChangeEventOptions struct {
 	Operation          string
 	PreviousMeta       *model.Metadata
 	ExplicitExpiration *int64
+	PatchType          string          // Patch format for OpPatch events (merge or json)
+	Patch              json.RawMessage // Original patch document for OpPatch events
+	RangeEnd           string          // Exclusive end key for OpDeleteRange events
+	Seq                uint64          // Document sequence number after the change
+	HLC                model.HLC       // HLC timestamp of the change
 }
 
 func PublishChangeEvent(opts ChangeEventOptions) error {
@@ -64,6 +80,11 @@ func PublishChangeEvent(opts ChangeEventOptions) error {
 		Document:           opts.Document,
 		PreviousMeta:       opts.PreviousMeta,
 		ExplicitExpiration: opts.ExplicitExpiration,
+		PatchType:          opts.PatchType,
+		Patch:              opts.Patch,
+		RangeEnd:           opts.RangeEnd,
+		Seq:                opts.Seq,
+		HLC:                opts.HLC,
 	}
 
 	data, err := json.Marshal(event)
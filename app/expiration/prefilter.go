@@ -4,7 +4,7 @@ import "mithrildb/events"
 
 func ShouldProcessTTL(event events.DocumentChangeEvent) bool {
 	// Always process deletes to clean up TTL index
-	if event.Operation == events.OpDelete {
+	if event.Operation == events.OpDelete || event.Operation == events.OpDeleteRange {
 		return true
 	}
 
@@ -48,6 +48,11 @@ func (l *Listener) Start() {
 					log.Printf("[ttl] ⚠️ failed to clear TTL for deleted key %s:%s: %v", evt.CF, evt.Key, err)
 				}
 
+			case evt.Operation == events.OpDeleteRange:
+				if err := l.DB.ClearTTLRange(evt.CF, evt.Key, evt.RangeEnd); err != nil {
+					log.Printf("[ttl] ⚠️ failed to clear TTL for deleted range %s:[%s, %s): %v", evt.CF, evt.Key, evt.RangeEnd, err)
+				}
+
 			case evt.Document != nil && evt.Document.Meta.Expiration > 0:
 				if err := l.DB.ReplaceTTL(evt.CF, evt.Key, evt.Document.Meta.Expiration); err != nil {
 					log.Printf("[ttl] ⚠️ failed to update TTL for key %s:%s: %v", evt.CF, evt.Key, err)
@@ -2,7 +2,10 @@ module mithrildb
 
 go 1.18
 
-require gopkg.in/ini.v1 v1.67.0
+require (
+	github.com/swaggo/swag v1.8.1
+	gopkg.in/ini.v1 v1.67.0
+)
 
 require (
 	github.com/KyleBanks/depth v1.2.1 // indirect
@@ -13,16 +16,20 @@ require (
 	github.com/josharian/intern v1.0.0 // indirect
 	github.com/mailru/easyjson v0.7.6 // indirect
 	github.com/swaggo/files v0.0.0-20220610200504-28940afbdbfe // indirect
-	github.com/swaggo/swag v1.8.1 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
 	golang.org/x/net v0.7.0 // indirect
 	golang.org/x/sys v0.5.0 // indirect
+	golang.org/x/text v0.7.0 // indirect
 	golang.org/x/tools v0.1.12 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
 
 require (
+	github.com/fxamacker/cbor/v2 v2.7.0
 	github.com/google/uuid v1.6.0
 	github.com/gorilla/mux v1.8.1
-	github.com/linxGnu/grocksdb v1.9.9 // indirect
+	github.com/linxGnu/grocksdb v1.9.9
 	github.com/swaggo/http-swagger v1.3.4
+	github.com/vmihailenco/msgpack/v5 v5.3.5
 )
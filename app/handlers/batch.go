@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"mithrildb/config"
+	"mithrildb/db"
+	"mithrildb/model"
+	"net/http"
+)
+
+// maxBatchOperations limits the number of operations accepted in a single batch.
+const maxBatchOperations = 1000
+
+// batchOperationRequest is a single operation in a batch request.
+type batchOperationRequest struct {
+	// Operation: put, insert, replace, delete, touch, counter_delta, list_push, list_unshift, list_pop, list_shift, set_add, set_remove
+	Op string `json:"op"`
+	// Column family (default: 'default')
+	CF string `json:"cf,omitempty"`
+	// Document key
+	Key string `json:"key"`
+	// Document value for put, insert and replace
+	Value interface{} `json:"value,omitempty"`
+	// Document type for put, insert and replace (default: 'json')
+	Type string `json:"type,omitempty"`
+	// Optional CAS (revision) the document must have
+	Cas string `json:"cas,omitempty"`
+	// Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d)
+	Expiration *int64 `json:"expiration,omitempty"`
+	// Counter delta for counter_delta
+	Delta int64 `json:"delta,omitempty"`
+	// Element for list and set operations
+	Element interface{} `json:"element,omitempty"`
+}
+
+// batchRequest represents the body of an atomic batch request.
+//
+// Example:
+//
+//	{"operations": [
+//	  {"op": "counter_delta", "cf": "accounts", "key": "alice", "delta": -100},
+//	  {"op": "counter_delta", "cf": "accounts", "key": "bob", "delta": 100},
+//	  {"op": "put", "cf": "ledger", "key": "tx-42", "value": {"from": "alice", "to": "bob", "amount": 100}}
+//	]}
+type batchRequest struct {
+	Operations []batchOperationRequest `json:"operations"`
+}
+
+// batchResponse lists the result of every operation, in request order.
+type batchResponse struct {
+	Results []db.BatchResult `json:"results"`
+}
+
+// batchErrorResponse reports which operation aborted the batch.
+type batchErrorResponse struct {
+	Error string `json:"error"`
+	Index int    `json:"index"` // Position of the failing operation
+	Op    string `json:"op"`    // Name of the failing operation
+}
+
+// batchHandler handles POST /batch
+//
+// @Summary      Execute an atomic batch
+// @Description  Executes an ordered list of heterogeneous operations (put, insert, replace, delete, touch, counter_delta, list_push, list_unshift, list_pop, list_shift, set_add, set_remove) across user column families in a single transaction. Either all operations are applied or none is.
+// @Tags         batch
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        sync         query  bool  false  "Write option: sync"
+// @Param        disable_wal  query  bool  false  "Write option: disable WAL"
+// @Param        no_slowdown  query  bool  false  "Write option: no slowdown"
+// @Param        body  body      batchRequest  true  "Operations to execute"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  batchResponse
+// @Failure      400   {object}  batchErrorResponse  "Invalid operation"
+// @Failure      404   {object}  batchErrorResponse  "Document not found"
+// @Failure      409   {object}  batchErrorResponse  "Key already exists or counter overflow"
+// @Failure      412   {object}  batchErrorResponse  "CAS mismatch"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /batch [post]
+func batchHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req batchRequest
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+		if len(req.Operations) == 0 {
+			respondWithError(w, http.StatusBadRequest, "'operations' must contain at least one operation")
+			return
+		}
+		if len(req.Operations) > maxBatchOperations {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("a batch accepts at most %d operations", maxBatchOperations))
+			return
+		}
+
+		ops := make([]db.BatchOperation, len(req.Operations))
+		for i, op := range req.Operations {
+			operation, err := buildBatchOperation(op)
+			if err != nil {
+				status, msg := mapErrorToResponse(err)
+				respondWithJSON(w, status, batchErrorResponse{Error: msg, Index: i, Op: op.Op})
+				return
+			}
+			ops[i] = operation
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		results, err := database.ExecuteBatch(db.BatchOptions{
+			Operations:   ops,
+			WriteOptions: opts,
+			TxnID:        getTxnQueryParam(r),
+		})
+		if err != nil {
+			var batchErr *db.BatchError
+			if errors.As(err, &batchErr) {
+				status, msg := mapErrorToResponse(err)
+				respondWithJSON(w, status, batchErrorResponse{Error: msg, Index: batchErr.Index, Op: batchErr.Op})
+				return
+			}
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		respondWithPayload(w, r, http.StatusOK, batchResponse{Results: results})
+	}
+}
+
+// buildBatchOperation applies request defaults and converts the expiration to a timestamp.
+func buildBatchOperation(op batchOperationRequest) (db.BatchOperation, error) {
+	cf := op.CF
+	if cf == "" {
+		cf = "default"
+	}
+	docType := op.Type
+	if docType == "" {
+		docType = model.DocTypeJSON
+	}
+
+	var expiration *int64
+	if op.Expiration != nil {
+		exp, err := parseExpirationParam(fmt.Sprint(*op.Expiration))
+		if err != nil {
+			return db.BatchOperation{}, err
+		}
+		expiration = exp
+	}
+
+	return db.BatchOperation{
+		Op:           op.Op,
+		ColumnFamily: cf,
+		Key:          op.Key,
+		Value:        op.Value,
+		Type:         docType,
+		Cas:          op.Cas,
+		Expiration:   expiration,
+		Delta:        op.Delta,
+		Element:      op.Element,
+	}, nil
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"mithrildb/model"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Conditional requests map HTTP entity tags to document revisions: the ETag of a document is
+// its quoted Meta.Rev, If-Match is an alternative to the 'cas' parameter and If-None-Match
+// enables 304 responses on reads and insert semantics on writes.
+//
+// A revision identifies the content of a document, not the bytes of one response: the same
+// revision is served as JSON, MessagePack or CBOR, whole or as selected paths. Its ETag is
+// therefore weak, except for raw blob content, which is the same bytes in every response.
+
+// etag formats a revision as a weak entity tag.
+func etag(rev string) string {
+	return "W/" + strconv.Quote(rev)
+}
+
+// strongETag formats a revision as a strong entity tag, for responses whose bytes are fully
+// determined by the revision.
+func strongETag(rev string) string {
+	return strconv.Quote(rev)
+}
+
+// setETag exposes the document revision in the ETag header.
+func setETag(w http.ResponseWriter, doc *model.Document) {
+	if doc != nil && doc.Meta.Rev != "" {
+		w.Header().Set("ETag", etag(doc.Meta.Rev))
+	}
+}
+
+// parseETags splits an If-Match or If-None-Match header into unquoted tags.
+// Weak tags are compared like strong ones, so the ETag of any response can be sent back in
+// If-Match.
+func parseETags(header string) []string {
+	var tags []string
+	for _, part := range strings.Split(header, ",") {
+		tag := strings.TrimSpace(part)
+		tag = strings.TrimPrefix(tag, "W/")
+		if unquoted, err := strconv.Unquote(tag); err == nil {
+			tag = unquoted
+		}
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// etagMatches reports whether a conditional header lists the revision or is "*".
+func etagMatches(header, rev string) bool {
+	for _, tag := range parseETags(header) {
+		if tag == "*" || tag == rev {
+			return true
+		}
+	}
+	return false
+}
+
+// isWildcardHeader reports whether a conditional header is "*".
+func isWildcardHeader(r *http.Request, name string) bool {
+	return strings.TrimSpace(r.Header.Get(name)) == "*"
+}
+
+// ifMatchCas returns the revision required by the If-Match header, or "" when the header is
+// absent or "*". Only a single revision can be checked atomically, so a list of tags is kept
+// as given and never matches, failing the request with 412 rather than skipping the check.
+func ifMatchCas(r *http.Request) string {
+	header := r.Header.Get("If-Match")
+	tags := parseETags(header)
+	switch {
+	case len(tags) == 0 || isWildcardHeader(r, "If-Match"):
+		return ""
+	case len(tags) == 1:
+		return tags[0]
+	default:
+		return header
+	}
+}
+
+// respondNotModified answers a read whose If-None-Match header matches the current revision.
+func respondNotModified(w http.ResponseWriter, rev string) {
+	w.Header().Set("ETag", etag(rev))
+	w.WriteHeader(http.StatusNotModified)
+}
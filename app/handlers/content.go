@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mithrildb/db"
+	"mithrildb/model"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Media types supported for request and response bodies.
+const (
+	contentTypeJSON    = "application/json"
+	contentTypeMsgPack = "application/msgpack"
+	contentTypeCBOR    = "application/cbor"
+
+	contentTypeOctetStream = "application/octet-stream"
+)
+
+var (
+	errUnsupportedMediaType = errors.New("unsupported media type")
+	errBodyTooLarge         = errors.New("request body too large")
+)
+
+var (
+	cborDecMode, _ = cbor.DecOptions{
+		DefaultMapType: reflect.TypeOf(map[string]interface{}(nil)),
+	}.DecMode()
+	cborEncMode, _ = cbor.EncOptions{
+		Time: cbor.TimeRFC3339Nano,
+	}.EncMode()
+)
+
+// normalizeMediaType maps a media type and its common aliases to one of the supported types.
+// It returns an empty string when the media type is not supported.
+func normalizeMediaType(mediaType string) string {
+	switch strings.ToLower(mediaType) {
+	case contentTypeJSON, "text/json":
+		return contentTypeJSON
+	case contentTypeMsgPack, "application/x-msgpack", "application/vnd.msgpack":
+		return contentTypeMsgPack
+	case contentTypeCBOR:
+		return contentTypeCBOR
+	default:
+		return ""
+	}
+}
+
+// requestContentType returns the media type of the request body. JSON is assumed when the
+// Content-Type header is missing.
+func requestContentType(r *http.Request) (string, error) {
+	header := r.Header.Get("Content-Type")
+	if header == "" {
+		return contentTypeJSON, nil
+	}
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return "", errUnsupportedMediaType
+	}
+	if normalized := normalizeMediaType(mediaType); normalized != "" {
+		return normalized, nil
+	}
+	return "", errUnsupportedMediaType
+}
+
+// responseContentType selects the response media type from the Accept header.
+// The first supported type listed by the client wins; JSON is the default.
+func responseContentType(r *http.Request) string {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if normalized := normalizeMediaType(mediaType); normalized != "" {
+			return normalized
+		}
+	}
+	return contentTypeJSON
+}
+
+// decodeBody decodes the request body into v according to its Content-Type.
+func decodeBody(r *http.Request, v interface{}) error {
+	contentType, err := requestContentType(r)
+	if err != nil {
+		return err
+	}
+
+	switch contentType {
+	case contentTypeMsgPack:
+		dec := msgpack.NewDecoder(r.Body)
+		dec.SetCustomStructTag("json")
+		return dec.Decode(v)
+	case contentTypeCBOR:
+		return cborDecMode.NewDecoder(r.Body).Decode(v)
+	default:
+		dec := json.NewDecoder(r.Body)
+		dec.UseNumber()
+		return dec.Decode(v)
+	}
+}
+
+// respondWithPayload encodes the payload using the media type negotiated with the client.
+// Responses vary with the Accept header, so caches keep one copy per representation.
+func respondWithPayload(w http.ResponseWriter, r *http.Request, status int, payload interface{}) {
+	contentType := responseContentType(r)
+
+	var buf bytes.Buffer
+	var err error
+	switch contentType {
+	case contentTypeMsgPack:
+		enc := msgpack.NewEncoder(&buf)
+		enc.SetCustomStructTag("json")
+		err = enc.Encode(payload)
+	case contentTypeCBOR:
+		err = cborEncMode.NewEncoder(&buf).Encode(payload)
+	default:
+		err = json.NewEncoder(&buf).Encode(payload)
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "failed to encode response")
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(status)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// respondWithDecodeError reports a request body that could not be decoded.
+func respondWithDecodeError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errUnsupportedMediaType) {
+		respondWithError(w, http.StatusUnsupportedMediaType, err.Error())
+		return
+	}
+	if errors.Is(err, errBodyTooLarge) {
+		respondWithError(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+	respondWithErrInvalidJSONBody(w)
+}
+
+// isBlobRequest reports whether the request body carries raw blob content rather than an
+// encoded document. Octet streams are always blobs; other media types are only treated as raw
+// content when the client asked for a blob and the body is not JSON, MessagePack or CBOR.
+func isBlobRequest(r *http.Request, docType string) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err == nil && strings.ToLower(mediaType) == contentTypeOctetStream {
+		return true
+	}
+	if docType != model.DocTypeBlob || r.Header.Get("Content-Type") == "" {
+		return false
+	}
+	_, err = requestContentType(r)
+	return err != nil
+}
+
+// readDocumentBody extracts the document value from a write request.
+//
+// Blob requests store the raw body and keep its Content-Type; every other request must wrap
+// the value in a 'value' field. It returns the value, the effective document type and the
+// content type to record for blobs.
+func readDocumentBody(r *http.Request, docType string) (interface{}, string, string, error) {
+	if isBlobRequest(r, docType) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, "", "", err
+		}
+		contentType := r.Header.Get("Content-Type")
+		if contentType == "" {
+			contentType = db.DefaultBlobContentType
+		}
+		return data, model.DocTypeBlob, contentType, nil
+	}
+
+	var body struct {
+		Value interface{} `json:"value"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		return nil, "", "", err
+	}
+	return body.Value, docType, "", nil
+}
+
+// LimitBodySize caps every request body at max bytes (0 = no limit) with http.MaxBytesReader.
+// Reading past the limit fails with errBodyTooLarge, which body decoding answers with 413.
+func LimitBodySize(next http.Handler, max int64) http.Handler {
+	if max <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = &limitedBody{ReadCloser: http.MaxBytesReader(w, r.Body, max), max: max}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limitedBody reports a body cut short by http.MaxBytesReader as errBodyTooLarge.
+type limitedBody struct {
+	io.ReadCloser
+	read int64
+	max  int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if err != nil && err != io.EOF && b.read >= b.max {
+		err = errBodyTooLarge
+	}
+	return n, err
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"mithrildb/config"
 	"mithrildb/db"
 	"net/http"
@@ -9,37 +8,56 @@ import (
 
 // incrementRequest represents the JSON body for a counter modification request.
 //
-// It expects a non-zero numeric value in the `delta` field. It can be positive or negative.
+// It expects a non-zero numeric value in the `delta` field. It can be positive or negative,
+// and a float delta makes the counter a float counter, even when the sum is whole.
 //
 // Examples:
 //
-//	{"delta": 5}     // Increment by 5
-//	{"delta": -2}    // Decrement by 2
+//	{"delta": 5}                                  // Increment by 5
+//	{"delta": -2}                                 // Decrement by 2
+//	{"delta": 0.5}                                // Increment by 0.5
+//	{"delta": 1, "initial": 1}                    // Increment, or create the counter with 1
+//	{"delta": 1, "initial": 1, "max": 100}        // Fail with 409 once the counter reaches 100
+//	{"delta": -1, "min": 0, "clamp": true}        // Decrement, never below 0
 type incrementRequest struct {
 	// The amount to increment (positive) or decrement (negative) the counter.
-	Delta int64 `json:"delta"`
+	Delta interface{} `json:"delta" swaggertype:"number" example:"5"`
+	// Value of the counter created when it does not exist. Omit to fail with 404 instead.
+	Initial interface{} `json:"initial,omitempty" swaggertype:"number" example:"0"`
+	counterBoundsRequest
 }
 
-// incrementResponse represents the response for a counter delta operation.
-type incrementResponse struct {
-	Old int64 `json:"old"` // Previous value
-	New int64 `json:"new"` // New value
+// counterBoundsRequest holds the optional bounds of a counter increment.
+type counterBoundsRequest struct {
+	// Lowest value allowed
+	Min interface{} `json:"min,omitempty" swaggertype:"number"`
+	// Highest value allowed
+	Max interface{} `json:"max,omitempty" swaggertype:"number"`
+	// Clamp the value to the bounds instead of failing with 409
+	Clamp bool `json:"clamp,omitempty"`
+}
+
+// bounds converts the request bounds into db.CounterBounds.
+func (b counterBoundsRequest) bounds() db.CounterBounds {
+	return db.CounterBounds{Min: b.Min, Max: b.Max, Clamp: b.Clamp}
 }
 
 // deltaCounterHandler applies a delta operation to a counter document.
 //
 // @Summary      Modify counter
-// @Description  Increments or decrements a counter document by a given integer value.
+// @Description  Increments or decrements a counter document by a given value. Integer counters stay integers; a float delta or value makes a float counter, which stays a float counter even when its value is whole. Float deltas are rejected with 400 on integer counters beyond ±2^53, where they would lose precision. With initial, a missing counter is created with that value instead of failing, without applying the delta. With min and/or max, a value out of the bounds fails with 409, or is clamped to them with clamp.
 // @Tags         counters
-// @Accept       json
-// @Produce      json
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
 // @Param        key   query     string               true  "Document key"
 // @Param        cf    query     string               false "Column family (default: 'default')"
 // @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
 // @Param        body  body      incrementRequest     true  "Delta value for increment or decrement"
-// @Success      200   {object}  incrementResponse
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  db.CounterResult
 // @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or JSON body"
 // @Failure      404   {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      409   {object}  handlers.ErrorResponse  "Counter would overflow or leave its bounds"
 // @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
 // @Router       /documents/counters/delta [post]
 func deltaCountertHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
@@ -59,12 +77,12 @@ func deltaCountertHandler(database *db.DB, defaults config.WriteOptionsConfig) h
 
 		// Parse JSON body with delta
 		var req incrementRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			respondWithErrInvalidJSONBody(w)
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
 			return
 		}
-		if req.Delta == 0 {
-			respondWithError(w, http.StatusBadRequest, "'delta' must be a non-zero integer")
+		if req.Delta == nil {
+			respondWithError(w, http.StatusBadRequest, "'delta' must be a non-zero number")
 			return
 		}
 
@@ -82,22 +100,21 @@ func deltaCountertHandler(database *db.DB, defaults config.WriteOptionsConfig) h
 			return
 		}
 
-		oldVal, newVal, err := database.IncrementCounter(db.CounterIncrementOptions{
-			ColumnFamily: cf,
-			Key:          key,
-			Delta:        req.Delta,
-			Expiration:   expiration,
-			WriteOptions: opts,
+		result, err := database.IncrementCounter(db.CounterIncrementOptions{
+			ColumnFamily:  cf,
+			Key:           key,
+			Delta:         req.Delta,
+			Initial:       req.Initial,
+			CounterBounds: req.bounds(),
+			Expiration:    expiration,
+			WriteOptions:  opts,
+			TxnID:         getTxnQueryParam(r),
 		})
 		if err != nil {
 			mapAndRespondWithError(w, err)
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(incrementResponse{
-			Old: oldVal,
-			New: newVal,
-		})
+		respondWithPayload(w, r, http.StatusOK, result)
 	}
 }
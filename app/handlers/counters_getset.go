@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"net/http"
+)
+
+// getSetCounterRequest represents the body of a counter getset request.
+//
+// Examples:
+//
+//	{"value": 10}    // Set the counter to 10
+//	{}               // Reset the counter to 0
+type getSetCounterRequest struct {
+	// New value of the counter (default: 0)
+	Value interface{} `json:"value,omitempty" swaggertype:"number" example:"0"`
+}
+
+// getSetCounterHandler replaces the value of a counter document and returns the previous one.
+//
+// @Summary      Set or reset counter
+// @Description  Atomically sets a counter document to a value, 0 when omitted, and returns its previous value. The counter is created if it does not exist, in which case old is null.
+// @Tags         counters
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key   query     string               true  "Document key"
+// @Param        cf    query     string               false "Column family (default: 'default')"
+// @Param        cas   query     string               false "CAS (revision) the counter must have"
+// @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        body  body      getSetCounterRequest  true  "New value"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  db.CounterResult
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or body, or document is not a counter"
+// @Failure      412   {object}  handlers.ErrorResponse  "Revision mismatch"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/counters/getset [post]
+func getSetCounterHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		var req getSetCounterRequest
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+		if req.Value == nil {
+			req.Value = int64(0)
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		result, err := database.SetCounter(db.CounterSetOptions{
+			ColumnFamily: cf,
+			Key:          key,
+			Value:        req.Value,
+			Cas:          getCasQueryParam(r),
+			Expiration:   expiration,
+			WriteOptions: opts,
+			TxnID:        getTxnQueryParam(r),
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, result)
+	}
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"net/http"
+)
+
+// multiIncrementEntry represents one counter of a multi-counter increment.
+type multiIncrementEntry struct {
+	// Counter key
+	Key string `json:"key"`
+	// Optional CAS (revision) the counter must have
+	Cas string `json:"cas,omitempty"`
+	incrementRequest
+}
+
+// multiIncrementRequest represents the body of a multi-counter increment request.
+//
+// Example:
+//
+//	{
+//	  "counters": [
+//	    {"key": "quota:alice:day", "delta": 1, "initial": 1, "max": 1000},
+//	    {"key": "quota:alice:month", "delta": 1, "initial": 1, "max": 20000}
+//	  ]
+//	}
+type multiIncrementRequest struct {
+	Counters []multiIncrementEntry `json:"counters"`
+}
+
+// multiDeltaCounterHandler increments several counters in one transaction.
+//
+// @Summary      Modify several counters
+// @Description  Increments or decrements several counter documents of a column family in order, in a single transaction, and returns the result of each. Every entry accepts the options of /documents/counters/delta. Either every counter is updated or none is.
+// @Tags         counters
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        cf    query     string               false "Column family (default: 'default')"
+// @Param        expiration  query  int  false  "Optional expiration applied to every counter. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        body  body      multiIncrementRequest  true  "Counters and their deltas"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{}  "Result of each counter, in request order"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or body"
+// @Failure      404   {object}  handlers.ErrorResponse  "A counter was not found"
+// @Failure      409   {object}  handlers.ErrorResponse  "A counter would overflow or leave its bounds"
+// @Failure      412   {object}  handlers.ErrorResponse  "Revision mismatch"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/counters/multi-delta [post]
+func multiDeltaCounterHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		var req multiIncrementRequest
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+		entries := make([]db.CounterIncrementEntry, len(req.Counters))
+		for i, c := range req.Counters {
+			if c.Key == "" || c.Delta == nil {
+				respondWithError(w, http.StatusBadRequest, "every counter needs a 'key' and a non-zero 'delta'")
+				return
+			}
+			entries[i] = db.CounterIncrementEntry{
+				Key:           c.Key,
+				Delta:         c.Delta,
+				Initial:       c.Initial,
+				CounterBounds: c.bounds(),
+				Cas:           c.Cas,
+			}
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		results, err := database.IncrementCounters(db.CounterMultiIncrementOptions{
+			ColumnFamily: cf,
+			Entries:      entries,
+			Expiration:   expiration,
+			WriteOptions: opts,
+			TxnID:        getTxnQueryParam(r),
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status":   "ok",
+			"counters": results,
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"bytes"
+	"mithrildb/config"
+	"mithrildb/db"
+	"mithrildb/model"
+	"net/http"
+)
+
+// documentBlobHandler handles GET /documents/blob
+//
+// @Summary      Download blob content
+// @Description  Serves the raw content of a blob document with its stored Content-Type. Supports Range requests and conditional requests via ETag.
+// @Tags         documents
+// @Produce      octet-stream
+// @Param        key  query     string  true   "Document key"
+// @Param        cf   query     string  false  "Column family (default: 'default')"
+// @Param        fill_cache query bool false "Optional RocksDB fill cache read option"
+// @Param        read_tier query string false "Optional RocksDB read tier (e.g. 'all', 'cache-only')"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Param        Range  header  string  false  "Byte range to return (e.g. 'bytes=0-1023')"
+// @Success      200  {file}    file    "Blob content"
+// @Success      206  {file}    file    "Partial blob content"
+// @Failure      400  {object}  handlers.ErrorResponse  "Missing key or document is not a blob"
+// @Failure      404  {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      416  {string}  string  "Requested range not satisfiable"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/blob [get]
+func documentBlobHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		opts, release, err := database.ResolveReadOptions(r, defaults)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		defer release()
+
+		doc, err := database.GetDocument(db.DocumentReadOptions{
+			ColumnFamily: cf,
+			Key:          key,
+			ReadOptions:  opts,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		data, ok := doc.Value.([]byte)
+		if doc.Meta.Type != model.DocTypeBlob || !ok {
+			mapAndRespondWithError(w, db.ErrInvalidBlobType)
+			return
+		}
+
+		contentType := doc.Meta.ContentType
+		if contentType == "" {
+			contentType = db.DefaultBlobContentType
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("ETag", strongETag(doc.Meta.Rev))
+		if doc.Meta.Checksum != "" {
+			w.Header().Set("X-Checksum-Sha256", doc.Meta.Checksum)
+		}
+
+		// ServeContent handles Range, If-Range, If-None-Match and If-Modified-Since.
+		http.ServeContent(w, r, "", doc.Meta.UpdatedAt, bytes.NewReader(data))
+	}
+}
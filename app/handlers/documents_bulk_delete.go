@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"mithrildb/config"
+	"mithrildb/db"
+	"net/http"
+)
+
+// maxBulkKeys limits the number of keys accepted by bulk delete and bulk touch.
+const maxBulkKeys = 10000
+
+// bulkKeyEntry identifies one document in a bulk delete or touch request.
+type bulkKeyEntry struct {
+	// Document key
+	Key string `json:"key"`
+	// Optional CAS (revision) the document must have
+	Cas string `json:"cas,omitempty"`
+	// Bulk touch only: expiration for this key, overriding the 'expiration' parameter.
+	// TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d)
+	Expiration *int64 `json:"expiration,omitempty"`
+}
+
+// bulkKeysRequest represents the body of bulk delete and bulk touch requests.
+//
+// Example:
+//
+//	{"keys": [
+//	  {"key": "session:1"},
+//	  {"key": "session:2", "cas": "2c1f0a4e-..."}
+//	]}
+type bulkKeysRequest struct {
+	Keys []bulkKeyEntry `json:"keys"`
+}
+
+// bulkDeleteResponse reports how many documents a bulk delete removed.
+type bulkDeleteResponse struct {
+	Deleted int `json:"deleted"`
+}
+
+// bulkDeleteRangeRequest selects the keys purged by a range delete.
+//
+// Example:
+//
+//	{"prefix": "user:42:"}
+type bulkDeleteRangeRequest struct {
+	// Delete every key starting with this prefix
+	Prefix string `json:"prefix,omitempty"`
+	// First key to delete (inclusive); requires end
+	Start string `json:"start,omitempty"`
+	// Key where deletion stops (exclusive); requires start
+	End string `json:"end,omitempty"`
+}
+
+// bulkDeleteRangeResponse reports the key range that was purged.
+type bulkDeleteRangeResponse struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// bulkDeleteHandler deletes multiple documents in a single transaction.
+//
+// @Summary      Bulk delete documents
+// @Description  Deletes a list of keys atomically: either every document is deleted or none is. Entries with a 'cas' must exist with that revision. Emits a delete change event per key. In column families with a trash the documents are moved there.
+// @Tags         documents
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        cf    query     string                     false  "Column family (defaults to 'default')"
+// @Param        actor query     string                     false  "Who deletes the documents, recorded in the trash"
+// @Param        txn   query     string                     false  "Interactive transaction ID returned by POST /transactions"
+// @Param        body  body      handlers.bulkKeysRequest  true   "Keys to delete"
+// @Success      200   {object}  handlers.bulkDeleteResponse
+// @Failure      400   {object}  handlers.batchErrorResponse  "Invalid input; 'index' identifies the failing key"
+// @Failure      404   {object}  handlers.batchErrorResponse  "Document with a CAS not found"
+// @Failure      412   {object}  handlers.batchErrorResponse  "CAS mismatch"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/bulk/delete [post]
+func bulkDeleteHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entries, ok := decodeBulkKeys(w, r)
+		if !ok {
+			return
+		}
+
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		err = database.BulkDeleteDocuments(db.BulkKeyOptions{
+			ColumnFamily: cf,
+			Entries:      entries,
+			Actor:        r.URL.Query().Get("actor"),
+			WriteOptions: opts,
+			TxnID:        getTxnQueryParam(r),
+		})
+		if err != nil {
+			respondWithBulkError(w, err)
+			return
+		}
+
+		respondWithPayload(w, r, http.StatusOK, bulkDeleteResponse{Deleted: len(entries)})
+	}
+}
+
+// bulkTouchHandler updates the expiration of multiple documents in a single transaction.
+//
+// @Summary      Bulk touch documents
+// @Description  Updates the expiration of a list of keys atomically. Each key uses its own 'expiration' when given and the 'expiration' parameter otherwise. Emits a touch change event per key so the TTL index stays current.
+// @Tags         documents
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        cf          query  string  false  "Column family (defaults to 'default')"
+// @Param        expiration  query  int     false  "Expiration for keys without their own. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d)"
+// @Param        txn         query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Param        body        body   handlers.bulkKeysRequest  true  "Keys to touch"
+// @Success      200   {array}   model.Document  "Touched documents, in request order"
+// @Failure      400   {object}  handlers.batchErrorResponse  "Invalid input or missing expiration; 'index' identifies the failing key"
+// @Failure      404   {object}  handlers.batchErrorResponse  "Document not found"
+// @Failure      412   {object}  handlers.batchErrorResponse  "CAS mismatch"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/bulk/touch [post]
+func bulkTouchHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entries, ok := decodeBulkKeys(w, r)
+		if !ok {
+			return
+		}
+
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		docs, err := database.BulkTouchDocuments(db.BulkKeyOptions{
+			ColumnFamily: cf,
+			Entries:      entries,
+			Expiration:   expiration,
+			WriteOptions: opts,
+			TxnID:        getTxnQueryParam(r),
+		})
+		if err != nil {
+			respondWithBulkError(w, err)
+			return
+		}
+
+		respondWithPayload(w, r, http.StatusOK, docs)
+	}
+}
+
+// bulkDeleteRangeHandler purges every document in a prefix or key range.
+//
+// @Summary      Delete documents by prefix or key range
+// @Description  Removes every document whose key starts with 'prefix', or lies between 'start' (inclusive) and 'end' (exclusive), using a RocksDB range delete. Documents are not read first, so this is suited to large purges. A single delete_range change event is emitted. The purge is not transactional and should not race with writes to the same keys. It skips the trash and history of the column family: purged documents cannot be restored and no revision records their deletion.
+// @Tags         documents
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        cf    query     string                           false  "Column family (defaults to 'default')"
+// @Param        body  body      handlers.bulkDeleteRangeRequest  true   "Prefix or key range to delete"
+// @Success      200   {object}  handlers.bulkDeleteRangeResponse  "Purged key range"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid prefix or range"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/bulk/delete-range [post]
+func bulkDeleteRangeHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req bulkDeleteRangeRequest
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		start, end, err := database.DeleteDocumentRange(db.RangeDeleteOptions{
+			ColumnFamily: cf,
+			Prefix:       req.Prefix,
+			Start:        req.Start,
+			End:          req.End,
+			WriteOptions: opts,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		respondWithPayload(w, r, http.StatusOK, bulkDeleteRangeResponse{Start: start, End: end})
+	}
+}
+
+// decodeBulkKeys reads and validates the keys of a bulk delete or touch request.
+// It writes the error response and returns false when the body is invalid.
+func decodeBulkKeys(w http.ResponseWriter, r *http.Request) ([]db.BulkKeyEntry, bool) {
+	var req bulkKeysRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondWithDecodeError(w, err)
+		return nil, false
+	}
+	if len(req.Keys) == 0 {
+		respondWithError(w, http.StatusBadRequest, "'keys' must contain at least one key")
+		return nil, false
+	}
+	if len(req.Keys) > maxBulkKeys {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("at most %d keys are accepted", maxBulkKeys))
+		return nil, false
+	}
+
+	entries := make([]db.BulkKeyEntry, len(req.Keys))
+	for i, k := range req.Keys {
+		entries[i] = db.BulkKeyEntry{Key: k.Key, Cas: k.Cas}
+		if k.Expiration != nil {
+			exp, err := parseExpirationParam(fmt.Sprint(*k.Expiration))
+			if err != nil {
+				status, msg := mapErrorToResponse(err)
+				respondWithJSON(w, status, batchErrorResponse{Error: msg, Index: i})
+				return nil, false
+			}
+			entries[i].Expiration = exp
+		}
+	}
+	return entries, true
+}
+
+// respondWithBulkError reports the failing key of a bulk operation, or a plain error.
+func respondWithBulkError(w http.ResponseWriter, err error) {
+	var batchErr *db.BatchError
+	if errors.As(err, &batchErr) {
+		status, msg := mapErrorToResponse(err)
+		respondWithJSON(w, status, batchErrorResponse{Error: msg, Index: batchErr.Index, Op: batchErr.Op})
+		return
+	}
+	mapAndRespondWithError(w, err)
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"mithrildb/config"
 	"mithrildb/db"
 	"mithrildb/model"
@@ -22,10 +21,11 @@ type MultiGetResponse map[string]*model.Document
 // @Summary      Bulk document fetch
 // @Description  Retrieves multiple documents with metadata by key. Missing keys will be returned with null values.
 // @Tags         documents
-// @Accept       json
-// @Produce      json
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
 // @Param        cf    query     string             false  "Column family (default: 'default')"
 // @Param        body  body      multiGetRequest    true   "List of keys to retrieve"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
 // @Success      200   {object}  MultiGetResponse
 // @Failure      400   {object}  ErrorResponse  "Invalid JSON or missing key list"
 // @Failure      500   {object}  ErrorResponse  "Internal server error"
@@ -33,8 +33,8 @@ type MultiGetResponse map[string]*model.Document
 func bulkGetHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req multiGetRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			respondWithErrInvalidJSONBody(w)
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
 			return
 		}
 		if len(req.Keys) == 0 {
@@ -48,12 +48,12 @@ func bulkGetHandler(database *db.DB, defaults config.ReadOptionsConfig) http.Han
 			return
 		}
 
-		opts := database.DefaultReadOptions
-		override := db.HasReadOptions(r)
-		if override {
-			opts = db.BuildReadOptions(r, defaults)
-			defer opts.Destroy()
+		opts, release, err := database.ResolveReadOptions(r, defaults)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
 		}
+		defer release()
 
 		// Perform the bulk-get operation and return document objects (not just values)
 		result, err := database.BulkGetDocuments(db.BulkReadOptions{
@@ -66,7 +66,6 @@ func bulkGetHandler(database *db.DB, defaults config.ReadOptionsConfig) http.Han
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(result)
+		respondWithPayload(w, r, http.StatusOK, result)
 	}
 }
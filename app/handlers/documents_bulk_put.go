@@ -1,28 +1,39 @@
 package handlers
 
 import (
-	"encoding/json"
+	"bytes"
+	"fmt"
+	"io"
 	"mithrildb/config"
 	"mithrildb/db"
 	"mithrildb/model"
 	"net/http"
-	"time"
-
-	"github.com/google/uuid"
+	"sort"
+	"strconv"
 )
 
 // BulkPutRequestEntry represents a single entry in a bulk document insert request.
 //
-// Each entry includes a value and an optional document type.
+// Each entry includes a value and an optional document type, expiration and CAS.
 // If the type is not provided, "json" will be assumed.
 //
-// Example:
+// Entries can be sent as an ordered array, which is written in order:
+//
+//	[
+//	  { "key": "user:1", "value": { "name": "Alice" } },
+//	  { "key": "queue:1", "value": [1, 2, 3], "type": "list", "expiration": 3600 }
+//	]
+//
+// or as a map of key to entry, which is written in key order:
 //
 //	{
 //	  "user:1": { "value": { "name": "Alice" }, "type": "json" },
 //	  "counter:1": { "value": 42, "type": "counter" }
 //	}
 type BulkPutRequestEntry struct {
+	// Document key. Required in the array form; ignored in the map form.
+	Key string `json:"key,omitempty"`
+
 	// The value to store for the document.
 	// Can be a string, number, object, array, etc.
 	Value interface{} `json:"value"`
@@ -30,33 +41,62 @@ type BulkPutRequestEntry struct {
 	// Optional document type (e.g., "json", "counter", "list", "set").
 	// If omitted, "json" is assumed.
 	Type string `json:"type,omitempty"`
+
+	// Optional expiration for this document, overriding the 'expiration' parameter.
+	// TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d).
+	Expiration *int64 `json:"expiration,omitempty"`
+
+	// Optional CAS (revision) the existing document must have.
+	Cas string `json:"cas,omitempty"`
+}
+
+// bulkPutResult reports the outcome of one bulk put entry.
+type bulkPutResult struct {
+	Key      string          `json:"key"`
+	Status   int             `json:"status"`             // HTTP status of this entry
+	Document *model.Document `json:"document,omitempty"` // Stored document on success
+	Error    string          `json:"error,omitempty"`    // Failure reason in partial mode
+}
+
+// bulkPutResponse lists the outcome of every entry, in request order. Atomic requests with a
+// map body get the map of key to stored document instead.
+type bulkPutResponse struct {
+	Results []bulkPutResult `json:"results"`
 }
 
 // bulkPutHandler stores multiple documents in a single request.
 //
 // @Summary      Bulk insert documents
-// @Description  Stores multiple documents in a single call. Each document is defined by a key and a value/type pair.
+// @Description  Stores multiple documents in a single transaction. Each entry carries its own value, type, and optional expiration and CAS. Array bodies are written in order; map bodies in key order. By default the request is atomic and the first failing entry aborts it; with partial=true, failing entries are skipped and reported while the rest are committed. Array bodies and partial requests return the result of every entry in request order; atomic map bodies return a map of key to stored document.
 // @Tags         documents
-// @Accept       json
-// @Produce      json
-// @Param        cf    query     string                              false  "Column family (defaults to 'default')"
-// @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
-// @Param        body  body      map[string]handlers.BulkPutRequestEntry  true  "Map of key to value/type entry"
-// @Success      200   {object}  map[string]model.Document
-// @Failure      400   {object}  handlers.ErrorResponse  "Invalid input or empty payload"
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        cf          query  string  false  "Column family (defaults to 'default')"
+// @Param        expiration  query  int     false  "Expiration for entries without their own. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to store without expiration."
+// @Param        partial     query  bool    false  "Commit successful entries and report per-key errors instead of failing the whole request"
+// @Param        txn         query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Param        body        body   []handlers.BulkPutRequestEntry  true  "Ordered entries, or a map of key to entry"
+// @Success      200   {object}  handlers.bulkPutResponse  "Result of every entry; atomic map bodies get a map of key to model.Document instead"
+// @Failure      400   {object}  handlers.batchErrorResponse  "Invalid input or empty payload; 'index' identifies the failing entry"
+// @Failure      413   {object}  handlers.ErrorResponse  "Body larger than the configured maximum"
+// @Failure      412   {object}  handlers.batchErrorResponse  "CAS mismatch"
 // @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
 // @Router       /documents/bulk/put [post]
 func bulkPutHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var payload map[string]BulkPutRequestEntry
-		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-			respondWithErrInvalidJSONBody(w)
+		payload, byKey, err := decodeBulkPutBody(r)
+		if err != nil {
+			respondWithDecodeError(w, err)
 			return
 		}
 		if len(payload) == 0 {
 			respondWithError(w, http.StatusBadRequest, "empty payload")
 			return
 		}
+		if len(payload) > maxBulkKeys {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("at most %d documents are accepted", maxBulkKeys))
+			return
+		}
 
 		cf, err := getCfQueryParam(r)
 		if err != nil {
@@ -70,6 +110,27 @@ func bulkPutHandler(database *db.DB, defaults config.WriteOptionsConfig) http.Ha
 			return
 		}
 
+		partial, _ := strconv.ParseBool(r.URL.Query().Get("partial"))
+
+		entries := make([]db.BulkPutEntry, len(payload))
+		for i, entry := range payload {
+			entries[i] = db.BulkPutEntry{
+				Key:   entry.Key,
+				Value: entry.Value,
+				Type:  entry.Type,
+				Cas:   entry.Cas,
+			}
+			if entry.Expiration != nil {
+				exp, err := parseExpirationParam(fmt.Sprint(*entry.Expiration))
+				if err != nil {
+					status, msg := mapErrorToResponse(err)
+					respondWithJSON(w, status, batchErrorResponse{Error: msg, Index: i, Op: db.BatchOpPut})
+					return
+				}
+				entries[i].Expiration = exp
+			}
+		}
+
 		// Write options
 		opts := database.DefaultWriteOptions
 		override := db.HasWriteOptions(r)
@@ -78,39 +139,71 @@ func bulkPutHandler(database *db.DB, defaults config.WriteOptionsConfig) http.Ha
 			defer opts.Destroy()
 		}
 
-		now := time.Now()
-		docs := make(map[string]*model.Document)
-		batch := make(map[string]interface{})
-
-		for key, entry := range payload {
-			if entry.Type == "" {
-				entry.Type = model.DocTypeJSON
-			}
-			doc := &model.Document{
-				Key:   key,
-				Value: entry.Value,
-				Meta: model.Metadata{
-					Rev:       uuid.NewString(),
-					Type:      entry.Type,
-					UpdatedAt: now,
-				},
-			}
-			docs[key] = doc
-			batch[key] = doc
-		}
-
-		err = database.BulkPutDocuments(db.BulkWriteOptions{
+		results, err := database.BulkPutDocuments(db.BulkWriteOptions{
 			ColumnFamily: cf,
-			Documents:    batch,
+			Entries:      entries,
 			Expiration:   expiration,
+			Partial:      partial,
 			WriteOptions: opts,
+			TxnID:        getTxnQueryParam(r),
 		})
 		if err != nil {
-			mapAndRespondWithError(w, err)
+			respondWithBulkError(w, err)
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(docs)
+		if byKey && !partial {
+			docs := make(map[string]*model.Document, len(results))
+			for _, res := range results {
+				docs[res.Key] = res.Document
+			}
+			respondWithPayload(w, r, http.StatusOK, docs)
+			return
+		}
+
+		resp := bulkPutResponse{Results: make([]bulkPutResult, len(results))}
+		for i, res := range results {
+			resp.Results[i] = bulkPutResult{Key: res.Key, Status: http.StatusOK, Document: res.Document}
+			if res.Err != nil {
+				resp.Results[i].Status, resp.Results[i].Error = mapErrorToResponse(res.Err)
+			}
+		}
+		respondWithPayload(w, r, http.StatusOK, resp)
+	}
+}
+
+// decodeBulkPutBody reads bulk put entries sent either as an ordered array or as a map of key
+// to entry, and reports which form was used. Map entries are returned sorted by key.
+func decodeBulkPutBody(r *http.Request) ([]BulkPutRequestEntry, bool, error) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var list []BulkPutRequestEntry
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	listErr := decodeBody(r, &list)
+	if listErr == nil {
+		return list, false, nil
+	}
+
+	var byKey map[string]BulkPutRequestEntry
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	if err := decodeBody(r, &byKey); err != nil {
+		return nil, false, listErr
+	}
+
+	keys := make([]string, 0, len(byKey))
+	for k := range byKey {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	list = make([]BulkPutRequestEntry, len(keys))
+	for i, k := range keys {
+		entry := byKey[k]
+		entry.Key = k
+		list[i] = entry
 	}
+	return list, true, nil
 }
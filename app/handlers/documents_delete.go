@@ -9,14 +9,20 @@ import (
 // documentDeleteHandler handles DELETE /documents
 //
 // @Summary      Delete a document
-// @Description  Deletes a document by key within a specified column family.
+// @Description  Deletes a document by key within a specified column family. In column families with a trash the document is moved there, recording the deletion time and 'actor', and can be restored with POST /documents/undelete; 'permanent=true' skips the trash.
 // @Tags         documents
 // @Produce      json
 // @Param        key  query  string  true   "Document key to delete"
 // @Param        cf   query  string  false  "Column family (default: 'default')"
+// @Param        cas  query  string  false  "CAS (revision) the document must have to be deleted"
+// @Param        If-Match  header  string  false  "Revision (ETag) the document must have, or '*' to require that it exists; alternative to the cas parameter"
+// @Param        actor      query  string  false  "Who deletes the document, recorded in the trash"
+// @Param        permanent  query  bool    false  "Delete the document without keeping it in the trash"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
 // @Success      200  "Document successfully deleted"
 // @Failure      400  {object}  handlers.ErrorResponse  "Missing or invalid parameters"
 // @Failure      404  {object}  handlers.ErrorResponse  "Document or column family not found"
+// @Failure      412  {object}  handlers.ErrorResponse  "CAS mismatch, or a CAS or If-Match given for a missing document"
 // @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
 // @Router       /documents [delete]
 func documentDeleteHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
@@ -45,7 +51,12 @@ func documentDeleteHandler(database *db.DB, defaults config.WriteOptionsConfig)
 		err = database.DeleteDocument(db.DocumentDeleteOptions{
 			ColumnFamily: cf,
 			Key:          key,
+			Cas:          getCasQueryParam(r),
+			MustExist:    isWildcardHeader(r, "If-Match"),
+			Permanent:    r.URL.Query().Get("permanent") == "true",
+			Actor:        r.URL.Query().Get("actor"),
 			WriteOptions: opts,
+			TxnID:        getTxnQueryParam(r),
 		})
 		if err != nil {
 			mapAndRespondWithError(w, err)
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"mithrildb/config"
 	"mithrildb/db"
 	"net/http"
@@ -10,16 +9,24 @@ import (
 // documentGetHandler handles GET /documents
 //
 // @Summary      Retrieve a document
-// @Description  Retrieves a document by key, including its value and metadata.
+// @Description  Retrieves a document by key, including its value and metadata. When one or more 'path' parameters are given, only those paths of the value are returned (as model.DocumentPaths) with per-path existence flags. The revision is returned as the ETag header; an If-None-Match header listing the current revision yields 304 Not Modified. In column families with history enabled, 'rev' returns a past revision and 'as_of' the version that was current at a point in time. Past revisions of lists, sets, sorted sets, streams and time series are not kept and answer 409; only their current revision can be read.
 // @Tags         documents
-// @Produce      json
+// @Produce      json,application/msgpack,application/cbor
 // @Param        key  query     string  true   "Document key"
 // @Param        cf   query     string  false  "Column family (default: 'default')"
+// @Param        path query    []string  false  "Paths to return, e.g. 'profile.address.city' or 'items[3]'. Repeat for multiple paths" collectionFormat(multi)
 // @Param        fill_cache query bool false "Optional RocksDB fill cache read option"
 // @Param        read_tier query string false "Optional RocksDB read tier (e.g. 'all', 'cache-only')"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Param        If-None-Match  header  string  false  "Return 304 when the document still has one of these revisions (ETags)"
+// @Param        rev    query  string  false  "Return this past revision of the document"
+// @Param        as_of  query  string  false  "Return the version current at this time: Unix timestamp in seconds or RFC 3339"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
 // @Success      200  {object}  model.Document
-// @Failure      400  {object}  handlers.ErrorResponse  "Missing or invalid key"
-// @Failure      404  {object}  handlers.ErrorResponse  "Document not found"
+// @Success      304  "Document not modified"
+// @Failure      400  {object}  handlers.ErrorResponse  "Missing or invalid key, or history not enabled"
+// @Failure      404  {object}  handlers.ErrorResponse  "Document or revision not found"
+// @Failure      409  {object}  handlers.ErrorResponse  "Past revision of a document stored as elements"
 // @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
 // @Router       /documents [get]
 func documentGetHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
@@ -37,24 +44,76 @@ func documentGetHandler(database *db.DB, defaults config.ReadOptionsConfig) http
 			return
 		}
 
-		opts := database.DefaultReadOptions
-		override := db.HasReadOptions(r)
-		if override {
-			opts = db.BuildReadOptions(r, defaults)
-			defer opts.Destroy()
+		opts, release, err := database.ResolveReadOptions(r, defaults)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		defer release()
+
+		// Time-travel read: a past version from the document history
+		rev, asOf := r.URL.Query().Get("rev"), r.URL.Query().Get("as_of")
+		if rev != "" || asOf != "" {
+			revOpts := db.DocumentRevisionReadOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				Rev:          rev,
+				ReadOptions:  opts,
+			}
+			if rev == "" {
+				if revOpts.AsOf, err = parseAsOfParam(asOf); err != nil {
+					respondWithError(w, http.StatusBadRequest, "'as_of' must be a Unix timestamp or an RFC 3339 time")
+					return
+				}
+			}
+			doc, err := database.GetDocumentRevision(revOpts)
+			if err != nil {
+				mapAndRespondWithError(w, err)
+				return
+			}
+			setETag(w, doc)
+			respondWithPayload(w, r, http.StatusOK, doc)
+			return
+		}
+
+		// Sub-document read: only the requested paths are returned
+		if paths := r.URL.Query()["path"]; len(paths) > 0 {
+			result, err := database.GetDocumentPaths(db.DocumentPathReadOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				Paths:        paths,
+				ReadOptions:  opts,
+				TxnID:        getTxnQueryParam(r),
+			})
+			if err != nil {
+				mapAndRespondWithError(w, err)
+				return
+			}
+			if etagMatches(r.Header.Get("If-None-Match"), result.Meta.Rev) {
+				respondNotModified(w, result.Meta.Rev)
+				return
+			}
+			w.Header().Set("ETag", etag(result.Meta.Rev))
+			respondWithPayload(w, r, http.StatusOK, result)
+			return
 		}
 
 		doc, err := database.GetDocument(db.DocumentReadOptions{
 			ColumnFamily: cf,
 			Key:          key,
 			ReadOptions:  opts,
+			TxnID:        getTxnQueryParam(r),
 		})
 		if err != nil {
 			mapAndRespondWithError(w, err)
 			return
 		}
+		if etagMatches(r.Header.Get("If-None-Match"), doc.Meta.Rev) {
+			respondNotModified(w, doc.Meta.Rev)
+			return
+		}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(doc)
+		setETag(w, doc)
+		respondWithPayload(w, r, http.StatusOK, doc)
 	}
 }
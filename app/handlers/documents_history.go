@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"mithrildb/model"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// documentHistoryResponse lists the stored revisions of a document, newest first.
+type documentHistoryResponse struct {
+	Key       string                   `json:"key"`
+	Revisions []model.DocumentRevision `json:"revisions"`
+}
+
+// documentHistoryHandler handles GET /documents/history
+//
+// @Summary      List document revisions
+// @Description  Lists the revisions kept for a document, newest first, in a column family with history enabled. Deletions appear as revisions flagged 'deleted'. Use 'values=true' to include the stored document of each revision. Revisions of documents stored as elements (lists, sets, sorted sets, streams and time series changed through their endpoints) keep their metadata and element header but no value.
+// @Tags         documents
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key     query  string  true   "Document key"
+// @Param        cf      query  string  false  "Column family (default: 'default')"
+// @Param        limit   query  int     false  "Maximum number of revisions to return (default: all)"
+// @Param        values  query  bool    false  "Include the stored document of each revision"
+// @Param        fill_cache query bool false "Optional RocksDB fill cache read option"
+// @Param        read_tier query string false "Optional RocksDB read tier (e.g. 'all', 'cache-only')"
+// @Success      200  {object}  documentHistoryResponse
+// @Failure      400  {object}  handlers.ErrorResponse  "Invalid key or history not enabled"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/history [get]
+func documentHistoryHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		limit := 0
+		if s := r.URL.Query().Get("limit"); s != "" {
+			if limit, err = strconv.Atoi(s); err != nil || limit < 0 {
+				respondWithError(w, http.StatusBadRequest, "'limit' must be a non-negative integer")
+				return
+			}
+		}
+
+		opts := database.DefaultReadOptions
+		if db.HasReadOptions(r) {
+			opts = db.BuildReadOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		revisions, err := database.GetDocumentHistory(db.DocumentHistoryOptions{
+			ColumnFamily:  cf,
+			Key:           key,
+			Limit:         limit,
+			IncludeValues: r.URL.Query().Get("values") == "true",
+			ReadOptions:   opts,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		respondWithPayload(w, r, http.StatusOK, documentHistoryResponse{Key: key, Revisions: revisions})
+	}
+}
+
+// documentRevertHandler handles POST /documents/revert
+//
+// @Summary      Revert a document to a past revision
+// @Description  Writes the value of a past revision back as the document's newest revision, in a column family with history enabled. Works for deleted documents too. Like a put, the restored document has no expiration unless one is given. Revisions of documents stored as elements cannot be restored.
+// @Tags         documents
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key         query  string  true   "Document key"
+// @Param        cf          query  string  false  "Column family (default: 'default')"
+// @Param        rev         query  string  true   "Revision to restore"
+// @Param        expiration  query  int     false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d)"
+// @Param        cas         query  string  false  "CAS (revision) the current document must have"
+// @Param        If-Match    header string  false  "Revision (ETag) the current document must have; alternative to the cas parameter"
+// @Param        sync         query  bool  false  "Write option: sync"
+// @Param        disable_wal  query  bool  false  "Write option: disable WAL"
+// @Param        no_slowdown  query  bool  false  "Write option: no slowdown"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200  {object}  model.Document
+// @Failure      400  {object}  handlers.ErrorResponse  "Invalid request or history not enabled"
+// @Failure      404  {object}  handlers.ErrorResponse  "Revision not found"
+// @Failure      409  {object}  handlers.ErrorResponse  "Revision of a document stored as elements"
+// @Failure      412  {object}  handlers.ErrorResponse  "CAS mismatch"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/revert [post]
+func documentRevertHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		rev, err := getQueryParam(r, "rev")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		doc, err := database.RevertDocument(db.DocumentRevertOptions{
+			ColumnFamily: cf,
+			Key:          key,
+			Rev:          rev,
+			Cas:          getCasQueryParam(r),
+			Expiration:   expiration,
+			WriteOptions: opts,
+			TxnID:        getTxnQueryParam(r),
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		setETag(w, doc)
+		respondWithPayload(w, r, http.StatusOK, doc)
+	}
+}
+
+// parseAsOfParam parses a point in time given as a Unix timestamp in seconds or as RFC 3339.
+func parseAsOfParam(s string) (time.Time, error) {
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(secs, 0), nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"mithrildb/config"
 	"mithrildb/db"
 	"net/http"
@@ -10,18 +9,20 @@ import (
 // @Summary Insert a document
 // @Description Insert a new document only if the key does not already exist
 // @Tags documents
-// @Accept json
-// @Produce json
+// @Accept json,application/msgpack,application/cbor,octet-stream
+// @Produce json,application/msgpack,application/cbor
 // @Param key query string true "Document key"
 // @Param cf query string false "Column family (defaults to 'default')"
-// @Param type query string false "Document type (json, counter, list, set)"
+// @Param type query string false "Document type (json, counter, list, set, zset, hash, blob, stream, timeseries). application/octet-stream bodies are stored as blobs"
 // @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to store without expiration."
 // @Param sync query bool false "Write option: sync"
 // @Param disable_wal query bool false "Write option: disable WAL"
 // @Param no_slowdown query bool false "Write option: no slowdown"
 // @Param document body map[string]interface{} true "Document value body. Must contain 'value' field"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
 // @Success 200 {object} model.Document
 // @Failure 400 {object} handlers.ErrorResponse
+// @Failure 413 {object} handlers.ErrorResponse
 // @Failure 409 {object} handlers.ErrorResponse
 // @Failure 500 {object} handlers.ErrorResponse
 // @Router /documents/insert [post]
@@ -48,15 +49,13 @@ func documentInsertHandler(database *db.DB, defaults config.WriteOptionsConfig)
 
 		docType := getDocTypeQueryParam(r)
 
-		// Read JSON body
-		var body struct {
-			Value interface{} `json:"value"`
-		}
-		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-			respondWithErrInvalidJSONBody(w)
+		// Read the body: an encoded {"value": ...} object or raw blob content
+		value, docType, contentType, err := readDocumentBody(r, docType)
+		if err != nil {
+			respondWithDecodeError(w, err)
 			return
 		}
-		if body.Value == nil {
+		if value == nil {
 			respondWithErrMissingValue(w)
 			return
 		}
@@ -73,10 +72,12 @@ func documentInsertHandler(database *db.DB, defaults config.WriteOptionsConfig)
 		doc, err := database.InsertDocument(db.DocumentWriteOptions{
 			ColumnFamily: cf,
 			Key:          key,
-			Value:        body.Value,
+			Value:        value,
 			Type:         docType,
+			ContentType:  contentType,
 			Expiration:   expiration,
 			WriteOptions: opts,
+			TxnID:        getTxnQueryParam(r),
 		})
 
 		if err != nil {
@@ -85,7 +86,7 @@ func documentInsertHandler(database *db.DB, defaults config.WriteOptionsConfig)
 		}
 
 		// Success
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(doc)
+		setETag(w, doc)
+		respondWithPayload(w, r, http.StatusOK, doc)
 	}
 }
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"mithrildb/config"
 	"mithrildb/db"
 	"net/http"
@@ -13,13 +12,14 @@ import (
 // @Summary      List document keys
 // @Description  Returns a list of keys within the specified column family, optionally filtered by prefix and pagination options.
 // @Tags         keys
-// @Produce      json
+// @Produce      json,application/msgpack,application/cbor
 // @Param        cf           query  string  false  "Column family (default: 'default')"
 // @Param        prefix       query  string  false  "Only return keys with this prefix"
 // @Param        start_after  query  string  false  "Return keys after this key (for pagination)"
 // @Param        limit        query  int     false  "Maximum number of keys to return (default: 100)"
 // @Param        fill_cache   query  bool    false  "Whether to fill RocksDB read cache"
 // @Param        read_tier    query  string  false  "RocksDB read tier (e.g. 'all', 'cache-only')"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
 // @Success      200  {array}  string
 // @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
 // @Router       /documents/keys [get]
@@ -43,12 +43,12 @@ func listKeysHandler(database *db.DB, defaults config.ReadOptionsConfig) http.Ha
 		}
 
 		// Read options
-		opts := database.DefaultReadOptions
-		override := db.HasReadOptions(r)
-		if override {
-			opts = db.BuildReadOptions(r, defaults)
-			defer opts.Destroy()
+		opts, release, err := database.ResolveReadOptions(r, defaults)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
 		}
+		defer release()
 
 		keys, err := database.ListDocumentKeys(db.KeyListOptions{
 			ColumnFamily: cf,
@@ -62,7 +62,6 @@ func listKeysHandler(database *db.DB, defaults config.ReadOptionsConfig) http.Ha
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(keys)
+		respondWithPayload(w, r, http.StatusOK, keys)
 	}
 }
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"mithrildb/config"
 	"mithrildb/db"
 	"net/http"
@@ -13,13 +12,14 @@ import (
 // @Summary      List documents
 // @Description  Returns a map of documents filtered by optional prefix and paginated using start_after and limit.
 // @Tags         documents
-// @Produce      json
+// @Produce      json,application/msgpack,application/cbor
 // @Param        cf           query     string  false  "Column family (default: 'default')"
 // @Param        prefix       query     string  false  "Filter documents whose keys begin with this prefix"
 // @Param        start_after  query     string  false  "Skip documents until this key (exclusive)"
 // @Param        limit        query     int     false  "Maximum number of documents to return (default: 100)"
 // @Param        fill_cache   query     bool    false  "Whether to fill RocksDB read cache"
 // @Param        read_tier    query     string  false  "RocksDB read tier (e.g., 'all', 'cache-only')"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
 // @Success      200  {object}  map[string]model.Document
 // @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
 // @Router       /documents/list [get]
@@ -43,12 +43,12 @@ func listDocumentsHandler(database *db.DB, defaults config.ReadOptionsConfig) ht
 		}
 
 		// Read options (use default for now)
-		opts := database.DefaultReadOptions
-		override := db.HasReadOptions(r)
-		if override {
-			opts = db.BuildReadOptions(r, defaults)
-			defer opts.Destroy()
+		opts, release, err := database.ResolveReadOptions(r, defaults)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
 		}
+		defer release()
 
 		// First list keys
 		keys, err := database.ListDocumentKeys(db.KeyListOptions{
@@ -75,7 +75,6 @@ func listDocumentsHandler(database *db.DB, defaults config.ReadOptionsConfig) ht
 		}
 
 		// Return map of key => document
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(docs)
+		respondWithPayload(w, r, http.StatusOK, docs)
 	}
 }
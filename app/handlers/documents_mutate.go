@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"fmt"
+	"mithrildb/config"
+	"mithrildb/db"
+	"mithrildb/model"
+	"net/http"
+)
+
+// mutateRequest represents the body for a sub-document mutation request.
+//
+// Example:
+//
+//	{"operations": [
+//	  {"op": "set", "path": "profile.address.city", "value": "Lisbon"},
+//	  {"op": "array_append", "path": "tags", "value": "vip"},
+//	  {"op": "increment", "path": "stats.logins", "value": 1}
+//	]}
+type mutateRequest struct {
+	// Operations applied in order; all succeed or none is applied.
+	Operations []model.MutationOp `json:"operations"`
+}
+
+// mutateBody is the decoded form of mutateRequest. Operations are kept as maps until they are
+// parsed, so a null value can be told apart from a missing one.
+type mutateBody struct {
+	Operations []map[string]interface{} `json:"operations"`
+}
+
+// documentMutateHandler applies sub-document operations to a JSON document.
+//
+// @Summary      Mutate document paths
+// @Description  Atomically applies a list of path operations (set, insert, replace, remove, array_append, array_insert, increment) to a JSON document. Values may be null, which writes null at the path. Honors CAS and expiration and returns the updated document with its new revision.
+// @Tags         documents
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key         query  string  true   "Document key"
+// @Param        cf          query  string  false  "Column family (default: 'default')"
+// @Param        cas         query  string  false  "CAS (revision) for concurrency control"
+// @Param        expiration  query  int     false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        If-Match  header  string  false  "Revision (ETag) the document must have; alternative to the cas parameter"
+// @Param        body        body   mutateRequest  true  "Operations to apply"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200  {object}  model.Document
+// @Failure      400  {object}  handlers.ErrorResponse  "Invalid operation, path or document type"
+// @Failure      404  {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      409  {object}  handlers.ErrorResponse  "Path already exists (insert)"
+// @Failure      412  {object}  handlers.ErrorResponse  "CAS mismatch"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/mutate [post]
+func documentMutateHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		var req mutateBody
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+		if len(req.Operations) == 0 {
+			respondWithError(w, http.StatusBadRequest, "'operations' must contain at least one operation")
+			return
+		}
+		ops := make([]model.MutationOp, len(req.Operations))
+		for i, raw := range req.Operations {
+			if ops[i], err = model.ParseMutationOp(raw); err != nil {
+				mapAndRespondWithError(w, fmt.Errorf("operation %d: %w", i, err))
+				return
+			}
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		doc, err := database.MutateDocument(db.DocumentMutateOptions{
+			ColumnFamily: cf,
+			Key:          key,
+			Operations:   ops,
+			Cas:          getCasQueryParam(r),
+			Expiration:   expiration,
+			WriteOptions: opts,
+			TxnID:        getTxnQueryParam(r),
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		setETag(w, doc)
+		respondWithPayload(w, r, http.StatusOK, doc)
+	}
+}
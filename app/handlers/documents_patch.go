@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"io"
+	"mime"
+	"mithrildb/config"
+	"mithrildb/db"
+	"mithrildb/model"
+	"net/http"
+	"strings"
+)
+
+// Media types accepted by PATCH /documents.
+const (
+	contentTypeMergePatch = "application/merge-patch+json"
+	contentTypeJSONPatch  = "application/json-patch+json"
+)
+
+// documentPatchHandler applies a JSON Merge Patch or JSON Patch to an existing document.
+//
+// @Summary      Patch a document
+// @Description  Applies an RFC 7396 JSON Merge Patch (application/merge-patch+json) or an RFC 6902 JSON Patch (application/json-patch+json) to an existing JSON document in a single transaction. JSON Patch 'test' operations can be used for conditional updates.
+// @Tags         documents
+// @Accept       application/merge-patch+json,application/json-patch+json
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key         query  string  true   "Document key"
+// @Param        cf          query  string  false  "Column family (default: 'default')"
+// @Param        cas         query  string  false  "CAS (revision) for concurrency control"
+// @Param        expiration  query  int     false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        If-Match  header  string  false  "Revision (ETag) the document must have; alternative to the cas parameter"
+// @Param        body        body   object  true   "Merge patch object or array of JSON Patch operations"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200  {object}  model.Document
+// @Failure      400  {object}  handlers.ErrorResponse  "Invalid patch or document type"
+// @Failure      413  {object}  handlers.ErrorResponse  "Body larger than the configured maximum"
+// @Failure      404  {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      412  {object}  handlers.ErrorResponse  "CAS mismatch or failed test operation"
+// @Failure      415  {object}  handlers.ErrorResponse  "Unsupported patch media type"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents [patch]
+func documentPatchHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		var patchType string
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		switch strings.ToLower(mediaType) {
+		case contentTypeMergePatch:
+			patchType = model.PatchTypeMerge
+		case contentTypeJSONPatch:
+			patchType = model.PatchTypeJSON
+		default:
+			respondWithError(w, http.StatusUnsupportedMediaType,
+				"Content-Type must be application/merge-patch+json or application/json-patch+json")
+			return
+		}
+
+		patch, err := io.ReadAll(r.Body)
+		if err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+		if len(patch) == 0 {
+			respondWithErrInvalidJSONBody(w)
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		doc, err := database.PatchDocument(db.DocumentPatchOptions{
+			ColumnFamily: cf,
+			Key:          key,
+			PatchType:    patchType,
+			Patch:        patch,
+			Cas:          getCasQueryParam(r),
+			Expiration:   expiration,
+			WriteOptions: opts,
+			TxnID:        getTxnQueryParam(r),
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		setETag(w, doc)
+		respondWithPayload(w, r, http.StatusOK, doc)
+	}
+}
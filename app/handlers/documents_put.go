@@ -1,29 +1,34 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
 	"mithrildb/config"
 	"mithrildb/db"
+	"mithrildb/model"
 	"net/http"
 )
 
 // documentPutHandler stores a document with optional CAS and metadata.
 //
 // @Summary      Store or update a document
-// @Description  Stores a document in the specified column family. Supports optimistic concurrency via CAS.
+// @Description  Stores a document in the specified column family. Supports optimistic concurrency via CAS, given as the 'cas' parameter or an If-Match header. "If-None-Match: *" only creates the document and "If-Match: *" only overwrites an existing one; both answer 412 when the condition fails. The new revision is returned as the ETag header.
 // @Tags         documents
-// @Accept       json
-// @Produce      json
+// @Accept       json,application/msgpack,application/cbor,octet-stream
+// @Produce      json,application/msgpack,application/cbor
 // @Param        key   query     string            true  "Document key"
 // @Param        cf    query     string            false "Column family (default: 'default')"
 // @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
-// @Param        type  query     string            false "Document type (e.g. 'json', 'counter', 'list')"
+// @Param        type  query     string            false "Document type (e.g. 'json', 'counter', 'list', 'zset', 'hash', 'blob', 'stream', 'timeseries'). application/octet-stream bodies are stored as blobs"
 // @Param        cas   query     string            false "CAS (revision) for concurrency control"
+// @Param        If-Match       header  string  false  "Revision (ETag) the document must have, or '*' to require that it exists"
+// @Param        If-None-Match  header  string  false  "'*' to only create the document"
 // @Param        body  body      map[string]interface{}  true  "Document value (JSON-encoded)"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
 // @Success      200   {object}  model.Document
 // @Failure      400   {object}  handlers.ErrorResponse "Invalid input or missing value"
+// @Failure      413   {object}  handlers.ErrorResponse "Body larger than the configured maximum"
 // @Failure      404   {object}  handlers.ErrorResponse "Column family not found"
-// @Failure      409   {object}  handlers.ErrorResponse "CAS mismatch"
+// @Failure      412   {object}  handlers.ErrorResponse "CAS mismatch or precondition failed"
 // @Failure      500   {object}  handlers.ErrorResponse "Internal server error"
 // @Router       /documents [post]
 func documentPutHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
@@ -49,15 +54,13 @@ func documentPutHandler(database *db.DB, defaults config.WriteOptionsConfig) htt
 		docType := getDocTypeQueryParam(r)
 		cas := getCasQueryParam(r)
 
-		// Parse the body (value can be any valid JSON type)
-		var body struct {
-			Value interface{} `json:"value"`
-		}
-		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-			respondWithErrInvalidJSONBody(w)
+		// Read the body: an encoded {"value": ...} object or raw blob content
+		value, docType, contentType, err := readDocumentBody(r, docType)
+		if err != nil {
+			respondWithDecodeError(w, err)
 			return
 		}
-		if body.Value == nil {
+		if value == nil {
 			respondWithErrMissingValue(w)
 			return
 		}
@@ -70,16 +73,36 @@ func documentPutHandler(database *db.DB, defaults config.WriteOptionsConfig) htt
 			defer opts.Destroy()
 		}
 
-		// Execute put
-		doc, err := database.PutDocument(db.DocumentWriteOptions{
+		writeOpts := db.DocumentWriteOptions{
 			ColumnFamily: cf,
 			Key:          key,
-			Value:        body.Value,
+			Value:        value,
 			Cas:          cas,
 			Type:         docType,
+			ContentType:  contentType,
 			Expiration:   expiration,
 			WriteOptions: opts,
-		})
+			TxnID:        getTxnQueryParam(r),
+		}
+
+		// Execute put; "If-None-Match: *" only creates and "If-Match: *" only overwrites
+		var doc *model.Document
+		switch {
+		case isWildcardHeader(r, "If-None-Match"):
+			doc, err = database.InsertDocument(writeOpts)
+			if errors.Is(err, db.ErrKeyAlreadyExists) {
+				respondWithError(w, http.StatusPreconditionFailed, err.Error())
+				return
+			}
+		case isWildcardHeader(r, "If-Match"):
+			doc, err = database.ReplaceDocument(writeOpts)
+			if errors.Is(err, db.ErrKeyNotFound) {
+				respondWithError(w, http.StatusPreconditionFailed, err.Error())
+				return
+			}
+		default:
+			doc, err = database.PutDocument(writeOpts)
+		}
 
 		if err != nil {
 			mapAndRespondWithError(w, err)
@@ -87,7 +110,7 @@ func documentPutHandler(database *db.DB, defaults config.WriteOptionsConfig) htt
 		}
 
 		// Respond with document
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(doc)
+		setETag(w, doc)
+		respondWithPayload(w, r, http.StatusOK, doc)
 	}
 }
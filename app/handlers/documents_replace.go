@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"mithrildb/config"
 	"mithrildb/db"
 	"net/http"
@@ -10,20 +9,23 @@ import (
 // documentReplaceHandler replaces an existing document by key, optionally using CAS.
 //
 // @Summary      Replace an existing document
-// @Description  Replaces a document if it already exists. Fails if the key does not exist. Supports CAS for concurrency control.
+// @Description  Replaces a document if it already exists. Fails if the key does not exist. Supports CAS for concurrency control via the cas parameter or an If-Match header.
 // @Tags         documents
-// @Accept       json
-// @Produce      json
+// @Accept       json,application/msgpack,application/cbor,octet-stream
+// @Produce      json,application/msgpack,application/cbor
 // @Param        key   query     string                 true  "Document key"
 // @Param        cf    query     string                 false "Column family (default: 'default')"
 // @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
-// @Param        type  query     string                 false "Document type (e.g. 'json', 'counter', 'list')"
+// @Param        type  query     string                 false "Document type (e.g. 'json', 'counter', 'list', 'zset', 'hash', 'blob', 'stream', 'timeseries'). application/octet-stream bodies are stored as blobs"
 // @Param        cas   query     string                 false "CAS (revision) for concurrency control"
+// @Param        If-Match  header  string  false  "Revision (ETag) the document must have; alternative to the cas parameter"
 // @Param        body  body      map[string]interface{} true  "New value for the document"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
 // @Success      200   {object}  model.Document
 // @Failure      400   {object}  handlers.ErrorResponse "Invalid request or missing value"
+// @Failure      413   {object}  handlers.ErrorResponse "Body larger than the configured maximum"
 // @Failure      404   {object}  handlers.ErrorResponse "Key not found or column family missing"
-// @Failure      409   {object}  handlers.ErrorResponse "CAS mismatch"
+// @Failure      412   {object}  handlers.ErrorResponse "CAS mismatch"
 // @Failure      500   {object}  handlers.ErrorResponse "Internal server error"
 // @Router       /documents/replace [post]
 func documentReplaceHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
@@ -47,15 +49,13 @@ func documentReplaceHandler(database *db.DB, defaults config.WriteOptionsConfig)
 		docType := getDocTypeQueryParam(r)
 		cas := getCasQueryParam(r)
 
-		// Read JSON body
-		var body struct {
-			Value interface{} `json:"value"`
-		}
-		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-			respondWithErrInvalidJSONBody(w)
+		// Read the body: an encoded {"value": ...} object or raw blob content
+		value, docType, contentType, err := readDocumentBody(r, docType)
+		if err != nil {
+			respondWithDecodeError(w, err)
 			return
 		}
-		if body.Value == nil {
+		if value == nil {
 			respondWithErrMissingValue(w)
 			return
 		}
@@ -72,11 +72,13 @@ func documentReplaceHandler(database *db.DB, defaults config.WriteOptionsConfig)
 		doc, err := database.ReplaceDocument(db.DocumentWriteOptions{
 			ColumnFamily: cf,
 			Key:          key,
-			Value:        body.Value,
+			Value:        value,
 			Cas:          cas,
 			Type:         docType,
+			ContentType:  contentType,
 			Expiration:   expiration,
 			WriteOptions: opts,
+			TxnID:        getTxnQueryParam(r),
 		})
 		if err != nil {
 			mapAndRespondWithError(w, err)
@@ -84,7 +86,7 @@ func documentReplaceHandler(database *db.DB, defaults config.WriteOptionsConfig)
 		}
 
 		// Success
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(doc)
+		setETag(w, doc)
+		respondWithPayload(w, r, http.StatusOK, doc)
 	}
 }
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"mithrildb/config"
 	"mithrildb/db"
 	"net/http"
@@ -12,14 +11,18 @@ import (
 // @Summary      Update document expiration
 // @Description  Updates the expiration time of an existing document without modifying its content. Fails if the key does not exist.
 // @Tags         documents
-// @Accept       json
-// @Produce      json
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
 // @Param        key         query  string true  "Document key"
 // @Param        cf          query  string false "Column family (default: 'default')"
 // @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Param        cas  query  string  false  "CAS (revision) for concurrency control"
+// @Param        If-Match  header  string  false  "Revision (ETag) the document must have; alternative to the cas parameter"
 // @Success      200 {object} model.Document
 // @Failure      400 {object} handlers.ErrorResponse "Invalid request"
 // @Failure      404 {object} handlers.ErrorResponse "Key not found or already expired"
+// @Failure      412 {object} handlers.ErrorResponse "CAS mismatch"
 // @Failure      500 {object} handlers.ErrorResponse "Internal server error"
 // @Router       /documents/touch [post]
 func documentTouchHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
@@ -55,8 +58,10 @@ func documentTouchHandler(database *db.DB, defaults config.WriteOptionsConfig) h
 		doc, err := database.TouchDocument(db.DocumentWriteOptions{
 			ColumnFamily: cf,
 			Key:          key,
+			Cas:          getCasQueryParam(r),
 			Expiration:   expiration,
 			WriteOptions: opts,
+			TxnID:        getTxnQueryParam(r),
 		})
 		if err != nil {
 			mapAndRespondWithError(w, err)
@@ -64,7 +69,7 @@ func documentTouchHandler(database *db.DB, defaults config.WriteOptionsConfig) h
 		}
 
 		// Success
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(doc)
+		setETag(w, doc)
+		respondWithPayload(w, r, http.StatusOK, doc)
 	}
 }
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"mithrildb/model"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// trashListResponse lists soft-deleted documents of a column family, ordered by key.
+type trashListResponse struct {
+	CF        string                  `json:"cf"`
+	Documents []model.TrashedDocument `json:"documents"`
+}
+
+// listTrashHandler handles GET /families/{cf}/trash
+//
+// @Summary      List deleted documents
+// @Description  Lists the documents deleted from a column family with a trash, with their deletion time and actor, ordered by key. Documents stay in the trash until restored or purged after the trash retention.
+// @Tags         families
+// @Produce      json,application/msgpack,application/cbor
+// @Param        cf           path   string  true   "Column family"
+// @Param        prefix       query  string  false  "Only return documents whose key has this prefix"
+// @Param        start_after  query  string  false  "Return documents after this key (for pagination)"
+// @Param        limit        query  int     false  "Maximum number of documents to return (default: 100)"
+// @Param        fill_cache   query  bool    false  "Whether to fill RocksDB read cache"
+// @Param        read_tier    query  string  false  "RocksDB read tier (e.g. 'all', 'cache-only')"
+// @Success      200  {object}  trashListResponse
+// @Failure      400  {object}  handlers.ErrorResponse  "Invalid column family"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /families/{cf}/trash [get]
+func listTrashHandler(database *db.DB, defaults config.ReadOptionsConfig, cf string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !db.IsValidUserCF(cf) {
+			mapAndRespondWithError(w, db.ErrInvalidUserColumnFamily)
+			return
+		}
+
+		limit := 100
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
+				limit = val
+			}
+		}
+
+		opts := database.DefaultReadOptions
+		if db.HasReadOptions(r) {
+			opts = db.BuildReadOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		docs, err := database.ListTrash(db.TrashListOptions{
+			ColumnFamily: cf,
+			Prefix:       r.URL.Query().Get("prefix"),
+			StartAfter:   r.URL.Query().Get("start_after"),
+			Limit:        limit,
+			ReadOptions:  opts,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		respondWithPayload(w, r, http.StatusOK, trashListResponse{CF: cf, Documents: docs})
+	}
+}
+
+// familyTrashPath extracts the column family from a /families/{cf}/trash path.
+func familyTrashPath(path string) (string, bool) {
+	rest := strings.TrimPrefix(path, "/families/")
+	cf, suffix, ok := strings.Cut(rest, "/")
+	if !ok || suffix != "trash" || cf == "" {
+		return "", false
+	}
+	return cf, true
+}
+
+// documentUndeleteHandler handles POST /documents/undelete
+//
+// @Summary      Restore a deleted document
+// @Description  Restores a document from the trash of its column family. The document gets a fresh revision and keeps the expiration it had when deleted unless 'expiration' is given; an expiration that has passed since is cleared. Fails if a live document has taken the key since.
+// @Tags         documents
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key         query  string  true   "Document key"
+// @Param        cf          query  string  false  "Column family (default: 'default')"
+// @Param        expiration  query  int     false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d)"
+// @Param        sync         query  bool  false  "Write option: sync"
+// @Param        disable_wal  query  bool  false  "Write option: disable WAL"
+// @Param        no_slowdown  query  bool  false  "Write option: no slowdown"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200  {object}  model.Document
+// @Failure      400  {object}  handlers.ErrorResponse  "Invalid request"
+// @Failure      404  {object}  handlers.ErrorResponse  "Document not in the trash"
+// @Failure      409  {object}  handlers.ErrorResponse  "Key already exists"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/undelete [post]
+func documentUndeleteHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		doc, err := database.UndeleteDocument(db.DocumentUndeleteOptions{
+			ColumnFamily: cf,
+			Key:          key,
+			Expiration:   expiration,
+			WriteOptions: opts,
+			TxnID:        getTxnQueryParam(r),
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		setETag(w, doc)
+		respondWithPayload(w, r, http.StatusOK, doc)
+	}
+}
@@ -4,33 +4,36 @@ package handlers
 import (
 	"encoding/json"
 	"mithrildb/db"
+	"mithrildb/model"
 	"net/http"
 	"strings"
 )
 
 // createFamilyRequest represents the body for creating a new column family.
 //
-// It requires a non-empty name for the new column family.
+// It requires a non-empty name for the new column family. Settings are optional.
 //
 // Example:
 //
 //	{
-//	  "name": "logs"
+//	  "name": "logs",
+//	  "settings": {"history": {"enabled": true, "max_revisions": 10}}
 //	}
 type createFamilyRequest struct {
-	Name string `json:"name" example:"logs"`
+	Name     string                `json:"name" example:"logs"`
+	Settings *model.FamilySettings `json:"settings,omitempty"`
 }
 
 // createFamilyHandler creates a new column family in the database.
 //
 // @Summary      Create column family
-// @Description  Creates a new column family with the specified name.
+// @Description  Creates a new column family with the specified name and, optionally, its settings.
 // @Tags         families
 // @Accept       json
 // @Produce      json
-// @Param        body  body      createFamilyRequest  true  "Name of the column family to create"
+// @Param        body  body      createFamilyRequest  true  "Name and optional settings of the column family to create"
 // @Success      201   {object}  map[string]string     "Created column family name"
-// @Failure      400   {object}  handlers.ErrorResponse "Invalid or missing column family name"
+// @Failure      400   {object}  handlers.ErrorResponse "Invalid or missing column family name, or invalid settings"
 // @Failure      409   {object}  handlers.ErrorResponse "Column family already exists"
 // @Failure      500   {object}  handlers.ErrorResponse "Internal server error"
 // @Router       /families [post]
@@ -53,12 +56,26 @@ func createFamilyHandler(database *db.DB) http.HandlerFunc {
 			return
 		}
 
+		if req.Settings != nil {
+			if err := db.ValidateFamilySettings(*req.Settings); err != nil {
+				mapAndRespondWithError(w, err)
+				return
+			}
+		}
+
 		err := database.CreateColumnFamily(name)
 		if err != nil {
 			mapAndRespondWithError(w, err)
 			return
 		}
 
+		if req.Settings != nil {
+			if err := database.SetFamilySettings(name, *req.Settings); err != nil {
+				mapAndRespondWithError(w, err)
+				return
+			}
+		}
+
 		w.WriteHeader(http.StatusCreated)
 		json.NewEncoder(w).Encode(map[string]string{
 			"status": "created",
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"mithrildb/db"
+	"mithrildb/model"
+	"net/http"
+)
+
+// getFamilySettingsHandler returns the settings of a column family.
+//
+// @Summary      Get column family settings
+// @Description  Returns the settings of a user column family, such as its document history and trash modes.
+// @Tags         families
+// @Produce      json
+// @Param        cf   query     string  true  "Column family"
+// @Success      200  {object}  model.FamilySettings
+// @Failure      400  {object}  handlers.ErrorResponse "Invalid column family"
+// @Failure      500  {object}  handlers.ErrorResponse "Internal server error"
+// @Router       /families/settings [get]
+func getFamilySettingsHandler(database *db.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getQueryParam(r, "cf")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		settings, err := database.GetFamilySettings(cf)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, settings)
+	}
+}
+
+// updateFamilySettingsHandler replaces the settings of a column family.
+//
+// @Summary      Update column family settings
+// @Description  Replaces the settings of a user column family. With history enabled, every write and delete keeps a revision of the document, pruned to 'max_revisions' per document and to revisions younger than 'retention'. Range deletes are not recorded. Disabling history keeps the stored revisions but makes them unavailable until it is enabled again. With the trash enabled, deleted documents, except those purged by range deletes, are moved to system.trash.<cf> and purged after the trash 'retention'.
+// @Tags         families
+// @Accept       json
+// @Produce      json
+// @Param        cf    query     string                true  "Column family"
+// @Param        body  body      model.FamilySettings  true  "New settings"
+// @Success      200   {object}  model.FamilySettings
+// @Failure      400   {object}  handlers.ErrorResponse "Invalid column family or settings"
+// @Failure      500   {object}  handlers.ErrorResponse "Internal server error"
+// @Router       /families/settings [put]
+func updateFamilySettingsHandler(database *db.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getQueryParam(r, "cf")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var settings model.FamilySettings
+		if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+			respondWithErrInvalidJSONBody(w)
+			return
+		}
+
+		if err := database.SetFamilySettings(cf, settings); err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, settings)
+	}
+}
@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"net/http"
+	"strconv"
+)
+
+// hashGetHandler handles GET /documents/hashes/get
+//
+// @Summary      Get hash field
+// @Description  Returns the value of a field of a "hash" document.
+// @Tags         hashes
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key     query     string  true   "Document key"
+// @Param        cf      query     string  false  "Column family (default: 'default')"
+// @Param        field   query     string  true   "Field to read"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200     {object}  map[string]interface{}  "Value of the field"
+// @Failure      400     {object}  handlers.ErrorResponse  "Missing or invalid parameters"
+// @Failure      404     {object}  handlers.ErrorResponse  "Document or field not found"
+// @Failure      500     {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/hashes/get [get]
+func hashGetHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		field, err := getQueryParam(r, "field")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		opts, release, ok := resolveHashRead(w, r, database, defaults)
+		if !ok {
+			return
+		}
+		defer release()
+
+		value, err := database.HGet(opts, field)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+			"value":  value,
+		})
+	}
+}
+
+// hashMGetHandler handles GET /documents/hashes/mget
+//
+// @Summary      Get several hash fields
+// @Description  Returns the values of several fields of a "hash" document in the order requested, with null for missing fields.
+// @Tags         hashes
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key     query     string    true   "Document key"
+// @Param        cf      query     string    false  "Column family (default: 'default')"
+// @Param        field   query     []string  true   "Fields to read (repeat the parameter for each field)" collectionFormat(multi)
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200     {object}  map[string]interface{}  "Values of the fields"
+// @Failure      400     {object}  handlers.ErrorResponse  "Missing or invalid parameters"
+// @Failure      404     {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500     {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/hashes/mget [get]
+func hashMGetHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fields := r.URL.Query()["field"]
+		if len(fields) == 0 {
+			respondWithError(w, http.StatusBadRequest, "missing 'field' parameter")
+			return
+		}
+		opts, release, ok := resolveHashRead(w, r, database, defaults)
+		if !ok {
+			return
+		}
+		defer release()
+
+		values, err := database.HMGet(opts, fields)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+			"values": values,
+		})
+	}
+}
+
+// hashExistsHandler handles GET /documents/hashes/exists
+//
+// @Summary      Check if hash field exists
+// @Description  Checks whether a field exists in a "hash" document.
+// @Tags         hashes
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key     query     string  true   "Document key"
+// @Param        cf      query     string  false  "Column family (default: 'default')"
+// @Param        field   query     string  true   "Field to check"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200     {object}  map[string]interface{}  "Whether the field exists"
+// @Failure      400     {object}  handlers.ErrorResponse  "Missing or invalid parameters"
+// @Failure      404     {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500     {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/hashes/exists [get]
+func hashExistsHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		field, err := getQueryParam(r, "field")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		opts, release, ok := resolveHashRead(w, r, database, defaults)
+		if !ok {
+			return
+		}
+		defer release()
+
+		exists, err := database.HExists(opts, field)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+			"exists": exists,
+		})
+	}
+}
+
+// hashKeysHandler handles GET /documents/hashes/keys
+//
+// @Summary      List hash fields
+// @Description  Returns the field names of a "hash" document in sorted order.
+// @Tags         hashes
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key     query     string  true   "Document key"
+// @Param        cf      query     string  false  "Column family (default: 'default')"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200     {object}  map[string]interface{}  "Field names"
+// @Failure      400     {object}  handlers.ErrorResponse  "Missing or invalid parameters"
+// @Failure      404     {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500     {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/hashes/keys [get]
+func hashKeysHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		opts, release, ok := resolveHashRead(w, r, database, defaults)
+		if !ok {
+			return
+		}
+		defer release()
+
+		fields, err := database.HKeys(opts)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+			"fields": fields,
+		})
+	}
+}
+
+// hashLenHandler handles GET /documents/hashes/len
+//
+// @Summary      Count hash fields
+// @Description  Returns the number of fields of a "hash" document.
+// @Tags         hashes
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key     query     string  true   "Document key"
+// @Param        cf      query     string  false  "Column family (default: 'default')"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200     {object}  map[string]interface{}  "Number of fields"
+// @Failure      400     {object}  handlers.ErrorResponse  "Missing or invalid parameters"
+// @Failure      404     {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500     {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/hashes/len [get]
+func hashLenHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		opts, release, ok := resolveHashRead(w, r, database, defaults)
+		if !ok {
+			return
+		}
+		defer release()
+
+		n, err := database.HLen(opts)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+			"len":    n,
+		})
+	}
+}
+
+// hashGetAllHandler handles GET /documents/hashes/getall
+//
+// @Summary      Get hash fields
+// @Description  Returns the fields of a "hash" document with their values, paginated in field order. When more fields follow, 'next' holds the value to pass as start_after for the next page.
+// @Tags         hashes
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key          query  string  true   "Document key"
+// @Param        cf           query  string  false  "Column family (default: 'default')"
+// @Param        start_after  query  string  false  "Only return fields sorting after this one"
+// @Param        limit        query  int     false  "Maximum number of fields to return (default: no limit)"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200     {object}  map[string]interface{}  "Fields with their values, and the next cursor"
+// @Failure      400     {object}  handlers.ErrorResponse  "Missing or invalid parameters"
+// @Failure      404     {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500     {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/hashes/getall [get]
+func hashGetAllHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := 0
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			val, err := strconv.Atoi(limitStr)
+			if err != nil || val < 0 {
+				respondWithError(w, http.StatusBadRequest, "limit must be a non-negative integer")
+				return
+			}
+			limit = val
+		}
+		opts, release, ok := resolveHashRead(w, r, database, defaults)
+		if !ok {
+			return
+		}
+		defer release()
+
+		fields, next, err := database.HGetAll(db.HGetAllOptions{
+			HashReadOptions: opts,
+			StartAfter:      r.URL.Query().Get("start_after"),
+			Limit:           limit,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		resp := map[string]interface{}{
+			"status": "ok",
+			"fields": fields,
+		}
+		if next != "" {
+			resp["next"] = next
+		}
+		respondWithPayload(w, r, http.StatusOK, resp)
+	}
+}
+
+// resolveHashRead parses the cf and key parameters and the read options of a hash read,
+// responding with an error when they are invalid. release must be called once the read is done.
+func resolveHashRead(w http.ResponseWriter, r *http.Request, database *db.DB, defaults config.ReadOptionsConfig) (db.HashReadOptions, func(), bool) {
+	cf, err := getCfQueryParam(r)
+	if err != nil {
+		mapAndRespondWithError(w, err)
+		return db.HashReadOptions{}, nil, false
+	}
+	key, err := getQueryParam(r, "key")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return db.HashReadOptions{}, nil, false
+	}
+
+	readOpts, release, err := database.ResolveReadOptions(r, defaults)
+	if err != nil {
+		mapAndRespondWithError(w, err)
+		return db.HashReadOptions{}, nil, false
+	}
+	return db.HashReadOptions{
+		ColumnFamily: cf,
+		Key:          key,
+		ReadOptions:  readOpts,
+	}, release, true
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"net/http"
+)
+
+// HSetRequest represents a request to set hash fields.
+// @Description Fields to set, with their values.
+type HSetRequest struct {
+	Fields map[string]interface{} `json:"fields"`
+}
+
+// HDelRequest represents a request to remove hash fields.
+// @Description Names of the fields to remove.
+type HDelRequest struct {
+	Fields []string `json:"fields"`
+}
+
+// HIncrByRequest represents a request to increment an integer hash field.
+// @Description Field and the amount to add to it.
+type HIncrByRequest struct {
+	Field string `json:"field"`
+	Delta int64  `json:"delta"`
+}
+
+// hashSetHandler handles POST /documents/hashes/set
+//
+// @Summary      Set hash fields
+// @Description  Sets fields of a document of type "hash", leaving its other fields untouched.
+// @Tags         hashes
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key   query     string                  true  "Document key"
+// @Param        cf    query     string                  false "Column family (default: 'default')"
+// @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        body  body      handlers.HSetRequest    true  "Fields to set"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{} "Number of fields that were added"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or body"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/hashes/set [post]
+func hashSetHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		var req HSetRequest
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		added, err := database.HSet(db.HSetOptions{
+			HashOpOptions: db.HashOpOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
+				Expiration:   expiration,
+			},
+			Fields: req.Fields,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+			"added":  added,
+		})
+	}
+}
+
+// hashRemoveHandler handles POST /documents/hashes/remove
+//
+// @Summary      Remove hash fields
+// @Description  Removes fields from a document of type "hash". Fields that do not exist are ignored.
+// @Tags         hashes
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key   query     string                  true  "Document key"
+// @Param        cf    query     string                  false "Column family (default: 'default')"
+// @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        body  body      handlers.HDelRequest    true  "Fields to remove"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{} "Number of fields removed"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or body"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/hashes/remove [post]
+func hashRemoveHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		var req HDelRequest
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+		if len(req.Fields) == 0 {
+			respondWithErrInvalidJSONBody(w)
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		removed, err := database.HDel(db.HDelOptions{
+			HashOpOptions: db.HashOpOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
+				Expiration:   expiration,
+			},
+			Fields: req.Fields,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status":  "ok",
+			"removed": removed,
+		})
+	}
+}
+
+// hashIncrByHandler handles POST /documents/hashes/incrby
+//
+// @Summary      Increment hash field
+// @Description  Atomically adds a delta to an integer field of a "hash" document. A missing field counts as 0.
+// @Tags         hashes
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key   query     string                  true  "Document key"
+// @Param        cf    query     string                  false "Column family (default: 'default')"
+// @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        body  body      handlers.HIncrByRequest true  "Field and delta"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{} "New value of the field"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters, body or non-integer field"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/hashes/incrby [post]
+func hashIncrByHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		var req HIncrByRequest
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		value, err := database.HIncrBy(db.HIncrByOptions{
+			HashOpOptions: db.HashOpOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
+				Expiration:   expiration,
+			},
+			Field: req.Field,
+			Delta: req.Delta,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+			"value":  value,
+		})
+	}
+}
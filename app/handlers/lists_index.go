@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"net/http"
+	"strconv"
+)
+
+// listIndexHandler retrieves the element at an index of a list document.
+//
+// @Summary      Get list element by index
+// @Description  Returns the element at an index of a list document. Negative indices count from the end (-1 is the last element).
+// @Tags         lists
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key   query     string  true  "Key of the list document"
+// @Param        cf    query     string  false "Column family (default: 'default')"
+// @Param        index query     int     true  "Index of the element"
+// @Param        fill_cache query bool false "Read option: whether to fill RocksDB cache"
+// @Param        read_tier  query string false "Read option: 'all' or 'cache-only'"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200  {object}  map[string]interface{}  "The element"
+// @Failure      400  {object}  handlers.ErrorResponse  "Invalid parameters or index out of range"
+// @Failure      404  {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/lists/index [get]
+func listIndexHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		indexStr, err := getQueryParam(r, "index")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		index, err := strconv.Atoi(indexStr)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "index must be an integer")
+			return
+		}
+
+		opts, release, err := database.ResolveReadOptions(r, defaults)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		defer release()
+
+		element, err := database.GetListIndex(db.ListIndexOptions{
+			ListReadOptions: db.ListReadOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				ReadOptions:  opts,
+			},
+			Index: index,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"element": element,
+		})
+	}
+}
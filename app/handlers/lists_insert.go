@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"net/http"
+)
+
+// listInsertRequest represents a request body to insert an element next to a pivot element.
+//
+// Example:
+//
+//	{"pivot": "b", "element": "a", "position": "before"}
+type listInsertRequest struct {
+	Pivot    interface{} `json:"pivot"`
+	Element  interface{} `json:"element"`
+	Position string      `json:"position"` // "before" (default) or "after"
+}
+
+// listInsertHandler inserts an element before or after a pivot element of a list document.
+//
+// @Summary      Insert element next to pivot
+// @Description  Inserts an element before or after the first element equal to the pivot. Returns the new length, or -1 when the pivot is not in the list.
+// @Tags         lists
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key   query     string             true  "Key of the list document"
+// @Param        cf    query     string             false "Column family (default: 'default')"
+// @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        body  body      listInsertRequest  true  "Pivot, element and position"
+// @Param        sync          query  boolean false "Write option: sync write to disk"
+// @Param        disable_wal   query  boolean false "Write option: disable write-ahead log"
+// @Param        no_slowdown   query  boolean false "Write option: disable slowdown on write buffer full"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200  {object}  map[string]interface{}  "New length, or -1 when the pivot was not found"
+// @Failure      400  {object}  handlers.ErrorResponse  "Invalid request"
+// @Failure      404  {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/lists/insert [post]
+func listInsertHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		var req listInsertRequest
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+		if req.Position != "" && req.Position != "before" && req.Position != "after" {
+			respondWithError(w, http.StatusBadRequest, "position must be 'before' or 'after'")
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		length, err := database.InsertIntoList(db.ListInsertOptions{
+			ListOpOptions: db.ListOpOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
+				Expiration:   expiration,
+			},
+			Pivot:   req.Pivot,
+			Element: req.Element,
+			After:   req.Position == "after",
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+			"length": length,
+		})
+	}
+}
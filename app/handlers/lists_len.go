@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"net/http"
+)
+
+// listLenHandler returns the number of elements of a list document.
+//
+// @Summary      Get list length
+// @Description  Returns the number of elements of a list document.
+// @Tags         lists
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key   query     string  true  "Key of the list document"
+// @Param        cf    query     string  false "Column family (default: 'default')"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200  {object}  map[string]interface{}  "List length"
+// @Failure      400  {object}  handlers.ErrorResponse  "Invalid input parameters"
+// @Failure      404  {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/lists/len [get]
+func listLenHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		opts, release, err := database.ResolveReadOptions(r, defaults)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		defer release()
+
+		length, err := database.GetListLength(db.ListReadOptions{
+			ColumnFamily: cf,
+			Key:          key,
+			ReadOptions:  opts,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"length": length,
+		})
+	}
+}
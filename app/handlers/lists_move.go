@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"net/http"
+)
+
+// listMoveHandler atomically moves an element from one list document to another.
+//
+// @Summary      Move element between lists
+// @Description  Atomically pops an element from one end of the source list and pushes it to one end of the destination list. The lists may be the same document or live in different column families; both must exist.
+// @Tags         lists
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        source          query  string  true   "Key of the source list"
+// @Param        source_cf       query  string  false  "Column family of the source list (default: 'default')"
+// @Param        destination     query  string  true   "Key of the destination list"
+// @Param        destination_cf  query  string  false  "Column family of the destination list (default: 'default')"
+// @Param        from            query  string  false  "End of the source to take from: 'left' or 'right' (default)"
+// @Param        to              query  string  false  "End of the destination to put at: 'left' or 'right' (default)"
+// @Param        sync          query  boolean false "Write option: sync write to disk"
+// @Param        disable_wal   query  boolean false "Write option: disable write-ahead log"
+// @Param        no_slowdown   query  boolean false "Write option: disable slowdown on write buffer full"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200  {object}  map[string]interface{}  "The moved element"
+// @Failure      400  {object}  handlers.ErrorResponse  "Invalid parameters or empty source list"
+// @Failure      404  {object}  handlers.ErrorResponse  "Source or destination not found"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/lists/move [post]
+func listMoveHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		source, err := getQueryParam(r, "source")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		destination, err := getQueryParam(r, "destination")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		sourceCF, destinationCF := q.Get("source_cf"), q.Get("destination_cf")
+		if sourceCF == "" {
+			sourceCF = "default"
+		}
+		if destinationCF == "" {
+			destinationCF = "default"
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		element, err := database.MoveListElement(db.ListMoveOptions{
+			SourceFamily:      sourceCF,
+			SourceKey:         source,
+			DestinationFamily: destinationCF,
+			DestinationKey:    destination,
+			From:              q.Get("from"),
+			To:                q.Get("to"),
+			WriteOptions:      opts,
+			TxnID:             getTxnQueryParam(r),
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status":  "ok",
+			"element": element,
+		})
+	}
+}
@@ -1,28 +1,32 @@
 package handlers
 
 import (
-	"encoding/json"
+	"fmt"
 	"mithrildb/config"
 	"mithrildb/db"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 // listPopHandler removes and returns the last element from a list document.
 //
 // @Summary      Pop element from list
-// @Description  Removes and returns the last element of a list-type document.
+// @Description  Removes and returns the last element of a list-type document. With `block`, an empty or missing list is waited on until an element is pushed or the timeout elapses; waiting clients are served in arrival order. While clients are waiting, the list reads as empty to pops that do not block.
 // @Tags         lists
-// @Accept       json
-// @Produce      json
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
 // @Param        key  query     string  true  "Key of the list document"
 // @Param        cf   query     string  false "Column family (default: 'default')"
 // @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
 // @Param        sync          query  boolean false "Write option: sync write to disk"
 // @Param        disable_wal   query  boolean false "Write option: disable write-ahead log"
 // @Param        no_slowdown   query  boolean false "Write option: disable slowdown on write buffer full"
+// @Param        block  query  number  false  "Seconds to wait for an element while the list is empty or missing (max 300). Not allowed with txn."
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
 // @Success      200  {object}  map[string]interface{}  "Returns the popped element"
 // @Failure      400  {object}  handlers.ErrorResponse  "Invalid request or missing key"
-// @Failure      404  {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      404  {object}  handlers.ErrorResponse  "Document not found, without block"
 // @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
 // @Router       /documents/lists/pop [post]
 func listPopHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
@@ -42,6 +46,11 @@ func listPopHandler(database *db.DB, defaults config.WriteOptionsConfig) http.Ha
 			mapAndRespondWithError(w, err)
 			return
 		}
+		block, err := getBlockQueryParam(r)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 
 		opts := database.DefaultWriteOptions
 		if db.HasWriteOptions(r) {
@@ -49,20 +58,51 @@ func listPopHandler(database *db.DB, defaults config.WriteOptionsConfig) http.Ha
 			defer opts.Destroy()
 		}
 
-		res, err := database.PopFromList(db.ListOpOptions{
+		listOpts := db.ListOpOptions{
 			ColumnFamily: cf,
 			Key:          key,
 			Expiration:   expiration,
 			WriteOptions: opts,
-		})
+			TxnID:        getTxnQueryParam(r),
+		}
+		var res interface{}
+		if block > 0 {
+			res, err = database.PopFromListBlocking(r.Context(), listOpts, false, block)
+		} else {
+			res, err = database.PopFromList(listOpts)
+		}
 		if err != nil {
+			if r.Context().Err() != nil {
+				return // The client went away while waiting.
+			}
 			mapAndRespondWithError(w, err)
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
 			"status":  "ok",
 			"element": res,
 		})
 	}
 }
+
+// maxListBlock bounds how long a blocking pop, shift or stream read may wait.
+const maxListBlock = 5 * time.Minute
+
+// getBlockQueryParam parses the optional block parameter, in seconds, of pops, shifts and
+// stream reads.
+// It returns zero when the request should not wait.
+func getBlockQueryParam(r *http.Request) (time.Duration, error) {
+	raw := r.URL.Query().Get("block")
+	if raw == "" {
+		return 0, nil
+	}
+	seconds, err := strconv.ParseFloat(raw, 64)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("block must be a non-negative number of seconds")
+	}
+	block := time.Duration(seconds * float64(time.Second))
+	if block > maxListBlock {
+		return 0, fmt.Errorf("block cannot exceed %d seconds", int(maxListBlock.Seconds()))
+	}
+	return block, nil
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"mithrildb/config"
 	"mithrildb/db"
 	"net/http"
@@ -12,26 +11,30 @@ import (
 // Example:
 //
 //	{"element": "myValue"}
+//	{"elements": ["a", "b"]}
 type listElementRequest struct {
 	// Element to add to the list (can be string, number, object, etc.)
 	Element interface{} `json:"element"`
+	// Elements to add in order, instead of a single element
+	Elements []interface{} `json:"elements,omitempty"`
 }
 
 // listPushHandler appends an element to the end of a list document.
 //
 // @Summary      Push element to list
-// @Description  Adds an element to the end of an existing list-type document.
+// @Description  Adds an element, or several in order, to the end of an existing list-type document and returns the new length.
 // @Tags         lists
-// @Accept       json
-// @Produce      json
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
 // @Param        key  query     string              true  "Key of the list document"
 // @Param        cf   query     string              false "Column family (default: 'default')"
 // @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
-// @Param        body body      listElementRequest  true  "Element to add"
+// @Param        body body      listElementRequest  true  "Element or elements to add"
 // @Param        sync          query  boolean false "Write option: sync write to disk"
 // @Param        disable_wal   query  boolean false "Write option: disable write-ahead log"
 // @Param        no_slowdown   query  boolean false "Write option: disable slowdown on write buffer full"
-// @Success      200  {object}  map[string]string   "Status message"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200  {object}  map[string]interface{}  "Status and new length"
 // @Failure      400  {object}  handlers.ErrorResponse  "Invalid request or JSON body"
 // @Failure      404  {object}  handlers.ErrorResponse  "Document not found"
 // @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
@@ -55,8 +58,8 @@ func listPushHandler(database *db.DB, defaults config.WriteOptionsConfig) http.H
 		}
 
 		var req listElementRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			respondWithErrInvalidJSONBody(w)
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
 			return
 		}
 
@@ -66,22 +69,24 @@ func listPushHandler(database *db.DB, defaults config.WriteOptionsConfig) http.H
 			defer opts.Destroy()
 		}
 
-		_, err = database.PushToList(db.ListPushOptions{
+		length, err := database.PushToList(db.ListPushOptions{
 			ListOpOptions: db.ListOpOptions{
 				ColumnFamily: cf,
 				Key:          key,
 				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
 				Expiration:   expiration,
 			},
-			Element: req.Element,
+			Element:  req.Element,
+			Elements: req.Elements,
 		})
 		if err != nil {
 			mapAndRespondWithError(w, err)
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
 			"status": "ok",
+			"length": length,
 		})
 	}
 }
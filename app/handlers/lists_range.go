@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"mithrildb/config"
 	"mithrildb/db"
 	"net/http"
@@ -13,14 +12,15 @@ import (
 // @Summary      Get elements from a list
 // @Description  Returns a slice of elements from a list document, based on start and end indices.
 // @Tags         lists
-// @Accept       json
-// @Produce      json
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
 // @Param        key   query     string  true  "Key of the list document"
 // @Param        cf    query     string  false "Column family (default: 'default')"
 // @Param        start query     int     true  "Start index (inclusive, 0-based)"
 // @Param        end   query     int     true  "End index (inclusive, -1 for end of list)"
 // @Param        fill_cache query bool false "Read option: whether to fill RocksDB cache"
 // @Param        read_tier  query string false "Read option: 'all' or 'cache-only'"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
 // @Success      200  {object}  map[string]interface{}  "List content"
 // @Failure      400  {object}  handlers.ErrorResponse  "Invalid input parameters"
 // @Failure      404  {object}  handlers.ErrorResponse  "Document not found"
@@ -42,11 +42,12 @@ func listRangeHandler(database *db.DB, defaults config.ReadOptionsConfig) http.H
 		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
 		end, _ := strconv.Atoi(r.URL.Query().Get("end"))
 
-		opts := database.DefaultReadOptions
-		if db.HasReadOptions(r) {
-			opts = db.BuildReadOptions(r, defaults)
-			defer opts.Destroy()
+		opts, release, err := database.ResolveReadOptions(r, defaults)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
 		}
+		defer release()
 
 		res, err := database.GetListRange(db.ListRangeOptions{
 			ColumnFamily: cf,
@@ -59,8 +60,7 @@ func listRangeHandler(database *db.DB, defaults config.ReadOptionsConfig) http.H
 			mapAndRespondWithError(w, err)
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
 			"list": res,
 		})
 	}
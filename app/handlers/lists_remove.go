@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"net/http"
+)
+
+// listRemoveRequest represents a request body to remove elements equal to a value.
+//
+// Example:
+//
+//	{"element": "a", "count": 2}
+type listRemoveRequest struct {
+	Element interface{} `json:"element"`
+	Count   int         `json:"count"` // >0 from the head, <0 from the tail, 0 removes every match
+}
+
+// listRemoveHandler removes elements equal to a value from a list document.
+//
+// @Summary      Remove elements by value
+// @Description  Removes elements equal to a value. A positive count removes up to count matches from the head, a negative count from the tail, and 0 removes every match.
+// @Tags         lists
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key   query     string             true  "Key of the list document"
+// @Param        cf    query     string             false "Column family (default: 'default')"
+// @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        body  body      listRemoveRequest  true  "Element and count"
+// @Param        sync          query  boolean false "Write option: sync write to disk"
+// @Param        disable_wal   query  boolean false "Write option: disable write-ahead log"
+// @Param        no_slowdown   query  boolean false "Write option: disable slowdown on write buffer full"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200  {object}  map[string]interface{}  "Number of elements removed"
+// @Failure      400  {object}  handlers.ErrorResponse  "Invalid request"
+// @Failure      404  {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/lists/remove [post]
+func listRemoveHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		var req listRemoveRequest
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		removed, err := database.RemoveFromList(db.ListRemoveOptions{
+			ListOpOptions: db.ListOpOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
+				Expiration:   expiration,
+			},
+			Element: req.Element,
+			Count:   req.Count,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status":  "ok",
+			"removed": removed,
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"net/http"
+	"strconv"
+)
+
+// listSetHandler replaces the element at an index of a list document.
+//
+// @Summary      Set list element by index
+// @Description  Replaces the element at an index of a list document. Negative indices count from the end.
+// @Tags         lists
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key   query     string              true  "Key of the list document"
+// @Param        cf    query     string              false "Column family (default: 'default')"
+// @Param        index query     int                 true  "Index of the element to replace"
+// @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        body  body      listElementRequest  true  "New element"
+// @Param        sync          query  boolean false "Write option: sync write to disk"
+// @Param        disable_wal   query  boolean false "Write option: disable write-ahead log"
+// @Param        no_slowdown   query  boolean false "Write option: disable slowdown on write buffer full"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200  {object}  map[string]interface{}  "Status message"
+// @Failure      400  {object}  handlers.ErrorResponse  "Invalid request or index out of range"
+// @Failure      404  {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/lists/set [post]
+func listSetHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		indexStr, err := getQueryParam(r, "index")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		index, err := strconv.Atoi(indexStr)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "index must be an integer")
+			return
+		}
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		var req listElementRequest
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		err = database.SetListElement(db.ListSetOptions{
+			ListOpOptions: db.ListOpOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
+				Expiration:   expiration,
+			},
+			Index:   index,
+			Element: req.Element,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+		})
+	}
+}
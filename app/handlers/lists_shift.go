@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"mithrildb/config"
 	"mithrildb/db"
 	"net/http"
@@ -10,19 +9,21 @@ import (
 // listShiftHandler removes and returns the first element from a list document.
 //
 // @Summary      Shift list (remove first element)
-// @Description  Removes and returns the first element of a list. Returns an error if the list is empty.
+// @Description  Removes and returns the first element of a list. Returns an error if the list is empty. With `block`, an empty or missing list is waited on until an element is pushed or the timeout elapses; waiting clients are served in arrival order. While clients are waiting, the list reads as empty to pops that do not block.
 // @Tags         lists
-// @Accept       json
-// @Produce      json
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
 // @Param        key  query     string  true  "Key of the list document"
 // @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
 // @Param        cf   query     string  false "Column family (default: 'default')"
 // @Param        sync        query bool false "Write option: wait for sync"
 // @Param        disable_wal query bool false "Write option: disable WAL"
 // @Param        no_slowdown query bool false "Write option: disable slowdown retries"
+// @Param        block  query  number  false  "Seconds to wait for an element while the list is empty or missing (max 300). Not allowed with txn."
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
 // @Success      200  {object}  map[string]interface{}  "Removed element from the list"
 // @Failure      400  {object}  handlers.ErrorResponse  "Invalid input parameters"
-// @Failure      404  {object}  handlers.ErrorResponse  "Document not found or list is empty"
+// @Failure      404  {object}  handlers.ErrorResponse  "Document not found, without block"
 // @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
 // @Router       /documents/lists/shift [post]
 func listShiftHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
@@ -42,6 +43,11 @@ func listShiftHandler(database *db.DB, defaults config.WriteOptionsConfig) http.
 			mapAndRespondWithError(w, err)
 			return
 		}
+		block, err := getBlockQueryParam(r)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 
 		opts := database.DefaultWriteOptions
 		if db.HasWriteOptions(r) {
@@ -49,19 +55,28 @@ func listShiftHandler(database *db.DB, defaults config.WriteOptionsConfig) http.
 			defer opts.Destroy()
 		}
 
-		result, err := database.ShiftFromList(db.ListOpOptions{
+		listOpts := db.ListOpOptions{
 			ColumnFamily: cf,
 			Key:          key,
 			WriteOptions: opts,
+			TxnID:        getTxnQueryParam(r),
 			Expiration:   expiration,
-		})
+		}
+		var result interface{}
+		if block > 0 {
+			result, err = database.PopFromListBlocking(r.Context(), listOpts, true, block)
+		} else {
+			result, err = database.ShiftFromList(listOpts)
+		}
 		if err != nil {
+			if r.Context().Err() != nil {
+				return // The client went away while waiting.
+			}
 			mapAndRespondWithError(w, err)
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
 			"status":  "ok",
 			"element": result,
 		})
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"net/http"
+	"strconv"
+)
+
+// listTrimHandler trims a list document to a range of indices.
+//
+// @Summary      Trim list
+// @Description  Keeps only the elements between start and stop, both inclusive. Negative indices count from the end; a range outside the list empties it.
+// @Tags         lists
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key   query     string  true  "Key of the list document"
+// @Param        cf    query     string  false "Column family (default: 'default')"
+// @Param        start query     int     true  "First index to keep"
+// @Param        stop  query     int     true  "Last index to keep"
+// @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        sync          query  boolean false "Write option: sync write to disk"
+// @Param        disable_wal   query  boolean false "Write option: disable write-ahead log"
+// @Param        no_slowdown   query  boolean false "Write option: disable slowdown on write buffer full"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200  {object}  map[string]interface{}  "Status message"
+// @Failure      400  {object}  handlers.ErrorResponse  "Invalid input parameters"
+// @Failure      404  {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/lists/trim [post]
+func listTrimHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		start, err := strconv.Atoi(r.URL.Query().Get("start"))
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "start must be an integer")
+			return
+		}
+		stop, err := strconv.Atoi(r.URL.Query().Get("stop"))
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "stop must be an integer")
+			return
+		}
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		err = database.TrimList(db.ListTrimOptions{
+			ListOpOptions: db.ListOpOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
+				Expiration:   expiration,
+			},
+			Start: start,
+			Stop:  stop,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"mithrildb/config"
 	"mithrildb/db"
 	"net/http"
@@ -10,18 +9,19 @@ import (
 // listUnshiftHandler adds an element to the beginning of a list document.
 //
 // @Summary      Unshift list (add to start)
-// @Description  Adds a new element to the beginning of a list document. The element can be of any JSON-compatible type.
+// @Description  Adds a new element to the beginning of a list document and returns the new length. The element can be of any JSON-compatible type. Several elements are inserted one after the other, so they end up in reverse order.
 // @Tags         lists
-// @Accept       json
-// @Produce      json
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
 // @Param        key   query     string                 true  "Key of the list document"
 // @Param        cf    query     string                 false "Column family (default: 'default')"
 // @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
-// @Param        body  body      listElementRequest     true  "Element to insert at the beginning"
+// @Param        body  body      listElementRequest     true  "Element or elements to insert at the beginning"
 // @Param        sync        query bool false "Write option: wait for sync"
 // @Param        disable_wal query bool false "Write option: disable WAL"
 // @Param        no_slowdown query bool false "Write option: disable slowdown retries"
-// @Success      200   {object}  map[string]interface{}  "Insertion successful"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{}  "Status and new length"
 // @Failure      400   {object}  handlers.ErrorResponse  "Invalid input or JSON body"
 // @Failure      404   {object}  handlers.ErrorResponse  "Document not found"
 // @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
@@ -44,10 +44,12 @@ func listUnshiftHandler(database *db.DB, defaults config.WriteOptionsConfig) htt
 			return
 		}
 
-		var req struct {
-			Element interface{} `json:"element"`
+		var req listElementRequest
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
 		}
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Element == nil {
+		if req.Element == nil && len(req.Elements) == 0 {
 			respondWithErrInvalidJSONBody(w)
 			return
 		}
@@ -58,23 +60,25 @@ func listUnshiftHandler(database *db.DB, defaults config.WriteOptionsConfig) htt
 			defer opts.Destroy()
 		}
 
-		_, err = database.UnshiftToList(db.ListPushOptions{
+		length, err := database.UnshiftToList(db.ListPushOptions{
 			ListOpOptions: db.ListOpOptions{
 				ColumnFamily: cf,
 				Key:          key,
 				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
 				Expiration:   expiration,
 			},
-			Element: req.Element,
+			Element:  req.Element,
+			Elements: req.Elements,
 		})
 		if err != nil {
 			mapAndRespondWithError(w, err)
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
 			"status": "ok",
+			"length": length,
 		})
 	}
 }
@@ -13,7 +13,7 @@ import (
 // metricsHandler provides a snapshot of internal and system metrics.
 //
 // @Summary      Retrieve internal metrics
-// @Description  Returns server-level, RocksDB and expiration system metrics including memory usage, uptime, compaction stats, and TTL cleanup activity.
+// @Description  Returns server-level, RocksDB, expiration, transaction, pinned snapshot and job queue metrics including memory usage, uptime, compaction stats, and TTL cleanup activity.
 // @Tags         monitoring
 // @Produce      json
 // @Success      200  {object}  metrics.FullMetrics "Detailed metrics of the server, database and expiration subsystem"
@@ -28,10 +28,13 @@ func metricsHandler(database *db.DB, expirer *expiration.Service, cfg *config.Ap
 		}
 
 		result := metrics.FullMetrics{
-			Server:     server,
-			RocksDB:    metrics.GetRocksDBMetrics(database.TransactionDB),
-			Expiration: metrics.GetExpirationMetrics(expirer),
-			Events:     metrics.GetEventSystemMetrics(),
+			Server:       server,
+			RocksDB:      metrics.GetRocksDBMetrics(database.TransactionDB),
+			Expiration:   metrics.GetExpirationMetrics(expirer),
+			Events:       metrics.GetEventSystemMetrics(),
+			Transactions: metrics.GetTransactionMetrics(database.Transactions),
+			Snapshots:    metrics.GetSnapshotMetrics(database.Snapshots),
+			JobQueues:    metrics.GetJobQueueMetrics(database.Queues),
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,338 @@
+package handlers
+
+import (
+	"fmt"
+	"mithrildb/db"
+	"mithrildb/model"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnqueueRequest is the body of POST /queues/{name}/enqueue.
+type EnqueueRequest struct {
+	Body     interface{} `json:"body"`                           // Message payload
+	Delay    string      `json:"delay,omitempty" example:"5s"`   // Time before the message can be received, as a duration or in seconds
+	Priority int         `json:"priority,omitempty" example:"0"` // 0 to 255; higher priorities are received first
+}
+
+// QueueAckRequest is the body of POST /queues/{name}/ack and POST /queues/{name}/nack.
+type QueueAckRequest struct {
+	ID      string `json:"id" example:"42"`               // Message ID
+	Receipt string `json:"receipt"`                       // Receipt of the delivery being acknowledged
+	Delay   string `json:"delay,omitempty" example:"10s"` // Nack only: retry delay overriding the queue's backoff
+}
+
+// queueListHandler handles GET /queues
+//
+// @Summary      List job queues
+// @Description  Lists the job queues with their settings, message counts and the age of their oldest message.
+// @Tags         queues
+// @Produce      json
+// @Success      200  {array}   db.QueueInfo
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /queues [get]
+func queueListHandler(database *db.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if database.Queues == nil {
+			respondWithJSON(w, http.StatusOK, []db.QueueInfo{})
+			return
+		}
+		queues, err := database.Queues.List()
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, queues)
+	}
+}
+
+// queueGetHandler handles GET /queues/{name}
+//
+// @Summary      Get a job queue
+// @Description  Returns a queue's settings, message counts by state, activity counters and the age of its oldest message.
+// @Tags         queues
+// @Produce      json
+// @Param        name  path  string  true  "Queue name"
+// @Success      200  {object}  db.QueueInfo
+// @Failure      404  {object}  handlers.ErrorResponse  "Queue not found"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /queues/{name} [get]
+func queueGetHandler(database *db.DB, name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if database.Queues == nil {
+			mapAndRespondWithError(w, db.ErrQueuesDisabled)
+			return
+		}
+		info, err := database.Queues.Get(name)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, info)
+	}
+}
+
+// queueConfigureHandler handles PUT /queues/{name}
+//
+// @Summary      Create or configure a job queue
+// @Description  Creates a queue or replaces its settings. Empty settings use the server defaults. Changes apply to the following deliveries; messages already received keep their visibility timeout.
+// @Tags         queues
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json
+// @Param        name  path  string               true  "Queue name"
+// @Param        body  body  model.QueueSettings  true  "Queue settings"
+// @Success      200  {object}  db.QueueInfo
+// @Failure      400  {object}  handlers.ErrorResponse  "Invalid name or settings"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /queues/{name} [put]
+func queueConfigureHandler(database *db.DB, name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if database.Queues == nil {
+			mapAndRespondWithError(w, db.ErrQueuesDisabled)
+			return
+		}
+		var settings model.QueueSettings
+		if err := decodeBody(r, &settings); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+		info, err := database.Queues.Configure(name, settings)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, info)
+	}
+}
+
+// queueDeleteHandler handles DELETE /queues/{name}
+//
+// @Summary      Delete a job queue
+// @Description  Deletes a queue and all its messages, including the ones in flight.
+// @Tags         queues
+// @Produce      json
+// @Param        name  path  string  true  "Queue name"
+// @Success      200  {object}  map[string]string  "Queue deleted"
+// @Failure      404  {object}  handlers.ErrorResponse  "Queue not found"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /queues/{name} [delete]
+func queueDeleteHandler(database *db.DB, name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if database.Queues == nil {
+			mapAndRespondWithError(w, db.ErrQueuesDisabled)
+			return
+		}
+		if err := database.Queues.Delete(name); err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]string{"status": "deleted", "queue": name})
+	}
+}
+
+// queueEnqueueHandler handles POST /queues/{name}/enqueue
+//
+// @Summary      Enqueue a message
+// @Description  Adds a message to a queue, creating the queue with default settings if it does not exist. A delayed message is not received before its delay passes; among ready messages, higher priorities are received first and equal priorities in enqueue order.
+// @Tags         queues
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        name  path  string                   true  "Queue name"
+// @Param        body  body  handlers.EnqueueRequest  true  "Message"
+// @Success      200  {object}  model.QueueMessage
+// @Failure      400  {object}  handlers.ErrorResponse  "Invalid name, body, delay or priority"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /queues/{name}/enqueue [post]
+func queueEnqueueHandler(database *db.DB, name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if database.Queues == nil {
+			mapAndRespondWithError(w, db.ErrQueuesDisabled)
+			return
+		}
+		var req EnqueueRequest
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+		delay, err := parseQueueDelay(req.Delay)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		msg, err := database.Queues.Enqueue(name, db.EnqueueOptions{
+			Body:     req.Body,
+			Delay:    delay,
+			Priority: req.Priority,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, msg)
+	}
+}
+
+// queueReceiveHandler handles POST /queues/{name}/receive
+//
+// @Summary      Receive messages
+// @Description  Leases up to `max` ready messages, highest priority first. They stay hidden from other receivers until the visibility timeout ends, then are delivered again unless acked. Each message carries the receipt needed to ack or nack it. Returns an empty list when no message is ready.
+// @Tags         queues
+// @Produce      json,application/msgpack,application/cbor
+// @Param        name        path   string  true   "Queue name"
+// @Param        max         query  int     false  "Maximum messages to receive (default: 1)"
+// @Param        visibility  query  string  false  "Visibility timeout as a duration (e.g. '30s', '5m') or in seconds (default: the queue's)"
+// @Success      200  {object}  map[string]interface{}  "Received messages"
+// @Failure      400  {object}  handlers.ErrorResponse  "Invalid max or visibility timeout"
+// @Failure      404  {object}  handlers.ErrorResponse  "Queue not found"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /queues/{name}/receive [post]
+func queueReceiveHandler(database *db.DB, name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if database.Queues == nil {
+			mapAndRespondWithError(w, db.ErrQueuesDisabled)
+			return
+		}
+		max := 0
+		if s := r.URL.Query().Get("max"); s != "" {
+			var err error
+			if max, err = strconv.Atoi(s); err != nil || max < 1 {
+				respondWithError(w, http.StatusBadRequest, "max must be a positive integer")
+				return
+			}
+		}
+		visibility, err := parseQueueDelay(r.URL.Query().Get("visibility"))
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		messages, err := database.Queues.Receive(name, max, visibility)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status":   "ok",
+			"messages": messages,
+		})
+	}
+}
+
+// queueAckHandler handles POST /queues/{name}/ack
+//
+// @Summary      Acknowledge a message
+// @Description  Removes a received message once its job is done. The receipt must be the one of the message's latest delivery; a message whose visibility timeout ended may have been delivered again.
+// @Tags         queues
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json
+// @Param        name  path  string                    true  "Queue name"
+// @Param        body  body  handlers.QueueAckRequest  true  "Message ID and receipt"
+// @Success      200  {object}  map[string]string  "Message acknowledged"
+// @Failure      400  {object}  handlers.ErrorResponse  "Invalid request"
+// @Failure      404  {object}  handlers.ErrorResponse  "Queue or message not found"
+// @Failure      409  {object}  handlers.ErrorResponse  "Receipt does not match the latest delivery"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /queues/{name}/ack [post]
+func queueAckHandler(database *db.DB, name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if database.Queues == nil {
+			mapAndRespondWithError(w, db.ErrQueuesDisabled)
+			return
+		}
+		req, id, ok := decodeQueueAck(w, r)
+		if !ok {
+			return
+		}
+		if err := database.Queues.Ack(name, id, req.Receipt); err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]string{"status": "acked", "id": req.ID})
+	}
+}
+
+// queueNackHandler handles POST /queues/{name}/nack
+//
+// @Summary      Reject a message
+// @Description  Gives a received message back for a retry after `delay` or, when omitted, the queue's retry backoff, doubled on each attempt. A message out of attempts moves to the queue's dead-letter queue, or is dropped if it has none.
+// @Tags         queues
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json
+// @Param        name  path  string                    true  "Queue name"
+// @Param        body  body  handlers.QueueAckRequest  true  "Message ID, receipt and optional retry delay"
+// @Success      200  {object}  map[string]string  "Message rejected"
+// @Failure      400  {object}  handlers.ErrorResponse  "Invalid request"
+// @Failure      404  {object}  handlers.ErrorResponse  "Queue or message not found"
+// @Failure      409  {object}  handlers.ErrorResponse  "Receipt does not match the latest delivery"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /queues/{name}/nack [post]
+func queueNackHandler(database *db.DB, name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if database.Queues == nil {
+			mapAndRespondWithError(w, db.ErrQueuesDisabled)
+			return
+		}
+		req, id, ok := decodeQueueAck(w, r)
+		if !ok {
+			return
+		}
+		var delay *time.Duration
+		if req.Delay != "" {
+			d, err := parseQueueDelay(req.Delay)
+			if err != nil {
+				mapAndRespondWithError(w, err)
+				return
+			}
+			delay = &d
+		}
+		if err := database.Queues.Nack(name, id, req.Receipt, delay); err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]string{"status": "nacked", "id": req.ID})
+	}
+}
+
+// decodeQueueAck reads an ack or nack body, responding with an error when it is invalid.
+func decodeQueueAck(w http.ResponseWriter, r *http.Request) (QueueAckRequest, uint64, bool) {
+	var req QueueAckRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondWithDecodeError(w, err)
+		return req, 0, false
+	}
+	id, err := strconv.ParseUint(req.ID, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "id must be a message ID")
+		return req, 0, false
+	}
+	if req.Receipt == "" {
+		respondWithError(w, http.StatusBadRequest, "receipt is required")
+		return req, 0, false
+	}
+	return req, id, true
+}
+
+// parseQueueDelay parses an optional duration given as a Go duration or in seconds.
+func parseQueueDelay(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := parseLeaseParam(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid duration %q", db.ErrInvalidQueueOperation, s)
+	}
+	return d, nil
+}
+
+// queuePath extracts the queue name and the action, if any, from a /queues/{name}[/{action}] path.
+func queuePath(path string) (name, action string, ok bool) {
+	rest := strings.TrimPrefix(path, "/queues/")
+	name, action, _ = strings.Cut(rest, "/")
+	if name == "" || strings.Contains(action, "/") {
+		return "", "", false
+	}
+	return name, action, true
+}
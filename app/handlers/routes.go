@@ -75,6 +75,31 @@ func SetupRoutes(database *db.DB, expirer *expiration.Service, cfg *config.AppCo
 		}
 	})
 
+	http.HandleFunc("/families/settings", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			getFamilySettingsHandler(database)(w, r)
+		case http.MethodPut:
+			updateFamilySettingsHandler(database)(w, r)
+		default:
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/families/", func(w http.ResponseWriter, r *http.Request) {
+		cf, ok := familyTrashPath(r.URL.Path)
+		if !ok {
+			respondWithError(w, http.StatusNotFound, "not found")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			listTrashHandler(database, cfg.ReadDefaults, cf)(w, r)
+		default:
+			respondWithNotAllowed(w)
+		}
+	})
+
 	http.HandleFunc("/documents/keys", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
@@ -103,6 +128,55 @@ func SetupRoutes(database *db.DB, expirer *expiration.Service, cfg *config.AppCo
 		}
 	})
 
+	http.HandleFunc("/documents/bulk/delete", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			bulkDeleteHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/bulk/touch", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			bulkTouchHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/bulk/delete-range", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			bulkDeleteRangeHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	// Document history
+	http.HandleFunc("/documents/history", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			documentHistoryHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/revert", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			documentRevertHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/undelete", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			documentUndeleteHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
 	http.HandleFunc("/documents/list", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodGet {
 			listDocumentsHandler(database, cfg.ReadDefaults)(w, r)
@@ -120,11 +194,21 @@ func SetupRoutes(database *db.DB, expirer *expiration.Service, cfg *config.AppCo
 			documentDeleteHandler(database, cfg.WriteDefaults)(w, r)
 		case http.MethodPost:
 			documentPutHandler(database, cfg.WriteDefaults)(w, r)
+		case http.MethodPatch:
+			documentPatchHandler(database, cfg.WriteDefaults)(w, r)
 		default:
 			respondWithNotAllowed(w)
 		}
 	})
 
+	http.HandleFunc("/documents/blob", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			documentBlobHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
 	http.HandleFunc("/documents/insert", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPost {
 			documentInsertHandler(database, cfg.WriteDefaults)(w, r)
@@ -141,6 +225,14 @@ func SetupRoutes(database *db.DB, expirer *expiration.Service, cfg *config.AppCo
 		}
 	})
 
+	http.HandleFunc("/documents/mutate", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			documentMutateHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
 	http.HandleFunc("/documents/touch", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPost {
 			documentTouchHandler(database, cfg.WriteDefaults)(w, r)
@@ -158,6 +250,22 @@ func SetupRoutes(database *db.DB, expirer *expiration.Service, cfg *config.AppCo
 		}
 	})
 
+	http.HandleFunc("/documents/counters/multi-delta", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			multiDeltaCounterHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/counters/getset", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			getSetCounterHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
 	// List operations
 	http.HandleFunc("/documents/lists/push", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPost {
@@ -199,6 +307,62 @@ func SetupRoutes(database *db.DB, expirer *expiration.Service, cfg *config.AppCo
 		}
 	})
 
+	http.HandleFunc("/documents/lists/index", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			listIndexHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/lists/len", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			listLenHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/lists/set", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			listSetHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/lists/insert", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			listInsertHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/lists/remove", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			listRemoveHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/lists/trim", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			listTrimHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/lists/move", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			listMoveHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
 	http.HandleFunc("/documents/sets/add", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPost {
 			setAddHandler(database, cfg.WriteDefaults)(w, r)
@@ -223,6 +387,441 @@ func SetupRoutes(database *db.DB, expirer *expiration.Service, cfg *config.AppCo
 		}
 	})
 
+	http.HandleFunc("/documents/sets/mcontains", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			setMContainsHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/sets/card", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			setCardHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/sets/members", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			setMembersHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/sets/random", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			setRandomHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/sets/pop", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			setPopHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/sets/union", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			setUnionHandler(database, cfg.ReadDefaults, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/sets/intersect", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			setIntersectHandler(database, cfg.ReadDefaults, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/sets/diff", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			setDiffHandler(database, cfg.ReadDefaults, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	// Sorted set operations
+	http.HandleFunc("/documents/zsets/add", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			zsetAddHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/zsets/incrby", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			zsetIncrByHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/zsets/remove", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			zsetRemoveHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/zsets/popmin", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			zsetPopMinHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/zsets/popmax", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			zsetPopMaxHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/zsets/score", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			zsetScoreHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/zsets/rank", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			zsetRankHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/zsets/range", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			zsetRangeHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/zsets/range-by-score", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			zsetRangeByScoreHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	// Hash operations
+	http.HandleFunc("/documents/hashes/set", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			hashSetHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/hashes/remove", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			hashRemoveHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/hashes/incrby", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			hashIncrByHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/hashes/get", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			hashGetHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/hashes/mget", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			hashMGetHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/hashes/exists", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			hashExistsHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/hashes/keys", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			hashKeysHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/hashes/len", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			hashLenHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/hashes/getall", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			hashGetAllHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/streams/append", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			streamAppendHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/streams/trim", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			streamTrimHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/streams/range", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			streamRangeHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/streams/tail", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			streamTailHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/streams/info", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			streamInfoHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/streams/groups/create", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			streamGroupCreateHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/streams/groups/delete", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			streamGroupDeleteHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/streams/groups/read", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			streamGroupReadHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/streams/groups/ack", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			streamGroupAckHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/streams/groups/claim", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			streamGroupClaimHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/streams/groups/pending", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			streamGroupPendingHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/timeseries/add", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			timeSeriesAddHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/timeseries/delete", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			timeSeriesDeleteHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/timeseries/config", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			timeSeriesConfigHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/timeseries/range", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			timeSeriesRangeHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/documents/timeseries/info", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			timeSeriesInfoHandler(database, cfg.ReadDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	// Atomic multi-document batch
+	http.HandleFunc("/batch", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			batchHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	// Interactive transactions
+	http.HandleFunc("/transactions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			transactionBeginHandler(database, cfg.WriteDefaults)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/transactions/commit", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			transactionCommitHandler(database)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/transactions/rollback", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			transactionRollbackHandler(database)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	// Pinned snapshots
+	http.HandleFunc("/snapshots", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			snapshotListHandler(database)(w, r)
+		case http.MethodPost:
+			snapshotCreateHandler(database)(w, r)
+		default:
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/snapshots/", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := snapshotPath(r.URL.Path)
+		if !ok {
+			respondWithError(w, http.StatusNotFound, "not found")
+			return
+		}
+		switch r.Method {
+		case http.MethodDelete:
+			snapshotReleaseHandler(database, id)(w, r)
+		default:
+			respondWithNotAllowed(w)
+		}
+	})
+
+	// Job queues
+	http.HandleFunc("/queues", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			queueListHandler(database)(w, r)
+		} else {
+			respondWithNotAllowed(w)
+		}
+	})
+
+	http.HandleFunc("/queues/", func(w http.ResponseWriter, r *http.Request) {
+		name, action, ok := queuePath(r.URL.Path)
+		if !ok {
+			respondWithError(w, http.StatusNotFound, "not found")
+			return
+		}
+		switch {
+		case action == "" && r.Method == http.MethodGet:
+			queueGetHandler(database, name)(w, r)
+		case action == "" && r.Method == http.MethodPut:
+			queueConfigureHandler(database, name)(w, r)
+		case action == "" && r.Method == http.MethodDelete:
+			queueDeleteHandler(database, name)(w, r)
+		case action == "enqueue" && r.Method == http.MethodPost:
+			queueEnqueueHandler(database, name)(w, r)
+		case action == "receive" && r.Method == http.MethodPost:
+			queueReceiveHandler(database, name)(w, r)
+		case action == "ack" && r.Method == http.MethodPost:
+			queueAckHandler(database, name)(w, r)
+		case action == "nack" && r.Method == http.MethodPost:
+			queueNackHandler(database, name)(w, r)
+		case action == "" || action == "enqueue" || action == "receive" || action == "ack" || action == "nack":
+			respondWithNotAllowed(w)
+		default:
+			respondWithError(w, http.StatusNotFound, "not found")
+		}
+	})
+
 	http.HandleFunc("/indexes", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"mithrildb/config"
 	"mithrildb/db"
 	"net/http"
@@ -18,12 +17,13 @@ type SetElementRequest struct {
 // @Summary      Add element to set
 // @Description  Adds a new element to a document of type "set". If the element already exists, it will not be duplicated.
 // @Tags         sets
-// @Accept       json
-// @Produce      json
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
 // @Param        key   query     string                  true  "Document key"
 // @Param        cf    query     string                  false "Column family (default: 'default')"
 // @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
 // @Param        body  body      handlers.SetElementRequest true "Element to add to the set"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
 // @Success      200   {object}  map[string]string       "Operation successful"
 // @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or body"
 // @Failure      404   {object}  handlers.ErrorResponse  "Document not found"
@@ -48,7 +48,11 @@ func setAddHandler(database *db.DB, defaults config.WriteOptionsConfig) http.Han
 		}
 
 		var req SetElementRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Element == nil {
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+		if req.Element == nil {
 			respondWithErrInvalidJSONBody(w)
 			return
 		}
@@ -63,6 +67,7 @@ func setAddHandler(database *db.DB, defaults config.WriteOptionsConfig) http.Han
 			ColumnFamily: cf,
 			Key:          key,
 			WriteOptions: opts,
+			TxnID:        getTxnQueryParam(r),
 			Expiration:   expiration,
 		},
 			req.Element,
@@ -71,8 +76,7 @@ func setAddHandler(database *db.DB, defaults config.WriteOptionsConfig) http.Han
 			mapAndRespondWithError(w, err)
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
 			"status": "ok",
 		})
 	}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"net/http"
+)
+
+// SetAlgebraRequest names the set documents to combine and, optionally, where to store the result.
+// @Description Request body listing the sets to combine.
+type SetAlgebraRequest struct {
+	Keys  []string `json:"keys"`            // Set documents to combine, in order
+	Store string   `json:"store,omitempty"` // Set document to replace with the result
+}
+
+// setUnionHandler handles POST /documents/sets/union
+//
+// @Summary      Union of sets
+// @Description  Returns the members found in any of the listed set documents. With `store`, the result replaces that document atomically with the reads and only its size is returned.
+// @Tags         sets
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        cf    query     string                      false "Column family of every set (default: 'default')"
+// @Param        expiration  query  int  false  "Expiration of the stored result. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d)."
+// @Param        body  body      handlers.SetAlgebraRequest  true  "Sets to combine and optional store target"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from, without store"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{}  "Members, or the stored count"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or body"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/sets/union [post]
+func setUnionHandler(database *db.DB, readDefaults config.ReadOptionsConfig, writeDefaults config.WriteOptionsConfig) http.HandlerFunc {
+	return setAlgebraHandler(database, readDefaults, writeDefaults, database.SetUnion)
+}
+
+// setIntersectHandler handles POST /documents/sets/intersect
+//
+// @Summary      Intersection of sets
+// @Description  Returns the members found in every one of the listed set documents. With `store`, the result replaces that document atomically with the reads and only its size is returned.
+// @Tags         sets
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        cf    query     string                      false "Column family of every set (default: 'default')"
+// @Param        expiration  query  int  false  "Expiration of the stored result. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d)."
+// @Param        body  body      handlers.SetAlgebraRequest  true  "Sets to combine and optional store target"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from, without store"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{}  "Members, or the stored count"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or body"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/sets/intersect [post]
+func setIntersectHandler(database *db.DB, readDefaults config.ReadOptionsConfig, writeDefaults config.WriteOptionsConfig) http.HandlerFunc {
+	return setAlgebraHandler(database, readDefaults, writeDefaults, database.SetIntersect)
+}
+
+// setDiffHandler handles POST /documents/sets/diff
+//
+// @Summary      Difference of sets
+// @Description  Returns the members of the first listed set document that are in none of the others. With `store`, the result replaces that document atomically with the reads and only its size is returned.
+// @Tags         sets
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        cf    query     string                      false "Column family of every set (default: 'default')"
+// @Param        expiration  query  int  false  "Expiration of the stored result. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d)."
+// @Param        body  body      handlers.SetAlgebraRequest  true  "Sets to combine and optional store target"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from, without store"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{}  "Members, or the stored count"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or body"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/sets/diff [post]
+func setDiffHandler(database *db.DB, readDefaults config.ReadOptionsConfig, writeDefaults config.WriteOptionsConfig) http.HandlerFunc {
+	return setAlgebraHandler(database, readDefaults, writeDefaults, database.SetDiff)
+}
+
+// setAlgebraHandler serves a set combination. Reads use the read defaults and the optional
+// store uses the write defaults.
+func setAlgebraHandler(
+	database *db.DB,
+	readDefaults config.ReadOptionsConfig,
+	writeDefaults config.WriteOptionsConfig,
+	combine func(db.SetAlgebraOptions) ([]interface{}, error),
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		var req SetAlgebraRequest
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+		if len(req.Keys) == 0 {
+			respondWithError(w, http.StatusBadRequest, "keys cannot be empty")
+			return
+		}
+
+		readOpts, release, err := database.ResolveReadOptions(r, readDefaults)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		defer release()
+
+		writeOpts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			writeOpts = db.BuildWriteOptions(r, writeDefaults)
+			defer writeOpts.Destroy()
+		}
+
+		members, err := combine(db.SetAlgebraOptions{
+			ColumnFamily: cf,
+			Keys:         req.Keys,
+			Store:        req.Store,
+			Expiration:   expiration,
+			ReadOptions:  readOpts,
+			WriteOptions: writeOpts,
+			TxnID:        getTxnQueryParam(r),
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		if req.Store != "" {
+			respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+				"status": "ok",
+				"count":  len(members),
+			})
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status":  "ok",
+			"members": members,
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"mithrildb/config"
 	"mithrildb/db"
 	"net/http"
@@ -12,11 +11,12 @@ import (
 // @Summary      Check if element exists in set
 // @Description  Checks whether a given element exists within a set-type document.
 // @Tags         sets
-// @Accept       json
-// @Produce      json
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
 // @Param        key     query     string  true   "Document key"
 // @Param        cf      query     string  false  "Column family (default: 'default')"
 // @Param        element query     string  true   "Element to check"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
 // @Success      200     {object}  map[string]interface{}  "Status and whether the element exists"
 // @Failure      400     {object}  handlers.ErrorResponse  "Missing or invalid parameters"
 // @Failure      404     {object}  handlers.ErrorResponse  "Document not found"
@@ -40,11 +40,12 @@ func setContainsHandler(database *db.DB, defaults config.ReadOptionsConfig) http
 			return
 		}
 
-		opts := database.DefaultReadOptions
-		if db.HasReadOptions(r) {
-			opts = db.BuildReadOptions(r, defaults)
-			defer opts.Destroy()
+		opts, release, err := database.ResolveReadOptions(r, defaults)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
 		}
+		defer release()
 
 		contains, err := database.CheckSetContains(db.SetContainsOptions{
 			ColumnFamily: cf,
@@ -57,8 +58,7 @@ func setContainsHandler(database *db.DB, defaults config.ReadOptionsConfig) http
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
 			"status":   "ok",
 			"contains": contains,
 		})
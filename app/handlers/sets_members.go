@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"net/http"
+	"strconv"
+)
+
+// setCardHandler handles GET /documents/sets/card
+//
+// @Summary      Count set members
+// @Description  Returns the number of members of a set-type document.
+// @Tags         sets
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key     query     string  true   "Document key"
+// @Param        cf      query     string  false  "Column family (default: 'default')"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200     {object}  map[string]interface{}  "Status and number of members"
+// @Failure      400     {object}  handlers.ErrorResponse  "Missing or invalid parameters"
+// @Failure      404     {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500     {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/sets/card [get]
+func setCardHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		opts, release, ok := resolveSetRead(w, r, database, defaults)
+		if !ok {
+			return
+		}
+		defer release()
+
+		count, err := database.GetSetCardinality(opts)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+			"count":  count,
+		})
+	}
+}
+
+// setMembersHandler handles GET /documents/sets/members
+//
+// @Summary      List set members
+// @Description  Returns a page of the members of a set-type document. Members come in a stable order; pass the returned `next` cursor to read the following page.
+// @Tags         sets
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key     query     string  true   "Document key"
+// @Param        cf      query     string  false  "Column family (default: 'default')"
+// @Param        cursor  query     string  false  "Cursor returned by the previous page"
+// @Param        limit   query     int     false  "Maximum number of members to return (0 = all)"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200     {object}  map[string]interface{}  "Members and, when more follow, the next cursor"
+// @Failure      400     {object}  handlers.ErrorResponse  "Missing or invalid parameters"
+// @Failure      404     {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500     {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/sets/members [get]
+func setMembersHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := 0
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			val, err := strconv.Atoi(limitStr)
+			if err != nil || val < 0 {
+				respondWithError(w, http.StatusBadRequest, "limit must be a non-negative integer")
+				return
+			}
+			limit = val
+		}
+		opts, release, ok := resolveSetRead(w, r, database, defaults)
+		if !ok {
+			return
+		}
+		defer release()
+
+		members, next, err := database.GetSetMembers(db.SetMembersOptions{
+			SetReadOptions: opts,
+			Cursor:         r.URL.Query().Get("cursor"),
+			Limit:          limit,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		resp := map[string]interface{}{
+			"status":  "ok",
+			"members": members,
+		}
+		if next != "" {
+			resp["next"] = next
+		}
+		respondWithPayload(w, r, http.StatusOK, resp)
+	}
+}
+
+// setRandomHandler handles GET /documents/sets/random
+//
+// @Summary      Get random set members
+// @Description  Returns up to `count` distinct members of a set-type document chosen at random, without removing them.
+// @Tags         sets
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key     query     string  true   "Document key"
+// @Param        cf      query     string  false  "Column family (default: 'default')"
+// @Param        count   query     int     false  "Number of members to return (default: 1)"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200     {object}  map[string]interface{}  "Random members"
+// @Failure      400     {object}  handlers.ErrorResponse  "Missing or invalid parameters"
+// @Failure      404     {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500     {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/sets/random [get]
+func setRandomHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		count, ok := getSetCountParam(w, r)
+		if !ok {
+			return
+		}
+		opts, release, ok := resolveSetRead(w, r, database, defaults)
+		if !ok {
+			return
+		}
+		defer release()
+
+		members, err := database.RandomSetMembers(opts, count)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status":  "ok",
+			"members": members,
+		})
+	}
+}
+
+// setMContainsHandler handles GET /documents/sets/mcontains
+//
+// @Summary      Check several elements in set
+// @Description  Checks, for each `element` parameter in order, whether it exists within a set-type document.
+// @Tags         sets
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key     query     string    true   "Document key"
+// @Param        cf      query     string    false  "Column family (default: 'default')"
+// @Param        element query     []string  true   "Elements to check (repeat the parameter)"  collectionFormat(multi)
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200     {object}  map[string]interface{}  "Whether each element exists, in request order"
+// @Failure      400     {object}  handlers.ErrorResponse  "Missing or invalid parameters"
+// @Failure      404     {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500     {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/sets/mcontains [get]
+func setMContainsHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		values := r.URL.Query()["element"]
+		if len(values) == 0 {
+			respondWithError(w, http.StatusBadRequest, "missing 'element' parameter")
+			return
+		}
+		opts, release, ok := resolveSetRead(w, r, database, defaults)
+		if !ok {
+			return
+		}
+		defer release()
+
+		elements := make([]interface{}, len(values))
+		for i, v := range values {
+			elements[i] = v
+		}
+		contains, err := database.CheckSetMembers(opts, elements)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status":   "ok",
+			"contains": contains,
+		})
+	}
+}
+
+// resolveSetRead parses the column family, key and read options shared by set reads. It
+// responds with an error and returns false when they are invalid.
+func resolveSetRead(w http.ResponseWriter, r *http.Request, database *db.DB, defaults config.ReadOptionsConfig) (db.SetReadOptions, func(), bool) {
+	cf, err := getCfQueryParam(r)
+	if err != nil {
+		mapAndRespondWithError(w, err)
+		return db.SetReadOptions{}, nil, false
+	}
+	key, err := getQueryParam(r, "key")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return db.SetReadOptions{}, nil, false
+	}
+
+	readOpts, release, err := database.ResolveReadOptions(r, defaults)
+	if err != nil {
+		mapAndRespondWithError(w, err)
+		return db.SetReadOptions{}, nil, false
+	}
+	return db.SetReadOptions{
+		ColumnFamily: cf,
+		Key:          key,
+		ReadOptions:  readOpts,
+	}, release, true
+}
+
+// getSetCountParam parses the optional positive count parameter of random set operations.
+func getSetCountParam(w http.ResponseWriter, r *http.Request) (int, bool) {
+	countStr := r.URL.Query().Get("count")
+	if countStr == "" {
+		return 1, true
+	}
+	count, err := strconv.Atoi(countStr)
+	if err != nil || count < 1 {
+		respondWithError(w, http.StatusBadRequest, "count must be a positive integer")
+		return 0, false
+	}
+	return count, true
+}
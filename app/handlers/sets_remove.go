@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"mithrildb/config"
 	"mithrildb/db"
 	"net/http"
@@ -12,12 +11,13 @@ import (
 // @Summary      Remove element from set
 // @Description  Removes a specific element from a set-type document.
 // @Tags         sets
-// @Accept       json
-// @Produce      json
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
 // @Param        key     query     string  true   "Document key"
 // @Param        cf      query     string  false  "Column family (default: 'default')"
 // @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
 // @Param        body    body      map[string]interface{}  true  "Element to remove from the set"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
 // @Success      200     {object}  map[string]string        "Success message"
 // @Failure      400     {object}  handlers.ErrorResponse   "Invalid request or missing parameters"
 // @Failure      404     {object}  handlers.ErrorResponse   "Document not found"
@@ -44,7 +44,11 @@ func setRemoveHandler(database *db.DB, defaults config.WriteOptionsConfig) http.
 		var req struct {
 			Element interface{} `json:"element"`
 		}
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Element == nil {
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+		if req.Element == nil {
 			respondWithErrInvalidJSONBody(w)
 			return
 		}
@@ -59,6 +63,7 @@ func setRemoveHandler(database *db.DB, defaults config.WriteOptionsConfig) http.
 			ColumnFamily: cf,
 			Key:          key,
 			WriteOptions: opts,
+			TxnID:        getTxnQueryParam(r),
 			Expiration:   expiration,
 		},
 			req.Element)
@@ -67,8 +72,7 @@ func setRemoveHandler(database *db.DB, defaults config.WriteOptionsConfig) http.
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
 			"status": "ok",
 		})
 	}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"mithrildb/db"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// snapshotCreateHandler handles POST /snapshots
+//
+// @Summary      Pin a snapshot
+// @Description  Pins a consistent view of the database and returns its ID. Pass the ID as 'snapshot' to document, bulk get, list, keys, list range and set contains reads so paginated scans and multi-step reads all see the same data. The snapshot is released with DELETE /snapshots/{id} or when its lease ends. Pinned snapshots keep old data from being compacted, so keep leases short.
+// @Tags         snapshots
+// @Produce      json
+// @Param        lease  query  string  false  "Lease as a duration (e.g. '30s', '5m') or in seconds (default from config)"
+// @Success      201  {object}  db.SnapshotInfo
+// @Failure      400  {object}  handlers.ErrorResponse  "Invalid lease"
+// @Failure      429  {object}  handlers.ErrorResponse  "Too many open snapshots"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /snapshots [post]
+func snapshotCreateHandler(database *db.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if database.Snapshots == nil {
+			respondWithError(w, http.StatusServiceUnavailable, "pinned snapshots are disabled")
+			return
+		}
+
+		var lease time.Duration
+		if s := r.URL.Query().Get("lease"); s != "" {
+			var err error
+			if lease, err = parseLeaseParam(s); err != nil {
+				mapAndRespondWithError(w, db.ErrInvalidSnapshotLease)
+				return
+			}
+		}
+
+		info, err := database.Snapshots.Create(lease)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		respondWithJSON(w, http.StatusCreated, info)
+	}
+}
+
+// snapshotListHandler handles GET /snapshots
+//
+// @Summary      List pinned snapshots
+// @Description  Lists the snapshots currently pinned and when their leases end.
+// @Tags         snapshots
+// @Produce      json
+// @Success      200  {array}   db.SnapshotInfo
+// @Router       /snapshots [get]
+func snapshotListHandler(database *db.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if database.Snapshots == nil {
+			respondWithJSON(w, http.StatusOK, []db.SnapshotInfo{})
+			return
+		}
+		respondWithJSON(w, http.StatusOK, database.Snapshots.List())
+	}
+}
+
+// snapshotReleaseHandler handles DELETE /snapshots/{id}
+//
+// @Summary      Release a snapshot
+// @Description  Releases a pinned snapshot. Reads already using it complete normally.
+// @Tags         snapshots
+// @Produce      json
+// @Param        id  path  string  true  "Snapshot ID"
+// @Success      200  {object}  map[string]string  "Snapshot released"
+// @Failure      404  {object}  handlers.ErrorResponse  "Snapshot not found or already released"
+// @Router       /snapshots/{id} [delete]
+func snapshotReleaseHandler(database *db.DB, id string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if database.Snapshots == nil {
+			mapAndRespondWithError(w, db.ErrSnapshotNotFound)
+			return
+		}
+		if err := database.Snapshots.Release(id); err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]string{"status": "released", "snapshot": id})
+	}
+}
+
+// parseLeaseParam accepts a Go duration or a number of seconds.
+func parseLeaseParam(s string) (time.Duration, error) {
+	if secs, err := strconv.Atoi(s); err == nil {
+		return time.Duration(secs) * time.Second, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// snapshotPath extracts the snapshot ID from a /snapshots/{id} path.
+func snapshotPath(path string) (string, bool) {
+	id := strings.TrimPrefix(path, "/snapshots/")
+	if id == "" || strings.Contains(id, "/") {
+		return "", false
+	}
+	return id, true
+}
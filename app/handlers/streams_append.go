@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"net/http"
+	"strconv"
+)
+
+// StreamAppendRequest represents a request to append an entry to a stream.
+// @Description Value to append and, optionally, its ID.
+type StreamAppendRequest struct {
+	Value interface{} `json:"value"`
+	// Entry ID as "<ms>-<seq>", or "<ms>-*" to generate the sequence number (default: generated from the clock)
+	ID string `json:"id,omitempty" example:"*"`
+}
+
+// streamAppendHandler handles POST /documents/streams/append
+//
+// @Summary      Append to a stream
+// @Description  Appends an entry to a document of type "stream", creating the stream if it does not exist, and returns its ID. IDs are "<ms>-<seq>": the append time in Unix milliseconds and a sequence number, always increasing. maxlen, max_age and min_id trim the oldest entries in the same write.
+// @Tags         streams
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key      query  string  true   "Document key"
+// @Param        cf       query  string  false  "Column family (default: 'default')"
+// @Param        maxlen   query  int     false  "Trim the stream to this many entries"
+// @Param        max_age  query  string  false  "Trim entries older than this, as a duration (e.g. '24h') or in seconds"
+// @Param        min_id   query  string  false  "Trim entries with lower IDs"
+// @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        body  body  handlers.StreamAppendRequest  true  "Entry to append"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{}  "ID of the appended entry"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters, body or ID, or document is not a stream"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/streams/append [post]
+func streamAppendHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		limits, ok := getStreamTrimParams(w, r)
+		if !ok {
+			return
+		}
+
+		var req StreamAppendRequest
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		id, err := database.AppendToStream(db.StreamAppendOptions{
+			StreamOpOptions: db.StreamOpOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
+				Expiration:   expiration,
+			},
+			ID:    req.ID,
+			Value: req.Value,
+			Trim:  limits,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+			"id":     id,
+		})
+	}
+}
+
+// streamTrimHandler handles POST /documents/streams/trim
+//
+// @Summary      Trim a stream
+// @Description  Removes the oldest entries of a "stream" document beyond maxlen entries, older than max_age or with IDs lower than min_id, and returns how many were removed. Removed entries still pending in a consumer group stay pending until acknowledged or claimed.
+// @Tags         streams
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key      query  string  true   "Document key"
+// @Param        cf       query  string  false  "Column family (default: 'default')"
+// @Param        maxlen   query  int     false  "Entries to keep"
+// @Param        max_age  query  string  false  "Remove entries older than this, as a duration (e.g. '24h') or in seconds"
+// @Param        min_id   query  string  false  "Remove entries with lower IDs"
+// @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{}  "Number of entries removed"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or document is not a stream"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/streams/trim [post]
+func streamTrimHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		limits, ok := getStreamTrimParams(w, r)
+		if !ok {
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		removed, err := database.TrimStream(db.StreamTrimOptions{
+			StreamOpOptions: db.StreamOpOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
+				Expiration:   expiration,
+			},
+			StreamTrimLimits: limits,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status":  "ok",
+			"removed": removed,
+		})
+	}
+}
+
+// getStreamTrimParams parses the optional maxlen, max_age and min_id parameters.
+func getStreamTrimParams(w http.ResponseWriter, r *http.Request) (db.StreamTrimLimits, bool) {
+	var limits db.StreamTrimLimits
+	q := r.URL.Query()
+	if s := q.Get("maxlen"); s != "" {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || n < 1 {
+			respondWithError(w, http.StatusBadRequest, "maxlen must be a positive integer")
+			return limits, false
+		}
+		limits.MaxLen = n
+	}
+	if s := q.Get("max_age"); s != "" {
+		age, err := parseLeaseParam(s)
+		if err != nil || age <= 0 {
+			respondWithError(w, http.StatusBadRequest, "max_age must be a positive duration")
+			return limits, false
+		}
+		limits.MaxAge = age
+	}
+	limits.MinID = q.Get("min_id")
+	return limits, true
+}
+
+// getStreamCountParam parses the optional count parameter of stream reads, where 0 means no
+// limit.
+func getStreamCountParam(w http.ResponseWriter, r *http.Request) (int, bool) {
+	s := r.URL.Query().Get("count")
+	if s == "" {
+		return 0, true
+	}
+	count, err := strconv.Atoi(s)
+	if err != nil || count < 0 {
+		respondWithError(w, http.StatusBadRequest, "count must be a non-negative integer")
+		return 0, false
+	}
+	return count, true
+}
@@ -0,0 +1,421 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"net/http"
+)
+
+// StreamAckRequest represents a request to acknowledge stream entries.
+// @Description IDs of the entries to acknowledge.
+type StreamAckRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// streamGroupCreateHandler handles POST /documents/streams/groups/create
+//
+// @Summary      Create a consumer group
+// @Description  Creates a consumer group on a "stream" document, creating the stream if it does not exist. The group is delivered the entries after `start`: '$' (default) for only new entries, '-' for every entry, or an ID.
+// @Tags         streams
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key    query  string  true   "Document key"
+// @Param        cf     query  string  false  "Column family (default: 'default')"
+// @Param        group  query  string  true   "Consumer group name"
+// @Param        start  query  string  false  "Deliver entries after this ID (default: '$')"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  db.StreamGroup
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or document is not a stream"
+// @Failure      409   {object}  handlers.ErrorResponse  "Consumer group already exists"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/streams/groups/create [post]
+func streamGroupCreateHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		group, err := getQueryParam(r, "group")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		created, err := database.CreateStreamGroup(db.StreamGroupOptions{
+			StreamOpOptions: db.StreamOpOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
+			},
+			Group:   group,
+			StartID: r.URL.Query().Get("start"),
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, created)
+	}
+}
+
+// streamGroupDeleteHandler handles POST /documents/streams/groups/delete
+//
+// @Summary      Delete a consumer group
+// @Description  Deletes a consumer group of a "stream" document along with its pending entries. The stream entries are kept.
+// @Tags         streams
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key    query  string  true   "Document key"
+// @Param        cf     query  string  false  "Column family (default: 'default')"
+// @Param        group  query  string  true   "Consumer group name"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{}  "Consumer group deleted"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or document is not a stream"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document or consumer group not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/streams/groups/delete [post]
+func streamGroupDeleteHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		group, err := getQueryParam(r, "group")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		err = database.DeleteStreamGroup(db.StreamGroupOptions{
+			StreamOpOptions: db.StreamOpOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
+			},
+			Group: group,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+		})
+	}
+}
+
+// streamGroupReadHandler handles POST /documents/streams/groups/read
+//
+// @Summary      Read as a group consumer
+// @Description  Delivers the entries of a "stream" document not yet delivered to the consumer group to `consumer`, and keeps them pending until they are acknowledged. Each entry goes to a single consumer of the group. With `block`, waits for entries while there are none.
+// @Tags         streams
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key       query  string  true   "Document key"
+// @Param        cf        query  string  false  "Column family (default: 'default')"
+// @Param        group     query  string  true   "Consumer group name"
+// @Param        consumer  query  string  true   "Consumer name"
+// @Param        count     query  int     false  "Maximum number of entries (default: no limit)"
+// @Param        block     query  number  false  "Seconds to wait for entries while there are none (max 300). Not allowed with txn."
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{}  "Delivered entries with their IDs"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or document is not a stream"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document or consumer group not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/streams/groups/read [post]
+func streamGroupReadHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		group, err := getQueryParam(r, "group")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		consumer, err := getQueryParam(r, "consumer")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		count, ok := getStreamCountParam(w, r)
+		if !ok {
+			return
+		}
+		block, err := getBlockQueryParam(r)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		entries, err := database.ReadStreamGroup(r.Context(), db.StreamGroupReadOptions{
+			StreamOpOptions: db.StreamOpOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
+			},
+			Group:    group,
+			Consumer: consumer,
+			Limit:    count,
+		}, block)
+		if err != nil {
+			if r.Context().Err() != nil {
+				return // The client went away while waiting.
+			}
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status":  "ok",
+			"entries": entries,
+		})
+	}
+}
+
+// streamGroupAckHandler handles POST /documents/streams/groups/ack
+//
+// @Summary      Acknowledge stream entries
+// @Description  Removes entries from the pending entries of a consumer group once they are processed, and returns how many were pending.
+// @Tags         streams
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key    query  string  true   "Document key"
+// @Param        cf     query  string  false  "Column family (default: 'default')"
+// @Param        group  query  string  true   "Consumer group name"
+// @Param        body   body   handlers.StreamAckRequest  true  "Entry IDs"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{}  "Number of entries acknowledged"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters, body or IDs"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document or consumer group not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/streams/groups/ack [post]
+func streamGroupAckHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		group, err := getQueryParam(r, "group")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var req StreamAckRequest
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+		if len(req.IDs) == 0 {
+			respondWithError(w, http.StatusBadRequest, "at least one ID is required")
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		acked, err := database.AckStream(db.StreamAckOptions{
+			StreamOpOptions: db.StreamOpOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
+			},
+			Group: group,
+			IDs:   req.IDs,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+			"acked":  acked,
+		})
+	}
+}
+
+// streamGroupClaimHandler handles POST /documents/streams/groups/claim
+//
+// @Summary      Claim idle pending entries
+// @Description  Hands the pending entries of a consumer group delivered at least `min_idle` ago, oldest ID first, to `consumer` and returns them, so a consumer can take over the work of one that stopped. Pending entries whose stream entry was trimmed are acknowledged instead of returned.
+// @Tags         streams
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key       query  string  true   "Document key"
+// @Param        cf        query  string  false  "Column family (default: 'default')"
+// @Param        group     query  string  true   "Consumer group name"
+// @Param        consumer  query  string  true   "Consumer to hand the entries to"
+// @Param        min_idle  query  string  false  "Only claim entries delivered at least this long ago, as a duration (e.g. '5m') or in seconds (default: 0)"
+// @Param        count     query  int     false  "Maximum number of entries (default: no limit)"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{}  "Claimed entries with their IDs"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or document is not a stream"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document or consumer group not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/streams/groups/claim [post]
+func streamGroupClaimHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		group, err := getQueryParam(r, "group")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		consumer, err := getQueryParam(r, "consumer")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		count, ok := getStreamCountParam(w, r)
+		if !ok {
+			return
+		}
+
+		claimOpts := db.StreamClaimOptions{
+			Group:    group,
+			Consumer: consumer,
+			Limit:    count,
+		}
+		if s := r.URL.Query().Get("min_idle"); s != "" {
+			if claimOpts.MinIdle, err = parseLeaseParam(s); err != nil || claimOpts.MinIdle < 0 {
+				respondWithError(w, http.StatusBadRequest, "min_idle must be a non-negative duration")
+				return
+			}
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+		claimOpts.StreamOpOptions = db.StreamOpOptions{
+			ColumnFamily: cf,
+			Key:          key,
+			WriteOptions: opts,
+			TxnID:        getTxnQueryParam(r),
+		}
+
+		entries, err := database.ClaimStream(claimOpts)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status":  "ok",
+			"entries": entries,
+		})
+	}
+}
+
+// streamGroupPendingHandler handles GET /documents/streams/groups/pending
+//
+// @Summary      List pending entries
+// @Description  Lists the entries delivered to a consumer group and not yet acknowledged, in ID order, with their consumer, last delivery time and number of deliveries.
+// @Tags         streams
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key       query  string  true   "Document key"
+// @Param        cf        query  string  false  "Column family (default: 'default')"
+// @Param        group     query  string  true   "Consumer group name"
+// @Param        consumer  query  string  false  "Only list the entries of this consumer"
+// @Param        after     query  string  false  "Only list entries after this ID"
+// @Param        count     query  int     false  "Maximum number of entries (default: no limit)"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200   {object}  map[string]interface{}  "Pending entries"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or document is not a stream"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document or consumer group not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/streams/groups/pending [get]
+func streamGroupPendingHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		group, err := getQueryParam(r, "group")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		count, ok := getStreamCountParam(w, r)
+		if !ok {
+			return
+		}
+		read, release, ok := resolveStreamRead(w, r, database, defaults)
+		if !ok {
+			return
+		}
+		defer release()
+
+		pending, err := database.GetStreamPending(db.StreamPendingOptions{
+			StreamReadOptions: read,
+			Group:             group,
+			Consumer:          r.URL.Query().Get("consumer"),
+			After:             r.URL.Query().Get("after"),
+			Limit:             count,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status":  "ok",
+			"pending": pending,
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"net/http"
+)
+
+// streamRangeHandler handles GET /documents/streams/range
+//
+// @Summary      Read stream entries by ID
+// @Description  Returns the entries of a "stream" document with IDs between start and end, inclusive, oldest first or, with reverse, newest first. "-" and "+" stand for the first and last entries; a bare "<ms>" end includes every entry of that millisecond. Use reverse with count to read the latest entries.
+// @Tags         streams
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key      query  string  true   "Document key"
+// @Param        cf       query  string  false  "Column family (default: 'default')"
+// @Param        start    query  string  false  "First ID (default: '-')"
+// @Param        end      query  string  false  "Last ID (default: '+')"
+// @Param        count    query  int     false  "Maximum number of entries (default: no limit)"
+// @Param        reverse  query  bool    false  "Return the newest entries first"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200   {object}  map[string]interface{}  "Entries with their IDs"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or document is not a stream"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/streams/range [get]
+func streamRangeHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		read, release, ok := resolveStreamRead(w, r, database, defaults)
+		if !ok {
+			return
+		}
+		defer release()
+		count, ok := getStreamCountParam(w, r)
+		if !ok {
+			return
+		}
+
+		entries, err := database.GetStreamRange(db.StreamRangeOptions{
+			StreamReadOptions: read,
+			Start:             r.URL.Query().Get("start"),
+			End:               r.URL.Query().Get("end"),
+			Limit:             count,
+			Reverse:           r.URL.Query().Get("reverse") == "true",
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status":  "ok",
+			"entries": entries,
+		})
+	}
+}
+
+// streamTailHandler handles GET /documents/streams/tail
+//
+// @Summary      Follow a stream
+// @Description  Returns the entries of a "stream" document appended after the ID `after`, or after its latest entry when omitted, and `last`, the ID to pass as `after` on the next call. With `block`, waits for entries while there are none. A stream that does not exist yet is followed from its first entry.
+// @Tags         streams
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key    query  string  true   "Document key"
+// @Param        cf     query  string  false  "Column family (default: 'default')"
+// @Param        after  query  string  false  "Only return entries after this ID (default: '$', the latest entry)"
+// @Param        count  query  int     false  "Maximum number of entries (default: no limit)"
+// @Param        block  query  number  false  "Seconds to wait for entries while there are none (max 300). Not allowed with snapshot."
+// @Success      200   {object}  map[string]interface{}  "Entries with their IDs, and the ID to continue after"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or document is not a stream"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/streams/tail [get]
+func streamTailHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		block, err := getBlockQueryParam(r)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if block > 0 && r.URL.Query().Get("snapshot") != "" {
+			respondWithError(w, http.StatusBadRequest, "block cannot be used with a pinned snapshot")
+			return
+		}
+		read, release, ok := resolveStreamRead(w, r, database, defaults)
+		if !ok {
+			return
+		}
+		defer release()
+		count, ok := getStreamCountParam(w, r)
+		if !ok {
+			return
+		}
+
+		entries, last, err := database.TailStream(r.Context(), db.StreamTailOptions{
+			StreamReadOptions: read,
+			After:             r.URL.Query().Get("after"),
+			Limit:             count,
+		}, block)
+		if err != nil {
+			if r.Context().Err() != nil {
+				return // The client went away while waiting.
+			}
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status":  "ok",
+			"entries": entries,
+			"last":    last,
+		})
+	}
+}
+
+// streamInfoHandler handles GET /documents/streams/info
+//
+// @Summary      Describe a stream
+// @Description  Returns the length, first and last entry IDs and consumer groups of a "stream" document.
+// @Tags         streams
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key   query  string  true   "Document key"
+// @Param        cf    query  string  false  "Column family (default: 'default')"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200   {object}  db.StreamInfo
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or document is not a stream"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/streams/info [get]
+func streamInfoHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		read, release, ok := resolveStreamRead(w, r, database, defaults)
+		if !ok {
+			return
+		}
+		defer release()
+
+		info, err := database.GetStreamInfo(read)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, info)
+	}
+}
+
+// resolveStreamRead parses the parameters shared by stream reads. The returned release
+// function must be called once the read is done.
+func resolveStreamRead(w http.ResponseWriter, r *http.Request, database *db.DB, defaults config.ReadOptionsConfig) (db.StreamReadOptions, func(), bool) {
+	cf, err := getCfQueryParam(r)
+	if err != nil {
+		mapAndRespondWithError(w, err)
+		return db.StreamReadOptions{}, nil, false
+	}
+	key, err := getQueryParam(r, "key")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return db.StreamReadOptions{}, nil, false
+	}
+
+	readOpts, release, err := database.ResolveReadOptions(r, defaults)
+	if err != nil {
+		mapAndRespondWithError(w, err)
+		return db.StreamReadOptions{}, nil, false
+	}
+	return db.StreamReadOptions{
+		ColumnFamily: cf,
+		Key:          key,
+		ReadOptions:  readOpts,
+	}, release, true
+}
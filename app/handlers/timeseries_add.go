@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"math"
+	"mithrildb/config"
+	"mithrildb/db"
+	"mithrildb/model"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// TimeSeriesSampleRequest represents a sample to add to a time series.
+// @Description Sample value with an optional timestamp and labels.
+type TimeSeriesSampleRequest struct {
+	// Unix time in milliseconds (default: now)
+	Timestamp *int64            `json:"timestamp,omitempty" example:"1718035200000"`
+	Value     *float64          `json:"value" example:"21.5"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+// TimeSeriesAddRequest represents a request to add samples to a time series.
+// @Description A single sample, or several in samples.
+type TimeSeriesAddRequest struct {
+	TimeSeriesSampleRequest
+	Samples []TimeSeriesSampleRequest `json:"samples,omitempty"`
+}
+
+// timeSeriesAddHandler handles POST /documents/timeseries/add
+//
+// @Summary      Add samples to a time series
+// @Description  Adds samples to a document of type "timeseries", creating the series if it does not exist, and returns its length. A sample replaces the one with the same timestamp. Samples older than the retention period before the newest sample are rejected; the compaction rules of the series update their destination series in the same write.
+// @Tags         timeseries
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key   query  string  true   "Document key"
+// @Param        cf    query  string  false  "Column family (default: 'default')"
+// @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        body  body  handlers.TimeSeriesAddRequest  true  "Samples to add"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{}  "Length of the series"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters, body or samples, or document is not a time series"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/timeseries/add [post]
+func timeSeriesAddHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		var req TimeSeriesAddRequest
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+		requested := req.Samples
+		if req.Value != nil || req.Timestamp != nil {
+			requested = append([]TimeSeriesSampleRequest{req.TimeSeriesSampleRequest}, requested...)
+		}
+		if len(requested) == 0 {
+			respondWithError(w, http.StatusBadRequest, "at least one sample is required")
+			return
+		}
+		now := time.Now().UnixMilli()
+		samples := make([]model.TimeSeriesSample, len(requested))
+		for i, s := range requested {
+			if s.Value == nil || math.IsNaN(*s.Value) || math.IsInf(*s.Value, 0) {
+				respondWithError(w, http.StatusBadRequest, "every sample needs a finite value")
+				return
+			}
+			samples[i] = model.TimeSeriesSample{Timestamp: now, Value: *s.Value, Labels: s.Labels}
+			if s.Timestamp != nil {
+				samples[i].Timestamp = *s.Timestamp
+			}
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		length, err := database.AddTimeSeriesSamples(db.TimeSeriesAddOptions{
+			TimeSeriesOpOptions: db.TimeSeriesOpOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
+				Expiration:   expiration,
+			},
+			Samples: samples,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+			"length": length,
+		})
+	}
+}
+
+// timeSeriesDeleteHandler handles POST /documents/timeseries/delete
+//
+// @Summary      Delete time series samples
+// @Description  Removes the samples of a "timeseries" document with timestamps between from and to, inclusive, and returns how many were removed. Compaction rules recompute the buckets of the removed samples.
+// @Tags         timeseries
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key   query  string  true   "Document key"
+// @Param        cf    query  string  false  "Column family (default: 'default')"
+// @Param        from  query  int     false  "First timestamp, in Unix milliseconds (default: the oldest sample)"
+// @Param        to    query  int     false  "Last timestamp, in Unix milliseconds (default: the newest sample)"
+// @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{}  "Number of samples removed"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or document is not a time series"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/timeseries/delete [post]
+func timeSeriesDeleteHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		from, to, ok := getTimeSeriesRangeParams(w, r)
+		if !ok {
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		removed, err := database.DeleteTimeSeriesRange(db.TimeSeriesDeleteOptions{
+			TimeSeriesOpOptions: db.TimeSeriesOpOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
+				Expiration:   expiration,
+			},
+			From: from,
+			To:   to,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status":  "ok",
+			"removed": removed,
+		})
+	}
+}
+
+// timeSeriesConfigHandler handles POST /documents/timeseries/config
+//
+// @Summary      Configure a time series
+// @Description  Sets the retention period and compaction rules of a "timeseries" document, creating the series if it does not exist. Samples older than the retention period before the newest sample are dropped. A compaction rule keeps another series in the same column family updated with one aggregated sample per bucket of the samples added from then on.
+// @Tags         timeseries
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key   query  string  true   "Document key"
+// @Param        cf    query  string  false  "Column family (default: 'default')"
+// @Param        body  body  model.TimeSeriesSettings  true  "Retention and compaction rules; omitted settings are cleared"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{}  "Settings of the series"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or settings, or document is not a time series"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/timeseries/config [post]
+func timeSeriesConfigHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var settings model.TimeSeriesSettings
+		if err := decodeBody(r, &settings); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		err = database.ConfigureTimeSeries(db.TimeSeriesConfigureOptions{
+			TimeSeriesOpOptions: db.TimeSeriesOpOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
+			},
+			Settings: settings,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status":   "ok",
+			"settings": settings,
+		})
+	}
+}
+
+// getTimeSeriesRangeParams parses the optional from and to parameters, in Unix milliseconds,
+// which default to the whole series.
+func getTimeSeriesRangeParams(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	from, to := int64(0), int64(math.MaxInt64)
+	q := r.URL.Query()
+	if s := q.Get("from"); s != "" {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || n < 0 {
+			respondWithError(w, http.StatusBadRequest, "from must be a non-negative timestamp in milliseconds")
+			return 0, 0, false
+		}
+		from = n
+	}
+	if s := q.Get("to"); s != "" {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || n < from {
+			respondWithError(w, http.StatusBadRequest, "to must be a timestamp in milliseconds not lower than from")
+			return 0, 0, false
+		}
+		to = n
+	}
+	return from, to, true
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"net/http"
+	"strings"
+)
+
+// timeSeriesRangeHandler handles GET /documents/timeseries/range
+//
+// @Summary      Read time series samples
+// @Description  Returns the samples of a "timeseries" document with timestamps between from and to, inclusive, oldest first or, with reverse, newest first. label filters keep the samples with every given label. With aggregation and bucket, returns one aggregate per bucket with samples instead, buckets starting at multiples of the bucket duration since the Unix epoch.
+// @Tags         timeseries
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key          query  string  true   "Document key"
+// @Param        cf           query  string  false  "Column family (default: 'default')"
+// @Param        from         query  int     false  "First timestamp, in Unix milliseconds (default: the oldest sample)"
+// @Param        to           query  int     false  "Last timestamp, in Unix milliseconds (default: the newest sample)"
+// @Param        label        query  []string  false  "Label filter as 'name:value'; repeat for several labels"  collectionFormat(multi)
+// @Param        aggregation  query  string  false  "Aggregate samples with min, max, avg, sum or count"
+// @Param        bucket       query  string  false  "Bucket duration for aggregation (e.g. '1h') or in seconds"
+// @Param        count        query  int     false  "Maximum number of samples or buckets (default: no limit)"
+// @Param        reverse      query  bool    false  "Return the newest samples or buckets first"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200   {object}  map[string]interface{}  "Samples, or buckets with aggregation"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or document is not a time series"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/timeseries/range [get]
+func timeSeriesRangeHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		from, to, ok := getTimeSeriesRangeParams(w, r)
+		if !ok {
+			return
+		}
+		count, ok := getStreamCountParam(w, r)
+		if !ok {
+			return
+		}
+		labels := make(map[string]string)
+		for _, l := range q["label"] {
+			name, value, found := strings.Cut(l, ":")
+			if !found || name == "" {
+				respondWithError(w, http.StatusBadRequest, "label must be 'name:value'")
+				return
+			}
+			labels[name] = value
+		}
+		aggregation := q.Get("aggregation")
+		if (aggregation == "") != (q.Get("bucket") == "") {
+			respondWithError(w, http.StatusBadRequest, "aggregation and bucket must be given together")
+			return
+		}
+
+		read, release, ok := resolveStreamRead(w, r, database, defaults)
+		if !ok {
+			return
+		}
+		defer release()
+
+		rangeOpts := db.TimeSeriesRangeOptions{
+			TimeSeriesReadOptions: read,
+			From:                  from,
+			To:                    to,
+			Labels:                labels,
+			Limit:                 count,
+			Reverse:               q.Get("reverse") == "true",
+		}
+		if aggregation == "" {
+			samples, err := database.GetTimeSeriesRange(rangeOpts)
+			if err != nil {
+				mapAndRespondWithError(w, err)
+				return
+			}
+			respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+				"status":  "ok",
+				"samples": samples,
+			})
+			return
+		}
+
+		bucket, err := parseLeaseParam(q.Get("bucket"))
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "bucket must be a duration")
+			return
+		}
+		buckets, err := database.AggregateTimeSeries(db.TimeSeriesAggregateOptions{
+			TimeSeriesRangeOptions: rangeOpts,
+			Aggregation:            aggregation,
+			Bucket:                 bucket,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status":  "ok",
+			"buckets": buckets,
+		})
+	}
+}
+
+// timeSeriesInfoHandler handles GET /documents/timeseries/info
+//
+// @Summary      Describe a time series
+// @Description  Returns the length, first and last sample timestamps, retention and compaction rules of a "timeseries" document.
+// @Tags         timeseries
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key   query  string  true   "Document key"
+// @Param        cf    query  string  false  "Column family (default: 'default')"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200   {object}  db.TimeSeriesInfo
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or document is not a time series"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/timeseries/info [get]
+func timeSeriesInfoHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		read, release, ok := resolveStreamRead(w, r, database, defaults)
+		if !ok {
+			return
+		}
+		defer release()
+
+		info, err := database.GetTimeSeriesInfo(read)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, info)
+	}
+}
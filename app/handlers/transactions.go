@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"net/http"
+)
+
+// transactionBeginHandler handles POST /transactions
+//
+// @Summary      Begin an interactive transaction
+// @Description  Opens a transaction with a snapshot and returns its ID. Pass the ID as 'txn' to document, counter, list, set and batch endpoints to run them inside the transaction, then commit or roll it back. Documents read or written in the transaction are locked until it ends; locking a document that changed after the transaction began fails with 409. Idle transactions are rolled back automatically.
+// @Tags         transactions
+// @Produce      json
+// @Param        sync         query  bool  false  "Optional RocksDB sync write option used at commit"
+// @Param        disable_wal  query  bool  false  "Optional RocksDB disable WAL write option"
+// @Param        no_slowdown  query  bool  false  "Optional RocksDB no slowdown write option"
+// @Success      201  {object}  db.TransactionInfo
+// @Failure      429  {object}  handlers.ErrorResponse  "Too many open transactions"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /transactions [post]
+func transactionBeginHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if database.Transactions == nil {
+			respondWithError(w, http.StatusServiceUnavailable, "interactive transactions are disabled")
+			return
+		}
+
+		// The transaction keeps its own copy of the write options.
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		info, err := database.Transactions.Begin(opts)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		respondWithJSON(w, http.StatusCreated, info)
+	}
+}
+
+// transactionCommitHandler handles POST /transactions/commit
+//
+// @Summary      Commit an interactive transaction
+// @Description  Commits every change made in the transaction atomically and releases its locks.
+// @Tags         transactions
+// @Produce      json
+// @Param        txn  query  string  true  "Transaction ID"
+// @Success      200  {object}  map[string]string  "Transaction committed"
+// @Failure      400  {object}  handlers.ErrorResponse  "Missing transaction ID"
+// @Failure      404  {object}  handlers.ErrorResponse  "Transaction not found or already finished"
+// @Failure      409  {object}  handlers.ErrorResponse  "Transaction conflict; the transaction was rolled back"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /transactions/commit [post]
+func transactionCommitHandler(database *db.DB) http.HandlerFunc {
+	return transactionEndHandler(database, "committed", func(m *db.TransactionManager, id string) error {
+		return m.Commit(id)
+	})
+}
+
+// transactionRollbackHandler handles POST /transactions/rollback
+//
+// @Summary      Roll back an interactive transaction
+// @Description  Discards every change made in the transaction and releases its locks.
+// @Tags         transactions
+// @Produce      json
+// @Param        txn  query  string  true  "Transaction ID"
+// @Success      200  {object}  map[string]string  "Transaction rolled back"
+// @Failure      400  {object}  handlers.ErrorResponse  "Missing transaction ID"
+// @Failure      404  {object}  handlers.ErrorResponse  "Transaction not found or already finished"
+// @Failure      500  {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /transactions/rollback [post]
+func transactionRollbackHandler(database *db.DB) http.HandlerFunc {
+	return transactionEndHandler(database, "rolled_back", func(m *db.TransactionManager, id string) error {
+		return m.Rollback(id)
+	})
+}
+
+// transactionEndHandler finishes the transaction named by the 'txn' parameter.
+func transactionEndHandler(database *db.DB, status string, end func(m *db.TransactionManager, id string) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := getQueryParam(r, "txn")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if database.Transactions == nil {
+			mapAndRespondWithError(w, db.ErrTransactionNotFound)
+			return
+		}
+
+		if err := end(database.Transactions, id); err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		respondWithJSON(w, http.StatusOK, map[string]string{
+			"status": status,
+			"txn":    id,
+		})
+	}
+}
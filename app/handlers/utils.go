@@ -50,8 +50,17 @@ func getQueryParam(r *http.Request, key string) (string, error) {
 	return val, nil
 }
 
+// getCasQueryParam returns the revision from the 'cas' parameter, or from the If-Match header
+// when the parameter is absent.
 func getCasQueryParam(r *http.Request) string {
-	return r.URL.Query().Get("cas")
+	if cas := r.URL.Query().Get("cas"); cas != "" {
+		return cas
+	}
+	return ifMatchCas(r)
+}
+
+func getTxnQueryParam(r *http.Request) string {
+	return r.URL.Query().Get("txn")
 }
 
 func getCfQueryParam(r *http.Request) (string, error) {
@@ -110,6 +119,28 @@ func mapErrorToResponse(err error) (int, string) {
 		return http.StatusBadRequest, err.Error()
 	case errors.Is(err, model.ErrInvalidDocumentKey):
 		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, model.ErrInvalidPath):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, model.ErrPathNotFound):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, model.ErrPathMismatch):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, model.ErrPathExists):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, db.ErrInvalidBatchOperation):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrInvalidKeyRange):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrNilValue):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, model.ErrInvalidExpiration):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, model.ErrInvalidMutation):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, model.ErrPatchTestFailed):
+		return http.StatusPreconditionFailed, err.Error()
+	case errors.Is(err, model.ErrInvalidPatch):
+		return http.StatusBadRequest, err.Error()
 	case errors.Is(err, db.ErrKeyNotFound):
 		return http.StatusNotFound, err.Error()
 	case errors.Is(err, db.ErrRevisionMismatch):
@@ -120,16 +151,92 @@ func mapErrorToResponse(err error) (int, string) {
 		return http.StatusBadRequest, err.Error()
 	case errors.Is(err, db.ErrEmptyList):
 		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrInvalidListOperation):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrListIndexOutOfRange):
+		return http.StatusBadRequest, err.Error()
 	case errors.Is(err, model.ErrInvalidCounterValue):
 		return http.StatusBadRequest, err.Error()
 	case errors.Is(err, model.ErrInvalidCounterType):
 		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrCounterOverflow):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, db.ErrCounterOutOfRange):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, db.ErrInvalidCounterOperation):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrTransactionNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, db.ErrTooManyTransactions):
+		return http.StatusTooManyRequests, err.Error()
+	case errors.Is(err, db.ErrTransactionConflict):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, model.ErrInvalidValue):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, model.ErrUnsupportedValue):
+		return http.StatusBadRequest, err.Error()
 	case errors.Is(err, db.ErrInvalidSetType):
 		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrInvalidSetOperation):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrInvalidZSetType):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrInvalidZSetOperation):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrMemberNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, db.ErrInvalidHashType):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrInvalidHashOperation):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrFieldNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, db.ErrInvalidBlobType):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrInvalidStreamType):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrInvalidStreamOperation):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrStreamGroupNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, db.ErrStreamGroupExists):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, db.ErrInvalidTimeSeriesType):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrInvalidTimeSeriesOperation):
+		return http.StatusBadRequest, err.Error()
 	case errors.Is(err, db.ErrFamilyExists):
 		return http.StatusConflict, err.Error()
 	case errors.Is(err, db.ErrInvalidUserColumnFamily):
 		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrInvalidFamilySettings):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrHistoryDisabled):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrRevisionNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, db.ErrRevisionValueNotKept):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, db.ErrSnapshotNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, db.ErrTooManySnapshots):
+		return http.StatusTooManyRequests, err.Error()
+	case errors.Is(err, db.ErrInvalidSnapshotLease):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrQueuesDisabled):
+		return http.StatusServiceUnavailable, err.Error()
+	case errors.Is(err, db.ErrQueueNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, db.ErrInvalidQueueName):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrInvalidQueueSettings):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrInvalidQueueOperation):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, db.ErrQueueMessageNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, db.ErrQueueReceiptMismatch):
+		return http.StatusConflict, err.Error()
 	default:
 		return http.StatusInternalServerError, "internal server error"
 	}
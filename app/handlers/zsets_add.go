@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"mithrildb/model"
+	"net/http"
+)
+
+// ZAddRequest represents a request to add members to a sorted set.
+// @Description Members with their scores, and flags controlling which members are changed.
+type ZAddRequest struct {
+	Entries []model.ZSetEntry `json:"entries"`
+	NX      bool              `json:"nx"` // Only add new members
+	XX      bool              `json:"xx"` // Only update existing members
+	GT      bool              `json:"gt"` // Only update when the new score is greater
+	LT      bool              `json:"lt"` // Only update when the new score is lower
+}
+
+// ZIncrByRequest represents a request to increment the score of a sorted set member.
+// @Description Member and the amount to add to its score.
+type ZIncrByRequest struct {
+	Member    *string `json:"member"`
+	Increment float64 `json:"increment"`
+}
+
+// zsetAddHandler handles POST /documents/zsets/add
+//
+// @Summary      Add members to sorted set
+// @Description  Adds members to a document of type "zset" or updates their scores. nx only adds new members, xx only updates existing ones, and gt/lt only update a score when the new one is greater/lower.
+// @Tags         zsets
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key   query     string                  true  "Document key"
+// @Param        cf    query     string                  false "Column family (default: 'default')"
+// @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        body  body      handlers.ZAddRequest    true  "Members and scores to add"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{} "Number of members added and updated"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters, body or flag combination"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/zsets/add [post]
+func zsetAddHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		var req ZAddRequest
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		res, err := database.ZAdd(db.ZAddOptions{
+			ZSetOpOptions: db.ZSetOpOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
+				Expiration:   expiration,
+			},
+			Entries: req.Entries,
+			NX:      req.NX,
+			XX:      req.XX,
+			GT:      req.GT,
+			LT:      req.LT,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status":  "ok",
+			"added":   res.Added,
+			"updated": res.Updated,
+		})
+	}
+}
+
+// zsetIncrByHandler handles POST /documents/zsets/incrby
+//
+// @Summary      Increment sorted set member score
+// @Description  Adds an increment to the score of a member of a "zset" document, adding the member with the increment as its score when missing.
+// @Tags         zsets
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key   query     string                  true  "Document key"
+// @Param        cf    query     string                  false "Column family (default: 'default')"
+// @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        body  body      handlers.ZIncrByRequest true  "Member and increment"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{} "New score of the member"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or body"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/zsets/incrby [post]
+func zsetIncrByHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		var req ZIncrByRequest
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+		if req.Member == nil {
+			respondWithErrInvalidJSONBody(w)
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		score, err := database.ZIncrBy(db.ZIncrByOptions{
+			ZSetOpOptions: db.ZSetOpOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
+				Expiration:   expiration,
+			},
+			Member:    *req.Member,
+			Increment: req.Increment,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+			"score":  score,
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"mithrildb/model"
+	"net/http"
+	"strconv"
+)
+
+// zsetScoreHandler handles GET /documents/zsets/score
+//
+// @Summary      Get sorted set member score
+// @Description  Returns the score of a member of a "zset" document.
+// @Tags         zsets
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key     query     string  true   "Document key"
+// @Param        cf      query     string  false  "Column family (default: 'default')"
+// @Param        member  query     string  true   "Member to look up"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200     {object}  map[string]interface{}  "Score of the member"
+// @Failure      400     {object}  handlers.ErrorResponse  "Missing or invalid parameters"
+// @Failure      404     {object}  handlers.ErrorResponse  "Document or member not found"
+// @Failure      500     {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/zsets/score [get]
+func zsetScoreHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		member, err := getQueryParam(r, "member")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		opts, release, err := database.ResolveReadOptions(r, defaults)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		defer release()
+
+		score, err := database.ZScore(db.ZSetReadOptions{
+			ColumnFamily: cf,
+			Key:          key,
+			ReadOptions:  opts,
+		}, member)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+			"score":  score,
+		})
+	}
+}
+
+// zsetRankHandler handles GET /documents/zsets/rank
+//
+// @Summary      Get sorted set member rank
+// @Description  Returns the 0-based rank of a member of a "zset" document, counting from the lowest score or, with reverse, from the highest.
+// @Tags         zsets
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key     query     string  true   "Document key"
+// @Param        cf      query     string  false  "Column family (default: 'default')"
+// @Param        member  query     string  true   "Member to look up"
+// @Param        reverse query     bool    false  "Rank from the highest score"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200     {object}  map[string]interface{}  "Rank of the member"
+// @Failure      400     {object}  handlers.ErrorResponse  "Missing or invalid parameters"
+// @Failure      404     {object}  handlers.ErrorResponse  "Document or member not found"
+// @Failure      500     {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/zsets/rank [get]
+func zsetRankHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		member, err := getQueryParam(r, "member")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		reverse, _ := strconv.ParseBool(r.URL.Query().Get("reverse"))
+
+		opts, release, err := database.ResolveReadOptions(r, defaults)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		defer release()
+
+		rank, err := database.ZRank(db.ZSetReadOptions{
+			ColumnFamily: cf,
+			Key:          key,
+			ReadOptions:  opts,
+		}, member, reverse)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+			"rank":   rank,
+		})
+	}
+}
+
+// zsetRangeHandler handles GET /documents/zsets/range
+//
+// @Summary      Get sorted set members by rank
+// @Description  Returns the members of a "zset" document between two ranks, both inclusive. Negative ranks count from the end (-1 is the last member).
+// @Tags         zsets
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key     query     string  true   "Document key"
+// @Param        cf      query     string  false  "Column family (default: 'default')"
+// @Param        start   query     int     false  "Start rank (inclusive, default: 0)"
+// @Param        stop    query     int     false  "Stop rank (inclusive, default: -1)"
+// @Param        reverse query     bool    false  "Rank from the highest score"
+// @Param        fill_cache query bool false "Read option: whether to fill RocksDB cache"
+// @Param        read_tier  query string false "Read option: 'all' or 'cache-only'"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200     {object}  map[string]interface{}  "Members with their scores"
+// @Failure      400     {object}  handlers.ErrorResponse  "Missing or invalid parameters"
+// @Failure      404     {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500     {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/zsets/range [get]
+func zsetRangeHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		start, stop := 0, -1
+		if s := r.URL.Query().Get("start"); s != "" {
+			if start, err = strconv.Atoi(s); err != nil {
+				respondWithError(w, http.StatusBadRequest, "start must be an integer")
+				return
+			}
+		}
+		if s := r.URL.Query().Get("stop"); s != "" {
+			if stop, err = strconv.Atoi(s); err != nil {
+				respondWithError(w, http.StatusBadRequest, "stop must be an integer")
+				return
+			}
+		}
+		reverse, _ := strconv.ParseBool(r.URL.Query().Get("reverse"))
+
+		opts, release, err := database.ResolveReadOptions(r, defaults)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		defer release()
+
+		entries, err := database.ZRange(db.ZRangeOptions{
+			ZSetReadOptions: db.ZSetReadOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				ReadOptions:  opts,
+			},
+			Start:   start,
+			Stop:    stop,
+			Reverse: reverse,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"entries": entries,
+		})
+	}
+}
+
+// zsetRangeByScoreHandler handles GET /documents/zsets/range-by-score
+//
+// @Summary      Get sorted set members by score
+// @Description  Returns the members of a "zset" document whose score lies between min and max. Bounds accept "-inf" and "+inf", and a "(" prefix makes them exclusive.
+// @Tags         zsets
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key     query     string  true   "Document key"
+// @Param        cf      query     string  false  "Column family (default: 'default')"
+// @Param        min     query     string  false  "Lowest score (default: -inf)"
+// @Param        max     query     string  false  "Highest score (default: +inf)"
+// @Param        offset  query     int     false  "Number of matching members to skip"
+// @Param        limit   query     int     false  "Maximum number of members to return (default: no limit)"
+// @Param        reverse query     bool    false  "Return the highest scores first"
+// @Param        fill_cache query bool false "Read option: whether to fill RocksDB cache"
+// @Param        read_tier  query string false "Read option: 'all' or 'cache-only'"
+// @Param        snapshot  query  string  false  "Pinned snapshot ID returned by POST /snapshots to read from"
+// @Success      200     {object}  map[string]interface{}  "Members with their scores"
+// @Failure      400     {object}  handlers.ErrorResponse  "Missing or invalid parameters"
+// @Failure      404     {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500     {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/zsets/range-by-score [get]
+func zsetRangeByScoreHandler(database *db.DB, defaults config.ReadOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		q := r.URL.Query()
+		minStr, maxStr := q.Get("min"), q.Get("max")
+		if minStr == "" {
+			minStr = "-inf"
+		}
+		if maxStr == "" {
+			maxStr = "+inf"
+		}
+		min, err := model.ParseScoreBound(minStr)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		max, err := model.ParseScoreBound(maxStr)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		offset, limit := 0, 0
+		if s := q.Get("offset"); s != "" {
+			if offset, err = strconv.Atoi(s); err != nil || offset < 0 {
+				respondWithError(w, http.StatusBadRequest, "offset must be a non-negative integer")
+				return
+			}
+		}
+		if s := q.Get("limit"); s != "" {
+			if limit, err = strconv.Atoi(s); err != nil || limit < 0 {
+				respondWithError(w, http.StatusBadRequest, "limit must be a non-negative integer")
+				return
+			}
+		}
+		reverse, _ := strconv.ParseBool(q.Get("reverse"))
+
+		opts, release, err := database.ResolveReadOptions(r, defaults)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		defer release()
+
+		entries, err := database.ZRangeByScore(db.ZRangeByScoreOptions{
+			ZSetReadOptions: db.ZSetReadOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				ReadOptions:  opts,
+			},
+			Min:     min,
+			Max:     max,
+			Offset:  offset,
+			Limit:   limit,
+			Reverse: reverse,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"entries": entries,
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"mithrildb/config"
+	"mithrildb/db"
+	"net/http"
+	"strconv"
+)
+
+// ZRemRequest represents a request to remove members from a sorted set.
+// @Description Members to remove.
+type ZRemRequest struct {
+	Members []string `json:"members"`
+}
+
+// zsetRemoveHandler handles POST /documents/zsets/remove
+//
+// @Summary      Remove members from sorted set
+// @Description  Removes members from a document of type "zset". Members not in the set are ignored.
+// @Tags         zsets
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key   query     string                  true  "Document key"
+// @Param        cf    query     string                  false "Column family (default: 'default')"
+// @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        body  body      handlers.ZRemRequest    true  "Members to remove"
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{} "Number of members removed"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters or body"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/zsets/remove [post]
+func zsetRemoveHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		var req ZRemRequest
+		if err := decodeBody(r, &req); err != nil {
+			respondWithDecodeError(w, err)
+			return
+		}
+		if len(req.Members) == 0 {
+			respondWithErrInvalidJSONBody(w)
+			return
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		removed, err := database.ZRem(db.ZRemOptions{
+			ZSetOpOptions: db.ZSetOpOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
+				Expiration:   expiration,
+			},
+			Members: req.Members,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status":  "ok",
+			"removed": removed,
+		})
+	}
+}
+
+// zsetPopMinHandler handles POST /documents/zsets/popmin
+//
+// @Summary      Pop lowest scored members
+// @Description  Removes and returns up to count members with the lowest scores from a "zset" document.
+// @Tags         zsets
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key   query     string  true  "Document key"
+// @Param        cf    query     string  false "Column family (default: 'default')"
+// @Param        count query     int     false "Number of members to pop (default: 1)"
+// @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{} "Popped members with their scores, lowest first"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/zsets/popmin [post]
+func zsetPopMinHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return zsetPopHandler(database, defaults, false)
+}
+
+// zsetPopMaxHandler handles POST /documents/zsets/popmax
+//
+// @Summary      Pop highest scored members
+// @Description  Removes and returns up to count members with the highest scores from a "zset" document.
+// @Tags         zsets
+// @Accept       json,application/msgpack,application/cbor
+// @Produce      json,application/msgpack,application/cbor
+// @Param        key   query     string  true  "Document key"
+// @Param        cf    query     string  false "Column family (default: 'default')"
+// @Param        count query     int     false "Number of members to pop (default: 1)"
+// @Param        expiration  query  int  false  "Optional expiration. TTL in seconds (<= 30d) or absolute Unix timestamp (> 30d). Omit to keep existing expiration."
+// @Param        txn  query  string  false  "Interactive transaction ID returned by POST /transactions"
+// @Success      200   {object}  map[string]interface{} "Popped members with their scores, highest first"
+// @Failure      400   {object}  handlers.ErrorResponse  "Invalid parameters"
+// @Failure      404   {object}  handlers.ErrorResponse  "Document not found"
+// @Failure      500   {object}  handlers.ErrorResponse  "Internal server error"
+// @Router       /documents/zsets/popmax [post]
+func zsetPopMaxHandler(database *db.DB, defaults config.WriteOptionsConfig) http.HandlerFunc {
+	return zsetPopHandler(database, defaults, true)
+}
+
+// zsetPopHandler implements popmin and popmax.
+func zsetPopHandler(database *db.DB, defaults config.WriteOptionsConfig, max bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cf, err := getCfQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		key, err := getQueryParam(r, "key")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		expiration, err := getExpirationQueryParam(r)
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+
+		count := 1
+		if countStr := r.URL.Query().Get("count"); countStr != "" {
+			val, err := strconv.Atoi(countStr)
+			if err != nil || val <= 0 {
+				respondWithError(w, http.StatusBadRequest, "count must be a positive integer")
+				return
+			}
+			count = val
+		}
+
+		opts := database.DefaultWriteOptions
+		if db.HasWriteOptions(r) {
+			opts = db.BuildWriteOptions(r, defaults)
+			defer opts.Destroy()
+		}
+
+		popped, err := database.ZPop(db.ZPopOptions{
+			ZSetOpOptions: db.ZSetOpOptions{
+				ColumnFamily: cf,
+				Key:          key,
+				WriteOptions: opts,
+				TxnID:        getTxnQueryParam(r),
+				Expiration:   expiration,
+			},
+			Count: count,
+			Max:   max,
+		})
+		if err != nil {
+			mapAndRespondWithError(w, err)
+			return
+		}
+		respondWithPayload(w, r, http.StatusOK, map[string]interface{}{
+			"status":  "ok",
+			"entries": popped,
+		})
+	}
+}
@@ -49,7 +49,7 @@ func GetIndex(database *db.DB, name string) (*model.IndexDefinition, error) {
 		return nil, err
 	}
 
-	cf, ok := database.Families[model.CFIndexDefinitions]
+	cf, ok := database.Family(model.CFIndexDefinitions)
 	if !ok {
 		return nil, db.ErrInvalidColumnFamily
 	}
@@ -81,7 +81,7 @@ func DeleteIndex(database *db.DB, name string) error {
 		return err
 	}
 
-	cf, ok := database.Families[model.CFIndexDefinitions]
+	cf, ok := database.Family(model.CFIndexDefinitions)
 	if !ok {
 		return db.ErrInvalidColumnFamily
 	}
@@ -90,7 +90,7 @@ func DeleteIndex(database *db.DB, name string) error {
 }
 
 func ListIndexes(database *db.DB) ([]*model.IndexDefinition, error) {
-	cf, ok := database.Families[model.CFIndexDefinitions]
+	cf, ok := database.Family(model.CFIndexDefinitions)
 	if !ok {
 		return nil, db.ErrInvalidColumnFamily
 	}
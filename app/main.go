@@ -30,11 +30,23 @@ func main() {
 	// Setup expiration service (cron + stats)
 	expirer := bootstrap.InitExpirationService(database, cfg)
 
+	// Setup interactive transactions (sessions + idle reaper)
+	bootstrap.InitTransactionManager(database, cfg)
+
+	// Setup pinned snapshots (leases + reaper)
+	bootstrap.InitSnapshotManager(database, cfg)
+
+	// Setup job queues
+	bootstrap.InitQueueManager(database, cfg)
+
 	// Setup HTTP routes
 	handlers.SetupRoutes(database, expirer, &cfg, startTime)
 
 	addr := fmt.Sprintf(":%d", cfg.Server.Port)
-	server := &http.Server{Addr: addr}
+	server := &http.Server{
+		Addr:    addr,
+		Handler: handlers.LimitBodySize(http.DefaultServeMux, cfg.Server.MaxBodySize),
+	}
 
 	// Graceful shutdown handling
 	stop := make(chan os.Signal, 1)
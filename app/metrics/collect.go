@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"mithrildb/db"
 	"mithrildb/events"
 	"mithrildb/expiration"
 )
@@ -54,3 +55,58 @@ func GetEventSystemMetrics() *EventSystemMetrics {
 
 	return &EventSystemMetrics{Queues: queues}
 }
+
+func GetTransactionMetrics(m *db.TransactionManager) *TransactionMetrics {
+	if m == nil {
+		return nil
+	}
+	stats := m.Stats()
+
+	return &TransactionMetrics{
+		Open:       stats.Open,
+		MaxOpen:    stats.MaxOpen,
+		Committed:  stats.Committed,
+		RolledBack: stats.RolledBack,
+		Expired:    stats.Expired,
+	}
+}
+
+func GetSnapshotMetrics(m *db.SnapshotManager) *SnapshotMetrics {
+	if m == nil {
+		return nil
+	}
+	stats := m.Stats()
+
+	return &SnapshotMetrics{
+		Open:             stats.Open,
+		MaxOpen:          stats.MaxOpen,
+		Created:          stats.Created,
+		Released:         stats.Released,
+		Expired:          stats.Expired,
+		OldestAgeSeconds: stats.OldestAgeSeconds,
+	}
+}
+
+func GetJobQueueMetrics(m *db.QueueManager) []JobQueueMetrics {
+	if m == nil {
+		return nil
+	}
+	queues, err := m.List()
+	if err != nil {
+		return nil
+	}
+
+	result := make([]JobQueueMetrics, 0, len(queues))
+	for _, q := range queues {
+		result = append(result, JobQueueMetrics{
+			Name:             q.Name,
+			Depth:            q.Depth,
+			Ready:            q.Stats.Ready,
+			Delayed:          q.Stats.Delayed,
+			InFlight:         q.Stats.InFlight,
+			DeadLettered:     q.Stats.DeadLettered,
+			OldestAgeSeconds: q.OldestAgeSeconds,
+		})
+	}
+	return result
+}
@@ -20,10 +20,13 @@ type ServerMetrics struct {
 }
 
 type FullMetrics struct {
-	Server     ServerMetrics       `json:"server"`
-	RocksDB    map[string]any      `json:"rocksdb"`
-	Expiration *ExpirationMetrics  `json:"expiration,omitempty"`
-	Events     *EventSystemMetrics `json:"events,omitempty"`
+	Server       ServerMetrics       `json:"server"`
+	RocksDB      map[string]any      `json:"rocksdb"`
+	Expiration   *ExpirationMetrics  `json:"expiration,omitempty"`
+	Events       *EventSystemMetrics `json:"events,omitempty"`
+	Transactions *TransactionMetrics `json:"transactions,omitempty"`
+	Snapshots    *SnapshotMetrics    `json:"snapshots,omitempty"`
+	JobQueues    []JobQueueMetrics   `json:"job_queues,omitempty"`
 }
 
 type ExpirationMetrics struct {
@@ -45,3 +48,30 @@ type QueueMetrics struct {
 type EventSystemMetrics struct {
 	Queues []QueueMetrics `json:"queues"`
 }
+
+type TransactionMetrics struct {
+	Open       int    `json:"open"`
+	MaxOpen    int    `json:"max_open"`
+	Committed  uint64 `json:"committed"`
+	RolledBack uint64 `json:"rolled_back"`
+	Expired    uint64 `json:"expired"`
+}
+
+type SnapshotMetrics struct {
+	Open             int     `json:"open"`
+	MaxOpen          int     `json:"max_open"`
+	Created          uint64  `json:"created"`
+	Released         uint64  `json:"released"`
+	Expired          uint64  `json:"expired"`
+	OldestAgeSeconds float64 `json:"oldest_age_seconds"`
+}
+
+type JobQueueMetrics struct {
+	Name             string  `json:"name"`
+	Depth            int64   `json:"depth"`
+	Ready            int64   `json:"ready"`
+	Delayed          int64   `json:"delayed"`
+	InFlight         int64   `json:"in_flight"`
+	DeadLettered     uint64  `json:"dead_lettered"`
+	OldestAgeSeconds float64 `json:"oldest_age_seconds"`
+}
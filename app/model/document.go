@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -14,21 +16,56 @@ var (
 	ErrInvalidCounterType  = errors.New("unsupported value type for counter")
 	ErrInvalidDocumentKey  = errors.New("invalid document key")
 	ErrInvalidExpiration   = errors.New("invalid expiration value")
+	ErrInvalidValue        = errors.New("invalid document value")
 )
 
 const (
-	DocTypeJSON    = "json"
-	DocTypeCounter = "counter"
-	DocTypeList    = "list"
-	DocTypeSet     = "set"
+	DocTypeJSON       = "json"
+	DocTypeCounter    = "counter"
+	DocTypeList       = "list"
+	DocTypeSet        = "set"
+	DocTypeZSet       = "zset"
+	DocTypeHash       = "hash"
+	DocTypeBlob       = "blob"
+	DocTypeStream     = "stream"
+	DocTypeTimeSeries = "timeseries"
 )
 
 // Metadata holds system-level data associated with a document.
 type Metadata struct {
-	Rev        string    `json:"rev"`        // Revision ID for conflict resolution
+	Rev        string    `json:"rev"`        // Revision ID for conflict resolution: "<seq>-<hlc hex>"
+	Seq        uint64    `json:"seq"`        // Per-document revision counter, incremented on every write
+	HLC        HLC       `json:"hlc,string"` // Hybrid logical clock timestamp of the last write
 	Expiration int64     `json:"expiration"` // TTL as Unix timestamp (0 = never)
-	Type       string    `json:"type"`       // Document type (json, counter, list, set)
+	Type       string    `json:"type"`       // Document type (json, counter, list, set, zset, hash, blob, stream, timeseries)
 	UpdatedAt  time.Time `json:"updated_at"` // When document was last updated
+
+	ContentType string `json:"content_type,omitempty"` // Media type of blob documents
+	Checksum    string `json:"checksum,omitempty"`     // Hex encoded SHA-256 of blob content
+	Size        int64  `json:"size,omitempty"`         // Blob content length in bytes
+
+	Elements *ElementsHeader `json:"elements,omitempty"` // Set when the value is stored as one key per element
+}
+
+// Element storage kinds.
+const (
+	ElementsList       = "list"
+	ElementsSet        = "set"
+	ElementsZSet       = "zset"
+	ElementsStream     = "stream"
+	ElementsTimeSeries = "timeseries"
+)
+
+// ElementsHeader describes a list, set, sorted set, stream or time series whose elements are
+// stored under their own keys instead of inline in the document value. The document value is
+// empty while it is set.
+type ElementsHeader struct {
+	Kind   string `json:"kind"`              // ElementsList, ElementsSet, ElementsZSet, ElementsStream or ElementsTimeSeries
+	Gen    uint64 `json:"gen"`               // Namespace of the element keys, unique per conversion
+	Head   int64  `json:"head,omitempty"`    // Index of the first list element
+	Tail   int64  `json:"tail,omitempty"`    // Index after the last list element
+	Len    int64  `json:"len"`               // Number of elements
+	LastID string `json:"last_id,omitempty"` // ID of the last entry appended to a stream, kept when it is trimmed
 }
 
 // Document is the main object stored in the database.
@@ -44,38 +81,75 @@ func ValidateValue(value interface{}, typeHint string) error {
 	case DocTypeJSON:
 		_, err := json.Marshal(value)
 		if err != nil {
-			return fmt.Errorf("invalid JSON: %w", err)
+			return fmt.Errorf("%w: invalid JSON: %v", ErrInvalidValue, err)
 		}
 	case DocTypeCounter:
-		if _, err := ParseCounterValue(value); err != nil {
+		if _, err := ParseCounterNumber(value); err != nil {
 			return ErrInvalidCounterValue
 		}
 	case DocTypeList:
 		if _, ok := value.([]interface{}); !ok {
-			return errors.New("list value must be a JSON array")
+			return fmt.Errorf("%w: list value must be a JSON array", ErrInvalidValue)
 		}
 	case DocTypeSet:
 		if _, ok := value.([]interface{}); !ok {
-			return errors.New("set value must be a JSON array")
+			return fmt.Errorf("%w: set value must be a JSON array", ErrInvalidValue)
+		}
+	case DocTypeZSet:
+		if _, err := ParseZSet(value); err != nil {
+			return err
+		}
+	case DocTypeHash:
+		if _, ok := value.(map[string]interface{}); !ok {
+			return fmt.Errorf("%w: hash value must be a JSON object", ErrInvalidValue)
+		}
+	case DocTypeBlob:
+		if _, ok := value.([]byte); !ok {
+			return fmt.Errorf("%w: blob value must be binary data", ErrInvalidValue)
+		}
+	case DocTypeStream:
+		if _, err := ParseStreamEntries(value); err != nil {
+			return err
+		}
+	case DocTypeTimeSeries:
+		if _, err := ParseTimeSeriesSamples(value); err != nil {
+			return err
 		}
 	default:
-		return fmt.Errorf("unsupported document type: %s", typeHint)
+		return fmt.Errorf("%w: unsupported document type: %s", ErrInvalidValue, typeHint)
 	}
 	return nil
 }
 
+// MaxSafeFloatInteger is the largest integer a float64 can represent without losing precision (2^53).
+const MaxSafeFloatInteger = 1 << 53
+
 // ParseCounterValue attempts to convert a document value into an int64 counter.
 func ParseCounterValue(val interface{}) (int64, error) {
 	switch v := val.(type) {
 	case float64:
+		// Floats are only accepted when they represent an integer exactly.
+		if v != math.Trunc(v) || v > MaxSafeFloatInteger || v < -MaxSafeFloatInteger {
+			return 0, ErrInvalidCounterValue
+		}
 		return int64(v), nil
+	case json.Number:
+		result, err := strconv.ParseInt(v.String(), 10, 64)
+		if err != nil {
+			return 0, ErrInvalidCounterValue
+		}
+		return result, nil
 	case int:
 		return int64(v), nil
 	case int64:
 		return v, nil
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, ErrInvalidCounterValue
+		}
+		return int64(v), nil
 	case string:
-		var result int64
-		_, err := fmt.Sscanf(v, "%d", &result)
+		result, err := strconv.ParseInt(v, 10, 64)
 		if err != nil {
 			return 0, ErrInvalidCounterValue
 		}
@@ -85,6 +159,45 @@ func ParseCounterValue(val interface{}) (int64, error) {
 	}
 }
 
+// ParseCounterNumber converts a document value into the value of an integer counter, as an
+// int64, or of a float counter, as a float64. The type is never inferred from the value:
+// floats stay floats even when they are whole, and numbers given as text are integers unless
+// they have a fraction or an exponent.
+func ParseCounterNumber(val interface{}) (interface{}, error) {
+	var f float64
+	switch v := val.(type) {
+	case float64:
+		f = v
+	case json.Number:
+		return parseCounterText(v.String())
+	case string:
+		return parseCounterText(v)
+	default:
+		return ParseCounterValue(val)
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, ErrInvalidCounterValue
+	}
+	return f, nil
+}
+
+// parseCounterText parses a counter value given as text. Integers out of the int64 range are
+// rejected rather than rounded to a float.
+func parseCounterText(s string) (interface{}, error) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err == nil {
+		return n, nil
+	}
+	if errors.Is(err, strconv.ErrRange) {
+		return nil, ErrInvalidCounterValue
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, ErrInvalidCounterValue
+	}
+	return f, nil
+}
+
 var docKeyRegex = regexp.MustCompile(`^[a-zA-Z0-9._:-]{1,250}$`)
 
 // ValidateDocumentKey ensures the key matches naming rules
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// FamilySettings holds the options of a user column family.
+type FamilySettings struct {
+	History HistorySettings `json:"history"`
+	Trash   TrashSettings   `json:"trash"`
+}
+
+// HistorySettings configures document version history for a column family.
+//
+// When enabled, every write and delete stores a revision of the document. Revisions beyond
+// MaxRevisions or older than Retention are pruned when the document is next written; the
+// newest revision is always kept. Revisions of lists, sets, sorted sets, streams and time
+// series stored as elements keep their metadata and element header but not their elements,
+// so they cannot be reverted to.
+type HistorySettings struct {
+	Enabled      bool   `json:"enabled"`
+	MaxRevisions int    `json:"max_revisions,omitempty" example:"10"` // Revisions kept per document (0 = no limit)
+	Retention    string `json:"retention,omitempty" example:"720h"`   // How long revisions are kept, as a Go duration (empty = forever)
+}
+
+// TrashSettings configures soft deletes for a column family.
+//
+// When enabled, deleted documents are moved to the system.trash.<cf> column family, from which
+// they can be restored until they are purged after Retention. Names longer than the 64
+// characters allowed for system column families end in a hash of <cf> instead.
+type TrashSettings struct {
+	Enabled   bool   `json:"enabled"`
+	Retention string `json:"retention,omitempty" example:"720h"` // How long deleted documents are kept, as a Go duration (empty = forever)
+}
+
+// TrashedDocument is a soft-deleted document kept in a trash column family.
+type TrashedDocument struct {
+	Document  Document  `json:"document"`
+	DeletedAt time.Time `json:"deleted_at"`
+	DeletedBy string    `json:"deleted_by,omitempty"` // Actor given with the delete request
+	PurgeAt   int64     `json:"purge_at,omitempty"`   // Unix time the document is purged (0 = never)
+}
+
+// DocumentRevision describes one stored version of a document.
+type DocumentRevision struct {
+	Rev       string    `json:"rev"`
+	Seq       uint64    `json:"seq"`
+	HLC       HLC       `json:"hlc,string"`
+	Timestamp time.Time `json:"timestamp"`          // Physical time of the HLC
+	Deleted   bool      `json:"deleted,omitempty"`  // The revision records a deletion
+	Document  *Document `json:"document,omitempty"` // The stored version, when values are requested
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// hlcLogicalBits is the number of low bits of an HLC timestamp holding the logical counter.
+const hlcLogicalBits = 16
+
+// HLC is a hybrid logical clock timestamp: milliseconds since the Unix epoch in the high bits
+// and a logical counter in the low 16 bits. Timestamps compare with plain integer ordering.
+type HLC uint64
+
+// NewHLC builds a timestamp from its physical and logical parts.
+func NewHLC(physicalMs int64, logical uint16) HLC {
+	return HLC(uint64(physicalMs)<<hlcLogicalBits | uint64(logical))
+}
+
+// Physical returns the wall clock part in milliseconds since the Unix epoch.
+func (h HLC) Physical() int64 {
+	return int64(h >> hlcLogicalBits)
+}
+
+// Logical returns the counter distinguishing timestamps within the same millisecond.
+func (h HLC) Logical() uint16 {
+	return uint16(h & (1<<hlcLogicalBits - 1))
+}
+
+// Time returns the wall clock part as a time.Time.
+func (h HLC) Time() time.Time {
+	return time.UnixMilli(h.Physical())
+}
+
+// HLCClock issues strictly increasing HLC timestamps, even if the wall clock moves backwards.
+type HLCClock struct {
+	last HLC
+	mu   sync.Mutex
+}
+
+// Now returns a timestamp greater than every timestamp issued before.
+func (c *HLCClock) Now() HLC {
+	return c.Next(0)
+}
+
+// Next returns a timestamp greater than both after and every timestamp issued before.
+// Passing the previous timestamp of a document keeps its history ordered even when it was
+// written by a clock that ran ahead of this one.
+func (c *HLCClock) Next(after HLC) HLC {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if after > c.last {
+		c.last = after
+	}
+	now := NewHLC(time.Now().UnixMilli(), 0)
+	if now > c.last {
+		c.last = now
+	} else {
+		c.last++
+	}
+	return c.last
+}
+
+// FormatRevision builds the revision string of a document from its sequence number and HLC.
+// Revisions of the same document sort by their sequence number.
+func FormatRevision(seq uint64, hlc HLC) string {
+	return fmt.Sprintf("%d-%016x", seq, uint64(hlc))
+}
+
+// ParseRevision splits a revision built by FormatRevision into its sequence number and HLC.
+// It reports false for malformed revisions, including the random IDs of legacy documents.
+func ParseRevision(rev string) (uint64, HLC, bool) {
+	seqStr, hlcStr, ok := strings.Cut(rev, "-")
+	if !ok || len(hlcStr) != 16 {
+		return 0, 0, false
+	}
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	hlc, err := strconv.ParseUint(hlcStr, 16, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return seq, HLC(hlc), true
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHLCParts(t *testing.T) {
+	h := NewHLC(1718035200123, 7)
+	if h.Physical() != 1718035200123 || h.Logical() != 7 {
+		t.Fatalf("parts = %d/%d", h.Physical(), h.Logical())
+	}
+	if !h.Time().Equal(time.UnixMilli(1718035200123)) {
+		t.Fatalf("Time() = %v", h.Time())
+	}
+	if !(NewHLC(1, 65535) < NewHLC(2, 0) && NewHLC(2, 0) < NewHLC(2, 1)) {
+		t.Fatal("timestamps should order by physical time, then logical counter")
+	}
+}
+
+func TestHLCClockIsStrictlyIncreasing(t *testing.T) {
+	var c HLCClock
+	prev := c.Now()
+	for i := 0; i < 1000; i++ {
+		next := c.Now()
+		if next <= prev {
+			t.Fatalf("Now() = %v after %v", next, prev)
+		}
+		prev = next
+	}
+}
+
+func TestHLCClockFollowsTimestampsAhead(t *testing.T) {
+	var c HLCClock
+	ahead := NewHLC(time.Now().Add(time.Hour).UnixMilli(), 3)
+	if got := c.Next(ahead); got != ahead+1 {
+		t.Fatalf("Next(ahead) = %v, want %v", got, ahead+1)
+	}
+	// Later timestamps stay ahead of it even though the wall clock is behind.
+	if got := c.Now(); got != ahead+2 {
+		t.Fatalf("Now() = %v, want %v", got, ahead+2)
+	}
+	if got := c.Next(NewHLC(1, 0)); got != ahead+3 {
+		t.Fatalf("Next(past) = %v, want %v", got, ahead+3)
+	}
+}
+
+func TestRevisionRoundTrip(t *testing.T) {
+	hlc := NewHLC(1718035200123, 2)
+	rev := FormatRevision(42, hlc)
+	if rev != "42-019002e0b87b0002" {
+		t.Fatalf("FormatRevision = %q", rev)
+	}
+	seq, parsed, ok := ParseRevision(rev)
+	if !ok || seq != 42 || parsed != hlc {
+		t.Fatalf("ParseRevision(%q) = %d, %v, %v", rev, seq, parsed, ok)
+	}
+}
+
+func TestParseRevisionRejectsMalformedRevisions(t *testing.T) {
+	for _, rev := range []string{
+		"",
+		"42",
+		"42-",
+		"42-019002e0b87b",            // HLC too short
+		"42-019002e0b87b000200",      // HLC too long
+		"x-019002e0b87b0002",         // Non-numeric sequence
+		"-1-019002e0b87b0002",        // Negative sequence
+		"42-019002e0b87bzzzz",        // Non-hex HLC
+		"9f86d081884c7d659a2feaa0c5", // Random ID of a legacy document
+	} {
+		if _, _, ok := ParseRevision(rev); ok {
+			t.Errorf("ParseRevision(%q) should fail", rev)
+		}
+	}
+}
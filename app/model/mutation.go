@@ -0,0 +1,259 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	ErrPathNotFound    = errors.New("path not found")
+	ErrPathExists      = errors.New("path already exists")
+	ErrPathMismatch    = errors.New("path does not match document structure")
+	ErrInvalidMutation = errors.New("invalid mutation")
+)
+
+// Supported sub-document mutation operations.
+const (
+	MutateSet         = "set"          // Create or overwrite the value at path
+	MutateInsert      = "insert"       // Create the value at path; fails if it exists
+	MutateReplace     = "replace"      // Overwrite the value at path; fails if it does not exist
+	MutateRemove      = "remove"       // Delete the value at path; fails if it does not exist
+	MutateArrayAppend = "array_append" // Append value to the array at path, creating it if missing
+	MutateArrayInsert = "array_insert" // Insert value into an array; path must end with an index
+	MutateIncrement   = "increment"    // Add an integer delta to the number at path, creating it if missing
+)
+
+// MutationOp describes a single sub-document operation.
+type MutationOp struct {
+	Op    string      `json:"op"`              // Operation name (set, insert, replace, remove, array_append, array_insert, increment)
+	Path  string      `json:"path"`            // Target path, e.g. "profile.address.city" or "items[3]"
+	Value interface{} `json:"value,omitempty"` // Operand; the delta for increment. May be null
+
+	valueSet bool // Value was given, even as null
+}
+
+// ParseMutationOp builds an operation from its decoded fields. Unlike decoding straight into
+// a MutationOp, it tells a null value apart from a missing one, so null can be written.
+func ParseMutationOp(raw map[string]interface{}) (MutationOp, error) {
+	var op MutationOp
+	var ok bool
+	if op.Op, ok = raw["op"].(string); !ok {
+		return op, fmt.Errorf("%w: op must be a string", ErrInvalidMutation)
+	}
+	if op.Path, ok = raw["path"].(string); !ok {
+		return op, fmt.Errorf("%w: path must be a string", ErrInvalidMutation)
+	}
+	op.Value, op.valueSet = raw["value"]
+	return op, nil
+}
+
+// hasValue reports whether the operation was given a value, which may be null.
+func (op MutationOp) hasValue() bool {
+	return op.Value != nil || op.valueSet
+}
+
+// leafFunc receives the current value at a path and returns its replacement.
+// Returning remove=true deletes the value instead.
+type leafFunc func(current interface{}, exists bool) (value interface{}, remove bool, err error)
+
+// ApplyMutations applies operations in order to a document value and returns the new value.
+// The input value may be modified in place.
+func ApplyMutations(value interface{}, ops []MutationOp) (interface{}, error) {
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("%w: no operations given", ErrInvalidMutation)
+	}
+	for i, op := range ops {
+		next, err := applyMutation(value, op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+		value = next
+	}
+	return value, nil
+}
+
+func applyMutation(root interface{}, op MutationOp) (interface{}, error) {
+	path, err := ParsePath(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case MutateSet:
+		if !op.hasValue() {
+			return nil, fmt.Errorf("%w: value is required", ErrInvalidMutation)
+		}
+		return updatePath(root, path, true, func(interface{}, bool) (interface{}, bool, error) {
+			return op.Value, false, nil
+		})
+
+	case MutateInsert:
+		if !op.hasValue() {
+			return nil, fmt.Errorf("%w: value is required", ErrInvalidMutation)
+		}
+		return updatePath(root, path, true, func(_ interface{}, exists bool) (interface{}, bool, error) {
+			if exists {
+				return nil, false, ErrPathExists
+			}
+			return op.Value, false, nil
+		})
+
+	case MutateReplace:
+		if !op.hasValue() {
+			return nil, fmt.Errorf("%w: value is required", ErrInvalidMutation)
+		}
+		return updatePath(root, path, false, func(_ interface{}, exists bool) (interface{}, bool, error) {
+			if !exists {
+				return nil, false, ErrPathNotFound
+			}
+			return op.Value, false, nil
+		})
+
+	case MutateRemove:
+		return updatePath(root, path, false, func(current interface{}, exists bool) (interface{}, bool, error) {
+			if !exists {
+				return nil, false, ErrPathNotFound
+			}
+			return nil, true, nil
+		})
+
+	case MutateArrayAppend:
+		if !op.hasValue() {
+			return nil, fmt.Errorf("%w: value is required", ErrInvalidMutation)
+		}
+		return updatePath(root, path, true, func(current interface{}, exists bool) (interface{}, bool, error) {
+			if !exists {
+				return []interface{}{op.Value}, false, nil
+			}
+			arr, ok := current.([]interface{})
+			if !ok {
+				return nil, false, ErrPathMismatch
+			}
+			return append(arr, op.Value), false, nil
+		})
+
+	case MutateArrayInsert:
+		if !op.hasValue() {
+			return nil, fmt.Errorf("%w: value is required", ErrInvalidMutation)
+		}
+		last := path[len(path)-1]
+		if !last.IsIndex {
+			return nil, fmt.Errorf("%w: array_insert path must end with an index", ErrInvalidPath)
+		}
+		return updatePath(root, path[:len(path)-1], false, func(current interface{}, exists bool) (interface{}, bool, error) {
+			arr, ok := current.([]interface{})
+			if !exists || !ok {
+				return nil, false, ErrPathMismatch
+			}
+			idx := last.Index
+			if idx < 0 {
+				idx += len(arr)
+			}
+			if idx < 0 || idx > len(arr) {
+				return nil, false, ErrPathNotFound
+			}
+			out := make([]interface{}, 0, len(arr)+1)
+			out = append(out, arr[:idx]...)
+			out = append(out, op.Value)
+			return append(out, arr[idx:]...), false, nil
+		})
+
+	case MutateIncrement:
+		delta, err := ParseCounterValue(op.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: increment value must be an integer", ErrInvalidMutation)
+		}
+		return updatePath(root, path, true, func(current interface{}, exists bool) (interface{}, bool, error) {
+			if !exists {
+				return delta, false, nil
+			}
+			n, ok := current.(int64)
+			if !ok {
+				return nil, false, ErrPathMismatch
+			}
+			if (delta > 0 && n > math.MaxInt64-delta) || (delta < 0 && n < math.MinInt64-delta) {
+				return nil, false, fmt.Errorf("%w: increment overflows int64", ErrInvalidMutation)
+			}
+			return n + delta, false, nil
+		})
+
+	default:
+		return nil, fmt.Errorf("%w: unsupported operation %q", ErrInvalidMutation, op.Op)
+	}
+}
+
+// updatePath walks the path inside container and replaces the addressed value with the result
+// of fn. When create is true, missing intermediate objects are created along the way.
+// It returns the (possibly new) container, since slices may be reallocated.
+func updatePath(container interface{}, path Path, create bool, fn leafFunc) (interface{}, error) {
+	if len(path) == 0 {
+		value, _, err := fn(container, true)
+		return value, err
+	}
+
+	seg := path[0]
+	rest := path[1:]
+
+	if seg.IsIndex {
+		arr, ok := container.([]interface{})
+		if !ok {
+			return nil, ErrPathMismatch
+		}
+		idx, ok := ResolveIndex(seg.Index, len(arr))
+		if !ok {
+			return nil, ErrPathNotFound
+		}
+		if len(rest) == 0 {
+			value, remove, err := fn(arr[idx], true)
+			if err != nil {
+				return nil, err
+			}
+			if remove {
+				out := make([]interface{}, 0, len(arr)-1)
+				out = append(out, arr[:idx]...)
+				return append(out, arr[idx+1:]...), nil
+			}
+			arr[idx] = value
+			return arr, nil
+		}
+		child, err := updatePath(arr[idx], rest, create, fn)
+		if err != nil {
+			return nil, err
+		}
+		arr[idx] = child
+		return arr, nil
+	}
+
+	obj, ok := container.(map[string]interface{})
+	if !ok {
+		if container != nil || !create {
+			return nil, ErrPathMismatch
+		}
+		obj = make(map[string]interface{})
+	}
+
+	child, exists := obj[seg.Field]
+	if len(rest) == 0 {
+		value, remove, err := fn(child, exists)
+		if err != nil {
+			return nil, err
+		}
+		if remove {
+			delete(obj, seg.Field)
+		} else {
+			obj[seg.Field] = value
+		}
+		return obj, nil
+	}
+
+	if !exists && !create {
+		return nil, ErrPathNotFound
+	}
+	child, err := updatePath(child, rest, create, fn)
+	if err != nil {
+		return nil, err
+	}
+	obj[seg.Field] = child
+	return obj, nil
+}
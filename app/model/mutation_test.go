@@ -0,0 +1,114 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func mutationDoc() map[string]interface{} {
+	return map[string]interface{}{
+		"name":  "ann",
+		"items": []interface{}{"a", "b", "c"},
+		"stats": map[string]interface{}{"views": int64(1)},
+	}
+}
+
+func TestApplyMutations(t *testing.T) {
+	tests := []struct {
+		name string
+		op   MutationOp
+		path string
+		want interface{}
+	}{
+		{"set creates parents", MutationOp{Op: MutateSet, Path: "profile.city", Value: "Oslo"}, "profile", map[string]interface{}{"city": "Oslo"}},
+		{"insert new field", MutationOp{Op: MutateInsert, Path: "age", Value: int64(30)}, "age", int64(30)},
+		{"replace array element", MutationOp{Op: MutateReplace, Path: "items[-1]", Value: "z"}, "items", []interface{}{"a", "b", "z"}},
+		{"remove first element", MutationOp{Op: MutateRemove, Path: "items[0]"}, "items", []interface{}{"b", "c"}},
+		{"remove last element", MutationOp{Op: MutateRemove, Path: "items[-1]"}, "items", []interface{}{"a", "b"}},
+		{"append to array", MutationOp{Op: MutateArrayAppend, Path: "items", Value: "d"}, "items", []interface{}{"a", "b", "c", "d"}},
+		{"append creates array", MutationOp{Op: MutateArrayAppend, Path: "tags", Value: "x"}, "tags", []interface{}{"x"}},
+		{"insert at head", MutationOp{Op: MutateArrayInsert, Path: "items[0]", Value: "x"}, "items", []interface{}{"x", "a", "b", "c"}},
+		{"insert at end", MutationOp{Op: MutateArrayInsert, Path: "items[3]", Value: "x"}, "items", []interface{}{"a", "b", "c", "x"}},
+		{"insert before last", MutationOp{Op: MutateArrayInsert, Path: "items[-1]", Value: "x"}, "items", []interface{}{"a", "b", "x", "c"}},
+		{"increment existing", MutationOp{Op: MutateIncrement, Path: "stats.views", Value: int64(4)}, "stats", map[string]interface{}{"views": int64(5)}},
+		{"increment creates", MutationOp{Op: MutateIncrement, Path: "stats.likes", Value: int64(-2)}, "stats", map[string]interface{}{"views": int64(1), "likes": int64(-2)}},
+	}
+	for _, tt := range tests {
+		value, err := ApplyMutations(mutationDoc(), []MutationOp{tt.op})
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		got := value.(map[string]interface{})[tt.path]
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: %s = %#v, want %#v", tt.name, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestApplyMutationsRejectsInvalidOperations(t *testing.T) {
+	tests := []struct {
+		name string
+		op   MutationOp
+		want error
+	}{
+		{"set past array end", MutationOp{Op: MutateSet, Path: "items[3]", Value: "x"}, ErrPathNotFound},
+		{"remove before array start", MutationOp{Op: MutateRemove, Path: "items[-4]"}, ErrPathNotFound},
+		{"insert past array end", MutationOp{Op: MutateArrayInsert, Path: "items[4]", Value: "x"}, ErrPathNotFound},
+		{"insert before array start", MutationOp{Op: MutateArrayInsert, Path: "items[-4]", Value: "x"}, ErrPathNotFound},
+		{"insert without index", MutationOp{Op: MutateArrayInsert, Path: "items", Value: "x"}, ErrInvalidPath},
+		{"insert existing field", MutationOp{Op: MutateInsert, Path: "name", Value: "bob"}, ErrPathExists},
+		{"replace missing field", MutationOp{Op: MutateReplace, Path: "age", Value: int64(1)}, ErrPathNotFound},
+		{"remove missing field", MutationOp{Op: MutateRemove, Path: "age"}, ErrPathNotFound},
+		{"index into object", MutationOp{Op: MutateSet, Path: "stats[0]", Value: "x"}, ErrPathMismatch},
+		{"field of string", MutationOp{Op: MutateSet, Path: "name.first", Value: "x"}, ErrPathMismatch},
+		{"append to object", MutationOp{Op: MutateArrayAppend, Path: "stats", Value: "x"}, ErrPathMismatch},
+		{"increment string", MutationOp{Op: MutateIncrement, Path: "name", Value: int64(1)}, ErrPathMismatch},
+		{"increment by float", MutationOp{Op: MutateIncrement, Path: "stats.views", Value: 1.5}, ErrInvalidMutation},
+		{"set without value", MutationOp{Op: MutateSet, Path: "name"}, ErrInvalidMutation},
+		{"unknown op", MutationOp{Op: "rename", Path: "name"}, ErrInvalidMutation},
+	}
+	for _, tt := range tests {
+		if _, err := ApplyMutations(mutationDoc(), []MutationOp{tt.op}); !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	doc := map[string]interface{}{"n": int64(math.MaxInt64)}
+	if _, err := ApplyMutations(doc, []MutationOp{{Op: MutateIncrement, Path: "n", Value: int64(1)}}); !errors.Is(err, ErrInvalidMutation) {
+		t.Errorf("overflow: got %v, want ErrInvalidMutation", err)
+	}
+	if _, err := ApplyMutations(mutationDoc(), nil); !errors.Is(err, ErrInvalidMutation) {
+		t.Errorf("no operations: got %v, want ErrInvalidMutation", err)
+	}
+}
+
+func TestParseMutationOpKeepsNullValue(t *testing.T) {
+	op, err := ParseMutationOp(map[string]interface{}{"op": MutateSet, "path": "name", "value": nil})
+	if err != nil {
+		t.Fatalf("ParseMutationOp: %v", err)
+	}
+	value, err := ApplyMutations(mutationDoc(), []MutationOp{op})
+	if err != nil {
+		t.Fatalf("set null: %v", err)
+	}
+	if v, ok := value.(map[string]interface{})["name"]; !ok || v != nil {
+		t.Fatalf("name = %v, %v, want null", v, ok)
+	}
+
+	op, err = ParseMutationOp(map[string]interface{}{"op": MutateSet, "path": "name"})
+	if err != nil {
+		t.Fatalf("ParseMutationOp: %v", err)
+	}
+	if _, err := ApplyMutations(mutationDoc(), []MutationOp{op}); !errors.Is(err, ErrInvalidMutation) {
+		t.Errorf("missing value: got %v, want ErrInvalidMutation", err)
+	}
+
+	for _, raw := range []map[string]interface{}{{"path": "a"}, {"op": MutateSet}, {"op": 1.0, "path": "a"}} {
+		if _, err := ParseMutationOp(raw); !errors.Is(err, ErrInvalidMutation) {
+			t.Errorf("%v: got %v, want ErrInvalidMutation", raw, err)
+		}
+	}
+}
@@ -0,0 +1,394 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidPatch    = errors.New("invalid patch")
+	ErrPatchTestFailed = errors.New("patch test operation failed")
+)
+
+// Supported patch formats.
+const (
+	PatchTypeMerge = "merge" // RFC 7396 JSON Merge Patch
+	PatchTypeJSON  = "json"  // RFC 6902 JSON Patch
+)
+
+// PatchOp is a single RFC 6902 JSON Patch operation.
+type PatchOp struct {
+	Op    string      `json:"op"`             // add, remove, replace, move, copy or test
+	Path  string      `json:"path"`           // JSON Pointer (RFC 6901) to the target location
+	From  string      `json:"from,omitempty"` // Source pointer for move and copy
+	Value interface{} `json:"value"`          // Operand for add, replace and test
+}
+
+// ApplyMergePatch applies an RFC 7396 merge patch to a value and returns the result.
+//
+// Object members set to null in the patch are removed; any non-object patch replaces the target.
+func ApplyMergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{}, len(p))
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = ApplyMergePatch(t[k], v)
+	}
+	return t
+}
+
+// ApplyJSONPatch applies RFC 6902 operations in order and returns the new value.
+// A failing "test" operation returns ErrPatchTestFailed.
+func ApplyJSONPatch(value interface{}, ops []PatchOp) (interface{}, error) {
+	for i, op := range ops {
+		next, err := applyPatchOp(value, op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+		value = next
+	}
+	return value, nil
+}
+
+func applyPatchOp(root interface{}, op PatchOp) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add":
+		return pointerAdd(root, path, op.Value)
+	case "remove":
+		return pointerRemove(root, path)
+	case "replace":
+		if _, err := pointerGet(root, path); err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return op.Value, nil
+		}
+		return applyAtParent(root, path, func(parent interface{}, token string) (interface{}, error) {
+			switch c := parent.(type) {
+			case map[string]interface{}:
+				c[token] = op.Value
+				return c, nil
+			case []interface{}:
+				idx, err := arrayIndex(token, len(c), false)
+				if err != nil {
+					return nil, err
+				}
+				c[idx] = op.Value
+				return c, nil
+			default:
+				return nil, ErrPathNotFound
+			}
+		})
+	case "move":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if op.Path != op.From && strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("%w: cannot move a value into one of its children", ErrInvalidPatch)
+		}
+		value, err := pointerGet(root, from)
+		if err != nil {
+			return nil, err
+		}
+		if root, err = pointerRemove(root, from); err != nil {
+			return nil, err
+		}
+		return pointerAdd(root, path, value)
+	case "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := pointerGet(root, from)
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(root, path, cloneValue(value))
+	case "test":
+		value, err := pointerGet(root, path)
+		if err != nil {
+			return nil, ErrPatchTestFailed
+		}
+		if !valuesEqual(value, op.Value) {
+			return nil, ErrPatchTestFailed
+		}
+		return root, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported operation %q", ErrInvalidPatch, op.Op)
+	}
+}
+
+// parsePointer splits an RFC 6901 JSON Pointer into unescaped reference tokens.
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("%w: pointer %q must start with '/'", ErrInvalidPath, ptr)
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// arrayIndex parses an array reference token. When allowEnd is true, "-" and len are accepted
+// and address the position after the last element.
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if token == "-" && allowEnd {
+		return length, nil
+	}
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInvalidPath, token)
+	}
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx < 0 {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInvalidPath, token)
+	}
+	if idx > length || (idx == length && !allowEnd) {
+		return 0, ErrPathNotFound
+	}
+	return idx, nil
+}
+
+// pointerGet returns the value referenced by the pointer tokens.
+func pointerGet(value interface{}, tokens []string) (interface{}, error) {
+	current := value
+	for _, token := range tokens {
+		switch c := current.(type) {
+		case map[string]interface{}:
+			next, ok := c[token]
+			if !ok {
+				return nil, ErrPathNotFound
+			}
+			current = next
+		case []interface{}:
+			idx, err := arrayIndex(token, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			current = c[idx]
+		default:
+			return nil, ErrPathNotFound
+		}
+	}
+	return current, nil
+}
+
+// applyAtParent walks to the container holding the last token and replaces it with fn's result.
+func applyAtParent(value interface{}, tokens []string, fn func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 1 {
+		return fn(value, tokens[0])
+	}
+
+	switch c := value.(type) {
+	case map[string]interface{}:
+		child, ok := c[tokens[0]]
+		if !ok {
+			return nil, ErrPathNotFound
+		}
+		updated, err := applyAtParent(child, tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		c[tokens[0]] = updated
+		return c, nil
+	case []interface{}:
+		idx, err := arrayIndex(tokens[0], len(c), false)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := applyAtParent(c[idx], tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		c[idx] = updated
+		return c, nil
+	default:
+		return nil, ErrPathNotFound
+	}
+}
+
+// pointerAdd implements the JSON Patch "add" operation.
+func pointerAdd(root interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return applyAtParent(root, tokens, func(parent interface{}, token string) (interface{}, error) {
+		switch c := parent.(type) {
+		case map[string]interface{}:
+			c[token] = value
+			return c, nil
+		case []interface{}:
+			idx, err := arrayIndex(token, len(c), true)
+			if err != nil {
+				return nil, err
+			}
+			out := make([]interface{}, 0, len(c)+1)
+			out = append(out, c[:idx]...)
+			out = append(out, value)
+			return append(out, c[idx:]...), nil
+		default:
+			return nil, ErrPathNotFound
+		}
+	})
+}
+
+// pointerRemove implements the JSON Patch "remove" operation.
+func pointerRemove(root interface{}, tokens []string) (interface{}, error) {
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: cannot remove the document root", ErrInvalidPatch)
+	}
+	return applyAtParent(root, tokens, func(parent interface{}, token string) (interface{}, error) {
+		switch c := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := c[token]; !ok {
+				return nil, ErrPathNotFound
+			}
+			delete(c, token)
+			return c, nil
+		case []interface{}:
+			idx, err := arrayIndex(token, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			out := make([]interface{}, 0, len(c)-1)
+			out = append(out, c[:idx]...)
+			return append(out, c[idx+1:]...), nil
+		default:
+			return nil, ErrPathNotFound
+		}
+	})
+}
+
+// valuesEqual compares two decoded values, treating numbers as equal when they have the same
+// numeric value regardless of their Go type.
+func valuesEqual(a, b interface{}) bool {
+	if equal, ok := numbersEqual(a, b); ok {
+		return equal
+	}
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, item := range av {
+			other, ok := bv[k]
+			if !ok || !valuesEqual(item, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !valuesEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
+
+// numbersEqual compares two normalized numbers exactly. Integers are compared as integers, so
+// values beyond 2^53 that share a float64 stay distinct, and a float only equals an integer
+// when it is whole and has that exact value. ok is false unless a is a number.
+func numbersEqual(a, b interface{}) (equal, ok bool) {
+	switch av := a.(type) {
+	case int64:
+		switch bv := b.(type) {
+		case int64:
+			return av == bv, true
+		case uint64:
+			return av >= 0 && uint64(av) == bv, true
+		case float64:
+			return floatEqualsInt(bv, av), true
+		}
+		return false, true
+	case uint64:
+		switch bv := b.(type) {
+		case int64:
+			return bv >= 0 && uint64(bv) == av, true
+		case uint64:
+			return av == bv, true
+		case float64:
+			return floatEqualsUint(bv, av), true
+		}
+		return false, true
+	case float64:
+		switch bv := b.(type) {
+		case int64:
+			return floatEqualsInt(av, bv), true
+		case uint64:
+			return floatEqualsUint(av, bv), true
+		case float64:
+			return av == bv, true
+		}
+		return false, true
+	}
+	return false, false
+}
+
+// floatEqualsInt reports whether f has exactly the value of i.
+func floatEqualsInt(f float64, i int64) bool {
+	if f != math.Trunc(f) || f < -(1<<63) || f >= 1<<63 {
+		return false
+	}
+	return int64(f) == i
+}
+
+// floatEqualsUint reports whether f has exactly the value of u.
+func floatEqualsUint(f float64, u uint64) bool {
+	if f != math.Trunc(f) || f < 0 || f >= 1<<64 {
+		return false
+	}
+	return uint64(f) == u
+}
+
+// cloneValue returns a deep copy of a decoded value.
+func cloneValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[k] = cloneValue(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = cloneValue(item)
+		}
+		return out
+	case []byte:
+		return append([]byte(nil), v...)
+	default:
+		return v
+	}
+}
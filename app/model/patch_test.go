@@ -0,0 +1,139 @@
+package model
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	tests := []struct {
+		name          string
+		target, patch interface{}
+		want          interface{}
+	}{
+		{
+			"null removes member",
+			map[string]interface{}{"a": "b", "c": "d"},
+			map[string]interface{}{"c": nil},
+			map[string]interface{}{"a": "b"},
+		},
+		{
+			"null removes nested member",
+			map[string]interface{}{"a": map[string]interface{}{"b": "c", "d": "e"}},
+			map[string]interface{}{"a": map[string]interface{}{"d": nil}},
+			map[string]interface{}{"a": map[string]interface{}{"b": "c"}},
+		},
+		{
+			"null for a missing member is a no-op",
+			map[string]interface{}{"a": "b"},
+			map[string]interface{}{"x": nil},
+			map[string]interface{}{"a": "b"},
+		},
+		{
+			"arrays are replaced",
+			map[string]interface{}{"a": []interface{}{"b", "c"}},
+			map[string]interface{}{"a": []interface{}{"d"}},
+			map[string]interface{}{"a": []interface{}{"d"}},
+		},
+		{
+			"object replaces scalar",
+			map[string]interface{}{"a": "b"},
+			map[string]interface{}{"a": map[string]interface{}{"c": nil, "d": "e"}},
+			map[string]interface{}{"a": map[string]interface{}{"d": "e"}},
+		},
+		{
+			"non-object patch replaces target",
+			map[string]interface{}{"a": "b"},
+			[]interface{}{"c"},
+			[]interface{}{"c"},
+		},
+	}
+	for _, tt := range tests {
+		if got := ApplyMergePatch(tt.target, tt.patch); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %#v, want %#v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func patchDoc() map[string]interface{} {
+	return map[string]interface{}{
+		"a/b":   "slash",
+		"m~n":   "tilde",
+		"n":     int64(1),
+		"items": []interface{}{"x", "y"},
+		"obj":   map[string]interface{}{"k": "v"},
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	tests := []struct {
+		name string
+		ops  []PatchOp
+		path string
+		want interface{}
+	}{
+		{"add appends with -", []PatchOp{{Op: "add", Path: "/items/-", Value: "z"}}, "items", []interface{}{"x", "y", "z"}},
+		{"add inserts at index", []PatchOp{{Op: "add", Path: "/items/0", Value: "w"}}, "items", []interface{}{"w", "x", "y"}},
+		{"add at array end", []PatchOp{{Op: "add", Path: "/items/2", Value: "z"}}, "items", []interface{}{"x", "y", "z"}},
+		{"remove array element", []PatchOp{{Op: "remove", Path: "/items/0"}}, "items", []interface{}{"y"}},
+		{"replace escaped key", []PatchOp{{Op: "replace", Path: "/a~1b", Value: "new"}}, "a/b", "new"},
+		{"move array element", []PatchOp{{Op: "move", From: "/items/0", Path: "/items/-"}}, "items", []interface{}{"y", "x"}},
+		{"move field", []PatchOp{{Op: "move", From: "/m~0n", Path: "/obj/moved"}}, "obj", map[string]interface{}{"k": "v", "moved": "tilde"}},
+		{"copy field", []PatchOp{{Op: "copy", From: "/obj", Path: "/copy"}}, "copy", map[string]interface{}{"k": "v"}},
+		{"test passes", []PatchOp{{Op: "test", Path: "/n", Value: 1.0}, {Op: "replace", Path: "/n", Value: int64(2)}}, "n", int64(2)},
+		{"test compares deeply", []PatchOp{{Op: "test", Path: "/obj", Value: map[string]interface{}{"k": "v"}}, {Op: "remove", Path: "/obj/k"}}, "obj", map[string]interface{}{}},
+	}
+	for _, tt := range tests {
+		value, err := ApplyJSONPatch(patchDoc(), tt.ops)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		got := value.(map[string]interface{})[tt.path]
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: %s = %#v, want %#v", tt.name, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestApplyJSONPatchCopyIsIndependent(t *testing.T) {
+	value, err := ApplyJSONPatch(patchDoc(), []PatchOp{
+		{Op: "copy", From: "/obj", Path: "/copy"},
+		{Op: "replace", Path: "/copy/k", Value: "changed"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyJSONPatch: %v", err)
+	}
+	if got := value.(map[string]interface{})["obj"].(map[string]interface{})["k"]; got != "v" {
+		t.Fatalf("source changed to %v", got)
+	}
+}
+
+func TestApplyJSONPatchRejectsInvalidOperations(t *testing.T) {
+	tests := []struct {
+		name string
+		ops  []PatchOp
+		want error
+	}{
+		{"test value differs", []PatchOp{{Op: "test", Path: "/n", Value: int64(2)}}, ErrPatchTestFailed},
+		{"test fraction against integer", []PatchOp{{Op: "test", Path: "/n", Value: 1.5}}, ErrPatchTestFailed},
+		{"test missing path", []PatchOp{{Op: "test", Path: "/missing", Value: nil}}, ErrPatchTestFailed},
+		{"move into own child", []PatchOp{{Op: "move", From: "/obj", Path: "/obj/k/x"}}, ErrInvalidPatch},
+		{"move missing source", []PatchOp{{Op: "move", From: "/missing", Path: "/x"}}, ErrPathNotFound},
+		{"copy missing source", []PatchOp{{Op: "copy", From: "/missing", Path: "/x"}}, ErrPathNotFound},
+		{"add past array end", []PatchOp{{Op: "add", Path: "/items/3", Value: "z"}}, ErrPathNotFound},
+		{"remove past array end", []PatchOp{{Op: "remove", Path: "/items/2"}}, ErrPathNotFound},
+		{"remove with -", []PatchOp{{Op: "remove", Path: "/items/-"}}, ErrInvalidPath},
+		{"leading zero index", []PatchOp{{Op: "replace", Path: "/items/01", Value: "z"}}, ErrInvalidPath},
+		{"replace missing member", []PatchOp{{Op: "replace", Path: "/missing", Value: "z"}}, ErrPathNotFound},
+		{"remove root", []PatchOp{{Op: "remove", Path: ""}}, ErrInvalidPatch},
+		{"pointer without slash", []PatchOp{{Op: "add", Path: "n", Value: "z"}}, ErrInvalidPath},
+		{"unknown op", []PatchOp{{Op: "merge", Path: "/n"}}, ErrInvalidPatch},
+	}
+	for _, tt := range tests {
+		if _, err := ApplyJSONPatch(patchDoc(), tt.ops); !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidPath = errors.New("invalid path")
+
+// PathSegment is a single step of a document path: either an object field or an array index.
+type PathSegment struct {
+	Field   string
+	Index   int
+	IsIndex bool
+}
+
+// Path addresses a value nested inside a document, e.g. "profile.address.city" or "items[3]".
+type Path []PathSegment
+
+// PathResult holds the outcome of reading a single path from a document.
+type PathResult struct {
+	Path   string      `json:"path"`            // Requested path
+	Exists bool        `json:"exists"`          // Whether the path resolved to a value
+	Value  interface{} `json:"value,omitempty"` // Value at the path, when it exists
+}
+
+// DocumentPaths is a partial view of a document containing only the requested paths.
+type DocumentPaths struct {
+	Key   string       `json:"key"`
+	Paths []PathResult `json:"paths"`
+	Meta  Metadata     `json:"meta"`
+}
+
+// ParsePath parses a dotted path with optional array indexes.
+//
+// Fields are separated by '.', array elements are addressed with "[n]". Negative indexes count
+// from the end of the array, so "items[-1]" is the last element.
+func ParsePath(raw string) (Path, error) {
+	if raw == "" {
+		return nil, fmt.Errorf("%w: path is empty", ErrInvalidPath)
+	}
+
+	var path Path
+	i := 0
+	expectField := true
+	for i < len(raw) {
+		switch raw[i] {
+		case '.':
+			if expectField {
+				return nil, fmt.Errorf("%w: empty field in %q", ErrInvalidPath, raw)
+			}
+			expectField = true
+			i++
+		case '[':
+			end := strings.IndexByte(raw[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated index in %q", ErrInvalidPath, raw)
+			}
+			if expectField && len(path) > 0 {
+				return nil, fmt.Errorf("%w: empty field in %q", ErrInvalidPath, raw)
+			}
+			index, err := strconv.Atoi(raw[i+1 : i+end])
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid index in %q", ErrInvalidPath, raw)
+			}
+			path = append(path, PathSegment{Index: index, IsIndex: true})
+			expectField = false
+			i += end + 1
+		case ']':
+			return nil, fmt.Errorf("%w: unexpected ']' in %q", ErrInvalidPath, raw)
+		default:
+			if !expectField {
+				return nil, fmt.Errorf("%w: missing '.' before field in %q", ErrInvalidPath, raw)
+			}
+			end := strings.IndexAny(raw[i:], ".[]")
+			if end < 0 {
+				end = len(raw) - i
+			}
+			path = append(path, PathSegment{Field: raw[i : i+end]})
+			expectField = false
+			i += end
+		}
+	}
+	if expectField {
+		return nil, fmt.Errorf("%w: path cannot end with '.'", ErrInvalidPath)
+	}
+	return path, nil
+}
+
+// String returns the canonical textual form of the path.
+func (p Path) String() string {
+	var b strings.Builder
+	for i, seg := range p {
+		if seg.IsIndex {
+			fmt.Fprintf(&b, "[%d]", seg.Index)
+			continue
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(seg.Field)
+	}
+	return b.String()
+}
+
+// Lookup resolves the path against a value and reports whether it exists.
+func (p Path) Lookup(value interface{}) (interface{}, bool) {
+	current := value
+	for _, seg := range p {
+		next, ok := seg.lookup(current)
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	return current, true
+}
+
+// lookup resolves a single segment against a container value.
+func (s PathSegment) lookup(value interface{}) (interface{}, bool) {
+	if s.IsIndex {
+		arr, ok := value.([]interface{})
+		if !ok {
+			return nil, false
+		}
+		idx, ok := ResolveIndex(s.Index, len(arr))
+		if !ok {
+			return nil, false
+		}
+		return arr[idx], true
+	}
+
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	v, ok := obj[s.Field]
+	return v, ok
+}
+
+// ResolveIndex converts a possibly negative index into an absolute position within an array
+// of the given length.
+func ResolveIndex(index, length int) (int, bool) {
+	if index < 0 {
+		index += length
+	}
+	if index < 0 || index >= length {
+		return 0, false
+	}
+	return index, true
+}
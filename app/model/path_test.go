@@ -0,0 +1,78 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Path
+	}{
+		{"name", Path{{Field: "name"}}},
+		{"profile.address.city", Path{{Field: "profile"}, {Field: "address"}, {Field: "city"}}},
+		{"items[3]", Path{{Field: "items"}, {Index: 3, IsIndex: true}}},
+		{"items[-1].id", Path{{Field: "items"}, {Index: -1, IsIndex: true}, {Field: "id"}}},
+		{"[0][1]", Path{{Index: 0, IsIndex: true}, {Index: 1, IsIndex: true}}},
+	}
+	for _, tt := range tests {
+		got, err := ParsePath(tt.in)
+		if err != nil {
+			t.Errorf("%q: %v", tt.in, err)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%q: got %+v, want %+v", tt.in, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%q: segment %d is %+v, want %+v", tt.in, i, got[i], tt.want[i])
+			}
+		}
+		if s := got.String(); s != tt.in {
+			t.Errorf("%q: String() = %q", tt.in, s)
+		}
+	}
+}
+
+func TestParsePathRejectsInvalidPaths(t *testing.T) {
+	for _, in := range []string{"", ".a", "a.", "a..b", "a.[0]", "a[", "a[x]", "a[]", "a]", "a[0]b"} {
+		if _, err := ParsePath(in); !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("%q: got %v, want ErrInvalidPath", in, err)
+		}
+	}
+}
+
+func TestPathLookup(t *testing.T) {
+	doc := map[string]interface{}{
+		"items": []interface{}{"a", "b", map[string]interface{}{"id": int64(7)}},
+		"empty": nil,
+	}
+	tests := []struct {
+		path   string
+		want   interface{}
+		exists bool
+	}{
+		{"items[0]", "a", true},
+		{"items[-3]", "a", true},
+		{"items[-1].id", int64(7), true},
+		{"items[3]", nil, false},
+		{"items[-4]", nil, false},
+		{"items.0", nil, false},
+		{"empty", nil, true},
+		{"missing", nil, false},
+		{"items[0].id", nil, false},
+	}
+	for _, tt := range tests {
+		path, err := ParsePath(tt.path)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.path, err)
+		}
+		got, ok := path.Lookup(doc)
+		if ok != tt.exists || got != tt.want {
+			t.Errorf("%q: got %v, %v, want %v, %v", tt.path, got, ok, tt.want, tt.exists)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// QueueSettings configures a job queue. Empty fields use the server defaults.
+type QueueSettings struct {
+	VisibilityTimeout string `json:"visibility_timeout,omitempty" example:"30s"`     // How long a received message stays hidden before it is delivered again, as a Go duration
+	MaxAttempts       int    `json:"max_attempts,omitempty" example:"5"`             // Deliveries before a message is dead-lettered (0 = server default)
+	RetryBackoff      string `json:"retry_backoff,omitempty" example:"1s"`           // Delay before a nacked message is retried, doubled on each further attempt
+	MaxRetryBackoff   string `json:"max_retry_backoff,omitempty" example:"5m"`       // Longest delay between retries
+	DeadLetterQueue   string `json:"dead_letter_queue,omitempty" example:"jobs-dlq"` // Queue receiving messages that ran out of attempts (empty = drop them)
+}
+
+// QueueMessage is a message of a job queue.
+type QueueMessage struct {
+	ID               uint64      `json:"id,string"`                    // Sequence number within the queue
+	Body             interface{} `json:"body"`                         // Payload given on enqueue
+	Priority         int         `json:"priority"`                     // Higher priorities are received first
+	Attempts         int         `json:"attempts"`                     // Deliveries so far
+	EnqueuedAt       time.Time   `json:"enqueued_at"`                  // When the message was enqueued
+	VisibleAt        time.Time   `json:"visible_at"`                   // When the message can next be received
+	Receipt          string      `json:"receipt,omitempty"`            // Lease of the current delivery, required to ack or nack it
+	DeadLetteredFrom string      `json:"dead_lettered_from,omitempty"` // Queue the message ran out of attempts in
+}
+
+// QueueStats counts the messages of a job queue by state, and its activity since creation.
+type QueueStats struct {
+	Ready        int64  `json:"ready"`         // Waiting to be received
+	Delayed      int64  `json:"delayed"`       // Waiting for their delay or retry backoff to pass
+	InFlight     int64  `json:"in_flight"`     // Received and not yet acked, nacked or timed out
+	Enqueued     uint64 `json:"enqueued"`      // Messages enqueued
+	Received     uint64 `json:"received"`      // Deliveries
+	Acked        uint64 `json:"acked"`         // Messages acknowledged
+	Retried      uint64 `json:"retried"`       // Deliveries nacked or timed out and scheduled again
+	DeadLettered uint64 `json:"dead_lettered"` // Messages that ran out of attempts
+}
@@ -0,0 +1,131 @@
+package model
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StreamID identifies a stream entry: the millisecond Unix time it was appended at and a
+// sequence number ordering the entries appended within the same millisecond.
+type StreamID struct {
+	Ms  uint64
+	Seq uint64
+}
+
+// ParseStreamID parses an ID in "<ms>-<seq>" form. A bare "<ms>" has sequence number 0.
+func ParseStreamID(s string) (StreamID, error) {
+	msPart, seqPart, hasSeq := strings.Cut(s, "-")
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+	if err != nil {
+		return StreamID{}, fmt.Errorf("%w: invalid stream ID %q", ErrInvalidValue, s)
+	}
+	id := StreamID{Ms: ms}
+	if hasSeq {
+		if id.Seq, err = strconv.ParseUint(seqPart, 10, 64); err != nil {
+			return StreamID{}, fmt.Errorf("%w: invalid stream ID %q", ErrInvalidValue, s)
+		}
+	}
+	return id, nil
+}
+
+// String formats the ID as "<ms>-<seq>".
+func (id StreamID) String() string {
+	return strconv.FormatUint(id.Ms, 10) + "-" + strconv.FormatUint(id.Seq, 10)
+}
+
+// Compare returns -1, 0 or 1 as id sorts before, equal to or after other.
+func (id StreamID) Compare(other StreamID) int {
+	switch {
+	case id.Ms < other.Ms || (id.Ms == other.Ms && id.Seq < other.Seq):
+		return -1
+	case id == other:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// Next returns the smallest ID after id.
+func (id StreamID) Next() StreamID {
+	if id.Seq == math.MaxUint64 {
+		return StreamID{Ms: id.Ms + 1}
+	}
+	return StreamID{Ms: id.Ms, Seq: id.Seq + 1}
+}
+
+// IsZero reports whether the ID is 0-0, which no entry can have.
+func (id StreamID) IsZero() bool {
+	return id == StreamID{}
+}
+
+// Time returns the time the ID was generated at.
+func (id StreamID) Time() time.Time {
+	return time.UnixMilli(int64(id.Ms))
+}
+
+// Bytes returns the big-endian encoding of the ID, which sorts in ID order.
+func (id StreamID) Bytes() []byte {
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint64(b, id.Ms)
+	binary.BigEndian.PutUint64(b[8:], id.Seq)
+	return b
+}
+
+// StreamIDFromBytes decodes an ID encoded with Bytes.
+func StreamIDFromBytes(b []byte) StreamID {
+	return StreamID{Ms: binary.BigEndian.Uint64(b), Seq: binary.BigEndian.Uint64(b[8:])}
+}
+
+// StreamEntry is an entry of a stream.
+type StreamEntry struct {
+	ID    string      `json:"id" example:"1718035200000-0"` // Time-ordered entry ID
+	Value interface{} `json:"value"`                        // Appended value
+}
+
+// ParseStreamEntries converts a document value into stream entries. The entries must be
+// {id, value} objects in increasing ID order.
+func ParseStreamEntries(value interface{}) ([]StreamEntry, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: stream value must be an array of {id, value} objects", ErrInvalidValue)
+	}
+
+	entries := make([]StreamEntry, 0, len(items))
+	var last StreamID
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: stream entries must be {id, value} objects", ErrInvalidValue)
+		}
+		s, ok := obj["id"].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: stream entry ID must be a string", ErrInvalidValue)
+		}
+		id, err := ParseStreamID(s)
+		if err != nil {
+			return nil, err
+		}
+		if id.Compare(last) <= 0 {
+			return nil, fmt.Errorf("%w: stream entry IDs must be greater than 0-0 and increasing", ErrInvalidValue)
+		}
+		if obj["value"] == nil {
+			return nil, fmt.Errorf("%w: stream entry %s has no value", ErrInvalidValue, id)
+		}
+		last = id
+		entries = append(entries, StreamEntry{ID: id.String(), Value: obj["value"]})
+	}
+	return entries, nil
+}
+
+// StreamValue converts stream entries back into a document value.
+func StreamValue(entries []StreamEntry) []interface{} {
+	out := make([]interface{}, len(entries))
+	for i, e := range entries {
+		out[i] = map[string]interface{}{"id": e.ID, "value": e.Value}
+	}
+	return out
+}
@@ -0,0 +1,118 @@
+package model
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseStreamID(t *testing.T) {
+	tests := []struct {
+		in   string
+		want StreamID
+	}{
+		{"1718035200000-0", StreamID{Ms: 1718035200000}},
+		{"1718035200000-7", StreamID{Ms: 1718035200000, Seq: 7}},
+		{"42", StreamID{Ms: 42}},
+		{"18446744073709551615-18446744073709551615", StreamID{Ms: math.MaxUint64, Seq: math.MaxUint64}},
+	}
+	for _, tt := range tests {
+		got, err := ParseStreamID(tt.in)
+		if err != nil {
+			t.Errorf("%q: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q: got %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "-", "abc", "1-", "-1", "1-2-3", "-1-0", "18446744073709551616-0"} {
+		if _, err := ParseStreamID(in); !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("%q: got %v, want ErrInvalidValue", in, err)
+		}
+	}
+}
+
+func TestStreamIDStringRoundTrip(t *testing.T) {
+	id := StreamID{Ms: 1718035200000, Seq: 3}
+	if s := id.String(); s != "1718035200000-3" {
+		t.Fatalf("String() = %q", s)
+	}
+	parsed, err := ParseStreamID(id.String())
+	if err != nil || parsed != id {
+		t.Fatalf("ParseStreamID(%q) = %+v, %v", id.String(), parsed, err)
+	}
+}
+
+func TestStreamIDOrdering(t *testing.T) {
+	ids := []StreamID{
+		{Ms: 1, Seq: 0},
+		{Ms: 1, Seq: 1},
+		{Ms: 1, Seq: math.MaxUint64},
+		{Ms: 2, Seq: 0},
+		{Ms: 1 << 40, Seq: 5},
+	}
+	for i := range ids {
+		for j := range ids {
+			want := 0
+			switch {
+			case i < j:
+				want = -1
+			case i > j:
+				want = 1
+			}
+			if got := ids[i].Compare(ids[j]); got != want {
+				t.Errorf("%v.Compare(%v) = %d, want %d", ids[i], ids[j], got, want)
+			}
+			if got := bytes.Compare(ids[i].Bytes(), ids[j].Bytes()); got != want {
+				t.Errorf("bytes of %v and %v compare %d, want %d", ids[i], ids[j], got, want)
+			}
+		}
+		if got := StreamIDFromBytes(ids[i].Bytes()); got != ids[i] {
+			t.Errorf("StreamIDFromBytes(%v.Bytes()) = %v", ids[i], got)
+		}
+	}
+}
+
+func TestStreamIDNext(t *testing.T) {
+	if got := (StreamID{Ms: 5, Seq: 1}).Next(); got != (StreamID{Ms: 5, Seq: 2}) {
+		t.Errorf("Next() = %v, want 5-2", got)
+	}
+	if got := (StreamID{Ms: 5, Seq: math.MaxUint64}).Next(); got != (StreamID{Ms: 6}) {
+		t.Errorf("Next() = %v, want 6-0", got)
+	}
+}
+
+func TestParseStreamEntriesRequiresIncreasingIDs(t *testing.T) {
+	entries, err := ParseStreamEntries([]interface{}{
+		map[string]interface{}{"id": "1-0", "value": "a"},
+		map[string]interface{}{"id": "1-1", "value": "b"},
+		map[string]interface{}{"id": "2", "value": "c"},
+	})
+	if err != nil {
+		t.Fatalf("ParseStreamEntries: %v", err)
+	}
+	if len(entries) != 3 || entries[2].ID != "2-0" {
+		t.Fatalf("got %+v", entries)
+	}
+
+	invalid := map[string][]interface{}{
+		"zero ID": {map[string]interface{}{"id": "0-0", "value": "a"}},
+		"duplicate ID": {
+			map[string]interface{}{"id": "1-0", "value": "a"},
+			map[string]interface{}{"id": "1-0", "value": "b"},
+		},
+		"decreasing IDs": {
+			map[string]interface{}{"id": "2-0", "value": "a"},
+			map[string]interface{}{"id": "1-5", "value": "b"},
+		},
+		"missing value": {map[string]interface{}{"id": "1-0"}},
+	}
+	for name, value := range invalid {
+		if _, err := ParseStreamEntries(value); !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("%s: got %v, want ErrInvalidValue", name, err)
+		}
+	}
+}
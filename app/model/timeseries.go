@@ -0,0 +1,226 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// TimeSeriesSample is a sample of a time series.
+type TimeSeriesSample struct {
+	Timestamp int64             `json:"timestamp" example:"1718035200000"` // Unix time in milliseconds
+	Value     float64           `json:"value" example:"21.5"`
+	Labels    map[string]string `json:"labels,omitempty"` // Optional labels, e.g. {"sensor": "t1"}
+}
+
+// TimeSeriesBucket is the aggregate of the samples of a time series in one bucket.
+type TimeSeriesBucket struct {
+	Timestamp int64   `json:"timestamp"` // Start of the bucket, in Unix milliseconds
+	Value     float64 `json:"value"`     // Aggregated value
+	Count     int64   `json:"count"`     // Samples aggregated
+}
+
+// TimeSeriesRule maintains a downsampled copy of a time series in another time series.
+type TimeSeriesRule struct {
+	Destination string `json:"destination" example:"cpu:1h"` // Key of the downsampled series, in the same column family
+	Aggregation string `json:"aggregation" example:"avg"`    // min, max, avg, sum or count
+	Bucket      string `json:"bucket" example:"1h"`          // Bucket duration, as a Go duration
+}
+
+// TimeSeriesSettings configures a time series. Empty fields disable the feature.
+type TimeSeriesSettings struct {
+	Retention string           `json:"retention,omitempty" example:"720h"` // Samples older than this relative to the newest sample are dropped, as a Go duration
+	Rules     []TimeSeriesRule `json:"rules,omitempty"`                    // Compaction rules applied to every sample added
+}
+
+// Time series aggregations.
+const (
+	AggregationMin   = "min"
+	AggregationMax   = "max"
+	AggregationAvg   = "avg"
+	AggregationSum   = "sum"
+	AggregationCount = "count"
+)
+
+// ValidAggregation reports whether name is a supported aggregation.
+func ValidAggregation(name string) bool {
+	switch name {
+	case AggregationMin, AggregationMax, AggregationAvg, AggregationSum, AggregationCount:
+		return true
+	}
+	return false
+}
+
+// Aggregator accumulates sample values into an aggregate.
+type Aggregator struct {
+	Count    int64
+	sum      float64
+	min, max float64
+}
+
+// Add accumulates a value.
+func (a *Aggregator) Add(v float64) {
+	if a.Count == 0 || v < a.min {
+		a.min = v
+	}
+	if a.Count == 0 || v > a.max {
+		a.max = v
+	}
+	a.sum += v
+	a.Count++
+}
+
+// Result returns the aggregate of the values added so far.
+func (a *Aggregator) Result(aggregation string) float64 {
+	switch aggregation {
+	case AggregationMin:
+		return a.min
+	case AggregationMax:
+		return a.max
+	case AggregationAvg:
+		if a.Count == 0 {
+			return 0
+		}
+		return a.sum / float64(a.Count)
+	case AggregationSum:
+		return a.sum
+	default:
+		return float64(a.Count)
+	}
+}
+
+// ParseTimeSeriesSample converts an object with timestamp, value and optional labels into a
+// sample.
+func ParseTimeSeriesSample(item interface{}) (TimeSeriesSample, error) {
+	obj, ok := item.(map[string]interface{})
+	if !ok {
+		return TimeSeriesSample{}, fmt.Errorf("%w: time series samples must be {timestamp, value, labels} objects", ErrInvalidValue)
+	}
+	ts, err := parseSampleTimestamp(obj["timestamp"])
+	if err != nil {
+		return TimeSeriesSample{}, err
+	}
+	value, err := ParseSampleValue(obj["value"])
+	if err != nil {
+		return TimeSeriesSample{}, err
+	}
+	sample := TimeSeriesSample{Timestamp: ts, Value: value}
+	if raw, ok := obj["labels"]; ok && raw != nil {
+		labels, ok := raw.(map[string]interface{})
+		if !ok {
+			return TimeSeriesSample{}, fmt.Errorf("%w: time series labels must be an object of strings", ErrInvalidValue)
+		}
+		sample.Labels = make(map[string]string, len(labels))
+		for k, v := range labels {
+			s, ok := v.(string)
+			if !ok {
+				return TimeSeriesSample{}, fmt.Errorf("%w: time series label %q must be a string", ErrInvalidValue, k)
+			}
+			sample.Labels[k] = s
+		}
+	}
+	return sample, nil
+}
+
+// ParseTimeSeriesSamples converts a document value into time series samples in timestamp
+// order. Of several samples with the same timestamp, the last one is kept.
+func ParseTimeSeriesSamples(value interface{}) ([]TimeSeriesSample, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: time series value must be an array of {timestamp, value, labels} objects", ErrInvalidValue)
+	}
+
+	byTimestamp := make(map[int64]TimeSeriesSample, len(items))
+	for _, item := range items {
+		sample, err := ParseTimeSeriesSample(item)
+		if err != nil {
+			return nil, err
+		}
+		byTimestamp[sample.Timestamp] = sample
+	}
+	samples := make([]TimeSeriesSample, 0, len(byTimestamp))
+	for _, s := range byTimestamp {
+		samples = append(samples, s)
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].Timestamp < samples[j].Timestamp })
+	return samples, nil
+}
+
+// TimeSeriesValue converts samples back into a document value.
+func TimeSeriesValue(samples []TimeSeriesSample) []interface{} {
+	out := make([]interface{}, len(samples))
+	for i, s := range samples {
+		item := map[string]interface{}{"timestamp": s.Timestamp, "value": s.Value}
+		if len(s.Labels) > 0 {
+			labels := make(map[string]interface{}, len(s.Labels))
+			for k, v := range s.Labels {
+				labels[k] = v
+			}
+			item["labels"] = labels
+		}
+		out[i] = item
+	}
+	return out
+}
+
+// MatchLabels reports whether the sample has every label of filter with the same value.
+func (s TimeSeriesSample) MatchLabels(filter map[string]string) bool {
+	for k, v := range filter {
+		if s.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseSampleValue converts a number into a finite sample value.
+func ParseSampleValue(value interface{}) (float64, error) {
+	var f float64
+	switch v := value.(type) {
+	case float64:
+		f = v
+	case int64:
+		f = float64(v)
+	case int:
+		f = float64(v)
+	case json.Number:
+		var err error
+		if f, err = v.Float64(); err != nil {
+			return 0, fmt.Errorf("%w: invalid sample value %q", ErrInvalidValue, v)
+		}
+	default:
+		return 0, fmt.Errorf("%w: sample value must be a number", ErrInvalidValue)
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("%w: sample value must be finite", ErrInvalidValue)
+	}
+	return f, nil
+}
+
+// parseSampleTimestamp converts a number into a non-negative timestamp in milliseconds.
+func parseSampleTimestamp(value interface{}) (int64, error) {
+	var ts int64
+	switch v := value.(type) {
+	case int64:
+		ts = v
+	case int:
+		ts = int64(v)
+	case float64:
+		if v != math.Trunc(v) || v > MaxSafeFloatInteger {
+			return 0, fmt.Errorf("%w: sample timestamp must be an integer number of milliseconds", ErrInvalidValue)
+		}
+		ts = int64(v)
+	case json.Number:
+		var err error
+		if ts, err = v.Int64(); err != nil {
+			return 0, fmt.Errorf("%w: sample timestamp must be an integer number of milliseconds", ErrInvalidValue)
+		}
+	default:
+		return 0, fmt.Errorf("%w: sample timestamp must be an integer number of milliseconds", ErrInvalidValue)
+	}
+	if ts < 0 {
+		return 0, fmt.Errorf("%w: sample timestamp cannot be negative", ErrInvalidValue)
+	}
+	return ts, nil
+}
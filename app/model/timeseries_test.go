@@ -0,0 +1,102 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestAggregator(t *testing.T) {
+	var a Aggregator
+	for _, v := range []float64{4, -2, 10, 0} {
+		a.Add(v)
+	}
+	want := map[string]float64{
+		AggregationMin:   -2,
+		AggregationMax:   10,
+		AggregationAvg:   3,
+		AggregationSum:   12,
+		AggregationCount: 4,
+	}
+	for aggregation, v := range want {
+		if !ValidAggregation(aggregation) {
+			t.Errorf("ValidAggregation(%q) = false", aggregation)
+		}
+		if got := a.Result(aggregation); got != v {
+			t.Errorf("Result(%q) = %v, want %v", aggregation, got, v)
+		}
+	}
+	if ValidAggregation("median") {
+		t.Error("ValidAggregation(\"median\") = true")
+	}
+}
+
+func TestAggregatorFirstValueSetsMinAndMax(t *testing.T) {
+	var a Aggregator
+	a.Add(5)
+	if a.Result(AggregationMin) != 5 || a.Result(AggregationMax) != 5 {
+		t.Fatalf("min/max of a single positive value: %v/%v", a.Result(AggregationMin), a.Result(AggregationMax))
+	}
+
+	var neg Aggregator
+	neg.Add(-7)
+	neg.Add(-3)
+	if neg.Result(AggregationMin) != -7 || neg.Result(AggregationMax) != -3 {
+		t.Fatalf("min/max of negative values: %v/%v", neg.Result(AggregationMin), neg.Result(AggregationMax))
+	}
+}
+
+func TestAggregatorEmptyAverage(t *testing.T) {
+	var a Aggregator
+	if got := a.Result(AggregationAvg); got != 0 {
+		t.Fatalf("avg of no values = %v, want 0", got)
+	}
+}
+
+func TestParseTimeSeriesSamplesSortsAndKeepsLastDuplicate(t *testing.T) {
+	samples, err := ParseTimeSeriesSamples([]interface{}{
+		map[string]interface{}{"timestamp": json.Number("2000"), "value": 1.0},
+		map[string]interface{}{"timestamp": int64(1000), "value": json.Number("2.5"), "labels": map[string]interface{}{"sensor": "t1"}},
+		map[string]interface{}{"timestamp": 2000.0, "value": int64(3)},
+	})
+	if err != nil {
+		t.Fatalf("ParseTimeSeriesSamples: %v", err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("got %d samples, want 2", len(samples))
+	}
+	if samples[0].Timestamp != 1000 || samples[0].Value != 2.5 || samples[0].Labels["sensor"] != "t1" {
+		t.Errorf("first sample = %+v", samples[0])
+	}
+	if samples[1].Timestamp != 2000 || samples[1].Value != 3 {
+		t.Errorf("second sample = %+v, want the last sample at 2000", samples[1])
+	}
+}
+
+func TestParseTimeSeriesSampleRejectsInvalidValues(t *testing.T) {
+	tests := map[string]interface{}{
+		"not an object":        1.0,
+		"fractional timestamp": map[string]interface{}{"timestamp": 1.5, "value": 1.0},
+		"negative timestamp":   map[string]interface{}{"timestamp": int64(-1), "value": 1.0},
+		"unsafe timestamp":     map[string]interface{}{"timestamp": float64(MaxSafeFloatInteger) * 2, "value": 1.0},
+		"missing value":        map[string]interface{}{"timestamp": int64(1)},
+		"infinite value":       map[string]interface{}{"timestamp": int64(1), "value": math.Inf(1)},
+		"non-string label":     map[string]interface{}{"timestamp": int64(1), "value": 1.0, "labels": map[string]interface{}{"n": 1.0}},
+	}
+	for name, item := range tests {
+		if _, err := ParseTimeSeriesSample(item); !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("%s: got %v, want ErrInvalidValue", name, err)
+		}
+	}
+}
+
+func TestMatchLabels(t *testing.T) {
+	s := TimeSeriesSample{Labels: map[string]string{"sensor": "t1", "room": "a"}}
+	if !s.MatchLabels(nil) || !s.MatchLabels(map[string]string{"sensor": "t1"}) {
+		t.Error("sample should match a subset of its labels")
+	}
+	if s.MatchLabels(map[string]string{"sensor": "t2"}) || s.MatchLabels(map[string]string{"floor": "1"}) {
+		t.Error("sample should not match other labels")
+	}
+}
@@ -0,0 +1,118 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+var ErrUnsupportedValue = errors.New("unsupported value")
+
+// NormalizeValue converts a decoded value into the canonical Go types used by the storage layer.
+//
+// Decoders for JSON, MessagePack and CBOR all produce slightly different representations of the
+// same data. After normalization integers are int64 (or uint64 when they exceed the int64 range),
+// floats are float64, binary data is []byte and objects are map[string]interface{}. Any other Go
+// value is normalized through its JSON representation.
+func NormalizeValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil, bool, string, int64, float64, []byte, time.Time:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case uint:
+		return normalizeUint(uint64(v)), nil
+	case uint8:
+		return int64(v), nil
+	case uint16:
+		return int64(v), nil
+	case uint32:
+		return int64(v), nil
+	case uint64:
+		return normalizeUint(v), nil
+	case float32:
+		return float64(v), nil
+	case json.Number:
+		return normalizeNumber(v)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			n, err := NormalizeValue(item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = n
+		}
+		return out, nil
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			n, err := NormalizeValue(item)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = n
+		}
+		return out, nil
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			key, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: object keys must be strings, got %T", ErrUnsupportedValue, k)
+			}
+			n, err := NormalizeValue(item)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = n
+		}
+		return out, nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupportedValue, err)
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		var generic interface{}
+		if err := dec.Decode(&generic); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupportedValue, err)
+		}
+		return NormalizeValue(generic)
+	}
+}
+
+// normalizeNumber converts a JSON number literal into an int64, uint64 or float64 without
+// losing precision for integers.
+func normalizeNumber(n json.Number) (interface{}, error) {
+	if i, err := strconv.ParseInt(n.String(), 10, 64); err == nil {
+		return i, nil
+	}
+	if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+		return u, nil
+	}
+	f, err := strconv.ParseFloat(n.String(), 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid number %q", ErrUnsupportedValue, n.String())
+	}
+	return f, nil
+}
+
+// normalizeUint keeps unsigned integers as int64 whenever they fit.
+func normalizeUint(v uint64) interface{} {
+	if v <= math.MaxInt64 {
+		return int64(v)
+	}
+	return v
+}
@@ -0,0 +1,163 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ZSetEntry is a member of a sorted set with its score.
+type ZSetEntry struct {
+	Member string  `json:"member"`
+	Score  float64 `json:"score"`
+}
+
+// ZSet is a sorted set: unique members ordered by score, ties broken by member.
+//
+// Its value is an array of {"member", "score"} objects kept in that order. Sorted sets
+// modified through the zset endpoints keep their members as elements instead.
+type ZSet []ZSetEntry
+
+// zsetLess reports whether a sorts before b.
+func zsetLess(a, b ZSetEntry) bool {
+	if a.Score != b.Score {
+		return a.Score < b.Score
+	}
+	return a.Member < b.Member
+}
+
+// ParseZSet converts a document value into a sorted set. The entries may be in any order;
+// duplicate members and non-finite scores are rejected.
+func ParseZSet(value interface{}) (ZSet, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: zset value must be an array of {member, score} objects", ErrInvalidValue)
+	}
+
+	z := make(ZSet, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: zset entries must be {member, score} objects", ErrInvalidValue)
+		}
+		member, ok := obj["member"].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: zset member must be a string", ErrInvalidValue)
+		}
+		score, err := ParseScore(obj["score"])
+		if err != nil {
+			return nil, err
+		}
+		if seen[member] {
+			return nil, fmt.Errorf("%w: duplicate zset member %q", ErrInvalidValue, member)
+		}
+		seen[member] = true
+		z = append(z, ZSetEntry{Member: member, Score: score})
+	}
+
+	sort.Slice(z, func(i, j int) bool { return zsetLess(z[i], z[j]) })
+	return z, nil
+}
+
+// ParseScore converts a number into a finite sorted set score.
+func ParseScore(value interface{}) (float64, error) {
+	var score float64
+	switch v := value.(type) {
+	case float64:
+		score = v
+	case float32:
+		score = float64(v)
+	case int64:
+		score = float64(v)
+	case int:
+		score = float64(v)
+	case uint64:
+		score = float64(v)
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("%w: invalid zset score %q", ErrInvalidValue, v)
+		}
+		score = f
+	default:
+		return 0, fmt.Errorf("%w: zset score must be a number", ErrInvalidValue)
+	}
+	if math.IsNaN(score) || math.IsInf(score, 0) {
+		return 0, fmt.Errorf("%w: zset score must be finite", ErrInvalidValue)
+	}
+	return score, nil
+}
+
+// Value returns the stored representation of the sorted set.
+func (z ZSet) Value() []interface{} {
+	out := make([]interface{}, len(z))
+	for i, e := range z {
+		out[i] = map[string]interface{}{"member": e.Member, "score": e.Score}
+	}
+	return out
+}
+
+// ScoreBound is one end of a score range. Exclusive bounds do not match their own value.
+type ScoreBound struct {
+	Value     float64
+	Exclusive bool
+}
+
+// Score bounds matching every score.
+var (
+	LowestScore  = ScoreBound{Value: math.Inf(-1)}
+	HighestScore = ScoreBound{Value: math.Inf(1)}
+)
+
+// ParseScoreBound parses a score range bound: a number, "-inf" or "+inf", optionally prefixed
+// with "(" to make it exclusive.
+func ParseScoreBound(s string) (ScoreBound, error) {
+	var b ScoreBound
+	if strings.HasPrefix(s, "(") {
+		b.Exclusive = true
+		s = s[1:]
+	}
+	switch strings.ToLower(s) {
+	case "-inf":
+		b.Value = math.Inf(-1)
+	case "+inf", "inf":
+		b.Value = math.Inf(1)
+	default:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(f) {
+			return b, fmt.Errorf("%w: invalid score bound %q", ErrInvalidValue, s)
+		}
+		b.Value = f
+	}
+	return b, nil
+}
+
+// AboveMin reports whether a score satisfies a lower bound.
+func (b ScoreBound) AboveMin(score float64) bool {
+	if b.Exclusive {
+		return score > b.Value
+	}
+	return score >= b.Value
+}
+
+// BelowMax reports whether a score satisfies an upper bound.
+func (b ScoreBound) BelowMax(score float64) bool {
+	if b.Exclusive {
+		return score < b.Value
+	}
+	return score <= b.Value
+}
+
+// ScoreRange returns the ranks [lo, hi) of the entries whose score lies between min and max.
+func (z ZSet) ScoreRange(min, max ScoreBound) (lo, hi int) {
+	lo = sort.Search(len(z), func(i int) bool { return min.AboveMin(z[i].Score) })
+	hi = sort.Search(len(z), func(i int) bool { return !max.BelowMax(z[i].Score) })
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseZSetSortsByScoreThenMember(t *testing.T) {
+	z, err := ParseZSet([]interface{}{
+		map[string]interface{}{"member": "c", "score": json.Number("2")},
+		map[string]interface{}{"member": "b", "score": 1.5},
+		map[string]interface{}{"member": "a", "score": int64(2)},
+	})
+	if err != nil {
+		t.Fatalf("ParseZSet: %v", err)
+	}
+	want := ZSet{{"b", 1.5}, {"a", 2}, {"c", 2}}
+	if len(z) != len(want) {
+		t.Fatalf("got %v, want %v", z, want)
+	}
+	for i := range want {
+		if z[i] != want[i] {
+			t.Fatalf("entry %d: got %v, want %v", i, z[i], want[i])
+		}
+	}
+}
+
+func TestParseZSetRejectsInvalidValues(t *testing.T) {
+	tests := map[string]interface{}{
+		"not an array":     map[string]interface{}{},
+		"entry not object": []interface{}{"a"},
+		"missing member":   []interface{}{map[string]interface{}{"score": 1.0}},
+		"missing score":    []interface{}{map[string]interface{}{"member": "a"}},
+		"infinite score":   []interface{}{map[string]interface{}{"member": "a", "score": math.Inf(1)}},
+		"duplicate member": []interface{}{
+			map[string]interface{}{"member": "a", "score": 1.0},
+			map[string]interface{}{"member": "a", "score": 2.0},
+		},
+	}
+	for name, value := range tests {
+		if _, err := ParseZSet(value); !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("%s: got %v, want ErrInvalidValue", name, err)
+		}
+	}
+}
+
+func TestParseScoreBound(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ScoreBound
+	}{
+		{"1.5", ScoreBound{Value: 1.5}},
+		{"(1.5", ScoreBound{Value: 1.5, Exclusive: true}},
+		{"-inf", LowestScore},
+		{"+inf", HighestScore},
+		{"(inf", ScoreBound{Value: math.Inf(1), Exclusive: true}},
+	}
+	for _, tt := range tests {
+		got, err := ParseScoreBound(tt.in)
+		if err != nil {
+			t.Errorf("%q: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q: got %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "(", "abc", "nan"} {
+		if _, err := ParseScoreBound(in); !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("%q: got %v, want ErrInvalidValue", in, err)
+		}
+	}
+}
+
+func TestScoreRange(t *testing.T) {
+	z := ZSet{{"a", 1}, {"b", 2}, {"c", 2}, {"d", 3}}
+	tests := []struct {
+		min, max ScoreBound
+		lo, hi   int
+	}{
+		{LowestScore, HighestScore, 0, 4},
+		{ScoreBound{Value: 2}, ScoreBound{Value: 2}, 1, 3},
+		{ScoreBound{Value: 2, Exclusive: true}, HighestScore, 3, 4},
+		{LowestScore, ScoreBound{Value: 2, Exclusive: true}, 0, 1},
+		{ScoreBound{Value: 4}, HighestScore, 4, 4},
+		{ScoreBound{Value: 3}, ScoreBound{Value: 1}, 3, 3},
+	}
+	for _, tt := range tests {
+		lo, hi := z.ScoreRange(tt.min, tt.max)
+		if lo != tt.lo || hi != tt.hi {
+			t.Errorf("ScoreRange(%+v, %+v) = [%d, %d), want [%d, %d)", tt.min, tt.max, lo, hi, tt.lo, tt.hi)
+		}
+	}
+}
@@ -249,6 +249,10 @@ echo "$POPPED" | grep -q '"element":"c"' && echo "✅ Popped 'c'" || echo "❌ U
 FINAL=$(curl -s "http://localhost:$PORT/documents/lists/range?cf=logs&key=mylist&start=0&end=-1")
 echo "Final list: $FINAL"
 
+echo "➡️ GET the list document (elements are stored under their own keys)"
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=logs&key=mylist")
+echo "$DOC" | grep -q '"value":\["a","b"\]' && echo "✅ Document value holds every element" || (echo "❌ List document value is wrong: $DOC"; exit 1)
+
 # -----------------------------------
 # SET
 # -----------------------------------
@@ -327,5 +331,1085 @@ sleep 3
 RESP=$(curl -s -o /dev/null -w "%{http_code}" "http://localhost:$PORT/documents?cf=logs&key=touch-me")
 [ "$RESP" = "404" ] && echo "✅ Document expired as expected" || echo "❌ Document did not expire"
 
+# -----------------------------------
+# SORTED SET
+# -----------------------------------
+echo
+echo "🔹 Test Sorted Set Operations"
+
+curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=myzset&type=zset" \
+     -H "Content-Type: application/json" -d '{"value": []}' >/dev/null
+
+echo "➡️ Add members with scores"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/zsets/add?cf=logs&key=myzset" \
+     -H "Content-Type: application/json" \
+     -d '{"entries": [{"member": "alice", "score": 30}, {"member": "bob", "score": 10}, {"member": "carol", "score": 20}]}')
+echo "Response: $RESP"
+echo "$RESP" | grep -q '"added":3' && echo "✅ 3 members added" || (echo "❌ ZADD failed"; exit 1)
+
+echo "➡️ Add with nx leaves existing members untouched"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/zsets/add?cf=logs&key=myzset" \
+     -H "Content-Type: application/json" -d '{"entries": [{"member": "bob", "score": 99}], "nx": true}')
+echo "$RESP" | grep -q '"added":0' && echo "✅ nx skipped 'bob'" || (echo "❌ nx updated 'bob': $RESP"; exit 1)
+
+echo "➡️ Increment 'bob' by 25"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/zsets/incrby?cf=logs&key=myzset" \
+     -H "Content-Type: application/json" -d '{"member": "bob", "increment": 25}')
+echo "$RESP" | grep -q '"score":35' && echo "✅ 'bob' scored 35" || (echo "❌ ZINCRBY failed: $RESP"; exit 1)
+
+echo "➡️ Score and rank of 'bob'"
+RESP=$(curl -s "http://localhost:$PORT/documents/zsets/score?cf=logs&key=myzset&member=bob")
+echo "$RESP" | grep -q '"score":35' && echo "✅ Score is 35" || (echo "❌ ZSCORE failed: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/zsets/rank?cf=logs&key=myzset&member=bob")
+echo "$RESP" | grep -q '"rank":2' && echo "✅ Rank is 2" || (echo "❌ ZRANK failed: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/zsets/rank?cf=logs&key=myzset&member=bob&reverse=true")
+echo "$RESP" | grep -q '"rank":0' && echo "✅ Reverse rank is 0" || (echo "❌ Reverse ZRANK failed: $RESP"; exit 1)
+
+echo "➡️ Range by rank"
+RESP=$(curl -s "http://localhost:$PORT/documents/zsets/range?cf=logs&key=myzset&start=0&stop=-1")
+echo "Range: $RESP"
+echo "$RESP" | grep -q '"entries":\[{"member":"carol","score":20},{"member":"alice","score":30},{"member":"bob","score":35}\]' \
+  && echo "✅ Members in score order" || (echo "❌ ZRANGE order wrong"; exit 1)
+
+echo "➡️ Range by score (20, +inf], highest first"
+RESP=$(curl -s "http://localhost:$PORT/documents/zsets/range-by-score?cf=logs&key=myzset&min=(20&max=%2Binf&reverse=true")
+echo "$RESP" | grep -q '"entries":\[{"member":"bob","score":35},{"member":"alice","score":30}\]' \
+  && echo "✅ Exclusive score range matched" || (echo "❌ ZRANGEBYSCORE failed: $RESP"; exit 1)
+
+echo "➡️ Pop the lowest and the highest member"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/zsets/popmin?cf=logs&key=myzset")
+echo "$RESP" | grep -q '"member":"carol"' && echo "✅ Popped 'carol'" || (echo "❌ ZPOPMIN failed: $RESP"; exit 1)
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/zsets/popmax?cf=logs&key=myzset")
+echo "$RESP" | grep -q '"member":"bob"' && echo "✅ Popped 'bob'" || (echo "❌ ZPOPMAX failed: $RESP"; exit 1)
+
+echo "➡️ Remove 'alice'"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/zsets/remove?cf=logs&key=myzset" \
+     -H "Content-Type: application/json" -d '{"members": ["alice", "nobody"]}')
+echo "$RESP" | grep -q '"removed":1' && echo "✅ 'alice' removed" || (echo "❌ ZREM failed: $RESP"; exit 1)
+RESP=$(curl -s -o /dev/null -w "%{http_code}" "http://localhost:$PORT/documents/zsets/score?cf=logs&key=myzset&member=alice")
+[ "$RESP" = "404" ] && echo "✅ Removed member not found" || (echo "❌ Removed member still scored"; exit 1)
+
+# -----------------------------------
+# HASH
+# -----------------------------------
+echo
+echo "🔹 Test Hash Operations"
+
+curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=myhash&type=hash" \
+     -H "Content-Type: application/json" -d '{"value": {}}' >/dev/null
+
+echo "➡️ Set fields"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/hashes/set?cf=logs&key=myhash" \
+     -H "Content-Type: application/json" -d '{"fields": {"name": "alice", "city": "Lisbon", "visits": 1}}')
+echo "$RESP" | grep -q '"added":3' && echo "✅ 3 fields added" || (echo "❌ HSET failed: $RESP"; exit 1)
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/hashes/set?cf=logs&key=myhash" \
+     -H "Content-Type: application/json" -d '{"fields": {"city": "Porto"}}')
+echo "$RESP" | grep -q '"added":0' && echo "✅ Overwriting 'city' adds no field" || (echo "❌ HSET overwrite failed: $RESP"; exit 1)
+
+echo "➡️ Get fields"
+RESP=$(curl -s "http://localhost:$PORT/documents/hashes/get?cf=logs&key=myhash&field=city")
+echo "$RESP" | grep -q '"value":"Porto"' && echo "✅ 'city' is Porto" || (echo "❌ HGET failed: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/hashes/mget?cf=logs&key=myhash&field=name&field=missing")
+echo "$RESP" | grep -q '"values":\["alice",null\]' && echo "✅ HMGET returned null for a missing field" || (echo "❌ HMGET failed: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/hashes/exists?cf=logs&key=myhash&field=name")
+echo "$RESP" | grep -q '"exists":true' && echo "✅ 'name' exists" || (echo "❌ HEXISTS failed: $RESP"; exit 1)
+
+echo "➡️ Increment 'visits' by 4"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/hashes/incrby?cf=logs&key=myhash" \
+     -H "Content-Type: application/json" -d '{"field": "visits", "delta": 4}')
+echo "$RESP" | grep -q '"value":5' && echo "✅ 'visits' is 5" || (echo "❌ HINCRBY failed: $RESP"; exit 1)
+
+echo "➡️ Keys, length and paginated fields"
+RESP=$(curl -s "http://localhost:$PORT/documents/hashes/keys?cf=logs&key=myhash")
+echo "$RESP" | grep -q '"fields":\["city","name","visits"\]' && echo "✅ Keys in field order" || (echo "❌ HKEYS failed: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/hashes/len?cf=logs&key=myhash")
+echo "$RESP" | grep -q '"len":3' && echo "✅ Length is 3" || (echo "❌ HLEN failed: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/hashes/getall?cf=logs&key=myhash&limit=2")
+echo "First page: $RESP"
+echo "$RESP" | grep -q '"next":"name"' && echo "✅ Next cursor is 'name'" || (echo "❌ HGETALL page failed"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/hashes/getall?cf=logs&key=myhash&start_after=name")
+echo "$RESP" | grep -q '"fields":{"visits":5}' && echo "✅ Second page has 'visits'" || (echo "❌ HGETALL next page failed: $RESP"; exit 1)
+
+echo "➡️ Remove fields"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/hashes/remove?cf=logs&key=myhash" \
+     -H "Content-Type: application/json" -d '{"fields": ["city", "missing"]}')
+echo "$RESP" | grep -q '"removed":1' && echo "✅ 'city' removed" || (echo "❌ HDEL failed: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/hashes/exists?cf=logs&key=myhash&field=city")
+echo "$RESP" | grep -q '"exists":false' && echo "✅ 'city' is gone" || (echo "❌ 'city' still present"; exit 1)
+
+# -----------------------------------
+# LIST INDEX, INSERT, REMOVE, TRIM AND MOVE
+# -----------------------------------
+echo
+echo "🔹 Test Extended List Operations"
+
+for k in feed processing; do
+    curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=$k&type=list" \
+         -H "Content-Type: application/json" -d '{"value": []}' >/dev/null
+done
+
+echo "➡️ Push several elements at once"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/lists/push?cf=logs&key=feed" \
+     -H "Content-Type: application/json" -d '{"elements": ["a", "b", "c", "d", "b"]}')
+echo "$RESP" | grep -q '"length":5' && echo "✅ 5 elements pushed" || (echo "❌ Multi-element push failed: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/lists/len?cf=logs&key=feed")
+echo "$RESP" | grep -q '"length":5' && echo "✅ LLEN is 5" || (echo "❌ LLEN failed: $RESP"; exit 1)
+
+echo "➡️ Read and replace by index"
+RESP=$(curl -s "http://localhost:$PORT/documents/lists/index?cf=logs&key=feed&index=-2")
+echo "$RESP" | grep -q '"element":"d"' && echo "✅ Index -2 is 'd'" || (echo "❌ LINDEX failed: $RESP"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents/lists/set?cf=logs&key=feed&index=0" \
+     -H "Content-Type: application/json" -d '{"element": "A"}')
+[ "$STATUS" = "200" ] && echo "✅ Index 0 replaced" || (echo "❌ LSET failed (status $STATUS)"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" "http://localhost:$PORT/documents/lists/index?cf=logs&key=feed&index=10")
+[ "$STATUS" = "400" ] && echo "✅ Out of range index rejected" || (echo "❌ LINDEX out of range returned $STATUS"; exit 1)
+
+echo "➡️ Insert before a pivot and remove every 'b'"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/lists/insert?cf=logs&key=feed" \
+     -H "Content-Type: application/json" -d '{"pivot": "c", "element": "x", "position": "before"}')
+echo "$RESP" | grep -q '"length":6' && echo "✅ 'x' inserted" || (echo "❌ LINSERT failed: $RESP"; exit 1)
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/lists/remove?cf=logs&key=feed" \
+     -H "Content-Type: application/json" -d '{"element": "b", "count": 0}')
+echo "$RESP" | grep -q '"removed":2' && echo "✅ Both 'b' removed" || (echo "❌ LREM failed: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/lists/range?cf=logs&key=feed&start=0&end=-1")
+echo "$RESP" | grep -q '"list":\["A","x","c","d"\]' && echo "✅ List is [A x c d]" || (echo "❌ Unexpected list: $RESP"; exit 1)
+
+echo "➡️ Trim to the first 3 elements"
+curl -s -X POST "http://localhost:$PORT/documents/lists/trim?cf=logs&key=feed&start=0&stop=2" >/dev/null
+RESP=$(curl -s "http://localhost:$PORT/documents/lists/range?cf=logs&key=feed&start=0&end=-1")
+echo "$RESP" | grep -q '"list":\["A","x","c"\]' && echo "✅ List trimmed" || (echo "❌ LTRIM failed: $RESP"; exit 1)
+
+echo "➡️ Move the tail of 'feed' to the head of 'processing'"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/lists/move?source_cf=logs&source=feed&destination_cf=logs&destination=processing&from=right&to=left")
+echo "$RESP" | grep -q '"element":"c"' && echo "✅ Moved 'c'" || (echo "❌ LMOVE failed: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/lists/range?cf=logs&key=processing&start=0&end=-1")
+echo "$RESP" | grep -q '"list":\["c"\]' && echo "✅ 'processing' holds 'c'" || (echo "❌ Destination list wrong: $RESP"; exit 1)
+
+# -----------------------------------
+# SET INSPECTION AND ALGEBRA
+# -----------------------------------
+echo
+echo "🔹 Test Set Inspection and Algebra"
+
+add_members() {
+    local key=$1
+    shift
+    curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=$key&type=set" \
+         -H "Content-Type: application/json" -d '{"value": []}' >/dev/null
+    for val in "$@"; do
+        curl -s -X POST "http://localhost:$PORT/documents/sets/add?cf=logs&key=$key" \
+             -H "Content-Type: application/json" -d "{\"element\": \"$val\"}" >/dev/null
+    done
+}
+add_members segment-a "a" "b" "c"
+add_members segment-b "b" "c" "d"
+
+echo "➡️ Cardinality and multi-member check"
+RESP=$(curl -s "http://localhost:$PORT/documents/sets/card?cf=logs&key=segment-a")
+echo "$RESP" | grep -q '"count":3' && echo "✅ SCARD is 3" || (echo "❌ SCARD failed: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/sets/mcontains?cf=logs&key=segment-a&element=a&element=d")
+echo "$RESP" | grep -q '"contains":\[true,false\]' && echo "✅ 'a' in set, 'd' not" || (echo "❌ SMISMEMBER failed: $RESP"; exit 1)
+
+echo "➡️ Paginated members"
+RESP=$(curl -s "http://localhost:$PORT/documents/sets/members?cf=logs&key=segment-a&limit=2")
+echo "First page: $RESP"
+echo "$RESP" | grep -q '"next":' && echo "✅ First page has a cursor" || (echo "❌ SMEMBERS page has no cursor"; exit 1)
+
+echo "➡️ Random members"
+RESP=$(curl -s "http://localhost:$PORT/documents/sets/random?cf=logs&key=segment-a&count=2")
+echo "Random: $RESP"
+echo "$RESP" | grep -q '"members":\["[abc]","[abc]"\]' && echo "✅ 2 random members" || (echo "❌ SRANDMEMBER failed"; exit 1)
+
+echo "➡️ Union, intersection and difference"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/sets/union?cf=logs" \
+     -H "Content-Type: application/json" -d '{"keys": ["segment-a", "segment-b"]}')
+echo "Union: $RESP"
+for val in a b c d; do
+    echo "$RESP" | grep -q "\"$val\"" || (echo "❌ '$val' missing from union"; exit 1)
+done
+echo "✅ Union has a, b, c and d"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/sets/intersect?cf=logs" \
+     -H "Content-Type: application/json" -d '{"keys": ["segment-a", "segment-b"]}')
+echo "Intersection: $RESP"
+echo "$RESP" | grep -q '"a"\|"d"' && (echo "❌ Intersection has extra members"; exit 1)
+echo "$RESP" | grep -q '"b"' && echo "$RESP" | grep -q '"c"' && echo "✅ Intersection is b, c" || (echo "❌ Intersection failed"; exit 1)
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/sets/diff?cf=logs" \
+     -H "Content-Type: application/json" -d '{"keys": ["segment-a", "segment-b"]}')
+echo "$RESP" | grep -q '"members":\["a"\]' && echo "✅ Difference is a" || (echo "❌ Difference failed: $RESP"; exit 1)
+
+echo "➡️ Store the intersection"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/sets/intersect?cf=logs" \
+     -H "Content-Type: application/json" -d '{"keys": ["segment-a", "segment-b"], "store": "segment-ab"}')
+echo "$RESP" | grep -q '"count":2' && echo "✅ 2 members stored" || (echo "❌ Stored intersection failed: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/sets/contains?cf=logs&key=segment-ab&element=c")
+echo "$RESP" | grep -q '"contains":true' && echo "✅ Stored set has 'c'" || (echo "❌ Stored set is wrong: $RESP"; exit 1)
+
+echo "➡️ Pop a member"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/sets/pop?cf=logs&key=segment-ab")
+echo "$RESP" | grep -q '"members":\["[bc]"\]' && echo "✅ Popped one member" || (echo "❌ SPOP failed: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/sets/card?cf=logs&key=segment-ab")
+echo "$RESP" | grep -q '"count":1' && echo "✅ One member left" || (echo "❌ SCARD after SPOP failed: $RESP"; exit 1)
+
+# -----------------------------------
+# BLOCKING POP
+# -----------------------------------
+echo
+echo "🔹 Test Blocking List Pop"
+
+curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=jobs&type=list" \
+     -H "Content-Type: application/json" -d '{"value": []}' >/dev/null
+
+echo "➡️ Blocking pop on an empty list times out"
+START=$(date +%s)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents/lists/pop?cf=logs&key=jobs&block=1")
+ELAPSED=$(( $(date +%s) - START ))
+[ "$STATUS" = "400" ] && [ "$ELAPSED" -ge 1 ] && echo "✅ Timed out after ${ELAPSED}s" || (echo "❌ Blocking pop returned $STATUS after ${ELAPSED}s"; exit 1)
+
+echo "➡️ Blocking shift is woken by a push"
+BLOCKED_OUT=$(mktemp)
+START=$(date +%s)
+curl -s -X POST "http://localhost:$PORT/documents/lists/shift?cf=logs&key=jobs&block=10" >"$BLOCKED_OUT" &
+BLOCKED_PID=$!
+sleep 1
+curl -s -X POST "http://localhost:$PORT/documents/lists/push?cf=logs&key=jobs" \
+     -H "Content-Type: application/json" -d '{"element": "job-1"}' >/dev/null
+wait $BLOCKED_PID
+ELAPSED=$(( $(date +%s) - START ))
+RESP=$(cat "$BLOCKED_OUT")
+rm -f "$BLOCKED_OUT"
+echo "Response: $RESP"
+echo "$RESP" | grep -q '"element":"job-1"' && [ "$ELAPSED" -lt 10 ] && echo "✅ Woken with 'job-1' after ${ELAPSED}s" || (echo "❌ Blocking shift was not woken"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/lists/len?cf=logs&key=jobs")
+echo "$RESP" | grep -q '"length":0' && echo "✅ Element consumed once" || (echo "❌ Element left in list: $RESP"; exit 1)
+
+echo "➡️ Waiters are served in arrival order, ahead of pops that do not block"
+FIRST_OUT=$(mktemp)
+SECOND_OUT=$(mktemp)
+curl -s -X POST "http://localhost:$PORT/documents/lists/shift?cf=logs&key=jobs&block=10" >"$FIRST_OUT" &
+FIRST_PID=$!
+sleep 0.5
+curl -s -X POST "http://localhost:$PORT/documents/lists/shift?cf=logs&key=jobs&block=10" >"$SECOND_OUT" &
+SECOND_PID=$!
+sleep 0.5
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents/lists/shift?cf=logs&key=jobs")
+[ "$STATUS" = "400" ] && echo "✅ Non-blocking shift finds the list empty" || (echo "❌ Non-blocking shift returned $STATUS"; exit 1)
+curl -s -X POST "http://localhost:$PORT/documents/lists/push?cf=logs&key=jobs" \
+     -H "Content-Type: application/json" -d '{"elements": ["job-2", "job-3"]}' >/dev/null
+wait $FIRST_PID $SECOND_PID
+grep -q '"element":"job-2"' "$FIRST_OUT" && grep -q '"element":"job-3"' "$SECOND_OUT" \
+  && echo "✅ First waiter got 'job-2', second got 'job-3'" || (echo "❌ Waiters served out of order: $(cat "$FIRST_OUT") $(cat "$SECOND_OUT")"; exit 1)
+rm -f "$FIRST_OUT" "$SECOND_OUT"
+
+echo "➡️ Blocking pop waits for a list that does not exist yet"
+BLOCKED_OUT=$(mktemp)
+curl -s -X POST "http://localhost:$PORT/documents/lists/pop?cf=logs&key=later&block=10" >"$BLOCKED_OUT" &
+BLOCKED_PID=$!
+sleep 1
+curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=later&type=list" \
+     -H "Content-Type: application/json" -d '{"value": ["first"]}' >/dev/null
+wait $BLOCKED_PID
+RESP=$(cat "$BLOCKED_OUT")
+rm -f "$BLOCKED_OUT"
+echo "$RESP" | grep -q '"element":"first"' && echo "✅ Woken when the list was created" || (echo "❌ Pop on a missing list failed: $RESP"; exit 1)
+
+# -----------------------------------
+# JOB QUEUES
+# -----------------------------------
+echo
+echo "🔹 Test Job Queues"
+
+json_field() {
+    echo "$1" | grep -o "\"$2\":\"[^\"]*\"" | head -n 1 | cut -d'"' -f4
+}
+
+echo "➡️ Configure queue 'emails' with a dead-letter queue"
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X PUT "http://localhost:$PORT/queues/emails" \
+     -H "Content-Type: application/json" \
+     -d '{"visibility_timeout": "1s", "max_attempts": 2, "retry_backoff": "1s", "dead_letter_queue": "emails-dlq"}')
+[ "$STATUS" = "200" ] && echo "✅ Queue configured" || (echo "❌ Queue configuration failed (status $STATUS)"; exit 1)
+
+echo "➡️ Enqueue two messages, one with a higher priority"
+curl -s -X POST "http://localhost:$PORT/queues/emails/enqueue" \
+     -H "Content-Type: application/json" -d '{"body": {"to": "regular"}}' >/dev/null
+curl -s -X POST "http://localhost:$PORT/queues/emails/enqueue" \
+     -H "Content-Type: application/json" -d '{"body": {"to": "vip"}, "priority": 5}' >/dev/null
+
+echo "➡️ Receive and ack the priority message"
+RESP=$(curl -s -X POST "http://localhost:$PORT/queues/emails/receive?max=1")
+echo "Received: $RESP"
+echo "$RESP" | grep -q '"to":"vip"' && echo "✅ Priority message received first" || (echo "❌ Wrong message received"; exit 1)
+RESP=$(curl -s -X POST "http://localhost:$PORT/queues/emails/ack" \
+     -H "Content-Type: application/json" \
+     -d "{\"id\": \"$(json_field "$RESP" id)\", \"receipt\": \"$(json_field "$RESP" receipt)\"}")
+echo "$RESP" | grep -q '"status":"acked"' && echo "✅ Message acked" || (echo "❌ Ack failed: $RESP"; exit 1)
+
+echo "➡️ Let a lease expire"
+RESP=$(curl -s -X POST "http://localhost:$PORT/queues/emails/receive")
+echo "$RESP" | grep -q '"attempts":1' && echo "✅ First delivery of 'regular'" || (echo "❌ Receive failed: $RESP"; exit 1)
+MSG_ID=$(json_field "$RESP" id)
+STALE_RECEIPT=$(json_field "$RESP" receipt)
+RESP=$(curl -s -X POST "http://localhost:$PORT/queues/emails/receive")
+echo "$RESP" | grep -q '"messages":\[\]' && echo "✅ Leased message is hidden" || (echo "❌ Leased message delivered twice: $RESP"; exit 1)
+echo "⏳ Waiting for the visibility timeout (2s)..."
+sleep 2
+RESP=$(curl -s -X POST "http://localhost:$PORT/queues/emails/receive")
+echo "Redelivered: $RESP"
+echo "$RESP" | grep -q '"attempts":2' && echo "✅ Message delivered again after the timeout" || (echo "❌ Message was not redelivered"; exit 1)
+RECEIPT=$(json_field "$RESP" receipt)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/queues/emails/ack" \
+     -H "Content-Type: application/json" -d "{\"id\": \"$MSG_ID\", \"receipt\": \"$STALE_RECEIPT\"}")
+[ "$STATUS" = "409" ] && echo "✅ Stale receipt rejected" || (echo "❌ Stale receipt returned $STATUS"; exit 1)
+
+echo "➡️ Nack the last attempt into the dead-letter queue"
+RESP=$(curl -s -X POST "http://localhost:$PORT/queues/emails/nack" \
+     -H "Content-Type: application/json" -d "{\"id\": \"$MSG_ID\", \"receipt\": \"$RECEIPT\"}")
+echo "$RESP" | grep -q '"status":"nacked"' && echo "✅ Message nacked" || (echo "❌ Nack failed: $RESP"; exit 1)
+RESP=$(curl -s -X POST "http://localhost:$PORT/queues/emails-dlq/receive")
+echo "Dead-lettered: $RESP"
+echo "$RESP" | grep -q '"dead_lettered_from":"emails"' && echo "$RESP" | grep -q '"to":"regular"' \
+  && echo "✅ Message moved to 'emails-dlq'" || (echo "❌ Message missing from the dead-letter queue"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/queues/emails")
+echo "$RESP" | grep -q '"dead_lettered":1' && echo "$RESP" | grep -q '"depth":0' \
+  && echo "✅ Queue stats updated" || (echo "❌ Unexpected queue stats: $RESP"; exit 1)
+
+# -----------------------------------
+# STREAMS
+# -----------------------------------
+echo
+echo "🔹 Test Streams and Consumer Groups"
+
+echo "➡️ Append entries with explicit and generated IDs"
+for entry in '1000-0:signup' '1000-1:login' '2000-0:logout'; do
+    curl -s -X POST "http://localhost:$PORT/documents/streams/append?cf=logs&key=events" \
+         -H "Content-Type: application/json" -d "{\"id\": \"${entry%%:*}\", \"value\": \"${entry#*:}\"}" >/dev/null
+done
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/streams/append?cf=logs&key=events" \
+     -H "Content-Type: application/json" -d '{"value": "purchase"}')
+echo "$RESP" | grep -q '"id":"[0-9]*-[0-9]*"' && echo "✅ Generated ID $(json_field "$RESP" id)" || (echo "❌ XADD failed: $RESP"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents/streams/append?cf=logs&key=events" \
+     -H "Content-Type: application/json" -d '{"id": "1500-0", "value": "late"}')
+[ "$STATUS" = "400" ] && echo "✅ Lower ID rejected" || (echo "❌ Lower ID returned $STATUS"; exit 1)
+
+echo "➡️ Range, reverse range and tail"
+RESP=$(curl -s "http://localhost:$PORT/documents/streams/range?cf=logs&key=events&start=-&end=1000")
+echo "$RESP" | grep -q '"entries":\[{"id":"1000-0","value":"signup"},{"id":"1000-1","value":"login"}\]' \
+  && echo "✅ Bare end ID includes its whole millisecond" || (echo "❌ XRANGE failed: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/streams/range?cf=logs&key=events&reverse=true&count=1")
+echo "$RESP" | grep -q '"value":"purchase"' && echo "✅ Latest entry first" || (echo "❌ Reverse XRANGE failed: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/streams/tail?cf=logs&key=events&after=1000-1")
+echo "$RESP" | grep -q '"value":"logout"' && echo "$RESP" | grep -q '"last":"' \
+  && echo "✅ Tail returns the entries after 1000-1" || (echo "❌ Tail failed: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/streams/info?cf=logs&key=events")
+echo "$RESP" | grep -q '"length":4' && echo "✅ Stream has 4 entries" || (echo "❌ Stream info failed: $RESP"; exit 1)
+
+echo "➡️ Consumer group delivery, claim and acknowledgement"
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents/streams/groups/create?cf=logs&key=events&group=workers&start=-")
+[ "$STATUS" = "200" ] && echo "✅ Group 'workers' created" || (echo "❌ Group creation failed (status $STATUS)"; exit 1)
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/streams/groups/read?cf=logs&key=events&group=workers&consumer=alice&count=2")
+echo "$RESP" | grep -q '"id":"1000-0"' && echo "$RESP" | grep -q '"id":"1000-1"' \
+  && echo "✅ 'alice' got the first two entries" || (echo "❌ Group read failed: $RESP"; exit 1)
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/streams/groups/read?cf=logs&key=events&group=workers&consumer=carol&count=1")
+echo "$RESP" | grep -q '"id":"2000-0"' && echo "✅ 'carol' got the next entry" || (echo "❌ Entries delivered twice: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/streams/groups/pending?cf=logs&key=events&group=workers&consumer=alice")
+echo "Pending: $RESP"
+echo "$RESP" | grep -o '"consumer":"alice"' | wc -l | grep -q '^ *2$' && echo "✅ 2 entries pending for 'alice'" || (echo "❌ Unexpected pending entries"; exit 1)
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/streams/groups/claim?cf=logs&key=events&group=workers&consumer=bob&min_idle=0&count=1")
+echo "$RESP" | grep -q '"id":"1000-0"' && echo "✅ 'bob' claimed 1000-0" || (echo "❌ Claim failed: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/streams/groups/pending?cf=logs&key=events&group=workers&consumer=bob")
+echo "$RESP" | grep -q '"id":"1000-0","consumer":"bob"' && echo "$RESP" | grep -q '"deliveries":2' \
+  && echo "✅ Claimed entry pending for 'bob'" || (echo "❌ Claimed entry not pending: $RESP"; exit 1)
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/streams/groups/ack?cf=logs&key=events&group=workers" \
+     -H "Content-Type: application/json" -d '{"ids": ["1000-0", "1000-1", "2000-0"]}')
+echo "$RESP" | grep -q '"acked":3' && echo "✅ 3 entries acked" || (echo "❌ Ack failed: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/streams/groups/pending?cf=logs&key=events&group=workers")
+echo "$RESP" | grep -q '"pending":\[\]' && echo "✅ Nothing left pending" || (echo "❌ Entries still pending: $RESP"; exit 1)
+
+echo "➡️ Trim to the latest entry"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/streams/trim?cf=logs&key=events&maxlen=1")
+echo "$RESP" | grep -q '"removed":3' && echo "✅ 3 entries trimmed" || (echo "❌ Trim failed: $RESP"; exit 1)
+
+# -----------------------------------
+# TIME SERIES
+# -----------------------------------
+echo
+echo "🔹 Test Time Series"
+
+echo "➡️ Configure a 1h retention and a 1m average compaction rule"
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents/timeseries/config?cf=logs&key=cpu" \
+     -H "Content-Type: application/json" \
+     -d '{"retention": "1h", "rules": [{"destination": "cpu-1m", "aggregation": "avg", "bucket": "1m"}]}')
+[ "$STATUS" = "200" ] && echo "✅ Series configured" || (echo "❌ Series configuration failed (status $STATUS)"; exit 1)
+
+echo "➡️ Add labelled samples"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/timeseries/add?cf=logs&key=cpu" \
+     -H "Content-Type: application/json" -d '{"samples": [
+       {"timestamp": 60000, "value": 10, "labels": {"host": "a"}},
+       {"timestamp": 90000, "value": 20, "labels": {"host": "b"}},
+       {"timestamp": 120000, "value": 30, "labels": {"host": "a"}}]}')
+echo "$RESP" | grep -q '"length":3' && echo "✅ 3 samples added" || (echo "❌ TS.ADD failed: $RESP"; exit 1)
+
+echo "➡️ Range, label filter and aggregation"
+RESP=$(curl -s "http://localhost:$PORT/documents/timeseries/range?cf=logs&key=cpu&from=60000&to=90000")
+echo "$RESP" | grep -q '"samples":\[{"timestamp":60000,"value":10,"labels":{"host":"a"}},{"timestamp":90000,"value":20,"labels":{"host":"b"}}\]' \
+  && echo "✅ Samples in range" || (echo "❌ TS.RANGE failed: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/timeseries/range?cf=logs&key=cpu&label=host:a")
+echo "$RESP" | grep -q '"timestamp":90000' && (echo "❌ Label filter kept host b: $RESP"; exit 1)
+echo "$RESP" | grep -q '"timestamp":120000' && echo "✅ Label filter keeps host a" || (echo "❌ Label filter failed: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/timeseries/range?cf=logs&key=cpu&aggregation=sum&bucket=1m")
+echo "$RESP" | grep -q '"buckets":\[{"timestamp":60000,"value":30,"count":2},{"timestamp":120000,"value":30,"count":1}\]' \
+  && echo "✅ Samples summed per minute" || (echo "❌ Aggregation failed: $RESP"; exit 1)
+
+echo "➡️ Compaction rule keeps 'cpu-1m' updated"
+RESP=$(curl -s "http://localhost:$PORT/documents/timeseries/range?cf=logs&key=cpu-1m")
+echo "$RESP" | grep -q '"samples":\[{"timestamp":60000,"value":15},{"timestamp":120000,"value":30}\]' \
+  && echo "✅ Per-minute averages written" || (echo "❌ Compaction failed: $RESP"; exit 1)
+
+echo "➡️ Retention drops samples older than 1h before the newest"
+curl -s -X POST "http://localhost:$PORT/documents/timeseries/add?cf=logs&key=cpu" \
+     -H "Content-Type: application/json" -d '{"timestamp": 3700000, "value": 40}' >/dev/null
+RESP=$(curl -s "http://localhost:$PORT/documents/timeseries/info?cf=logs&key=cpu")
+echo "Info: $RESP"
+echo "$RESP" | grep -q '"length":2' && echo "$RESP" | grep -q '"first_timestamp":120000' \
+  && echo "✅ Samples before 100000 dropped" || (echo "❌ Retention failed"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents/timeseries/add?cf=logs&key=cpu" \
+     -H "Content-Type: application/json" -d '{"timestamp": 50000, "value": 1}')
+[ "$STATUS" = "400" ] && echo "✅ Sample out of retention rejected" || (echo "❌ Old sample returned $STATUS"; exit 1)
+
+echo "➡️ Delete a sample"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/timeseries/delete?cf=logs&key=cpu&from=120000&to=120000")
+echo "$RESP" | grep -q '"removed":1' && echo "✅ Sample removed" || (echo "❌ TS.DEL failed: $RESP"; exit 1)
+
+# -----------------------------------
+# BLOBS
+# -----------------------------------
+echo
+echo "🔹 Test Blob Documents"
+
+BLOB_FILE=$(mktemp)
+BLOB_OUT=$(mktemp)
+printf '0123456789\000\377' > "$BLOB_FILE"
+
+echo "➡️ PUT raw bytes as application/octet-stream"
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents?cf=logs&key=blob1" \
+     -H "Content-Type: application/octet-stream" --data-binary @"$BLOB_FILE")
+[ "$STATUS" = "200" ] && echo "✅ Blob stored" || (echo "❌ Blob PUT failed (status $STATUS)"; exit 1)
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=logs&key=blob1")
+echo "$DOC" | grep -q '"type":"blob"' && echo "$DOC" | grep -q '"size":12' \
+  && echo "✅ Blob metadata recorded" || (echo "❌ Blob metadata wrong: $DOC"; exit 1)
+
+echo "➡️ GET blob content back"
+HEADERS=$(curl -s -D - -o "$BLOB_OUT" "http://localhost:$PORT/documents/blob?cf=logs&key=blob1")
+cmp -s "$BLOB_FILE" "$BLOB_OUT" && echo "✅ Blob bytes round-trip" || (echo "❌ Blob content differs"; exit 1)
+echo "$HEADERS" | grep -qi '^content-type: application/octet-stream' \
+  && echo "✅ Content-Type preserved" || (echo "❌ Wrong Content-Type: $HEADERS"; exit 1)
+BLOB_ETAG=$(echo "$HEADERS" | grep -i '^etag:' | cut -d' ' -f2 | tr -d '\r')
+[ -n "$BLOB_ETAG" ] && echo "✅ ETag $BLOB_ETAG returned" || (echo "❌ Missing ETag"; exit 1)
+
+echo "➡️ Range requests"
+STATUS=$(curl -s -o "$BLOB_OUT" -w "%{http_code}" -H "Range: bytes=2-5" "http://localhost:$PORT/documents/blob?cf=logs&key=blob1")
+[ "$STATUS" = "206" ] && [ "$(cat "$BLOB_OUT")" = "2345" ] \
+  && echo "✅ Partial content served" || (echo "❌ Range request returned $STATUS"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -H "Range: bytes=100-200" "http://localhost:$PORT/documents/blob?cf=logs&key=blob1")
+[ "$STATUS" = "416" ] && echo "✅ Unsatisfiable range rejected" || (echo "❌ Out of bounds range returned $STATUS"; exit 1)
+
+echo "➡️ Conditional requests"
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -H "If-None-Match: $BLOB_ETAG" "http://localhost:$PORT/documents/blob?cf=logs&key=blob1")
+[ "$STATUS" = "304" ] && echo "✅ Unchanged blob not resent" || (echo "❌ If-None-Match returned $STATUS"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents?cf=logs&key=blob1" \
+     -H "Content-Type: application/octet-stream" -H "If-None-Match: *" --data-binary @"$BLOB_FILE")
+[ "$STATUS" = "412" ] && echo "✅ Create-only write refused" || (echo "❌ If-None-Match: * returned $STATUS"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents?cf=logs&key=blob1" \
+     -H "Content-Type: application/octet-stream" -H 'If-Match: "stale"' --data-binary 'new')
+[ "$STATUS" = "412" ] && echo "✅ Stale If-Match refused" || (echo "❌ Stale If-Match returned $STATUS"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents?cf=logs&key=blob1&type=blob" \
+     -H "Content-Type: text/plain" -H "If-Match: $BLOB_ETAG" --data-binary 'new')
+[ "$STATUS" = "200" ] && echo "✅ Matching If-Match accepted" || (echo "❌ Matching If-Match returned $STATUS"; exit 1)
+STATUS=$(curl -s -o "$BLOB_OUT" -w "%{http_code}" -H "If-None-Match: $BLOB_ETAG" "http://localhost:$PORT/documents/blob?cf=logs&key=blob1")
+[ "$STATUS" = "200" ] && [ "$(cat "$BLOB_OUT")" = "new" ] \
+  && echo "✅ Changed text/plain blob served again" || (echo "❌ Updated blob returned $STATUS"; exit 1)
+rm -f "$BLOB_FILE" "$BLOB_OUT"
+
+# -----------------------------------
+# HISTORY OF ELEMENT DOCUMENTS
+# -----------------------------------
+echo
+echo "🔹 Test History of Lists"
+
+echo "➡️ Create column family 'audit' with history enabled"
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST -H "Content-Type: application/json" \
+     -d '{"name": "audit"}' "http://localhost:$PORT/families")
+[ "$STATUS" = "201" ] && echo "✅ Column family 'audit' created" || (echo "❌ Failed to create 'audit' (status $STATUS)"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X PUT "http://localhost:$PORT/families/settings?cf=audit" \
+     -H "Content-Type: application/json" -d '{"history": {"enabled": true}}')
+[ "$STATUS" = "200" ] && echo "✅ History enabled" || (echo "❌ Enabling history failed (status $STATUS)"; exit 1)
+
+echo "➡️ Past revisions of a list are not readable"
+DOC=$(curl -s -X POST "http://localhost:$PORT/documents?cf=audit&key=tasks&type=list" \
+     -H "Content-Type: application/json" -d '{"value": ["a"]}')
+OLD_REV=$(json_field "$DOC" rev)
+curl -s -X POST "http://localhost:$PORT/documents/lists/push?cf=audit&key=tasks" \
+     -H "Content-Type: application/json" -d '{"element": "b"}' >/dev/null
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" "http://localhost:$PORT/documents?cf=audit&key=tasks&rev=$OLD_REV")
+[ "$STATUS" = "409" ] && echo "✅ Past list revision answers 409" || (echo "❌ Past list revision returned $STATUS"; exit 1)
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=audit&key=tasks&as_of=$(( $(date +%s) + 1 ))")
+echo "$DOC" | grep -q '"value":\["a","b"\]' && echo "✅ Current list served for as_of after the last write" || (echo "❌ as_of read failed: $DOC"; exit 1)
+
+# -----------------------------------
+# CONTENT NEGOTIATION
+# -----------------------------------
+echo
+echo "🔹 Test MessagePack and CBOR Bodies"
+
+echo "➡️ Store a MessagePack document holding an ID beyond 2^53"
+# {"value": {"id": 9007199254740993}}, the ID encoded as a MessagePack uint64
+STATUS=$(printf '\x81\xa5value\x81\xa2id\xcf\x00\x20\x00\x00\x00\x00\x00\x01' | \
+     curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents?cf=logs&key=packed" \
+     -H "Content-Type: application/msgpack" --data-binary @-)
+[ "$STATUS" = "200" ] && echo "✅ MessagePack body accepted" || (echo "❌ MessagePack PUT returned $STATUS"; exit 1)
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=logs&key=packed")
+echo "$DOC" | grep -q '"id":9007199254740993' && echo "✅ ID read back as JSON without losing precision" || (echo "❌ Unexpected document: $DOC"; exit 1)
+
+echo "➡️ Read it back as MessagePack"
+HEADERS=$(curl -s -D - -o /dev/null -H "Accept: application/msgpack" "http://localhost:$PORT/documents?cf=logs&key=packed")
+echo "$HEADERS" | grep -qi '^Content-Type: application/msgpack' && echo "$HEADERS" | grep -qi '^Vary: Accept' \
+  && echo "✅ Response encoded as MessagePack" || (echo "❌ Unexpected headers: $HEADERS"; exit 1)
+HEX=$(curl -s -H "Accept: application/msgpack" "http://localhost:$PORT/documents?cf=logs&key=packed" | od -An -tx1 | tr -d ' \n')
+echo "$HEX" | grep -Eq 'a26964(cf|d3)0020000000000001' && echo "✅ ID kept as a 64-bit integer" || (echo "❌ ID not encoded as a 64-bit integer: $HEX"; exit 1)
+
+echo "➡️ Store and read a CBOR document"
+# {"value": "cbor"}
+STATUS=$(printf '\xa1\x65value\x64cbor' | \
+     curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents?cf=logs&key=concise" \
+     -H "Content-Type: application/cbor" --data-binary @-)
+[ "$STATUS" = "200" ] && echo "✅ CBOR body accepted" || (echo "❌ CBOR PUT returned $STATUS"; exit 1)
+HEADERS=$(curl -s -D - -o /dev/null -H "Accept: application/cbor" "http://localhost:$PORT/documents?cf=logs&key=concise")
+echo "$HEADERS" | grep -qi '^Content-Type: application/cbor' && echo "✅ Response encoded as CBOR" || (echo "❌ Unexpected headers: $HEADERS"; exit 1)
+DOC=$(curl -s -H "Accept: text/html, application/json" "http://localhost:$PORT/documents?cf=logs&key=concise")
+echo "$DOC" | grep -q '"value":"cbor"' && echo "✅ First supported Accept type used" || (echo "❌ Unexpected document: $DOC"; exit 1)
+
+echo "➡️ Unsupported body types are rejected"
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents?cf=logs&key=plain" \
+     -H "Content-Type: text/plain" -d 'plain')
+[ "$STATUS" = "415" ] && echo "✅ text/plain body answers 415" || (echo "❌ text/plain PUT returned $STATUS"; exit 1)
+
+# -----------------------------------
+# INTEGER PRECISION
+# -----------------------------------
+echo
+echo "🔹 Test Integer Precision of Counters"
+
+echo "➡️ Increment a counter beyond 2^53"
+curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=bytes&type=counter" \
+     -H "Content-Type: application/json" -d '{"value": 9007199254740993}' >/dev/null
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/counters/delta?cf=logs&key=bytes" \
+     -H "Content-Type: application/json" -d '{"delta": 2}')
+echo "Response: $RESP"
+echo "$RESP" | grep -q '"old":9007199254740993' && echo "$RESP" | grep -q '"new":9007199254740995' \
+  && echo "✅ Counter incremented exactly" || (echo "❌ Counter lost precision"; exit 1)
+
+echo "➡️ Overflowing a counter fails"
+curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=sequence&type=counter" \
+     -H "Content-Type: application/json" -d '{"value": 9223372036854775807}' >/dev/null
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents/counters/delta?cf=logs&key=sequence" \
+     -H "Content-Type: application/json" -d '{"delta": 1}')
+[ "$STATUS" = "409" ] && echo "✅ Overflow answers 409" || (echo "❌ Overflow returned $STATUS"; exit 1)
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=logs&key=sequence")
+echo "$DOC" | grep -q '"value":9223372036854775807' && echo "✅ Counter left unchanged" || (echo "❌ Unexpected counter: $DOC"; exit 1)
+
+echo "➡️ Large integers in JSON documents are kept"
+curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=order" \
+     -H "Content-Type: application/json" -d '{"value": {"id": 1234567890123456789, "price": 9.99}}' >/dev/null
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=logs&key=order")
+echo "$DOC" | grep -q '"id":1234567890123456789' && echo "$DOC" | grep -q '"price":9.99' \
+  && echo "✅ Numbers read back unchanged" || (echo "❌ Unexpected document: $DOC"; exit 1)
+
+# -----------------------------------
+# SUB-DOCUMENT READS
+# -----------------------------------
+echo
+echo "🔹 Test Sub-document Reads"
+
+curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=profile" \
+     -H "Content-Type: application/json" \
+     -d '{"value": {"name": "ann", "address": {"city": "Oslo"}, "items": ["a", "b", "c"]}}' >/dev/null
+
+echo "➡️ Read nested fields and array elements"
+RESP=$(curl -s "http://localhost:$PORT/documents?cf=logs&key=profile&path=address.city&path=items%5B-1%5D&path=missing")
+echo "Response: $RESP"
+echo "$RESP" | grep -q '{"path":"address.city","exists":true,"value":"Oslo"}' && echo "✅ Nested field read" || (echo "❌ Nested field missing"; exit 1)
+echo "$RESP" | grep -q '{"path":"items\[-1\]","exists":true,"value":"c"}' && echo "✅ Last array element read" || (echo "❌ Array element missing"; exit 1)
+echo "$RESP" | grep -q '{"path":"missing","exists":false}' && echo "✅ Missing path reported" || (echo "❌ Missing path not reported"; exit 1)
+echo "$RESP" | grep -qv '"name"' && echo "✅ Only requested paths returned" || (echo "❌ Unrequested field returned"; exit 1)
+
+echo "➡️ Invalid paths are rejected"
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" "http://localhost:$PORT/documents?cf=logs&key=profile&path=items%5Bx%5D")
+[ "$STATUS" = "400" ] && echo "✅ Invalid path answers 400" || (echo "❌ Invalid path returned $STATUS"; exit 1)
+
+# -----------------------------------
+# SUB-DOCUMENT MUTATIONS
+# -----------------------------------
+echo
+echo "🔹 Test Sub-document Mutations"
+
+echo "➡️ Apply several path operations at once"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/mutate?cf=logs&key=profile" \
+     -H "Content-Type: application/json" \
+     -d '{"operations": [
+           {"op": "set", "path": "address.zip", "value": "0150"},
+           {"op": "array_append", "path": "items", "value": "d"},
+           {"op": "increment", "path": "stats.logins", "value": 1},
+           {"op": "remove", "path": "items[0]"}
+         ]}')
+echo "Response: $RESP"
+echo "$RESP" | grep -q '"zip":"0150"' && echo "✅ Field set" || (echo "❌ Field not set"; exit 1)
+echo "$RESP" | grep -q '"items":\["b","c","d"\]' && echo "✅ Array updated" || (echo "❌ Array not updated"; exit 1)
+echo "$RESP" | grep -q '"logins":1' && echo "✅ Counter field created" || (echo "❌ Counter field missing"; exit 1)
+
+echo "➡️ A failing operation leaves the document unchanged"
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents/mutate?cf=logs&key=profile" \
+     -H "Content-Type: application/json" \
+     -d '{"operations": [{"op": "set", "path": "name", "value": "bob"}, {"op": "insert", "path": "address.city", "value": "Bergen"}]}')
+[ "$STATUS" = "409" ] && echo "✅ Insert of an existing path answers 409" || (echo "❌ Insert returned $STATUS"; exit 1)
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=logs&key=profile")
+echo "$DOC" | grep -q '"name":"ann"' && echo "✅ Earlier operations rolled back" || (echo "❌ Partial mutation applied: $DOC"; exit 1)
+
+echo "➡️ Mutations honor CAS"
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents/mutate?cf=logs&key=profile&cas=1-0000000000000000" \
+     -H "Content-Type: application/json" -d '{"operations": [{"op": "set", "path": "name", "value": "bob"}]}')
+[ "$STATUS" = "412" ] && echo "✅ Stale CAS answers 412" || (echo "❌ Stale CAS returned $STATUS"; exit 1)
+
+# -----------------------------------
+# JSON PATCH
+# -----------------------------------
+echo
+echo "🔹 Test Merge Patch and JSON Patch"
+
+echo "➡️ Apply a merge patch"
+RESP=$(curl -s -X PATCH "http://localhost:$PORT/documents?cf=logs&key=profile" \
+     -H "Content-Type: application/merge-patch+json" -d '{"address": {"zip": null}, "role": "admin"}')
+echo "Response: $RESP"
+echo "$RESP" | grep -q '"role":"admin"' && echo "$RESP" | grep -qv '"zip"' \
+  && echo "✅ Merge patch applied" || (echo "❌ Merge patch not applied"; exit 1)
+
+echo "➡️ Apply a JSON Patch guarded by a test operation"
+RESP=$(curl -s -X PATCH "http://localhost:$PORT/documents?cf=logs&key=profile" \
+     -H "Content-Type: application/json-patch+json" \
+     -d '[{"op": "test", "path": "/role", "value": "admin"}, {"op": "move", "from": "/role", "path": "/address/role"}]')
+echo "$RESP" | grep -q '"address":{"city":"Oslo","role":"admin"}' && echo "✅ JSON Patch applied" || (echo "❌ JSON Patch not applied: $RESP"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X PATCH "http://localhost:$PORT/documents?cf=logs&key=profile" \
+     -H "Content-Type: application/json-patch+json" -d '[{"op": "test", "path": "/name", "value": "bob"}]')
+[ "$STATUS" = "412" ] && echo "✅ Failed test operation answers 412" || (echo "❌ Failed test returned $STATUS"; exit 1)
+
+echo "➡️ Other media types are rejected"
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X PATCH "http://localhost:$PORT/documents?cf=logs&key=profile" \
+     -H "Content-Type: application/json" -d '{"role": "user"}')
+[ "$STATUS" = "415" ] && echo "✅ Plain JSON patch answers 415" || (echo "❌ Plain JSON patch returned $STATUS"; exit 1)
+
+# -----------------------------------
+# ATOMIC BATCH
+# -----------------------------------
+echo
+echo "🔹 Test Atomic Batch"
+
+echo "➡️ Create column family 'ledger' and two accounts"
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST -H "Content-Type: application/json" \
+     -d '{"name": "ledger"}' "http://localhost:$PORT/families")
+[ "$STATUS" = "201" ] && echo "✅ Column family 'ledger' created" || (echo "❌ Failed to create 'ledger' (status $STATUS)"; exit 1)
+curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=alice&type=counter" \
+     -H "Content-Type: application/json" -d '{"value": 500}' >/dev/null
+curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=bob&type=counter" \
+     -H "Content-Type: application/json" -d '{"value": 0}' >/dev/null
+
+echo "➡️ Move money and record it across families"
+RESP=$(curl -s -X POST "http://localhost:$PORT/batch" -H "Content-Type: application/json" \
+     -d '{"operations": [
+           {"op": "counter_delta", "cf": "logs", "key": "alice", "delta": -100},
+           {"op": "counter_delta", "cf": "logs", "key": "bob", "delta": 100},
+           {"op": "insert", "cf": "ledger", "key": "tx-1", "value": {"from": "alice", "to": "bob", "amount": 100}},
+           {"op": "list_push", "cf": "ledger", "key": "recent", "element": "tx-1"}
+         ]}')
+echo "Response: $RESP"
+echo "$RESP" | grep -q '"key":"alice","old":500,"new":400' && echo "$RESP" | grep -q '"key":"bob","old":0,"new":100' \
+  && echo "✅ Counters updated" || (echo "❌ Counter results missing"; exit 1)
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=ledger&key=tx-1")
+echo "$DOC" | grep -q '"amount":100' && echo "✅ Ledger entry written" || (echo "❌ Ledger entry missing: $DOC"; exit 1)
+
+echo "➡️ A failing operation aborts the whole batch"
+RESP=$(curl -s -w " %{http_code}" -X POST "http://localhost:$PORT/batch" -H "Content-Type: application/json" \
+     -d '{"operations": [
+           {"op": "counter_delta", "cf": "logs", "key": "alice", "delta": -100},
+           {"op": "insert", "cf": "ledger", "key": "tx-1", "value": {"amount": 100}}
+         ]}')
+echo "Response: $RESP"
+echo "$RESP" | grep -q '"index":1' && echo "$RESP" | grep -q ' 409$' \
+  && echo "✅ Failing operation reported with 409" || (echo "❌ Unexpected batch error"; exit 1)
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=logs&key=alice")
+echo "$DOC" | grep -q '"value":400' && echo "✅ Earlier operations rolled back" || (echo "❌ Partial batch applied: $DOC"; exit 1)
+
+# -----------------------------------
+# INTERACTIVE TRANSACTIONS
+# -----------------------------------
+echo
+echo "🔹 Test Interactive Transactions"
+
+echo "➡️ Writes stay private until commit"
+RESP=$(curl -s -X POST "http://localhost:$PORT/transactions")
+TXN=$(json_field "$RESP" txn)
+[ -n "$TXN" ] && echo "✅ Transaction $TXN started" || (echo "❌ Begin failed: $RESP"; exit 1)
+curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=order-1&txn=$TXN" \
+     -H "Content-Type: application/json" -d '{"value": {"status": "paid"}}' >/dev/null
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" "http://localhost:$PORT/documents?cf=logs&key=order-1")
+[ "$STATUS" = "404" ] && echo "✅ Uncommitted write is not visible outside" || (echo "❌ Uncommitted write visible ($STATUS)"; exit 1)
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=logs&key=order-1&txn=$TXN")
+echo "$DOC" | grep -q '"status":"paid"' && echo "✅ Transaction reads its own write" || (echo "❌ Own write not visible: $DOC"; exit 1)
+RESP=$(curl -s -X POST "http://localhost:$PORT/transactions/commit?txn=$TXN")
+echo "$RESP" | grep -q '"status":"committed"' && echo "✅ Transaction committed" || (echo "❌ Commit failed: $RESP"; exit 1)
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=logs&key=order-1")
+echo "$DOC" | grep -q '"status":"paid"' && echo "✅ Committed write visible" || (echo "❌ Committed write missing: $DOC"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/transactions/commit?txn=$TXN")
+[ "$STATUS" = "404" ] && echo "✅ Finished transaction answers 404" || (echo "❌ Second commit returned $STATUS"; exit 1)
+
+echo "➡️ Rollback discards the writes"
+TXN=$(json_field "$(curl -s -X POST "http://localhost:$PORT/transactions")" txn)
+curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=order-1&txn=$TXN" \
+     -H "Content-Type: application/json" -d '{"value": {"status": "refunded"}}' >/dev/null
+RESP=$(curl -s -X POST "http://localhost:$PORT/transactions/rollback?txn=$TXN")
+echo "$RESP" | grep -q '"status":"rolled_back"' && echo "✅ Transaction rolled back" || (echo "❌ Rollback failed: $RESP"; exit 1)
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=logs&key=order-1")
+echo "$DOC" | grep -q '"status":"paid"' && echo "✅ Rolled back write discarded" || (echo "❌ Rolled back write applied: $DOC"; exit 1)
+
+echo "➡️ Documents changed after the transaction began cannot be written"
+TXN=$(json_field "$(curl -s -X POST "http://localhost:$PORT/transactions")" txn)
+curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=order-1" \
+     -H "Content-Type: application/json" -d '{"value": {"status": "shipped"}}' >/dev/null
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents?cf=logs&key=order-1&txn=$TXN" \
+     -H "Content-Type: application/json" -d '{"value": {"status": "cancelled"}}')
+[ "$STATUS" = "409" ] && echo "✅ Conflicting write answers 409" || (echo "❌ Conflicting write returned $STATUS"; exit 1)
+curl -s -X POST "http://localhost:$PORT/transactions/rollback?txn=$TXN" >/dev/null
+
+# -----------------------------------
+# BULK DELETE AND TOUCH
+# -----------------------------------
+echo
+echo "🔹 Test Bulk Delete, Touch and Range Delete"
+
+for KEY in sess:1 sess:2 sess:3 user:7:a user:7:b user:8:a; do
+    curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=$KEY" \
+         -H "Content-Type: application/json" -d "{\"value\": \"$KEY\"}" >/dev/null
+done
+
+echo "➡️ Touch several keys at once"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/bulk/touch?cf=logs&expiration=2" \
+     -H "Content-Type: application/json" -d '{"keys": [{"key": "sess:1"}, {"key": "sess:2"}]}')
+echo "$RESP" | grep -q '"key":"sess:1"' && echo "$RESP" | grep -q '"key":"sess:2"' \
+  && echo "✅ Keys touched" || (echo "❌ Bulk touch failed: $RESP"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents/bulk/touch?cf=logs&expiration=2" \
+     -H "Content-Type: application/json" -d '{"keys": [{"key": "sess:3"}, {"key": "nope"}]}')
+[ "$STATUS" = "404" ] && echo "✅ Touching a missing key answers 404" || (echo "❌ Missing key touch returned $STATUS"; exit 1)
+
+echo "➡️ Delete several keys at once"
+RESP=$(curl -s -w " %{http_code}" -X POST "http://localhost:$PORT/documents/bulk/delete?cf=logs" \
+     -H "Content-Type: application/json" -d '{"keys": [{"key": "sess:3"}, {"key": "user:8:a", "cas": "1-0000000000000000"}]}')
+echo "$RESP" | grep -q '"index":1' && echo "$RESP" | grep -q ' 412$' \
+  && echo "✅ CAS mismatch aborts the bulk delete" || (echo "❌ Unexpected bulk delete result: $RESP"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" "http://localhost:$PORT/documents?cf=logs&key=sess:3")
+[ "$STATUS" = "200" ] && echo "✅ No key deleted" || (echo "❌ Aborted bulk delete removed sess:3"; exit 1)
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/bulk/delete?cf=logs" \
+     -H "Content-Type: application/json" -d '{"keys": [{"key": "sess:3"}]}')
+echo "$RESP" | grep -q '"deleted":1' && echo "✅ Bulk delete succeeded" || (echo "❌ Bulk delete failed: $RESP"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" "http://localhost:$PORT/documents?cf=logs&key=sess:3")
+[ "$STATUS" = "404" ] && echo "✅ Deleted key is gone" || (echo "❌ sess:3 still readable ($STATUS)"; exit 1)
+
+echo "➡️ Purge a key prefix"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/bulk/delete-range?cf=logs" \
+     -H "Content-Type: application/json" -d '{"prefix": "user:7:"}')
+echo "$RESP" | grep -q '"start":"user:7:","end":"user:7;"' && echo "✅ Prefix range purged" || (echo "❌ Range delete failed: $RESP"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" "http://localhost:$PORT/documents?cf=logs&key=user:7:b")
+[ "$STATUS" = "404" ] && echo "✅ Keys in the prefix are gone" || (echo "❌ user:7:b still readable ($STATUS)"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" "http://localhost:$PORT/documents?cf=logs&key=user:8:a")
+[ "$STATUS" = "200" ] && echo "✅ Keys outside the prefix kept" || (echo "❌ user:8:a was purged ($STATUS)"; exit 1)
+
+echo "⏳ Waiting for the touched keys to expire (3s)..."
+sleep 3
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" "http://localhost:$PORT/documents?cf=logs&key=sess:2")
+[ "$STATUS" = "404" ] && echo "✅ Touched keys expired" || (echo "❌ sess:2 still readable ($STATUS)"; exit 1)
+
+# -----------------------------------
+# BULK PUT ENTRIES
+# -----------------------------------
+echo
+echo "🔹 Test Bulk Put with Per-entry Options"
+
+echo "➡️ Store entries with their own type and expiration"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/bulk/put?cf=logs" -H "Content-Type: application/json" \
+     -d '[{"key": "bp:list", "value": [1, 2], "type": "list"},
+          {"key": "bp:counter", "value": 7, "type": "counter"},
+          {"key": "bp:temp", "value": "soon gone", "expiration": 3600}]')
+echo "Response: $RESP"
+echo "$RESP" | grep -q '"results":\[{"key":"bp:list","status":200' && echo "✅ Results returned in request order" || (echo "❌ Unexpected results"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/lists/len?cf=logs&key=bp:list")
+echo "$RESP" | grep -q '"length":2' && echo "✅ List entry stored as a list" || (echo "❌ List entry not a list: $RESP"; exit 1)
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=logs&key=bp:counter")
+echo "$DOC" | grep -q '"type":"counter"' && echo "✅ Counter entry stored as a counter" || (echo "❌ Counter entry not a counter: $DOC"; exit 1)
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=logs&key=bp:temp")
+echo "$DOC" | grep -q '"expiration":[1-9]' && echo "✅ Entry expiration applied" || (echo "❌ Entry has no expiration: $DOC"; exit 1)
+CAS=$(echo "$DOC" | grep -o '"rev":"[^"]*' | cut -d'"' -f4)
+
+echo "➡️ A CAS mismatch aborts an atomic bulk put"
+RESP=$(curl -s -w " %{http_code}" -X POST "http://localhost:$PORT/documents/bulk/put?cf=logs" -H "Content-Type: application/json" \
+     -d '[{"key": "bp:new", "value": "new"}, {"key": "bp:counter", "value": 8, "type": "counter", "cas": "1-0000000000000000"}]')
+echo "$RESP" | grep -q '"index":1' && echo "$RESP" | grep -q ' 412$' \
+  && echo "✅ Failing entry reported with 412" || (echo "❌ Unexpected bulk put result: $RESP"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" "http://localhost:$PORT/documents?cf=logs&key=bp:new")
+[ "$STATUS" = "404" ] && echo "✅ No entry written" || (echo "❌ Aborted bulk put wrote bp:new"; exit 1)
+
+echo "➡️ Partial mode commits the entries that succeed"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/bulk/put?cf=logs&partial=true" -H "Content-Type: application/json" \
+     -d "[{\"key\": \"bp:new\", \"value\": \"new\"},
+          {\"key\": \"bp:counter\", \"value\": 8, \"type\": \"counter\", \"cas\": \"1-0000000000000000\"},
+          {\"key\": \"bp:temp\", \"value\": \"updated\", \"cas\": \"$CAS\"}]")
+echo "Response: $RESP"
+echo "$RESP" | grep -q '"key":"bp:counter","status":412' && echo "✅ Failing entry reported" || (echo "❌ Failing entry not reported"; exit 1)
+echo "$RESP" | grep -q '"key":"bp:temp","status":200' && echo "✅ Entry with a matching CAS written" || (echo "❌ Entry with CAS failed"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" "http://localhost:$PORT/documents?cf=logs&key=bp:new")
+[ "$STATUS" = "200" ] && echo "✅ Successful entries committed" || (echo "❌ bp:new missing ($STATUS)"; exit 1)
+
+# -----------------------------------
+# REVISIONS
+# -----------------------------------
+echo
+echo "🔹 Test Revision Format"
+
+echo "➡️ Revisions count writes and carry a hybrid logical clock"
+DOC=$(curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=revved" \
+     -H "Content-Type: application/json" -d '{"value": 1}')
+REV=$(json_field "$DOC" rev)
+echo "$REV" | grep -Eq '^1-[0-9a-f]{16}$' && echo "✅ First revision is $REV" || (echo "❌ Unexpected revision: $DOC"; exit 1)
+DOC=$(curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=revved" \
+     -H "Content-Type: application/json" -d '{"value": 2}')
+NEXT=$(json_field "$DOC" rev)
+echo "$NEXT" | grep -Eq '^2-[0-9a-f]{16}$' && echo "$DOC" | grep -q '"seq":2' \
+  && echo "✅ Second revision is $NEXT" || (echo "❌ Unexpected revision: $DOC"; exit 1)
+[ "${NEXT#2-}" \> "${REV#1-}" ] && echo "✅ Clock moved forward" || (echo "❌ Clock went backwards: $REV -> $NEXT"; exit 1)
+
+# -----------------------------------
+# CONDITIONAL REQUESTS
+# -----------------------------------
+echo
+echo "🔹 Test ETag and Conditional Requests"
+
+echo "➡️ Reads expose the revision as ETag"
+HEADERS=$(curl -s -D - -o /dev/null -X POST "http://localhost:$PORT/documents?cf=logs&key=etagged" \
+     -H "Content-Type: application/json" -d '{"value": "v1"}')
+ETAG=$(echo "$HEADERS" | grep -i '^ETag:' | cut -d' ' -f2 | tr -d '\r')
+[ -n "$ETAG" ] && echo "✅ Write returned ETag $ETAG" || (echo "❌ Missing ETag: $HEADERS"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -H "If-None-Match: $ETAG" "http://localhost:$PORT/documents?cf=logs&key=etagged")
+[ "$STATUS" = "304" ] && echo "✅ Unchanged document answers 304" || (echo "❌ If-None-Match returned $STATUS"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -H 'If-None-Match: "1-0000000000000000"' "http://localhost:$PORT/documents?cf=logs&key=etagged")
+[ "$STATUS" = "200" ] && echo "✅ Other revisions are served" || (echo "❌ Stale If-None-Match returned $STATUS"; exit 1)
+
+echo "➡️ If-Match guards writes"
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents?cf=logs&key=etagged" \
+     -H "Content-Type: application/json" -H 'If-Match: "1-0000000000000000"' -d '{"value": "stale"}')
+[ "$STATUS" = "412" ] && echo "✅ Stale If-Match answers 412" || (echo "❌ Stale If-Match returned $STATUS"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents?cf=logs&key=etagged" \
+     -H "Content-Type: application/json" -H "If-Match: $ETAG" -d '{"value": "v2"}')
+[ "$STATUS" = "200" ] && echo "✅ Matching If-Match accepted" || (echo "❌ Matching If-Match returned $STATUS"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X DELETE -H "If-Match: $ETAG" "http://localhost:$PORT/documents?cf=logs&key=etagged")
+[ "$STATUS" = "412" ] && echo "✅ Delete with an outdated ETag answers 412" || (echo "❌ Outdated delete returned $STATUS"; exit 1)
+
+echo "➡️ Wildcards select create-only and update-only writes"
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents?cf=logs&key=etagged" \
+     -H "Content-Type: application/json" -H "If-None-Match: *" -d '{"value": "v3"}')
+[ "$STATUS" = "412" ] && echo "✅ If-None-Match: * refuses existing documents" || (echo "❌ If-None-Match: * returned $STATUS"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents?cf=logs&key=etagged-missing" \
+     -H "Content-Type: application/json" -H "If-Match: *" -d '{"value": "v1"}')
+[ "$STATUS" = "412" ] && echo "✅ If-Match: * refuses missing documents" || (echo "❌ If-Match: * returned $STATUS"; exit 1)
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=logs&key=etagged")
+echo "$DOC" | grep -q '"value":"v2"' && echo "✅ Only the matching write applied" || (echo "❌ Unexpected document: $DOC"; exit 1)
+
+# -----------------------------------
+# DOCUMENT HISTORY
+# -----------------------------------
+echo
+echo "🔹 Test Document History"
+
+echo "➡️ Write, update and delete a document in 'audit'"
+DOC=$(curl -s -X POST "http://localhost:$PORT/documents?cf=audit&key=note" \
+     -H "Content-Type: application/json" -d '{"value": "v1"}')
+REV1=$(json_field "$DOC" rev)
+sleep 1
+AS_OF=$(date +%s)
+sleep 1
+curl -s -X POST "http://localhost:$PORT/documents?cf=audit&key=note" \
+     -H "Content-Type: application/json" -d '{"value": "v2"}' >/dev/null
+curl -s -o /dev/null -X DELETE "http://localhost:$PORT/documents?cf=audit&key=note"
+
+echo "➡️ List the revisions"
+RESP=$(curl -s "http://localhost:$PORT/documents/history?cf=audit&key=note")
+echo "History: $RESP"
+[ "$(echo "$RESP" | grep -o '"rev":' | wc -l)" -eq 3 ] && echo "✅ Three revisions kept" || (echo "❌ Unexpected revision count"; exit 1)
+echo "$RESP" | grep -q '"revisions":\[{"rev":"[^"]*","seq":3,[^}]*"deleted":true' && echo "✅ Deletion listed first" || (echo "❌ Deletion not listed first"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/history?cf=audit&key=note&limit=1&values=true")
+[ "$(echo "$RESP" | grep -o '"rev":"[^"]*","seq"' | wc -l)" -eq 1 ] && echo "✅ Limit applied" || (echo "❌ Limit ignored: $RESP"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" "http://localhost:$PORT/documents/history?cf=logs&key=foo")
+[ "$STATUS" = "400" ] && echo "✅ History of a family without history answers 400" || (echo "❌ History without history returned $STATUS"; exit 1)
+
+echo "➡️ Read past versions"
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=audit&key=note&rev=$REV1")
+echo "$DOC" | grep -q '"value":"v1"' && echo "✅ Past revision read by rev" || (echo "❌ rev read failed: $DOC"; exit 1)
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=audit&key=note&as_of=$AS_OF")
+echo "$DOC" | grep -q '"value":"v1"' && echo "✅ Past version read by as_of" || (echo "❌ as_of read failed: $DOC"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" "http://localhost:$PORT/documents?cf=audit&key=note&as_of=$(( $(date +%s) + 1 ))")
+[ "$STATUS" = "404" ] && echo "✅ Deleted document not found as of now" || (echo "❌ as_of after the delete returned $STATUS"; exit 1)
+
+echo "➡️ Revert the deleted document to its first revision"
+DOC=$(curl -s -X POST "http://localhost:$PORT/documents/revert?cf=audit&key=note&rev=$REV1")
+echo "$DOC" | grep -q '"value":"v1"' && echo "$DOC" | grep -q '"seq":4' \
+  && echo "✅ First revision restored as the newest" || (echo "❌ Revert failed: $DOC"; exit 1)
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=audit&key=note")
+echo "$DOC" | grep -q '"value":"v1"' && echo "✅ Restored document readable" || (echo "❌ Restored document missing: $DOC"; exit 1)
+
+# -----------------------------------
+# TRASH AND UNDELETE
+# -----------------------------------
+echo
+echo "🔹 Test Trash and Undelete"
+
+echo "➡️ Create column family 'bin' with a trash"
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST -H "Content-Type: application/json" \
+     -d '{"name": "bin"}' "http://localhost:$PORT/families")
+[ "$STATUS" = "201" ] && echo "✅ Column family 'bin' created" || (echo "❌ Failed to create 'bin' (status $STATUS)"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X PUT "http://localhost:$PORT/families/settings?cf=bin" \
+     -H "Content-Type: application/json" -d '{"trash": {"enabled": true, "retention": "1h"}}')
+[ "$STATUS" = "200" ] && echo "✅ Trash enabled" || (echo "❌ Enabling the trash failed (status $STATUS)"; exit 1)
+
+echo "➡️ Deleted documents move to the trash"
+curl -s -X POST "http://localhost:$PORT/documents?cf=bin&key=draft&expiration=2" \
+     -H "Content-Type: application/json" -d '{"value": "draft"}' >/dev/null
+curl -s -X POST "http://localhost:$PORT/documents?cf=bin&key=final" \
+     -H "Content-Type: application/json" -d '{"value": "final"}' >/dev/null
+curl -s -o /dev/null -X DELETE "http://localhost:$PORT/documents?cf=bin&key=draft&actor=ops"
+curl -s -o /dev/null -X DELETE "http://localhost:$PORT/documents?cf=bin&key=final"
+RESP=$(curl -s "http://localhost:$PORT/families/bin/trash")
+echo "Trash: $RESP"
+echo "$RESP" | grep -q '"key":"draft"' && echo "$RESP" | grep -q '"deleted_by":"ops"' && echo "$RESP" | grep -q '"key":"final"' \
+  && echo "✅ Both documents listed with their actor" || (echo "❌ Unexpected trash listing"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" "http://localhost:$PORT/documents?cf=bin&key=final")
+[ "$STATUS" = "404" ] && echo "✅ Trashed document not readable" || (echo "❌ Trashed document returned $STATUS"; exit 1)
+
+echo "➡️ Restore a document whose expiration passed in the trash"
+echo "⏳ Waiting for the expiration of 'draft' (3s)..."
+sleep 3
+DOC=$(curl -s -X POST "http://localhost:$PORT/documents/undelete?cf=bin&key=draft")
+echo "$DOC" | grep -q '"value":"draft"' && echo "$DOC" | grep -q '"expiration":0' \
+  && echo "✅ Restored without the passed expiration" || (echo "❌ Undelete failed: $DOC"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" "http://localhost:$PORT/documents?cf=bin&key=draft")
+[ "$STATUS" = "200" ] && echo "✅ Restored document readable" || (echo "❌ Restored document returned $STATUS"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents/undelete?cf=bin&key=draft")
+[ "$STATUS" = "404" ] && echo "✅ Restored document left the trash" || (echo "❌ Second undelete returned $STATUS"; exit 1)
+
+echo "➡️ A live document blocks the restore"
+curl -s -X POST "http://localhost:$PORT/documents?cf=bin&key=final" \
+     -H "Content-Type: application/json" -d '{"value": "rewritten"}' >/dev/null
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents/undelete?cf=bin&key=final")
+[ "$STATUS" = "409" ] && echo "✅ Undelete over a live key answers 409" || (echo "❌ Undelete over a live key returned $STATUS"; exit 1)
+
+echo "➡️ Permanent deletes skip the trash"
+curl -s -o /dev/null -X DELETE "http://localhost:$PORT/documents?cf=bin&key=draft&permanent=true"
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents/undelete?cf=bin&key=draft")
+[ "$STATUS" = "404" ] && echo "✅ Permanently deleted document not in the trash" || (echo "❌ Undelete after a permanent delete returned $STATUS"; exit 1)
+
+# -----------------------------------
+# SNAPSHOTS
+# -----------------------------------
+echo
+echo "🔹 Test Pinned Snapshots"
+
+echo "➡️ Pin a snapshot, then change the data"
+curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=snap:a" \
+     -H "Content-Type: application/json" -d '{"value": "before"}' >/dev/null
+RESP=$(curl -s -X POST "http://localhost:$PORT/snapshots?lease=30s")
+SNAP=$(json_field "$RESP" snapshot)
+[ -n "$SNAP" ] && echo "✅ Snapshot $SNAP pinned" || (echo "❌ Snapshot failed: $RESP"; exit 1)
+curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=snap:a" \
+     -H "Content-Type: application/json" -d '{"value": "after"}' >/dev/null
+curl -s -X POST "http://localhost:$PORT/documents?cf=logs&key=snap:b" \
+     -H "Content-Type: application/json" -d '{"value": "new"}' >/dev/null
+
+echo "➡️ Reads with the snapshot see the pinned data"
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=logs&key=snap:a&snapshot=$SNAP")
+echo "$DOC" | grep -q '"value":"before"' && echo "✅ Document read as pinned" || (echo "❌ Snapshot read failed: $DOC"; exit 1)
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=logs&key=snap:a")
+echo "$DOC" | grep -q '"value":"after"' && echo "✅ Reads without the snapshot see the change" || (echo "❌ Unexpected document: $DOC"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/documents/keys?cf=logs&prefix=snap:&snapshot=$SNAP")
+echo "$RESP" | grep -q '"snap:a"' && echo "$RESP" | grep -qv '"snap:b"' \
+  && echo "✅ Key listing sees the pinned keys only" || (echo "❌ Unexpected keys: $RESP"; exit 1)
+RESP=$(curl -s "http://localhost:$PORT/snapshots")
+echo "$RESP" | grep -q "\"snapshot\":\"$SNAP\"" && echo "✅ Snapshot listed" || (echo "❌ Snapshot not listed: $RESP"; exit 1)
+
+echo "➡️ Release the snapshot"
+RESP=$(curl -s -X DELETE "http://localhost:$PORT/snapshots/$SNAP")
+echo "$RESP" | grep -q '"status":"released"' && echo "✅ Snapshot released" || (echo "❌ Release failed: $RESP"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" "http://localhost:$PORT/documents?cf=logs&key=snap:a&snapshot=$SNAP")
+[ "$STATUS" = "404" ] && echo "✅ Released snapshot answers 404" || (echo "❌ Released snapshot returned $STATUS"; exit 1)
+
+# -----------------------------------
+# COUNTER OPTIONS
+# -----------------------------------
+echo
+echo "🔹 Test Counter Bounds, Getset and Multi-delta"
+
+echo "➡️ Create a missing counter with an initial value"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/counters/delta?cf=logs&key=quota" \
+     -H "Content-Type: application/json" -d '{"delta": 1, "initial": 9, "max": 10}')
+echo "$RESP" | grep -q '"old":null,"new":9,"created":true' && echo "✅ Counter created with 9" || (echo "❌ Create failed: $RESP"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents/counters/delta?cf=logs&key=nocounter" \
+     -H "Content-Type: application/json" -d '{"delta": 1}')
+[ "$STATUS" = "404" ] && echo "✅ Missing counter without initial answers 404" || (echo "❌ Missing counter returned $STATUS"; exit 1)
+
+echo "➡️ Enforce and clamp to bounds"
+curl -s -X POST "http://localhost:$PORT/documents/counters/delta?cf=logs&key=quota" \
+     -H "Content-Type: application/json" -d '{"delta": 1, "max": 10}' >/dev/null
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents/counters/delta?cf=logs&key=quota" \
+     -H "Content-Type: application/json" -d '{"delta": 1, "max": 10}')
+[ "$STATUS" = "409" ] && echo "✅ Exceeding max answers 409" || (echo "❌ Exceeding max returned $STATUS"; exit 1)
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/counters/delta?cf=logs&key=quota" \
+     -H "Content-Type: application/json" -d '{"delta": -25, "min": 0, "clamp": true}')
+echo "$RESP" | grep -q '"old":10,"new":0,"clamped":true' && echo "✅ Decrement clamped to 0" || (echo "❌ Clamp failed: $RESP"; exit 1)
+
+echo "➡️ Float deltas make float counters"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/counters/delta?cf=logs&key=quota" \
+     -H "Content-Type: application/json" -d '{"delta": 0.5}')
+echo "$RESP" | grep -q '"new":0.5' && echo "✅ Counter is now 0.5" || (echo "❌ Float delta failed: $RESP"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents/counters/delta?cf=logs&key=bytes" \
+     -H "Content-Type: application/json" -d '{"delta": 0.5}')
+[ "$STATUS" = "400" ] && echo "✅ Float delta on a large integer counter answers 400" || (echo "❌ Float delta returned $STATUS"; exit 1)
+
+echo "➡️ Get and set a counter"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/counters/getset?cf=logs&key=quota" \
+     -H "Content-Type: application/json" -d '{"value": 3}')
+echo "$RESP" | grep -q '"old":0.5,"new":3' && echo "✅ Previous value returned" || (echo "❌ Getset failed: $RESP"; exit 1)
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/counters/getset?cf=logs&key=fresh" \
+     -H "Content-Type: application/json" -d '{}')
+echo "$RESP" | grep -q '"old":null,"new":0,"created":true' && echo "✅ Missing counter created with 0" || (echo "❌ Getset create failed: $RESP"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents/counters/getset?cf=logs&key=foo" \
+     -H "Content-Type: application/json" -d '{"value": 1}')
+[ "$STATUS" = "400" ] && echo "✅ Getset on a JSON document answers 400" || (echo "❌ Getset on a JSON document returned $STATUS"; exit 1)
+
+echo "➡️ Increment several counters atomically"
+RESP=$(curl -s -X POST "http://localhost:$PORT/documents/counters/multi-delta?cf=logs" \
+     -H "Content-Type: application/json" \
+     -d '{"counters": [{"key": "quota", "delta": 1}, {"key": "fresh", "delta": 2}, {"key": "hits", "delta": 1, "initial": 1}]}')
+echo "Response: $RESP"
+echo "$RESP" | grep -q '"counters":\[{"key":"quota","old":3,"new":4},{"key":"fresh","old":0,"new":2},{"key":"hits","old":null,"new":1,"created":true}\]' \
+  && echo "✅ Results returned in request order" || (echo "❌ Unexpected results"; exit 1)
+STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X POST "http://localhost:$PORT/documents/counters/multi-delta?cf=logs" \
+     -H "Content-Type: application/json" \
+     -d '{"counters": [{"key": "quota", "delta": 1}, {"key": "hits", "delta": 1, "max": 1}]}')
+[ "$STATUS" = "409" ] && echo "✅ A counter out of bounds aborts the request" || (echo "❌ Out of bounds multi-delta returned $STATUS"; exit 1)
+DOC=$(curl -s "http://localhost:$PORT/documents?cf=logs&key=quota")
+echo "$DOC" | grep -q '"value":4' && echo "✅ No counter changed" || (echo "❌ Partial multi-delta applied: $DOC"; exit 1)
+
 echo
 echo "✅ All tests completed successfully."